@@ -5,9 +5,13 @@ import (
 
 	"sso/internal/storage"
 	"sso/internal/storage/cache/authcode"
+	"sso/internal/storage/cache/clientassertion"
 	"sso/internal/storage/cache/consent"
+	"sso/internal/storage/cache/device"
 	"sso/internal/storage/cache/otp"
+	"sso/internal/storage/cache/requestobject"
 	"sso/internal/storage/cache/resetcode"
+	"sso/internal/storage/cache/revokedtoken"
 	"sso/internal/storage/cache/session"
 	mock_otp "sso/mocks/storage/cache/otp"
 	resetcode2 "sso/mocks/storage/cache/resetcode"
@@ -17,11 +21,15 @@ import (
 )
 
 type CacheLayer struct {
-	OTPCacheLayer       storage.OTPCache
-	SessionCacheLayer   storage.SessionCache
-	ConsentCacheLayer   storage.ConsentCache
-	AuthCodeCacheLayer  storage.AuthCodeCache
-	ResetCodeCacheLayer storage.ResetCodeCache
+	OTPCacheLayer             storage.OTPCache
+	SessionCacheLayer         storage.SessionCache
+	ConsentCacheLayer         storage.ConsentCache
+	AuthCodeCacheLayer        storage.AuthCodeCache
+	ResetCodeCacheLayer       storage.ResetCodeCache
+	DeviceCacheLayer          storage.DeviceCache
+	RevokedTokenCacheLayer    storage.RevokedTokenCache
+	ClientAssertionCacheLayer storage.ClientAssertionCache
+	RequestObjectCacheLayer   storage.RequestObjectCache
 }
 
 type CacheOptions struct {
@@ -30,24 +38,33 @@ type CacheOptions struct {
 	ConsentExpireTime   time.Duration
 	AuthCodeExpireTime  time.Duration
 	ResetCodeExpireTime time.Duration
+	DeviceExpireTime    time.Duration
 }
 
 func InitCacheLayer(client *redis.Client, options CacheOptions, log logger.Logger) CacheLayer {
 	return CacheLayer{
-		OTPCacheLayer:       otp.InitOTPCache(client, log.Named("otp-cache"), options.OTPExpireTime),
-		SessionCacheLayer:   session.InitSessionCache(client, log.Named("session-cache"), options.SessionExpireTime),
-		ConsentCacheLayer:   consent.InitConsentCache(client, log.Named("consent-cache"), options.ConsentExpireTime),
-		AuthCodeCacheLayer:  authcode.InitAuthCodeCache(client, log.Named("authcode-cache"), options.AuthCodeExpireTime),
-		ResetCodeCacheLayer: resetcode.InitResetCode(client, log.Named("reset-code-cache"), options.ResetCodeExpireTime),
+		OTPCacheLayer:             otp.InitOTPCache(client, log.Named("otp-cache"), options.OTPExpireTime),
+		SessionCacheLayer:         session.InitSessionCache(client, log.Named("session-cache"), options.SessionExpireTime),
+		ConsentCacheLayer:         consent.InitConsentCache(client, log.Named("consent-cache"), options.ConsentExpireTime),
+		AuthCodeCacheLayer:        authcode.InitAuthCodeCache(client, log.Named("authcode-cache"), options.AuthCodeExpireTime),
+		ResetCodeCacheLayer:       resetcode.InitResetCode(client, log.Named("reset-code-cache"), options.ResetCodeExpireTime),
+		DeviceCacheLayer:          device.InitDeviceCache(client, log.Named("device-cache"), options.DeviceExpireTime),
+		RevokedTokenCacheLayer:    revokedtoken.InitRevokedTokenCache(client, log.Named("revoked-token-cache")),
+		ClientAssertionCacheLayer: clientassertion.InitClientAssertionCache(client, log.Named("client-assertion-cache")),
+		RequestObjectCacheLayer:   requestobject.InitRequestObjectCache(client, log.Named("request-object-cache")),
 	}
 }
 
 func InitMockCacheLayer(client *redis.Client, _ time.Duration, mockOTP string, log logger.Logger, options CacheOptions) CacheLayer {
 	return CacheLayer{
-		OTPCacheLayer:       mock_otp.InitMockOTPCache(client, log.Named("otp-cache"), options.OTPExpireTime, mockOTP),
-		SessionCacheLayer:   session.InitSessionCache(client, log.Named("session-cache"), options.SessionExpireTime),
-		ConsentCacheLayer:   consent.InitConsentCache(client, log.Named("consent-cache"), options.ConsentExpireTime),
-		AuthCodeCacheLayer:  authcode.InitAuthCodeCache(client, log.Named("authcode-cache"), options.AuthCodeExpireTime),
-		ResetCodeCacheLayer: resetcode2.InitMockResetCode(client, log.Named("reset-code-cache"), options.ResetCodeExpireTime, mockOTP),
+		OTPCacheLayer:             mock_otp.InitMockOTPCache(client, log.Named("otp-cache"), options.OTPExpireTime, mockOTP),
+		SessionCacheLayer:         session.InitSessionCache(client, log.Named("session-cache"), options.SessionExpireTime),
+		ConsentCacheLayer:         consent.InitConsentCache(client, log.Named("consent-cache"), options.ConsentExpireTime),
+		AuthCodeCacheLayer:        authcode.InitAuthCodeCache(client, log.Named("authcode-cache"), options.AuthCodeExpireTime),
+		ResetCodeCacheLayer:       resetcode2.InitMockResetCode(client, log.Named("reset-code-cache"), options.ResetCodeExpireTime, mockOTP),
+		DeviceCacheLayer:          device.InitDeviceCache(client, log.Named("device-cache"), options.DeviceExpireTime),
+		RevokedTokenCacheLayer:    revokedtoken.InitRevokedTokenCache(client, log.Named("revoked-token-cache")),
+		ClientAssertionCacheLayer: clientassertion.InitClientAssertionCache(client, log.Named("client-assertion-cache")),
+		RequestObjectCacheLayer:   requestobject.InitRequestObjectCache(client, log.Named("request-object-cache")),
 	}
 }
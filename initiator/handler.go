@@ -13,6 +13,7 @@ import (
 	"sso/internal/handler/rest/role"
 	rs_api "sso/internal/handler/rest/rs-api"
 	"sso/internal/handler/rest/scope"
+	signing_key "sso/internal/handler/rest/signing-key"
 	"sso/internal/handler/rest/user"
 	"sso/platform/logger"
 	"sso/platform/utils"
@@ -33,6 +34,7 @@ type Handler struct {
 	identityProvider rest.IdentityProvider
 	rsAPI            rest.RSAPI
 	asset            rest.Asset
+	signingKey       rest.SigningKey
 }
 
 func InitHandler(module Module, log logger.Logger) Handler {
@@ -81,5 +83,6 @@ func InitHandler(module Module, log logger.Logger) Handler {
 		identityProvider: identity_provider.InitIdentityProvider(log.Named("identity-provider-handler"), module.identityProvider),
 		rsAPI:            rs_api.Init(log.Named("rs_api"), module.rsAPI),
 		asset:            asset.Init(log.Named("asset-handler"), module.asset),
+		signingKey:       signing_key.Init(log.Named("signing-key-handler"), module.SigningKeyModule),
 	}
 }
@@ -8,6 +8,7 @@ import (
 	"os/signal"
 	"syscall"
 
+	"sso/internal/constant"
 	"sso/internal/constant/model/persistencedb"
 	"sso/internal/handler/middleware"
 	"sso/platform/logger"
@@ -79,6 +80,7 @@ func Initiate() {
 		ConsentExpireTime:   viper.GetDuration("redis.consent_expire_time"),
 		AuthCodeExpireTime:  viper.GetDuration("redis.authcode_expire_time"),
 		ResetCodeExpireTime: viper.GetDuration("redis.reset_code_expire_time"),
+		DeviceExpireTime:    viper.GetDuration("redis.device_code_expire_time"),
 	}, log)
 	log.Info(context.Background(), "cache layer initialized")
 
@@ -93,6 +95,11 @@ func Initiate() {
 	log.Info(context.Background(), "initializing module")
 	module := InitModule(persistence, cacheLayer, viper.GetString("private_key"), platformLayer, log, enforcer, state)
 	log.Info(context.Background(), "module initialized")
+
+	log.Info(context.Background(), "initializing signing keys")
+	InitSigningKeys(module.SigningKeyModule, viper.GetDuration("signing_keys.refresh_interval"), log)
+	log.Info(context.Background(), "signing keys initialized")
+
 	platformLayer.Kafka.RegisterKafkaEventHandler(string("CREATE"), module.MiniRideModule.CreateUser)
 	platformLayer.Kafka.RegisterKafkaEventHandler(string("UPDATE"), module.MiniRideModule.UpdateUser)
 
@@ -115,7 +122,7 @@ func Initiate() {
 	log.Info(context.Background(), "metrics route initialized")
 
 	log.Info(context.Background(), "initializing router")
-	v1 := server.Group("/v1")
+	v1 := server.Group(constant.APIBasePath)
 	InitRouter(server, v1, handler, module, log, enforcer, platformLayer)
 	log.Info(context.Background(), "router initialized")
 
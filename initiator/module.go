@@ -13,6 +13,7 @@ import (
 	"sso/internal/module/role"
 	rs_api "sso/internal/module/rs-api"
 	"sso/internal/module/scope"
+	signing_key "sso/internal/module/signing-key"
 	"sso/internal/module/user"
 	"sso/platform/logger"
 
@@ -34,6 +35,7 @@ type Module struct {
 	identityProvider module.IdentityProviderModule
 	rsAPI            module.RSAPI
 	asset            module.Asset
+	SigningKeyModule module.SigningKeyModule
 }
 
 func InitModule(persistence Persistence, cache CacheLayer, privateKeyPath string, platformLayer PlatformLayer, log logger.Logger, enforcer *casbin.Enforcer, state State) Module {
@@ -56,14 +58,18 @@ func InitModule(persistence Persistence, cache CacheLayer, privateKeyPath string
 			platformLayer.Sms,
 			platformLayer.SelfIP,
 			cache.ResetCodeCacheLayer,
+			state.URLs,
 			oauth.SetOptions(oauth.Options{
-				AccessTokenExpireTime:  viper.GetDuration("server.login.access_token.expire_time"),
-				RefreshTokenExpireTime: viper.GetDuration("server.login.refresh_token.expire_time"),
-				IDTokenExpireTime:      viper.GetDuration("server.login.id_token.expire_time"),
-				ExcludedPhones:         state.ExcludedPhones,
+				AccessTokenExpireTime:          viper.GetDuration("server.login.access_token.expire_time"),
+				RefreshTokenExpireTime:         viper.GetDuration("server.login.refresh_token.expire_time"),
+				IDTokenExpireTime:              viper.GetDuration("server.login.id_token.expire_time"),
+				ExcludedPhones:                 state.ExcludedPhones,
+				LogoutTokenExpireTime:          viper.GetDuration("server.logout.logout_token.expire_time"),
+				BackChannelLogoutAttempts:      viper.GetInt("server.logout.backchannel.attempts"),
+				BackChannelLogoutRetryInterval: viper.GetDuration("server.logout.backchannel.retry_interval"),
 			}),
 		),
-		clientModule: client.InitClient(log.Named("client-module"), persistence.ClientPersistence),
+		clientModule: client.InitClient(log.Named("client-module"), persistence.ClientPersistence, persistence.SigningKeyPersistence, persistence.ScopePersistence, cache.ClientAssertionCacheLayer, state.URLs),
 		OAuth2Module: oauth2.InitOAuth2(
 			log.Named("oauth2-module"),
 			persistence.OAuth2Persistence,
@@ -71,14 +77,21 @@ func InitModule(persistence Persistence, cache CacheLayer, privateKeyPath string
 			persistence.ClientPersistence,
 			cache.ConsentCacheLayer,
 			cache.AuthCodeCacheLayer,
+			cache.DeviceCacheLayer,
+			cache.RevokedTokenCacheLayer,
+			cache.RequestObjectCacheLayer,
 			platformLayer.Token,
 			oauth2.SetOptions(
 				oauth2.Options{
 					AccessTokenExpireTime:  viper.GetDuration("server.client.access_token.expire_time"),
 					RefreshTokenExpireTime: viper.GetDuration("server.client.refresh_token.expire_time"),
+					DeviceCodeExpireTime:   viper.GetDuration("redis.device_code_expire_time"),
+					DevicePollInterval:     viper.GetDuration("server.client.device_code.poll_interval"),
+					ConsentExpireTime:      viper.GetDuration("redis.consent_expire_time"),
 				},
 			),
 			persistence.ScopePersistence,
+			persistence.ResourceServerPersistence,
 			state.URLs),
 		scopeModule: scope.InitScope(log.Named("scope-module"), persistence.ScopePersistence),
 		profile: profile.InitProfile(
@@ -93,9 +106,18 @@ func InitModule(persistence Persistence, cache CacheLayer, privateKeyPath string
 		resourceServer:   resource_server.InitResourceServer(log.Named("resource-server-module"), persistence.ResourceServerPersistence, persistence.ScopePersistence),
 		RoleModule:       role.InitRole(log.Named("role-module"), persistence.RolePersistence),
 		identityProvider: identity_provider.InitIdentityProvider(log.Named("identity-provider-module"), persistence.IdentityProviderPersistence),
-		rsAPI:            rs_api.Init(log.Named("rs_api_module"), persistence.UserPersistence),
+		rsAPI:            rs_api.Init(log.Named("rs_api_module"), persistence.UserPersistence, persistence.ResourceServerPersistence),
 		asset:            asset.Init(log.Named("asset-module"), platformLayer.Asset, state.UploadParams),
 		MiniRideModule:   miniRideModule,
+		SigningKeyModule: signing_key.InitSigningKey(
+			log.Named("signing-key-module"),
+			persistence.SigningKeyPersistence,
+			platformLayer.Token,
+			signing_key.SetOptions(signing_key.Options{
+				KeySize:               viper.GetInt("signing_keys.key_size"),
+				DefaultPrivateKeyPath: privateKeyPath,
+				EncryptionKey:         viper.GetString("signing_keys.encryption_key"),
+			})),
 	}
 }
 
@@ -117,14 +139,18 @@ func InitMockModule(persistence Persistence, cache CacheLayer, privateKeyPath st
 			platformLayer.Sms,
 			platformLayer.SelfIP,
 			cache.ResetCodeCacheLayer,
+			state.URLs,
 			oauth.SetOptions(oauth.Options{
-				AccessTokenExpireTime:  viper.GetDuration("server.login.access_token.expire_time"),
-				RefreshTokenExpireTime: viper.GetDuration("server.login.refresh_token.expire_time"),
-				IDTokenExpireTime:      viper.GetDuration("server.login.id_token.expire_time"),
-				ExcludedPhones:         state.ExcludedPhones,
+				AccessTokenExpireTime:          viper.GetDuration("server.login.access_token.expire_time"),
+				RefreshTokenExpireTime:         viper.GetDuration("server.login.refresh_token.expire_time"),
+				IDTokenExpireTime:              viper.GetDuration("server.login.id_token.expire_time"),
+				ExcludedPhones:                 state.ExcludedPhones,
+				LogoutTokenExpireTime:          viper.GetDuration("server.logout.logout_token.expire_time"),
+				BackChannelLogoutAttempts:      viper.GetInt("server.logout.backchannel.attempts"),
+				BackChannelLogoutRetryInterval: viper.GetDuration("server.logout.backchannel.retry_interval"),
 			}),
 		),
-		clientModule: client.InitClient(log.Named("client-module"), persistence.ClientPersistence),
+		clientModule: client.InitClient(log.Named("client-module"), persistence.ClientPersistence, persistence.SigningKeyPersistence, persistence.ScopePersistence, cache.ClientAssertionCacheLayer, state.URLs),
 		OAuth2Module: oauth2.InitOAuth2(
 			log.Named("oauth2-module"),
 			persistence.OAuth2Persistence,
@@ -132,14 +158,21 @@ func InitMockModule(persistence Persistence, cache CacheLayer, privateKeyPath st
 			persistence.ClientPersistence,
 			cache.ConsentCacheLayer,
 			cache.AuthCodeCacheLayer,
+			cache.DeviceCacheLayer,
+			cache.RevokedTokenCacheLayer,
+			cache.RequestObjectCacheLayer,
 			platformLayer.Token,
 			oauth2.SetOptions(
 				oauth2.Options{
 					AccessTokenExpireTime:  viper.GetDuration("server.client.access_token.expire_time"),
 					RefreshTokenExpireTime: viper.GetDuration("server.client.refresh_token.expire_time"),
+					DeviceCodeExpireTime:   viper.GetDuration("redis.device_code_expire_time"),
+					DevicePollInterval:     viper.GetDuration("server.client.device_code.poll_interval"),
+					ConsentExpireTime:      viper.GetDuration("redis.consent_expire_time"),
 				},
 			),
 			persistence.ScopePersistence,
+			persistence.ResourceServerPersistence,
 			state.URLs),
 		scopeModule: scope.InitScope(log.Named("scope-module"), persistence.ScopePersistence),
 		profile: profile.InitProfile(
@@ -155,7 +188,16 @@ func InitMockModule(persistence Persistence, cache CacheLayer, privateKeyPath st
 		MiniRideModule:   mini_ride.InitMinRide(log.Named("mini-ride-module"), persistence.MiniRidePersistence, platformLayer.Kafka),
 		RoleModule:       role.InitRole(log.Named("role-module"), persistence.RolePersistence),
 		identityProvider: identity_provider.InitIdentityProvider(log.Named("identity-provider-module"), persistence.IdentityProviderPersistence),
-		rsAPI:            rs_api.Init(log.Named("rs_api_module"), persistence.UserPersistence),
+		rsAPI:            rs_api.Init(log.Named("rs_api_module"), persistence.UserPersistence, persistence.ResourceServerPersistence),
 		asset:            asset.Init(log.Named("asset-module"), platformLayer.Asset, state.UploadParams),
+		SigningKeyModule: signing_key.InitSigningKey(
+			log.Named("signing-key-module"),
+			persistence.SigningKeyPersistence,
+			platformLayer.Token,
+			signing_key.SetOptions(signing_key.Options{
+				KeySize:               viper.GetInt("signing_keys.key_size"),
+				DefaultPrivateKeyPath: privateKeyPath,
+				EncryptionKey:         viper.GetString("signing_keys.encryption_key"),
+			})),
 	}
 }
@@ -12,6 +12,7 @@ import (
 	resource_server "sso/internal/storage/persistence/resource-server"
 	"sso/internal/storage/persistence/role"
 	"sso/internal/storage/persistence/scope"
+	signing_key "sso/internal/storage/persistence/signing-key"
 	"sso/internal/storage/persistence/user"
 	"sso/platform/logger"
 )
@@ -28,13 +29,14 @@ type Persistence struct {
 	MiniRidePersistence         storage.MiniRidePersistence
 	RolePersistence             storage.RolePersistence
 	IdentityProviderPersistence storage.IdentityProviderPersistence
+	SigningKeyPersistence       storage.SigningKeyPersistence
 }
 
 func InitPersistence(db persistencedb.PersistenceDB, log logger.Logger) Persistence {
 	return Persistence{
 		OAuthPersistence:            oauth.InitOAuth(log.Named("oauth-persistence"), &db),
 		ClientPersistence:           client.InitClient(log.Named("client-persistence"), db.Queries),
-		OAuth2Persistence:           oauth2.InitOAuth2(log.Named("oauth2-persistence"), db.Queries),
+		OAuth2Persistence:           oauth2.InitOAuth2(log.Named("oauth2-persistence"), &db),
 		ScopePersistence:            scope.InitScopePersistence(log.Named("scope-persistence"), db.Queries),
 		UserPersistence:             user.InitUserPersistence(log.Named("user-persistence"), &db),
 		ProfilePersistence:          profile.InitProfilePersistence(log.Named("profile-persistence"), &db),
@@ -42,5 +44,6 @@ func InitPersistence(db persistencedb.PersistenceDB, log logger.Logger) Persiste
 		MiniRidePersistence:         mini_ride.InitMiniRidePersistence(log.Named("mini-ride-persistence"), &db),
 		RolePersistence:             role.InitRolePersistence(log.Named("role-persistence"), &db),
 		IdentityProviderPersistence: identity_provider.InitIdentityProviderPersistence(log.Named("identity-provider-persistence"), &db),
+		SigningKeyPersistence:       signing_key.InitSigningKeyPersistence(log.Named("signing-key-persistence"), &db),
 	}
 }
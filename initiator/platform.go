@@ -48,6 +48,7 @@ func InitPlatformLayer(logger logger.Logger, privateKeyPath, publicKeyPath strin
 			},
 			logger.Named("sms-platform")),
 		Token: token.JwtInit(logger.Named("token-platform"),
+			oidcIssuer(logger),
 			privateKey(privateKeyPath),
 			publicKey(publicKeyPath),
 		),
@@ -68,6 +69,7 @@ func InitMockPlatformLayer(logger logger.Logger, privateKeyPath, publicKeyPath s
 			platform.SMSConfig{},
 			logger.Named("sms-platform")),
 		Token: token.JwtInit(logger.Named("token-platform"),
+			oidcIssuer(logger),
 			privateKey(privateKeyPath),
 			publicKey(publicKeyPath),
 		),
@@ -8,6 +8,7 @@ import (
 	resource_server "sso/internal/glue/routing/resource-server"
 	"sso/internal/glue/routing/role"
 	rs_api "sso/internal/glue/routing/rs-api"
+	signing_key "sso/internal/glue/routing/signing-key"
 
 	"sso/docs"
 
@@ -31,6 +32,7 @@ func InitRouter(router *gin.Engine, group *gin.RouterGroup, handler Handler, mod
 	authMiddleware := middleware.InitAuthMiddleware(
 		enforcer,
 		module.OAuthModule,
+		module.OAuth2Module,
 		platformLayer.Token,
 		module.clientModule,
 		middleware.MiniRideCredential{
@@ -45,6 +47,7 @@ func InitRouter(router *gin.Engine, group *gin.RouterGroup, handler Handler, mod
 
 	oauth.InitRoute(group, handler.oauth, authMiddleware, enforcer)
 	oauth2.InitRoute(group, handler.oauth2, authMiddleware, enforcer)
+	oauth2.InitWellKnownRoute(router, handler.oauth2, enforcer)
 	user.InitRoute(group, handler.user, authMiddleware, enforcer)
 	client.InitRoute(group, handler.client, authMiddleware, enforcer)
 	scope.InitRoute(group, handler.scope, authMiddleware, enforcer)
@@ -55,4 +58,5 @@ func InitRouter(router *gin.Engine, group *gin.RouterGroup, handler Handler, mod
 	identity_provider.InitRoute(group, handler.identityProvider, authMiddleware, enforcer)
 	rs_api.InitRoute(group, handler.rsAPI, authMiddleware, enforcer)
 	asset.InitRoute(group, handler.asset, authMiddleware, enforcer)
+	signing_key.InitRoute(group, handler.signingKey, authMiddleware, enforcer)
 }
@@ -0,0 +1,32 @@
+package initiator
+
+import (
+	"context"
+	"time"
+
+	"sso/internal/module"
+	"sso/platform/logger"
+
+	"go.uber.org/zap"
+)
+
+// InitSigningKeys loads the key ring and keeps reloading it,
+// so that keys rotated or retired through another instance are picked up.
+func InitSigningKeys(signingKeyModule module.SigningKeyModule, refreshInterval time.Duration, log logger.Logger) {
+	if err := signingKeyModule.LoadSigningKeys(context.Background()); err != nil {
+		log.Fatal(context.Background(), "failed to load signing keys", zap.Error(err))
+	}
+
+	if refreshInterval == 0 {
+		refreshInterval = time.Minute
+	}
+	go func() {
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := signingKeyModule.LoadSigningKeys(context.Background()); err != nil {
+				log.Warn(context.Background(), "failed to refresh signing keys", zap.Error(err))
+			}
+		}
+	}()
+}
@@ -56,6 +56,22 @@ func InitState(logger logger.Logger) State {
 		logger.Fatal(context.Background(), "unable to parse frontend.logout_url")
 	}
 
+	deviceURLString := viper.GetString("frontend.device_url")
+	if deviceURLString == "" {
+		deviceURLString = consentURLString
+		logger.Warn(context.Background(), "unable to read frontend.device_url in viper, using frontend.consent_url",
+			zap.String("device-url", deviceURLString))
+	}
+	deviceURL, err := url.Parse(deviceURLString)
+	if err != nil {
+		logger.Fatal(context.Background(), "unable to parse frontend.device_url")
+	}
+
+	issuerURL, err := url.Parse(oidcIssuer(logger))
+	if err != nil {
+		logger.Fatal(context.Background(), "unable to parse oidc.issuer")
+	}
+
 	phones := viper.GetStringSlice("excluded_phones.phones")
 	defaultOTP := viper.GetString("excluded_phones.default_otp")
 	sendSMS := viper.GetBool("excluded_phones.send_sms")
@@ -79,6 +95,8 @@ func InitState(logger logger.Logger) State {
 			ErrorURL:   errorURL,
 			ConsentURL: consentURL,
 			LogoutURL:  logoutURL,
+			IssuerURL:  issuerURL,
+			DeviceURL:  deviceURL,
 		},
 		UploadParams: asset.SetParams(logger, state.UploadParams{
 			FileTypes: fileTypes,
@@ -90,3 +108,15 @@ func InitState(logger logger.Logger) State {
 		},
 	}
 }
+
+// oidcIssuer returns the issuer identifier of the sso,
+// it is used both as the iss of the tokens and in the discovery document.
+func oidcIssuer(logger logger.Logger) string {
+	issuer := viper.GetString("oidc.issuer")
+	if issuer == "" {
+		issuer = "http://localhost:" + viper.GetString("server.port")
+		logger.Warn(context.Background(), "unable to read oidc.issuer in viper, using default issuer",
+			zap.String("issuer", issuer))
+	}
+	return issuer
+}
@@ -27,6 +27,98 @@ const (
 	AuthorizationCode = "authorization_code"
 	RefreshToken      = "refresh_token"
 	ClientCredentials = "client_credentials"
+	DeviceCode        = "urn:ietf:params:oauth:grant-type:device_code"
+	TokenExchange     = "urn:ietf:params:oauth:grant-type:token-exchange"
+)
+
+const (
+	DeviceAuthorizationPending  = "pending"
+	DeviceAuthorizationApproved = "approved"
+	DeviceAuthorizationDenied   = "denied"
+)
+
+const (
+	ConfidentialClient = "confidential"
+	PublicClient       = "public"
+)
+
+const (
+	ResponseTypeCode        = "code"
+	ResponseTypeIDToken     = "id_token"
+	ResponseTypeToken       = "token"
+	ResponseTypeCodeIDToken = "code id_token"
+	ResponseTypeCodeToken   = "code token"
+)
+
+const (
+	ResponseModeQuery    = "query"
+	ResponseModeFragment = "fragment"
+)
+
+const (
+	CodeChallengeS256  = "S256"
+	CodeChallengePlain = "plain"
+)
+
+// authentication method references of RFC 8176, fed is used for logins through an identity provider.
+const (
+	AMRPassword  = "pwd"
+	AMROTP       = "otp"
+	AMRFederated = "fed"
+)
+
+const (
+	ACRSingleFactor = "urn:sso:acr:sfa"
+	ACRFederated    = "urn:sso:acr:federated"
+)
+
+// signing algorithms the key ring can hold keys for.
+// PS512 is the default, the session and access tokens of the sso are always signed with it.
+const (
+	SigningAlgorithmPS512 = "PS512"
+	SigningAlgorithmRS256 = "RS256"
+	SigningAlgorithmES256 = "ES256"
+	SigningAlgorithmEdDSA = "EdDSA"
+)
+
+// token types set as the typ header of the tokens the sso issues.
+const (
+	SessionTokenType = "session+jwt"
+	AccessTokenType  = "at+jwt"
+	IDTokenType      = "JWT"
+	LogoutTokenType  = "logout+jwt"
+)
+
+// BackChannelLogoutEvent is the event a logout token carries.
+const BackChannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// statuses of back-channel logout deliveries.
+const (
+	LogoutDelivered = "DELIVERED"
+	LogoutFailed    = "FAILED"
+)
+
+// AccessTokenTypeURI identifies access tokens on the token exchange grant.
+const AccessTokenTypeURI = "urn:ietf:params:oauth:token-type:access_token"
+
+const (
+	AccessTokenHint  = "access_token"
+	RefreshTokenHint = "refresh_token"
+)
+
+const (
+	ClientSecretBasic = "client_secret_basic"
+	PrivateKeyJWT     = "private_key_jwt"
+	TLSClientAuth     = "tls_client_auth"
+	NoneAuthMethod    = "none"
+)
+
+const (
+	ClientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+const (
+	RequestURIPrefix = "urn:ietf:params:oauth:request_uri:"
 )
 
 const (
@@ -38,4 +130,23 @@ const (
 	PromptConsent  = "consent"
 	PromptEmail    = "email"
 	PromptRegister = "register"
+	PromptLogin    = "login"
+)
+
+const (
+	APIBasePath                 = "/v1"
+	OAuth2BasePath              = "/oauth"
+	WellKnownBasePath           = "/.well-known"
+	AuthorizeEndpoint           = "/authorize"
+	TokenEndpoint               = "/token"
+	UserInfoEndpoint            = "/userinfo"
+	LogoutEndpoint              = "/logout"
+	JWKSEndpoint                = "/jwks"
+	DeviceAuthorizationEndpoint = "/device_authorization"
+	IntrospectionEndpoint       = "/introspect"
+	RevocationEndpoint          = "/revoke"
+	RegistrationEndpoint        = "/register"
+	PushedAuthorizationEndpoint = "/par"
+	CheckSessionEndpoint        = "/check_session"
+	OpenIDConfigurationEndpoint = "/openid-configuration"
 )
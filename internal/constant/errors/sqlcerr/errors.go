@@ -1,6 +1,13 @@
 package sqlcerr
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/jackc/pgconn"
+)
+
+// uniqueViolation is the postgres error code of an insert or update conflicting with a unique constraint.
+const uniqueViolation = "23505"
 
 var (
 	ErrNoRows = errors.New("no rows in result set")
@@ -9,3 +16,9 @@ var (
 func Is(err, target error) bool {
 	return err.Error() == target.Error() // FIXME: a better way to do this?
 }
+
+// IsUniqueViolation tells if the error is a conflict with a unique constraint.
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
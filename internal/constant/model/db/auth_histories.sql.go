@@ -28,7 +28,7 @@ RETURNING id, code, user_id, scope, status, redirect_uri, client_id, created_at
 
 type CreateAuthHistoryParams struct {
 	Code        string         `json:"code"`
-	UserID      uuid.UUID      `json:"user_id"`
+	UserID      uuid.NullUUID  `json:"user_id"`
 	Scope       sql.NullString `json:"scope"`
 	RedirectUri sql.NullString `json:"redirect_uri"`
 	ClientID    uuid.UUID      `json:"client_id"`
@@ -83,8 +83,8 @@ SELECT id, code, user_id, scope, status, redirect_uri, client_id, created_at FRO
 `
 
 type GetLastAuthHistoryParams struct {
-	UserID   uuid.UUID `json:"user_id"`
-	ClientID uuid.UUID `json:"client_id"`
+	UserID   uuid.NullUUID `json:"user_id"`
+	ClientID uuid.UUID     `json:"client_id"`
 }
 
 func (q *Queries) GetLastAuthHistory(ctx context.Context, arg GetLastAuthHistoryParams) (AuthHistory, error) {
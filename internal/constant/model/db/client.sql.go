@@ -19,19 +19,53 @@ INSERT INTO clients (
     redirect_uris,
     scopes,
     secret,
-    logo_url
+    logo_url,
+    require_pkce,
+    response_types,
+    id_token_signed_response_alg,
+    grant_types,
+    token_endpoint_auth_method,
+    jwks,
+    tls_client_certificate_thumbprint,
+    require_pushed_authorization_requests,
+    backchannel_logout_uri,
+    frontchannel_logout_uri,
+    access_token_lifetime,
+    refresh_token_lifetime,
+    refresh_token_idle_lifetime,
+    refresh_token_absolute_lifetime,
+    id_token_lifetime,
+    auth_code_lifetime,
+    optional_scopes
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
-) RETURNING id, name, client_type, redirect_uris, scopes, secret, logo_url, status, created_at, first_party
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23
+) RETURNING id, name, client_type, redirect_uris, scopes, secret, logo_url, status, created_at, first_party, require_pkce, response_types, id_token_signed_response_alg, grant_types, previous_secret, previous_secret_expires_at, token_endpoint_auth_method, jwks, tls_client_certificate_thumbprint, require_pushed_authorization_requests, backchannel_logout_uri, frontchannel_logout_uri, access_token_lifetime, refresh_token_lifetime, refresh_token_idle_lifetime, refresh_token_absolute_lifetime, id_token_lifetime, auth_code_lifetime, optional_scopes
 `
 
 type CreateClientParams struct {
-	Name         string `json:"name"`
-	ClientType   string `json:"client_type"`
-	RedirectUris string `json:"redirect_uris"`
-	Scopes       string `json:"scopes"`
-	Secret       string `json:"secret"`
-	LogoUrl      string `json:"logo_url"`
+	Name                               string `json:"name"`
+	ClientType                         string `json:"client_type"`
+	RedirectUris                       string `json:"redirect_uris"`
+	Scopes                             string `json:"scopes"`
+	Secret                             string `json:"secret"`
+	LogoUrl                            string `json:"logo_url"`
+	RequirePkce                        bool   `json:"require_pkce"`
+	ResponseTypes                      string `json:"response_types"`
+	IDTokenSignedResponseAlg           string `json:"id_token_signed_response_alg"`
+	GrantTypes                         string `json:"grant_types"`
+	TokenEndpointAuthMethod            string `json:"token_endpoint_auth_method"`
+	Jwks                               string `json:"jwks"`
+	TlsClientCertificateThumbprint     string `json:"tls_client_certificate_thumbprint"`
+	RequirePushedAuthorizationRequests bool   `json:"require_pushed_authorization_requests"`
+	BackchannelLogoutUri               string `json:"backchannel_logout_uri"`
+	FrontchannelLogoutUri              string `json:"frontchannel_logout_uri"`
+	AccessTokenLifetime                int32  `json:"access_token_lifetime"`
+	RefreshTokenLifetime               int32  `json:"refresh_token_lifetime"`
+	RefreshTokenIdleLifetime           int32  `json:"refresh_token_idle_lifetime"`
+	RefreshTokenAbsoluteLifetime       int32  `json:"refresh_token_absolute_lifetime"`
+	IDTokenLifetime                    int32  `json:"id_token_lifetime"`
+	AuthCodeLifetime                   int32  `json:"auth_code_lifetime"`
+	OptionalScopes                     string `json:"optional_scopes"`
 }
 
 func (q *Queries) CreateClient(ctx context.Context, arg CreateClientParams) (Client, error) {
@@ -42,6 +76,23 @@ func (q *Queries) CreateClient(ctx context.Context, arg CreateClientParams) (Cli
 		arg.Scopes,
 		arg.Secret,
 		arg.LogoUrl,
+		arg.RequirePkce,
+		arg.ResponseTypes,
+		arg.IDTokenSignedResponseAlg,
+		arg.GrantTypes,
+		arg.TokenEndpointAuthMethod,
+		arg.Jwks,
+		arg.TlsClientCertificateThumbprint,
+		arg.RequirePushedAuthorizationRequests,
+		arg.BackchannelLogoutUri,
+		arg.FrontchannelLogoutUri,
+		arg.AccessTokenLifetime,
+		arg.RefreshTokenLifetime,
+		arg.RefreshTokenIdleLifetime,
+		arg.RefreshTokenAbsoluteLifetime,
+		arg.IDTokenLifetime,
+		arg.AuthCodeLifetime,
+		arg.OptionalScopes,
 	)
 	var i Client
 	err := row.Scan(
@@ -55,12 +106,31 @@ func (q *Queries) CreateClient(ctx context.Context, arg CreateClientParams) (Cli
 		&i.Status,
 		&i.CreatedAt,
 		&i.FirstParty,
+		&i.RequirePkce,
+		&i.ResponseTypes,
+		&i.IDTokenSignedResponseAlg,
+		&i.GrantTypes,
+		&i.PreviousSecret,
+		&i.PreviousSecretExpiresAt,
+		&i.TokenEndpointAuthMethod,
+		&i.Jwks,
+		&i.TlsClientCertificateThumbprint,
+		&i.RequirePushedAuthorizationRequests,
+		&i.BackchannelLogoutUri,
+		&i.FrontchannelLogoutUri,
+		&i.AccessTokenLifetime,
+		&i.RefreshTokenLifetime,
+		&i.RefreshTokenIdleLifetime,
+		&i.RefreshTokenAbsoluteLifetime,
+		&i.IDTokenLifetime,
+		&i.AuthCodeLifetime,
+		&i.OptionalScopes,
 	)
 	return i, err
 }
 
 const deleteClient = `-- name: DeleteClient :one
-DELETE FROM clients WHERE id = $1 RETURNING id, name, client_type, redirect_uris, scopes, secret, logo_url, status, created_at, first_party
+DELETE FROM clients WHERE id = $1 RETURNING id, name, client_type, redirect_uris, scopes, secret, logo_url, status, created_at, first_party, require_pkce, response_types, id_token_signed_response_alg, grant_types, previous_secret, previous_secret_expires_at, token_endpoint_auth_method, jwks, tls_client_certificate_thumbprint, require_pushed_authorization_requests, backchannel_logout_uri, frontchannel_logout_uri, access_token_lifetime, refresh_token_lifetime, refresh_token_idle_lifetime, refresh_token_absolute_lifetime, id_token_lifetime, auth_code_lifetime, optional_scopes
 `
 
 func (q *Queries) DeleteClient(ctx context.Context, id uuid.UUID) (Client, error) {
@@ -77,12 +147,31 @@ func (q *Queries) DeleteClient(ctx context.Context, id uuid.UUID) (Client, error
 		&i.Status,
 		&i.CreatedAt,
 		&i.FirstParty,
+		&i.RequirePkce,
+		&i.ResponseTypes,
+		&i.IDTokenSignedResponseAlg,
+		&i.GrantTypes,
+		&i.PreviousSecret,
+		&i.PreviousSecretExpiresAt,
+		&i.TokenEndpointAuthMethod,
+		&i.Jwks,
+		&i.TlsClientCertificateThumbprint,
+		&i.RequirePushedAuthorizationRequests,
+		&i.BackchannelLogoutUri,
+		&i.FrontchannelLogoutUri,
+		&i.AccessTokenLifetime,
+		&i.RefreshTokenLifetime,
+		&i.RefreshTokenIdleLifetime,
+		&i.RefreshTokenAbsoluteLifetime,
+		&i.IDTokenLifetime,
+		&i.AuthCodeLifetime,
+		&i.OptionalScopes,
 	)
 	return i, err
 }
 
 const getClientByID = `-- name: GetClientByID :one
-SELECT id, name, client_type, redirect_uris, scopes, secret, logo_url, status, created_at, first_party FROM clients WHERE id = $1
+SELECT id, name, client_type, redirect_uris, scopes, secret, logo_url, status, created_at, first_party, require_pkce, response_types, id_token_signed_response_alg, grant_types, previous_secret, previous_secret_expires_at, token_endpoint_auth_method, jwks, tls_client_certificate_thumbprint, require_pushed_authorization_requests, backchannel_logout_uri, frontchannel_logout_uri, access_token_lifetime, refresh_token_lifetime, refresh_token_idle_lifetime, refresh_token_absolute_lifetime, id_token_lifetime, auth_code_lifetime, optional_scopes FROM clients WHERE id = $1
 `
 
 func (q *Queries) GetClientByID(ctx context.Context, id uuid.UUID) (Client, error) {
@@ -99,10 +188,52 @@ func (q *Queries) GetClientByID(ctx context.Context, id uuid.UUID) (Client, erro
 		&i.Status,
 		&i.CreatedAt,
 		&i.FirstParty,
+		&i.RequirePkce,
+		&i.ResponseTypes,
+		&i.IDTokenSignedResponseAlg,
+		&i.GrantTypes,
+		&i.PreviousSecret,
+		&i.PreviousSecretExpiresAt,
+		&i.TokenEndpointAuthMethod,
+		&i.Jwks,
+		&i.TlsClientCertificateThumbprint,
+		&i.RequirePushedAuthorizationRequests,
+		&i.BackchannelLogoutUri,
+		&i.FrontchannelLogoutUri,
+		&i.AccessTokenLifetime,
+		&i.RefreshTokenLifetime,
+		&i.RefreshTokenIdleLifetime,
+		&i.RefreshTokenAbsoluteLifetime,
+		&i.IDTokenLifetime,
+		&i.AuthCodeLifetime,
+		&i.OptionalScopes,
 	)
 	return i, err
 }
 
+const rotateClientSecret = `-- name: RotateClientSecret :one
+UPDATE clients
+SET
+ previous_secret = secret,
+ previous_secret_expires_at = $2,
+ secret = $3
+WHERE id = $1
+RETURNING id
+`
+
+type RotateClientSecretParams struct {
+	ID                      uuid.UUID    `json:"id"`
+	PreviousSecretExpiresAt sql.NullTime `json:"previous_secret_expires_at"`
+	Secret                  string       `json:"secret"`
+}
+
+func (q *Queries) RotateClientSecret(ctx context.Context, arg RotateClientSecretParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, rotateClientSecret, arg.ID, arg.PreviousSecretExpiresAt, arg.Secret)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
 const updateClient = `-- name: UpdateClient :one
 UPDATE clients
 SET
@@ -112,20 +243,28 @@ SET
  scopes = coalesce($4, scopes),
  secret = coalesce($5, secret),
  logo_url = coalesce($6, logo_url),
- status = coalesce($7, status)
-WHERE id = $8
-RETURNING id, name, client_type, redirect_uris, scopes, secret, logo_url, status, created_at, first_party
+ status = coalesce($7, status),
+ require_pkce = coalesce($8, require_pkce),
+ response_types = coalesce($9, response_types),
+ id_token_signed_response_alg = coalesce($10, id_token_signed_response_alg),
+ grant_types = coalesce($11, grant_types)
+WHERE id = $12
+RETURNING id, name, client_type, redirect_uris, scopes, secret, logo_url, status, created_at, first_party, require_pkce, response_types, id_token_signed_response_alg, grant_types, previous_secret, previous_secret_expires_at, token_endpoint_auth_method, jwks, tls_client_certificate_thumbprint, require_pushed_authorization_requests, backchannel_logout_uri, frontchannel_logout_uri, access_token_lifetime, refresh_token_lifetime, refresh_token_idle_lifetime, refresh_token_absolute_lifetime, id_token_lifetime, auth_code_lifetime, optional_scopes
 `
 
 type UpdateClientParams struct {
-	Name         sql.NullString `json:"name"`
-	ClientType   sql.NullString `json:"client_type"`
-	RedirectUris sql.NullString `json:"redirect_uris"`
-	Scopes       sql.NullString `json:"scopes"`
-	Secret       sql.NullString `json:"secret"`
-	LogoUrl      sql.NullString `json:"logo_url"`
-	Status       sql.NullString `json:"status"`
-	ID           uuid.UUID      `json:"id"`
+	Name                     sql.NullString `json:"name"`
+	ClientType               sql.NullString `json:"client_type"`
+	RedirectUris             sql.NullString `json:"redirect_uris"`
+	Scopes                   sql.NullString `json:"scopes"`
+	Secret                   sql.NullString `json:"secret"`
+	LogoUrl                  sql.NullString `json:"logo_url"`
+	Status                   sql.NullString `json:"status"`
+	RequirePkce              sql.NullBool   `json:"require_pkce"`
+	ResponseTypes            sql.NullString `json:"response_types"`
+	IDTokenSignedResponseAlg sql.NullString `json:"id_token_signed_response_alg"`
+	GrantTypes               sql.NullString `json:"grant_types"`
+	ID                       uuid.UUID      `json:"id"`
 }
 
 func (q *Queries) UpdateClient(ctx context.Context, arg UpdateClientParams) (Client, error) {
@@ -137,6 +276,10 @@ func (q *Queries) UpdateClient(ctx context.Context, arg UpdateClientParams) (Cli
 		arg.Secret,
 		arg.LogoUrl,
 		arg.Status,
+		arg.RequirePkce,
+		arg.ResponseTypes,
+		arg.IDTokenSignedResponseAlg,
+		arg.GrantTypes,
 		arg.ID,
 	)
 	var i Client
@@ -151,6 +294,25 @@ func (q *Queries) UpdateClient(ctx context.Context, arg UpdateClientParams) (Cli
 		&i.Status,
 		&i.CreatedAt,
 		&i.FirstParty,
+		&i.RequirePkce,
+		&i.ResponseTypes,
+		&i.IDTokenSignedResponseAlg,
+		&i.GrantTypes,
+		&i.PreviousSecret,
+		&i.PreviousSecretExpiresAt,
+		&i.TokenEndpointAuthMethod,
+		&i.Jwks,
+		&i.TlsClientCertificateThumbprint,
+		&i.RequirePushedAuthorizationRequests,
+		&i.BackchannelLogoutUri,
+		&i.FrontchannelLogoutUri,
+		&i.AccessTokenLifetime,
+		&i.RefreshTokenLifetime,
+		&i.RefreshTokenIdleLifetime,
+		&i.RefreshTokenAbsoluteLifetime,
+		&i.IDTokenLifetime,
+		&i.AuthCodeLifetime,
+		&i.OptionalScopes,
 	)
 	return i, err
 }
@@ -162,18 +324,52 @@ SET
  client_type = $3,
  redirect_uris = $4,
  scopes = $5,
- logo_url = $6
+ logo_url = $6,
+ require_pkce = $7,
+ response_types = $8,
+ id_token_signed_response_alg = $9,
+ grant_types = $10,
+ token_endpoint_auth_method = $11,
+ jwks = $12,
+ tls_client_certificate_thumbprint = $13,
+ require_pushed_authorization_requests = $14,
+ backchannel_logout_uri = $15,
+ frontchannel_logout_uri = $16,
+ access_token_lifetime = $17,
+ refresh_token_lifetime = $18,
+ refresh_token_idle_lifetime = $19,
+ refresh_token_absolute_lifetime = $20,
+ id_token_lifetime = $21,
+ auth_code_lifetime = $22,
+ optional_scopes = $23
 WHERE id = $1
-RETURNING id, name, client_type, redirect_uris, scopes, secret, logo_url, status, created_at, first_party
+RETURNING id, name, client_type, redirect_uris, scopes, secret, logo_url, status, created_at, first_party, require_pkce, response_types, id_token_signed_response_alg, grant_types, previous_secret, previous_secret_expires_at, token_endpoint_auth_method, jwks, tls_client_certificate_thumbprint, require_pushed_authorization_requests, backchannel_logout_uri, frontchannel_logout_uri, access_token_lifetime, refresh_token_lifetime, refresh_token_idle_lifetime, refresh_token_absolute_lifetime, id_token_lifetime, auth_code_lifetime, optional_scopes
 `
 
 type UpdateEntireClientParams struct {
-	ID           uuid.UUID `json:"id"`
-	Name         string    `json:"name"`
-	ClientType   string    `json:"client_type"`
-	RedirectUris string    `json:"redirect_uris"`
-	Scopes       string    `json:"scopes"`
-	LogoUrl      string    `json:"logo_url"`
+	ID                                 uuid.UUID `json:"id"`
+	Name                               string    `json:"name"`
+	ClientType                         string    `json:"client_type"`
+	RedirectUris                       string    `json:"redirect_uris"`
+	Scopes                             string    `json:"scopes"`
+	LogoUrl                            string    `json:"logo_url"`
+	RequirePkce                        bool      `json:"require_pkce"`
+	ResponseTypes                      string    `json:"response_types"`
+	IDTokenSignedResponseAlg           string    `json:"id_token_signed_response_alg"`
+	GrantTypes                         string    `json:"grant_types"`
+	TokenEndpointAuthMethod            string    `json:"token_endpoint_auth_method"`
+	Jwks                               string    `json:"jwks"`
+	TlsClientCertificateThumbprint     string    `json:"tls_client_certificate_thumbprint"`
+	RequirePushedAuthorizationRequests bool      `json:"require_pushed_authorization_requests"`
+	BackchannelLogoutUri               string    `json:"backchannel_logout_uri"`
+	FrontchannelLogoutUri              string    `json:"frontchannel_logout_uri"`
+	AccessTokenLifetime                int32     `json:"access_token_lifetime"`
+	RefreshTokenLifetime               int32     `json:"refresh_token_lifetime"`
+	RefreshTokenIdleLifetime           int32     `json:"refresh_token_idle_lifetime"`
+	RefreshTokenAbsoluteLifetime       int32     `json:"refresh_token_absolute_lifetime"`
+	IDTokenLifetime                    int32     `json:"id_token_lifetime"`
+	AuthCodeLifetime                   int32     `json:"auth_code_lifetime"`
+	OptionalScopes                     string    `json:"optional_scopes"`
 }
 
 func (q *Queries) UpdateEntireClient(ctx context.Context, arg UpdateEntireClientParams) (Client, error) {
@@ -184,6 +380,23 @@ func (q *Queries) UpdateEntireClient(ctx context.Context, arg UpdateEntireClient
 		arg.RedirectUris,
 		arg.Scopes,
 		arg.LogoUrl,
+		arg.RequirePkce,
+		arg.ResponseTypes,
+		arg.IDTokenSignedResponseAlg,
+		arg.GrantTypes,
+		arg.TokenEndpointAuthMethod,
+		arg.Jwks,
+		arg.TlsClientCertificateThumbprint,
+		arg.RequirePushedAuthorizationRequests,
+		arg.BackchannelLogoutUri,
+		arg.FrontchannelLogoutUri,
+		arg.AccessTokenLifetime,
+		arg.RefreshTokenLifetime,
+		arg.RefreshTokenIdleLifetime,
+		arg.RefreshTokenAbsoluteLifetime,
+		arg.IDTokenLifetime,
+		arg.AuthCodeLifetime,
+		arg.OptionalScopes,
 	)
 	var i Client
 	err := row.Scan(
@@ -197,6 +410,25 @@ func (q *Queries) UpdateEntireClient(ctx context.Context, arg UpdateEntireClient
 		&i.Status,
 		&i.CreatedAt,
 		&i.FirstParty,
+		&i.RequirePkce,
+		&i.ResponseTypes,
+		&i.IDTokenSignedResponseAlg,
+		&i.GrantTypes,
+		&i.PreviousSecret,
+		&i.PreviousSecretExpiresAt,
+		&i.TokenEndpointAuthMethod,
+		&i.Jwks,
+		&i.TlsClientCertificateThumbprint,
+		&i.RequirePushedAuthorizationRequests,
+		&i.BackchannelLogoutUri,
+		&i.FrontchannelLogoutUri,
+		&i.AccessTokenLifetime,
+		&i.RefreshTokenLifetime,
+		&i.RefreshTokenIdleLifetime,
+		&i.RefreshTokenAbsoluteLifetime,
+		&i.IDTokenLifetime,
+		&i.AuthCodeLifetime,
+		&i.OptionalScopes,
 	)
 	return i, err
 }
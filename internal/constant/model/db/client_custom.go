@@ -19,6 +19,25 @@ func (q *Queries) GetAllClients(ctx context.Context, pgnFlt db_pgnflt.FilterPara
 		"status",
 		"created_at",
 		"first_party",
+		"require_pkce",
+		"response_types",
+		"id_token_signed_response_alg",
+		"grant_types",
+		"previous_secret",
+		"previous_secret_expires_at",
+		"token_endpoint_auth_method",
+		"jwks",
+		"tls_client_certificate_thumbprint",
+		"require_pushed_authorization_requests",
+		"backchannel_logout_uri",
+		"frontchannel_logout_uri",
+		"access_token_lifetime",
+		"refresh_token_lifetime",
+		"refresh_token_idle_lifetime",
+		"refresh_token_absolute_lifetime",
+		"id_token_lifetime",
+		"auth_code_lifetime",
+		"optional_scopes",
 	}, "clients", sql))
 	if err != nil {
 		return nil, 0, err
@@ -38,6 +57,25 @@ func (q *Queries) GetAllClients(ctx context.Context, pgnFlt db_pgnflt.FilterPara
 			&i.Status,
 			&i.CreatedAt,
 			&i.FirstParty,
+			&i.RequirePkce,
+			&i.ResponseTypes,
+			&i.IDTokenSignedResponseAlg,
+			&i.GrantTypes,
+			&i.PreviousSecret,
+			&i.PreviousSecretExpiresAt,
+			&i.TokenEndpointAuthMethod,
+			&i.Jwks,
+			&i.TlsClientCertificateThumbprint,
+			&i.RequirePushedAuthorizationRequests,
+			&i.BackchannelLogoutUri,
+			&i.FrontchannelLogoutUri,
+			&i.AccessTokenLifetime,
+			&i.RefreshTokenLifetime,
+			&i.RefreshTokenIdleLifetime,
+			&i.RefreshTokenAbsoluteLifetime,
+			&i.IDTokenLifetime,
+			&i.AuthCodeLifetime,
+			&i.OptionalScopes,
 			&totalCount); err != nil {
 			return nil, 0, err
 		}
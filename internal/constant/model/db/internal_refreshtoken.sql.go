@@ -13,7 +13,7 @@ import (
 )
 
 const getInternalRefreshToken = `-- name: GetInternalRefreshToken :one
-SELECT id, refresh_token, user_id, ip_address, user_agent, expires_at, created_at, updated_at FROM internalrefreshtokens WHERE refresh_token = $1
+SELECT id, refresh_token, user_id, ip_address, user_agent, expires_at, created_at, updated_at, auth_time, amr FROM internalrefreshtokens WHERE refresh_token = $1
 `
 
 func (q *Queries) GetInternalRefreshToken(ctx context.Context, refreshToken string) (Internalrefreshtoken, error) {
@@ -28,12 +28,38 @@ func (q *Queries) GetInternalRefreshToken(ctx context.Context, refreshToken stri
 		&i.ExpiresAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AuthTime,
+		&i.Amr,
+	)
+	return i, err
+}
+
+const getInternalRefreshTokenBySupersededToken = `-- name: GetInternalRefreshTokenBySupersededToken :one
+SELECT internalrefreshtokens.id, internalrefreshtokens.refresh_token, internalrefreshtokens.user_id, internalrefreshtokens.ip_address, internalrefreshtokens.user_agent, internalrefreshtokens.expires_at, internalrefreshtokens.created_at, internalrefreshtokens.updated_at, internalrefreshtokens.auth_time, internalrefreshtokens.amr FROM superseded_internalrefreshtokens
+JOIN internalrefreshtokens ON superseded_internalrefreshtokens.family_id = internalrefreshtokens.id
+WHERE superseded_internalrefreshtokens.refresh_token = $1
+`
+
+func (q *Queries) GetInternalRefreshTokenBySupersededToken(ctx context.Context, refreshToken string) (Internalrefreshtoken, error) {
+	row := q.db.QueryRow(ctx, getInternalRefreshTokenBySupersededToken, refreshToken)
+	var i Internalrefreshtoken
+	err := row.Scan(
+		&i.ID,
+		&i.RefreshToken,
+		&i.UserID,
+		&i.IpAddress,
+		&i.UserAgent,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AuthTime,
+		&i.Amr,
 	)
 	return i, err
 }
 
 const getInternalRefreshTokensByUserID = `-- name: GetInternalRefreshTokensByUserID :many
-SELECT id, refresh_token, user_id, ip_address, user_agent, expires_at, created_at, updated_at FROM internalrefreshtokens WHERE user_id = $1
+SELECT id, refresh_token, user_id, ip_address, user_agent, expires_at, created_at, updated_at, auth_time, amr FROM internalrefreshtokens WHERE user_id = $1
 `
 
 func (q *Queries) GetInternalRefreshTokensByUserID(ctx context.Context, userID uuid.UUID) ([]Internalrefreshtoken, error) {
@@ -54,6 +80,8 @@ func (q *Queries) GetInternalRefreshTokensByUserID(ctx context.Context, userID u
 			&i.ExpiresAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AuthTime,
+			&i.Amr,
 		); err != nil {
 			return nil, err
 		}
@@ -89,11 +117,13 @@ INSERT INTO internalrefreshtokens (
     user_id,
     refresh_token,
     ip_address,
-    user_agent
+    user_agent,
+    auth_time,
+    amr
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6, $7
 )
-RETURNING id, refresh_token, user_id, ip_address, user_agent, expires_at, created_at, updated_at
+RETURNING id, refresh_token, user_id, ip_address, user_agent, expires_at, created_at, updated_at, auth_time, amr
 `
 
 type SaveInternalRefreshTokenParams struct {
@@ -102,6 +132,8 @@ type SaveInternalRefreshTokenParams struct {
 	RefreshToken string    `json:"refresh_token"`
 	IpAddress    string    `json:"ip_address"`
 	UserAgent    string    `json:"user_agent"`
+	AuthTime     time.Time `json:"auth_time"`
+	Amr          string    `json:"amr"`
 }
 
 func (q *Queries) SaveInternalRefreshToken(ctx context.Context, arg SaveInternalRefreshTokenParams) (Internalrefreshtoken, error) {
@@ -111,6 +143,8 @@ func (q *Queries) SaveInternalRefreshToken(ctx context.Context, arg SaveInternal
 		arg.RefreshToken,
 		arg.IpAddress,
 		arg.UserAgent,
+		arg.AuthTime,
+		arg.Amr,
 	)
 	var i Internalrefreshtoken
 	err := row.Scan(
@@ -122,12 +156,28 @@ func (q *Queries) SaveInternalRefreshToken(ctx context.Context, arg SaveInternal
 		&i.ExpiresAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AuthTime,
+		&i.Amr,
 	)
 	return i, err
 }
 
+const saveSupersededInternalRefreshToken = `-- name: SaveSupersededInternalRefreshToken :exec
+INSERT INTO superseded_internalrefreshtokens (refresh_token, family_id) VALUES ($1, $2)
+`
+
+type SaveSupersededInternalRefreshTokenParams struct {
+	RefreshToken string    `json:"refresh_token"`
+	FamilyID     uuid.UUID `json:"family_id"`
+}
+
+func (q *Queries) SaveSupersededInternalRefreshToken(ctx context.Context, arg SaveSupersededInternalRefreshTokenParams) error {
+	_, err := q.db.Exec(ctx, saveSupersededInternalRefreshToken, arg.RefreshToken, arg.FamilyID)
+	return err
+}
+
 const updateInternalRefreshToken = `-- name: UpdateInternalRefreshToken :one
-UPDATE internalrefreshtokens SET refresh_token=$2, updated_at=now() WHERE refresh_token=$1 RETURNING id, refresh_token, user_id, ip_address, user_agent, expires_at, created_at, updated_at
+UPDATE internalrefreshtokens SET refresh_token=$2, updated_at=now() WHERE refresh_token=$1 RETURNING id, refresh_token, user_id, ip_address, user_agent, expires_at, created_at, updated_at, auth_time, amr
 `
 
 type UpdateInternalRefreshTokenParams struct {
@@ -147,12 +197,14 @@ func (q *Queries) UpdateInternalRefreshToken(ctx context.Context, arg UpdateInte
 		&i.ExpiresAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AuthTime,
+		&i.Amr,
 	)
 	return i, err
 }
 
 const updateRefreshToken = `-- name: UpdateRefreshToken :one
-Update internalrefreshtokens set expires_at = $2, refresh_token= $3 WHERE id= $1 RETURNING id, refresh_token, user_id, ip_address, user_agent, expires_at, created_at, updated_at
+Update internalrefreshtokens set expires_at = $2, refresh_token= $3 WHERE id= $1 RETURNING id, refresh_token, user_id, ip_address, user_agent, expires_at, created_at, updated_at, auth_time, amr
 `
 
 type UpdateRefreshTokenParams struct {
@@ -173,6 +225,8 @@ func (q *Queries) UpdateRefreshToken(ctx context.Context, arg UpdateRefreshToken
 		&i.ExpiresAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AuthTime,
+		&i.Amr,
 	)
 	return i, err
 }
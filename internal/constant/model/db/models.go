@@ -14,7 +14,7 @@ import (
 type AuthHistory struct {
 	ID          uuid.UUID      `json:"id"`
 	Code        string         `json:"code"`
-	UserID      uuid.UUID      `json:"user_id"`
+	UserID      uuid.NullUUID  `json:"user_id"`
 	Scope       sql.NullString `json:"scope"`
 	Status      string         `json:"status"`
 	RedirectUri sql.NullString `json:"redirect_uri"`
@@ -22,17 +22,61 @@ type AuthHistory struct {
 	CreatedAt   time.Time      `json:"created_at"`
 }
 
+type AuthorizationDetailType struct {
+	ID               uuid.UUID `json:"id"`
+	Type             string    `json:"type"`
+	Description      string    `json:"description"`
+	ResourceServerID uuid.UUID `json:"resource_server_id"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type BackchannelLogoutDelivery struct {
+	ID        uuid.UUID `json:"id"`
+	ClientID  uuid.UUID `json:"client_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	SessionID string    `json:"session_id"`
+	Status    string    `json:"status"`
+	Attempts  int32     `json:"attempts"`
+	Error     string    `json:"error"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Client struct {
-	ID           uuid.UUID `json:"id"`
-	Name         string    `json:"name"`
-	ClientType   string    `json:"client_type"`
-	RedirectUris string    `json:"redirect_uris"`
-	Scopes       string    `json:"scopes"`
-	Secret       string    `json:"secret"`
-	LogoUrl      string    `json:"logo_url"`
-	Status       string    `json:"status"`
-	CreatedAt    time.Time `json:"created_at"`
-	FirstParty   bool      `json:"first_party"`
+	ID                                 uuid.UUID    `json:"id"`
+	Name                               string       `json:"name"`
+	ClientType                         string       `json:"client_type"`
+	RedirectUris                       string       `json:"redirect_uris"`
+	Scopes                             string       `json:"scopes"`
+	Secret                             string       `json:"secret"`
+	LogoUrl                            string       `json:"logo_url"`
+	Status                             string       `json:"status"`
+	CreatedAt                          time.Time    `json:"created_at"`
+	FirstParty                         bool         `json:"first_party"`
+	RequirePkce                        bool         `json:"require_pkce"`
+	ResponseTypes                      string       `json:"response_types"`
+	IDTokenSignedResponseAlg           string       `json:"id_token_signed_response_alg"`
+	GrantTypes                         string       `json:"grant_types"`
+	PreviousSecret                     string       `json:"previous_secret"`
+	PreviousSecretExpiresAt            sql.NullTime `json:"previous_secret_expires_at"`
+	TokenEndpointAuthMethod            string       `json:"token_endpoint_auth_method"`
+	Jwks                               string       `json:"jwks"`
+	TlsClientCertificateThumbprint     string       `json:"tls_client_certificate_thumbprint"`
+	RequirePushedAuthorizationRequests bool         `json:"require_pushed_authorization_requests"`
+	BackchannelLogoutUri               string       `json:"backchannel_logout_uri"`
+	FrontchannelLogoutUri              string       `json:"frontchannel_logout_uri"`
+	AccessTokenLifetime                int32        `json:"access_token_lifetime"`
+	RefreshTokenLifetime               int32        `json:"refresh_token_lifetime"`
+	RefreshTokenIdleLifetime           int32        `json:"refresh_token_idle_lifetime"`
+	RefreshTokenAbsoluteLifetime       int32        `json:"refresh_token_absolute_lifetime"`
+	IDTokenLifetime                    int32        `json:"id_token_lifetime"`
+	AuthCodeLifetime                   int32        `json:"auth_code_lifetime"`
+	OptionalScopes                     string       `json:"optional_scopes"`
+}
+
+type ClientRegistrationToken struct {
+	ClientID  uuid.UUID `json:"client_id"`
+	TokenHash string    `json:"token_hash"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type IdentityProvider struct {
@@ -50,6 +94,16 @@ type IdentityProvider struct {
 	UpdatedAt           time.Time      `json:"updated_at"`
 }
 
+type InitialAccessToken struct {
+	ID         uuid.UUID `json:"id"`
+	TokenHash  string    `json:"token_hash"`
+	CreatedBy  uuid.UUID `json:"created_by"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	Scopes     string    `json:"scopes"`
+	GrantTypes string    `json:"grant_types"`
+}
+
 type Internalrefreshtoken struct {
 	ID           uuid.UUID `json:"id"`
 	RefreshToken string    `json:"refresh_token"`
@@ -59,6 +113,8 @@ type Internalrefreshtoken struct {
 	ExpiresAt    time.Time `json:"expires_at"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+	AuthTime     time.Time `json:"auth_time"`
+	Amr          string    `json:"amr"`
 }
 
 type IpAccessToken struct {
@@ -78,24 +134,29 @@ type KafkaOffset struct {
 }
 
 type RefreshToken struct {
-	ID           uuid.UUID      `json:"id"`
-	RefreshToken string         `json:"refresh_token"`
-	Code         string         `json:"code"`
-	UserID       uuid.UUID      `json:"user_id"`
-	Scope        sql.NullString `json:"scope"`
-	RedirectUri  sql.NullString `json:"redirect_uri"`
-	ExpiresAt    time.Time      `json:"expires_at"`
-	ClientID     uuid.UUID      `json:"client_id"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
+	ID             uuid.UUID      `json:"id"`
+	RefreshToken   string         `json:"refresh_token"`
+	Code           string         `json:"code"`
+	UserID         uuid.UUID      `json:"user_id"`
+	Scope          sql.NullString `json:"scope"`
+	RedirectUri    sql.NullString `json:"redirect_uri"`
+	ExpiresAt      time.Time      `json:"expires_at"`
+	ClientID       uuid.UUID      `json:"client_id"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	RequestedScope sql.NullString `json:"requested_scope"`
+	AuthTime       sql.NullTime   `json:"auth_time"`
+	Amr            sql.NullString `json:"amr"`
 }
 
 type ResourceServer struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Secret    string    `json:"secret"`
+	ID                      uuid.UUID    `json:"id"`
+	Name                    string       `json:"name"`
+	CreatedAt               time.Time    `json:"created_at"`
+	UpdatedAt               time.Time    `json:"updated_at"`
+	Secret                  string       `json:"secret"`
+	PreviousSecret          string       `json:"previous_secret"`
+	PreviousSecretExpiresAt sql.NullTime `json:"previous_secret_expires_at"`
 }
 
 type Role struct {
@@ -115,6 +176,29 @@ type Scope struct {
 	CreatedAt          time.Time      `json:"created_at"`
 }
 
+type SigningKey struct {
+	ID          uuid.UUID    `json:"id"`
+	Kid         string       `json:"kid"`
+	Algorithm   string       `json:"algorithm"`
+	PrivateKey  string       `json:"private_key"`
+	PublicKey   string       `json:"public_key"`
+	ActivatesAt time.Time    `json:"activates_at"`
+	RetiresAt   sql.NullTime `json:"retires_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+type SupersededInternalrefreshtoken struct {
+	RefreshToken string    `json:"refresh_token"`
+	FamilyID     uuid.UUID `json:"family_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type SupersededRefreshToken struct {
+	RefreshToken string    `json:"refresh_token"`
+	FamilyID     uuid.UUID `json:"family_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
 type User struct {
 	ID             uuid.UUID      `json:"id"`
 	FirstName      string         `json:"first_name"`
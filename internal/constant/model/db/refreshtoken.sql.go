@@ -14,7 +14,8 @@ import (
 )
 
 const getAuthorizedClientsForUser = `-- name: GetAuthorizedClientsForUser :many
-SELECT refresh_tokens.scope,
+SELECT DISTINCT ON (clients.id) refresh_tokens.scope,
+       refresh_tokens.requested_scope,
        refresh_tokens.expires_at,
        refresh_tokens.created_at,
        refresh_tokens.updated_at,
@@ -26,17 +27,19 @@ FROM refresh_tokens
          JOIN clients ON refresh_tokens.client_id = clients.id
 WHERE user_id = $1
   AND refresh_tokens.scope NOT ILIKE 'openid'
+ORDER BY clients.id, refresh_tokens.updated_at DESC
 `
 
 type GetAuthorizedClientsForUserRow struct {
-	Scope      sql.NullString `json:"scope"`
-	ExpiresAt  time.Time      `json:"expires_at"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	ID         uuid.UUID      `json:"id"`
-	Name       string         `json:"name"`
-	ClientType string         `json:"client_type"`
-	LogoUrl    string         `json:"logo_url"`
+	Scope          sql.NullString `json:"scope"`
+	RequestedScope sql.NullString `json:"requested_scope"`
+	ExpiresAt      time.Time      `json:"expires_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	ID             uuid.UUID      `json:"id"`
+	Name           string         `json:"name"`
+	ClientType     string         `json:"client_type"`
+	LogoUrl        string         `json:"logo_url"`
 }
 
 func (q *Queries) GetAuthorizedClientsForUser(ctx context.Context, userID uuid.UUID) ([]GetAuthorizedClientsForUserRow, error) {
@@ -50,6 +53,7 @@ func (q *Queries) GetAuthorizedClientsForUser(ctx context.Context, userID uuid.U
 		var i GetAuthorizedClientsForUserRow
 		if err := rows.Scan(
 			&i.Scope,
+			&i.RequestedScope,
 			&i.ExpiresAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
@@ -68,8 +72,56 @@ func (q *Queries) GetAuthorizedClientsForUser(ctx context.Context, userID uuid.U
 	return items, nil
 }
 
+const getLogoutClientsForUser = `-- name: GetLogoutClientsForUser :many
+SELECT DISTINCT clients.id,
+       clients.backchannel_logout_uri,
+       clients.frontchannel_logout_uri,
+       clients.id_token_signed_response_alg
+FROM refresh_tokens
+         JOIN clients ON refresh_tokens.client_id = clients.id
+WHERE user_id = $1
+  AND refresh_tokens.expires_at > now()
+  AND (clients.refresh_token_idle_lifetime <= 0
+    OR refresh_tokens.updated_at + make_interval(secs => clients.refresh_token_idle_lifetime) > now())
+  AND (clients.refresh_token_absolute_lifetime <= 0
+    OR refresh_tokens.created_at + make_interval(secs => clients.refresh_token_absolute_lifetime) > now())
+  AND (clients.backchannel_logout_uri != '' OR clients.frontchannel_logout_uri != '')
+`
+
+type GetLogoutClientsForUserRow struct {
+	ID                       uuid.UUID `json:"id"`
+	BackchannelLogoutUri     string    `json:"backchannel_logout_uri"`
+	FrontchannelLogoutUri    string    `json:"frontchannel_logout_uri"`
+	IDTokenSignedResponseAlg string    `json:"id_token_signed_response_alg"`
+}
+
+func (q *Queries) GetLogoutClientsForUser(ctx context.Context, userID uuid.UUID) ([]GetLogoutClientsForUserRow, error) {
+	rows, err := q.db.Query(ctx, getLogoutClientsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLogoutClientsForUserRow
+	for rows.Next() {
+		var i GetLogoutClientsForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.BackchannelLogoutUri,
+			&i.FrontchannelLogoutUri,
+			&i.IDTokenSignedResponseAlg,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getOpenIDAuthorizedClientsForUser = `-- name: GetOpenIDAuthorizedClientsForUser :many
-SELECT refresh_tokens.scope,
+SELECT DISTINCT ON (clients.id) refresh_tokens.scope,
        refresh_tokens.expires_at,
        refresh_tokens.created_at,
        refresh_tokens.updated_at,
@@ -81,6 +133,7 @@ FROM refresh_tokens
          JOIN clients ON refresh_tokens.client_id = clients.id
 WHERE user_id = $1
   AND refresh_tokens.scope ILIKE '%openid%'
+ORDER BY clients.id, refresh_tokens.updated_at DESC
 `
 
 type GetOpenIDAuthorizedClientsForUserRow struct {
@@ -124,7 +177,7 @@ func (q *Queries) GetOpenIDAuthorizedClientsForUser(ctx context.Context, userID
 }
 
 const getRefreshToken = `-- name: GetRefreshToken :one
-SELECT id, refresh_token, code, user_id, scope, redirect_uri, expires_at, client_id, created_at, updated_at
+SELECT id, refresh_token, code, user_id, scope, redirect_uri, expires_at, client_id, created_at, updated_at, requested_scope, auth_time, amr
 FROM refresh_tokens
 WHERE refresh_token = $1
 `
@@ -143,15 +196,48 @@ func (q *Queries) GetRefreshToken(ctx context.Context, refreshToken string) (Ref
 		&i.ClientID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RequestedScope,
+		&i.AuthTime,
+		&i.Amr,
+	)
+	return i, err
+}
+
+const getRefreshTokenBySupersededToken = `-- name: GetRefreshTokenBySupersededToken :one
+SELECT refresh_tokens.id, refresh_tokens.refresh_token, refresh_tokens.code, refresh_tokens.user_id, refresh_tokens.scope, refresh_tokens.redirect_uri, refresh_tokens.expires_at, refresh_tokens.client_id, refresh_tokens.created_at, refresh_tokens.updated_at, refresh_tokens.requested_scope, refresh_tokens.auth_time, refresh_tokens.amr
+FROM superseded_refresh_tokens
+         JOIN refresh_tokens ON superseded_refresh_tokens.family_id = refresh_tokens.id
+WHERE superseded_refresh_tokens.refresh_token = $1
+`
+
+func (q *Queries) GetRefreshTokenBySupersededToken(ctx context.Context, refreshToken string) (RefreshToken, error) {
+	row := q.db.QueryRow(ctx, getRefreshTokenBySupersededToken, refreshToken)
+	var i RefreshToken
+	err := row.Scan(
+		&i.ID,
+		&i.RefreshToken,
+		&i.Code,
+		&i.UserID,
+		&i.Scope,
+		&i.RedirectUri,
+		&i.ExpiresAt,
+		&i.ClientID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RequestedScope,
+		&i.AuthTime,
+		&i.Amr,
 	)
 	return i, err
 }
 
 const getRefreshTokenByUserIDAndClientID = `-- name: GetRefreshTokenByUserIDAndClientID :one
-SELECT id, refresh_token, code, user_id, scope, redirect_uri, expires_at, client_id, created_at, updated_at
+SELECT id, refresh_token, code, user_id, scope, redirect_uri, expires_at, client_id, created_at, updated_at, requested_scope, auth_time, amr
 FROM refresh_tokens
 WHERE user_id = $1
   AND client_id = $2
+ORDER BY updated_at DESC
+LIMIT 1
 `
 
 type GetRefreshTokenByUserIDAndClientIDParams struct {
@@ -173,6 +259,9 @@ func (q *Queries) GetRefreshTokenByUserIDAndClientID(ctx context.Context, arg Ge
 		&i.ClientID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RequestedScope,
+		&i.AuthTime,
+		&i.Amr,
 	)
 	return i, err
 }
@@ -199,6 +288,23 @@ func (q *Queries) RemoveRefreshTokenByCode(ctx context.Context, code string) err
 	return err
 }
 
+const removeRefreshTokensByUserIDAndClientID = `-- name: RemoveRefreshTokensByUserIDAndClientID :exec
+DELETE
+FROM refresh_tokens
+WHERE user_id = $1
+  AND client_id = $2
+`
+
+type RemoveRefreshTokensByUserIDAndClientIDParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	ClientID uuid.UUID `json:"client_id"`
+}
+
+func (q *Queries) RemoveRefreshTokensByUserIDAndClientID(ctx context.Context, arg RemoveRefreshTokensByUserIDAndClientIDParams) error {
+	_, err := q.db.Exec(ctx, removeRefreshTokensByUserIDAndClientID, arg.UserID, arg.ClientID)
+	return err
+}
+
 const saveRefreshToken = `-- name: SaveRefreshToken :one
 INSERT INTO refresh_tokens (expires_at,
                             user_id,
@@ -206,19 +312,25 @@ INSERT INTO refresh_tokens (expires_at,
                             redirect_uri,
                             client_id,
                             refresh_token,
-                            code)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, refresh_token, code, user_id, scope, redirect_uri, expires_at, client_id, created_at, updated_at
+                            code,
+                            requested_scope,
+                            auth_time,
+                            amr)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, refresh_token, code, user_id, scope, redirect_uri, expires_at, client_id, created_at, updated_at, requested_scope, auth_time, amr
 `
 
 type SaveRefreshTokenParams struct {
-	ExpiresAt    time.Time      `json:"expires_at"`
-	UserID       uuid.UUID      `json:"user_id"`
-	Scope        sql.NullString `json:"scope"`
-	RedirectUri  sql.NullString `json:"redirect_uri"`
-	ClientID     uuid.UUID      `json:"client_id"`
-	RefreshToken string         `json:"refresh_token"`
-	Code         string         `json:"code"`
+	ExpiresAt      time.Time      `json:"expires_at"`
+	UserID         uuid.UUID      `json:"user_id"`
+	Scope          sql.NullString `json:"scope"`
+	RedirectUri    sql.NullString `json:"redirect_uri"`
+	ClientID       uuid.UUID      `json:"client_id"`
+	RefreshToken   string         `json:"refresh_token"`
+	Code           string         `json:"code"`
+	RequestedScope sql.NullString `json:"requested_scope"`
+	AuthTime       sql.NullTime   `json:"auth_time"`
+	Amr            sql.NullString `json:"amr"`
 }
 
 func (q *Queries) SaveRefreshToken(ctx context.Context, arg SaveRefreshTokenParams) (RefreshToken, error) {
@@ -230,6 +342,9 @@ func (q *Queries) SaveRefreshToken(ctx context.Context, arg SaveRefreshTokenPara
 		arg.ClientID,
 		arg.RefreshToken,
 		arg.Code,
+		arg.RequestedScope,
+		arg.AuthTime,
+		arg.Amr,
 	)
 	var i RefreshToken
 	err := row.Scan(
@@ -243,15 +358,33 @@ func (q *Queries) SaveRefreshToken(ctx context.Context, arg SaveRefreshTokenPara
 		&i.ClientID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RequestedScope,
+		&i.AuthTime,
+		&i.Amr,
 	)
 	return i, err
 }
 
+const saveSupersededRefreshToken = `-- name: SaveSupersededRefreshToken :exec
+INSERT INTO superseded_refresh_tokens (refresh_token, family_id)
+VALUES ($1, $2)
+`
+
+type SaveSupersededRefreshTokenParams struct {
+	RefreshToken string    `json:"refresh_token"`
+	FamilyID     uuid.UUID `json:"family_id"`
+}
+
+func (q *Queries) SaveSupersededRefreshToken(ctx context.Context, arg SaveSupersededRefreshTokenParams) error {
+	_, err := q.db.Exec(ctx, saveSupersededRefreshToken, arg.RefreshToken, arg.FamilyID)
+	return err
+}
+
 const updateOAuthRefreshToken = `-- name: UpdateOAuthRefreshToken :one
 UPDATE refresh_tokens
 SET refresh_token = $1, updated_at = now()
 WHERE refresh_token = $2
-RETURNING id, refresh_token, code, user_id, scope, redirect_uri, expires_at, client_id, created_at, updated_at
+RETURNING id, refresh_token, code, user_id, scope, redirect_uri, expires_at, client_id, created_at, updated_at, requested_scope, auth_time, amr
 `
 
 type UpdateOAuthRefreshTokenParams struct {
@@ -273,6 +406,9 @@ func (q *Queries) UpdateOAuthRefreshToken(ctx context.Context, arg UpdateOAuthRe
 		&i.ClientID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RequestedScope,
+		&i.AuthTime,
+		&i.Amr,
 	)
 	return i, err
 }
@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
@@ -14,7 +15,7 @@ import (
 const createResourceServer = `-- name: CreateResourceServer :one
 INSERT INTO resource_servers (name)
 VALUES ($1)
-RETURNING id, name, created_at, updated_at, secret
+RETURNING id, name, created_at, updated_at, secret, previous_secret, previous_secret_expires_at
 `
 
 func (q *Queries) CreateResourceServer(ctx context.Context, name string) (ResourceServer, error) {
@@ -26,6 +27,8 @@ func (q *Queries) CreateResourceServer(ctx context.Context, name string) (Resour
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Secret,
+		&i.PreviousSecret,
+		&i.PreviousSecretExpiresAt,
 	)
 	return i, err
 }
@@ -34,7 +37,7 @@ const deleteResourceServer = `-- name: DeleteResourceServer :one
 DELETE
 FROM resource_servers
 WHERE id = $1
-RETURNING id, name, created_at, updated_at, secret
+RETURNING id, name, created_at, updated_at, secret, previous_secret, previous_secret_expires_at
 `
 
 func (q *Queries) DeleteResourceServer(ctx context.Context, id uuid.UUID) (ResourceServer, error) {
@@ -46,12 +49,14 @@ func (q *Queries) DeleteResourceServer(ctx context.Context, id uuid.UUID) (Resou
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Secret,
+		&i.PreviousSecret,
+		&i.PreviousSecretExpiresAt,
 	)
 	return i, err
 }
 
 const getResourceServerByID = `-- name: GetResourceServerByID :one
-SELECT id, name, created_at, updated_at, secret
+SELECT id, name, created_at, updated_at, secret, previous_secret, previous_secret_expires_at
 FROM resource_servers
 WHERE id = $1
 `
@@ -65,12 +70,14 @@ func (q *Queries) GetResourceServerByID(ctx context.Context, id uuid.UUID) (Reso
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Secret,
+		&i.PreviousSecret,
+		&i.PreviousSecretExpiresAt,
 	)
 	return i, err
 }
 
 const getResourceServerByName = `-- name: GetResourceServerByName :one
-SELECT id, name, created_at, updated_at, secret
+SELECT id, name, created_at, updated_at, secret, previous_secret, previous_secret_expires_at
 FROM resource_servers
 WHERE name = $1
 `
@@ -84,6 +91,32 @@ func (q *Queries) GetResourceServerByName(ctx context.Context, name string) (Res
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Secret,
+		&i.PreviousSecret,
+		&i.PreviousSecretExpiresAt,
 	)
 	return i, err
 }
+
+const rotateResourceServerSecret = `-- name: RotateResourceServerSecret :one
+UPDATE resource_servers
+SET
+ previous_secret = secret,
+ previous_secret_expires_at = $2,
+ secret = $3,
+ updated_at = now()
+WHERE id = $1
+RETURNING id
+`
+
+type RotateResourceServerSecretParams struct {
+	ID                      uuid.UUID    `json:"id"`
+	PreviousSecretExpiresAt sql.NullTime `json:"previous_secret_expires_at"`
+	Secret                  string       `json:"secret"`
+}
+
+func (q *Queries) RotateResourceServerSecret(ctx context.Context, arg RotateResourceServerSecretParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, rotateResourceServerSecret, arg.ID, arg.PreviousSecretExpiresAt, arg.Secret)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
@@ -79,6 +79,30 @@ func (q *Queries) GetScope(ctx context.Context, name string) (Scope, error) {
 	return i, err
 }
 
+const getScopeNamesByStatus = `-- name: GetScopeNamesByStatus :many
+SELECT name FROM scopes WHERE status = $1
+`
+
+func (q *Queries) GetScopeNamesByStatus(ctx context.Context, status string) ([]string, error) {
+	rows, err := q.db.Query(ctx, getScopeNamesByStatus, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getScopesByResourceServerName = `-- name: GetScopesByResourceServerName :many
 SELECT id, name, description, resource_server_id, resource_server_name, status, created_at
 FROM scopes
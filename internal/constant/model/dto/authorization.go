@@ -1,12 +1,15 @@
 package dto
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
 	"sso/internal/constant"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 )
 
@@ -18,6 +21,24 @@ type Consent struct {
 	Approved bool `json:"approved"`
 	// RequestOrigin is the origin of the client requesting authorization
 	RequestOrigin string
+	// DeviceCode is the device code of the device authorization this consent is given for.
+	DeviceCode string `json:"device_code,omitempty"`
+	// Pushed tells if this is an authorization request pushed to the par endpoint
+	// that is waiting to be referenced by a request_uri on the authorization endpoint.
+	Pushed bool `json:"pushed,omitempty"`
+	// RequestedScope is the scope the client requested when the user approved only part of it,
+	// the scope of the consent is then the part the user approved.
+	RequestedScope string `json:"requested_scope,omitempty"`
+	// CreatedAt is when the authorization request was made, the max_age of the request counts back from it.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PushedAuthorizationResponse is the response of the par endpoint.
+type PushedAuthorizationResponse struct {
+	// RequestURI references the pushed authorization request on the authorization endpoint.
+	RequestURI string `json:"request_uri"`
+	// ExpiresIn is the number of seconds the request_uri is valid for.
+	ExpiresIn int `json:"expires_in"`
 }
 
 type AuthCode struct {
@@ -29,10 +50,26 @@ type AuthCode struct {
 	RedirectURI string `json:"redirect_uri"`
 	// The scope of the access request expressed as a list of space-delimited,
 	Scope string `json:"scope"`
+	// The scope the client requested when the user granted only part of it.
+	RequestedScope string `json:"requested_scope,omitempty"`
 	// The state parameter passed in the initial authorization request.
 	UserID uuid.UUID `json:"user_id"`
 	// The state parameter passed in the initial authorization request.
 	State string `json:"state"`
+	// The PKCE code challenge passed in the initial authorization request.
+	CodeChallenge string `json:"code_challenge,omitempty"`
+	// The method the code challenge is derived from the code verifier with.
+	CodeChallengeMethod string `json:"code_challenge_method,omitempty"`
+	// The nonce passed in the initial authorization request, it is carried into the id token.
+	Nonce string `json:"nonce,omitempty"`
+	// The resource servers passed in the initial authorization request, the access tokens are restricted to them.
+	Resources []string `json:"resources,omitempty"`
+	// The authorization details the user approved, they are carried into the access token.
+	AuthorizationDetails []AuthorizationDetail `json:"authorization_details,omitempty"`
+	// How and when the user who granted the authorization authenticated.
+	Authentication Authentication `json:"authentication,omitempty"`
+	// ExpiresAt is when the auth code expires, the auth code cache expires it after its default lifetime when it's not set.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
 }
 
 type AuthorizationRequestParam struct {
@@ -41,19 +78,148 @@ type AuthorizationRequestParam struct {
 	// redirection URI used in the initial authorization request.
 	ResponseType string `form:"response_type" json:"response_type" query:"response_type"`
 	// state parameter passed in the initial authorization request.
-	State string `form:"state.omitempty" json:"state,omitempty" query:"state,omitempty"`
+	State string `form:"state,omitempty" json:"state,omitempty" query:"state,omitempty"`
 	// scope of the access request expressed as a list of space-delimited,
 	Scope string `form:"scope" json:"scope" query:"scope"`
 	// redirection URI used in the initial authorization request.
 	RedirectURI string `form:"redirect_uri" json:"redirect_uri" query:"redirect_uri"`
 	// specifies whether the Authorization Server MUST prompt the End-User for reauthentication.
 	Prompt string `form:"prompt,omitempty" json:"prompt,omitempty" query:"prompt,omitempty"`
+	// PKCE code challenge derived from the code verifier the client will send to the token endpoint.
+	CodeChallenge string `form:"code_challenge" json:"code_challenge,omitempty" query:"code_challenge"`
+	// method used to derive the code challenge, only S256 is supported.
+	CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method,omitempty" query:"code_challenge_method"`
+	// string value used to associate a client session with an id token, it is required when an id token is returned from the authorization endpoint.
+	Nonce string `form:"nonce" json:"nonce,omitempty" query:"nonce"`
+	// mechanism used to return the authorization response, it can be query or fragment.
+	ResponseMode string `form:"response_mode" json:"response_mode,omitempty" query:"response_mode"`
+	// reference to an authorization request the client pushed to the par endpoint, the request is then taken from it.
+	RequestURI string `form:"request_uri" json:"request_uri,omitempty" query:"request_uri"`
+	// JWT signed by the client holding the parameters of the request, they take precedence over the other parameters.
+	Request string `form:"request" json:"request,omitempty" query:"request"`
+	// maximum number of seconds since the user last authenticated, the user is asked to authenticate again if it elapsed.
+	MaxAge *int `form:"max_age" json:"max_age,omitempty" query:"max_age"`
+	// names of the resource servers the requested access is for, the issued access tokens are restricted to them.
+	Resource []string `form:"resource" json:"resource,omitempty" query:"resource"`
+	// JSON array of the specific actions the client asks the user to approve, as described on RFC 9396.
+	AuthorizationDetails string `form:"authorization_details" json:"authorization_details,omitempty" query:"authorization_details"`
+}
+
+// BrowserSession is the sso session held by the cookies of the user agent making an authorization request.
+type BrowserSession struct {
+	// RefreshToken is the internal refresh token the user agent got when the user logged in.
+	RefreshToken string
+	// OPBS is the browser state the session state of the authorization response is calculated from.
+	OPBS string
+}
+
+// RequestObject is the JWT secured authorization request of RFC 9101 a client signs with its registered keys.
+type RequestObject struct {
+	ClientID            string `json:"client_id,omitempty"`
+	ResponseType        string `json:"response_type,omitempty"`
+	Scope               string `json:"scope,omitempty"`
+	RedirectURI         string `json:"redirect_uri,omitempty"`
+	State               string `json:"state,omitempty"`
+	Prompt              string `json:"prompt,omitempty"`
+	CodeChallenge       string `json:"code_challenge,omitempty"`
+	CodeChallengeMethod string `json:"code_challenge_method,omitempty"`
+	Nonce               string `json:"nonce,omitempty"`
+	ResponseMode        string `json:"response_mode,omitempty"`
+	MaxAge              *int   `json:"max_age,omitempty"`
+	// Resource may be sent as a single resource server name or an array of them.
+	Resource jwt.ClaimStrings `json:"resource,omitempty"`
+	// AuthorizationDetails is sent as a JSON array in the request object rather than the string it's sent as in the query.
+	AuthorizationDetails json.RawMessage `json:"authorization_details,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Apply overrides the parameters of the request with the ones the request object holds.
+func (r RequestObject) Apply(param *AuthorizationRequestParam) {
+	for _, p := range []struct {
+		value string
+		param *string
+	}{
+		{r.ResponseType, &param.ResponseType},
+		{r.Scope, &param.Scope},
+		{r.RedirectURI, &param.RedirectURI},
+		{r.State, &param.State},
+		{r.Prompt, &param.Prompt},
+		{r.CodeChallenge, &param.CodeChallenge},
+		{r.CodeChallengeMethod, &param.CodeChallengeMethod},
+		{r.Nonce, &param.Nonce},
+		{r.ResponseMode, &param.ResponseMode},
+	} {
+		if p.value != "" {
+			*p.param = p.value
+		}
+	}
+	if r.MaxAge != nil {
+		param.MaxAge = r.MaxAge
+	}
+	if len(r.Resource) > 0 {
+		param.Resource = r.Resource
+	}
+	if len(r.AuthorizationDetails) > 0 {
+		param.AuthorizationDetails = string(r.AuthorizationDetails)
+	}
+	param.Request = ""
+}
+
+// NormalizeResponseType orders the space-delimited values of the response type
+// so that equivalent response types like "token code" and "code token" compare equal.
+func (a *AuthorizationRequestParam) NormalizeResponseType() {
+	values := strings.Fields(a.ResponseType)
+	normalized := make([]string, 0, len(values))
+	for _, rt := range []string{constant.ResponseTypeCode, constant.ResponseTypeIDToken, constant.ResponseTypeToken} {
+		for _, v := range values {
+			if v == rt {
+				normalized = append(normalized, rt)
+				break
+			}
+		}
+	}
+	if len(normalized) == len(values) {
+		a.ResponseType = strings.Join(normalized, " ")
+	}
+}
+
+// HasResponseType tells if the given value is one of the space-delimited values of the response type.
+func (a *AuthorizationRequestParam) HasResponseType(responseType string) bool {
+	for _, v := range strings.Fields(a.ResponseType) {
+		if v == responseType {
+			return true
+		}
+	}
+	return false
+}
+
+// GetResponseMode returns the response mode of the request,
+// defaulting to query for code and to fragment for every other response type.
+func (a *AuthorizationRequestParam) GetResponseMode() string {
+	if a.ResponseMode != "" {
+		return a.ResponseMode
+	}
+	if a.ResponseType == constant.ResponseTypeCode {
+		return constant.ResponseModeQuery
+	}
+	return constant.ResponseModeFragment
 }
 
 func (a *AuthorizationRequestParam) Validate() error {
 	return validation.ValidateStruct(a,
 		validation.Field(&a.ClientID, validation.Required.Error("client_id is required")),
-		validation.Field(&a.ResponseType, validation.Required.Error("response_type is required"), validation.In("code", "token")),
+		validation.Field(&a.ResponseType,
+			validation.Required.Error("response_type is required"),
+			validation.In(
+				constant.ResponseTypeCode,
+				constant.ResponseTypeIDToken,
+				constant.ResponseTypeCodeIDToken,
+				constant.ResponseTypeCodeToken,
+			).Error("unsupported response_type")),
+		validation.Field(&a.Nonce, validation.When(a.ResponseType != constant.ResponseTypeCode, validation.Required.Error("nonce is required"))),
+		validation.Field(&a.ResponseMode,
+			validation.In(constant.ResponseModeQuery, constant.ResponseModeFragment).Error("unsupported response_mode"),
+			validation.When(a.ResponseType != constant.ResponseTypeCode, validation.NotIn(constant.ResponseModeQuery).Error("query response_mode is not allowed for this response_type"))),
 		validation.Field(&a.Scope, validation.Required.Error("scope is required")),
 		validation.Field(&a.RedirectURI, validation.Required.Error("redirect_uri is required")),
 		validation.Field(&a.Prompt,
@@ -64,6 +230,13 @@ func (a *AuthorizationRequestParam) Validate() error {
 				constant.PromptEmail,
 				constant.PromptRegister,
 			).Error("invalid prompt value")),
+		validation.Field(&a.MaxAge, validation.Min(0).Error("max_age must not be negative")),
+		validation.Field(&a.Resource, validation.Each(validation.Required.Error("resource must not be empty"))),
+		validation.Field(&a.AuthorizationDetails, validation.By(authorizationDetailsValidate)),
+		validation.Field(&a.CodeChallenge, validation.Length(43, 128).Error("code_challenge must be between 43 and 128 characters")),
+		validation.Field(&a.CodeChallengeMethod,
+			validation.When(a.CodeChallenge != "", validation.Required.Error("code_challenge_method is required")),
+			validation.In(constant.CodeChallengeS256).Error("code_challenge_method not supported")),
 	)
 }
 
@@ -91,7 +264,7 @@ type AuthHistory struct {
 
 type ConsentResponse struct {
 	// Scopes is the list of scopes this consent holds
-	Scopes []Scope `json:"scopes"`
+	Scopes []ConsentScope `json:"scopes"`
 	// ClientName is the name of the client
 	ClientName string `json:"client_name"`
 	// ClientLogo is the logo url of the client
@@ -105,8 +278,12 @@ type ConsentResponse struct {
 	ClientID uuid.UUID `json:"client_id"`
 	// UserID is the id of the user this consent is being given to
 	UserID uuid.UUID `json:"user_id"`
-	// Approved tells if this exact scope is previously approved by this user
+	// Approved tells if every requested scope is previously approved by this user
 	Approved bool `json:"approved"`
+	// AuthorizationDetails is the specific actions the client asks the user to approve
+	AuthorizationDetails []AuthorizationDetail `json:"authorization_details,omitempty"`
+	// AuthorizationDetailTypes describes the types of the authorization details
+	AuthorizationDetailTypes []AuthorizationDetailType `json:"authorization_detail_types,omitempty"`
 }
 
 type LogoutRequest struct {
@@ -125,9 +302,21 @@ func (l *LogoutRequest) Validate() error {
 	)
 }
 
+// ConsentScope is a scope the user is asked to approve.
+type ConsentScope struct {
+	Scope
+	// Required tells if the user must approve the scope, the user may leave out the optional scopes of the client.
+	Required bool `json:"required"`
+	// Granted tells if the user already granted the scope to the client.
+	Granted bool `json:"granted"`
+}
+
 type ConsentResultRsp struct {
 	ConsentID     string `json:"consent_id"`
 	FailureReason string `json:"failure_reason"`
+	// Scope is the space-delimited list of the requested scopes the user approved.
+	// Every requested scope is approved when it's not set.
+	Scope string `json:"scope"`
 }
 
 // AuthorizedClientsResponse holds client data and access details for authorized client
@@ -141,6 +330,8 @@ type AuthorizedClientsResponse struct {
 	AuthExpiresAt time.Time `json:"expires_at"`
 	// AuthScopes is the scopes this authorization is given access to
 	AuthScopes []Scope `json:"auth_scopes,omitempty"`
+	// RequestedScopes is the scopes the client requested, the user may have granted only part of them
+	RequestedScopes []Scope `json:"requested_scopes,omitempty"`
 }
 
 type ResetPasswordRequest struct {
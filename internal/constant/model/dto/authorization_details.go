@@ -0,0 +1,111 @@
+package dto
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+)
+
+// AuthorizationDetail is an entry of the authorization_details of RFC 9396, it describes a specific action
+// the client asks the user to approve such as a payment of an amount to a wallet.
+// Besides its type, the fields of an authorization detail are defined by the resource server that registered the type.
+type AuthorizationDetail map[string]interface{}
+
+// Type returns the type of the authorization detail.
+func (a AuthorizationDetail) Type() string {
+	t, _ := a["type"].(string)
+	return t
+}
+
+// ParseAuthorizationDetails parses the JSON array the authorization_details parameter is sent as.
+func ParseAuthorizationDetails(authorizationDetails string) ([]AuthorizationDetail, error) {
+	if authorizationDetails == "" {
+		return nil, nil
+	}
+
+	var details []AuthorizationDetail
+	if err := json.Unmarshal([]byte(authorizationDetails), &details); err != nil {
+		return nil, fmt.Errorf("authorization_details must be a JSON array of objects")
+	}
+	for _, detail := range details {
+		if detail.Type() == "" {
+			return nil, fmt.Errorf("type of authorization details is required")
+		}
+	}
+	return details, nil
+}
+
+// AuthorizationDetailTypes returns the distinct types of the authorization details.
+func AuthorizationDetailTypes(details []AuthorizationDetail) []string {
+	types := []string{}
+	seen := map[string]bool{}
+	for _, detail := range details {
+		if !seen[detail.Type()] {
+			seen[detail.Type()] = true
+			types = append(types, detail.Type())
+		}
+	}
+	return types
+}
+
+func authorizationDetailsValidate(value interface{}) error {
+	authorizationDetails, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("invalid authorization_details")
+	}
+	_, err := ParseAuthorizationDetails(authorizationDetails)
+	return err
+}
+
+// AuthorizationDetailType is a type of authorization details a resource server accepts.
+type AuthorizationDetailType struct {
+	// ID is the unique identifier of the authorization detail type.
+	ID uuid.UUID `json:"id"`
+	// Type is the value of the type field of the authorization details, it must be unique across the sso.
+	Type string `json:"type"`
+	// Description is what the user is asked to approve by authorization details of the type.
+	Description string `json:"description"`
+	// ResourceServerName is the name of the resource server that registered the type.
+	ResourceServerName string `json:"resource_server_name,omitempty"`
+	// CreatedAt is the time the type was registered at.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuthorizationDetailTypesRequest is sent by a resource server to register the authorization detail types it accepts,
+// they replace the types it registered before.
+type AuthorizationDetailTypesRequest struct {
+	// Types is the authorization detail types the resource server accepts.
+	Types []AuthorizationDetailType `json:"types"`
+}
+
+func (a AuthorizationDetailTypesRequest) Validate() error {
+	return validation.ValidateStruct(&a,
+		validation.Field(&a.Types, validation.By(authorizationDetailTypesValidate)),
+	)
+}
+
+func authorizationDetailTypesValidate(value interface{}) error {
+	types, ok := value.([]AuthorizationDetailType)
+	if !ok {
+		return fmt.Errorf("invalid types")
+	}
+
+	for i := 0; i < len(types); i++ {
+		if err := validation.Validate(types[i].Type, validation.Required.Error("type is required")); err != nil {
+			return err
+		}
+		if err := validation.Validate(types[i].Description, validation.Required.Error("type description is required")); err != nil {
+			return err
+		}
+		for j := 0; j < len(types); j++ {
+			if types[i].Type == types[j].Type && i != j {
+				return fmt.Errorf("type must be unique")
+			}
+		}
+	}
+
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 	"time"
 
+	"sso/internal/constant"
+
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/google/uuid"
@@ -27,9 +29,17 @@ type Client struct {
 	RedirectURIs []string `json:"redirect_uris,omitempty"`
 	// Scopes is the list of default scopes of the client if one is not provided.
 	Scopes string `json:"scopes,omitempty"`
+	// OptionalScopes is the list of scopes the user may leave out when approving a consent for the client,
+	// the rest of the scopes the client requests are required.
+	OptionalScopes string `json:"optional_scopes,omitempty"`
 	// Secret is the secret the client uses to authenticate itself.
-	// It is automatically generated when the client is registered.
+	// It is automatically generated when the client is registered and only returned then, it is stored hashed.
 	Secret string `json:"secret,omitempty"`
+	// PreviousSecret is the hash of the secret the current one replaced.
+	// It is accepted until PreviousSecretExpiresAt so clients can switch to the new secret.
+	PreviousSecret string `json:"-"`
+	// PreviousSecretExpiresAt is the time the previous secret stops being accepted.
+	PreviousSecretExpiresAt time.Time `json:"-"`
 	// LogoURL is the URL of the client's logo.
 	// It must be a valid URL.
 	LogoURL string `json:"logo_url"`
@@ -38,19 +48,145 @@ type Client struct {
 	Status string `json:"status,omitempty"`
 	// CreatedAt is the time this client was created at
 	CreatedAt time.Time `json:"created_at"`
+	// RequirePKCE makes the client send a code_challenge on every authorization request.
+	// PKCE is always required for public clients.
+	RequirePKCE bool `json:"require_pkce"`
+	// ResponseTypes is the list of response types the client may use on the authorization endpoint.
+	// It is set to code by default.
+	ResponseTypes []string `json:"response_types,omitempty"`
+	// IDTokenSignedResponseAlg is the algorithm the id tokens issued to the client are signed with.
+	// It is set to PS512 by default.
+	IDTokenSignedResponseAlg string `json:"id_token_signed_response_alg,omitempty"`
+	// GrantTypes is the list of grant types the client may use on the token endpoint.
+	// A client with no grant types may use every grant type.
+	GrantTypes []string `json:"grant_types,omitempty"`
+	// TokenEndpointAuthMethod is how the client authenticates on the token endpoint.
+	// It can be client_secret_basic, private_key_jwt, tls_client_auth or none for public clients.
+	// It is set to client_secret_basic for confidential clients and none for public clients by default.
+	TokenEndpointAuthMethod string `json:"token_endpoint_auth_method,omitempty"`
+	// JWKS is the set of public keys the client signs its client assertions with.
+	// It is required for private_key_jwt.
+	JWKS *JWKS `json:"jwks,omitempty"`
+	// TLSClientCertificateThumbprint is the base64url encoded SHA-256 thumbprint of the certificate
+	// the client presents on the TLS connection. It is required for tls_client_auth.
+	TLSClientCertificateThumbprint string `json:"tls_client_certificate_thumbprint,omitempty"`
+	// RequirePushedAuthorizationRequests makes the client push its authorization requests to the par endpoint,
+	// the authorization endpoint then only accepts a request_uri from the client.
+	RequirePushedAuthorizationRequests bool `json:"require_pushed_authorization_requests"`
+	// BackchannelLogoutURI is the url the sso posts a logout token to when the user logs out.
+	BackchannelLogoutURI string `json:"backchannel_logout_uri,omitempty"`
+	// FrontchannelLogoutURI is the url the logout page of the sso loads in an iframe when the user logs out.
+	FrontchannelLogoutURI string `json:"frontchannel_logout_uri,omitempty"`
+	// AccessTokenLifetime is how many seconds the access tokens issued to the client are valid for.
+	// The lifetimes of the client are optional, the sso wide lifetime is used when they are not set.
+	AccessTokenLifetime int `json:"access_token_lifetime,omitempty"`
+	// RefreshTokenLifetime is how many seconds the refresh tokens issued to the client are valid for.
+	RefreshTokenLifetime int `json:"refresh_token_lifetime,omitempty"`
+	// RefreshTokenIdleLifetime is how many seconds a refresh token of the client stays valid without being used.
+	RefreshTokenIdleLifetime int `json:"refresh_token_idle_lifetime,omitempty"`
+	// RefreshTokenAbsoluteLifetime is how many seconds after the user granted the client its refresh tokens stop being valid,
+	// however recently they were used. It applies to the refresh tokens issued before it was set too.
+	RefreshTokenAbsoluteLifetime int `json:"refresh_token_absolute_lifetime,omitempty"`
+	// IDTokenLifetime is how many seconds the id tokens issued to the client are valid for.
+	IDTokenLifetime int `json:"id_token_lifetime,omitempty"`
+	// AuthCodeLifetime is how many seconds the authorization codes issued to the client are valid for.
+	AuthCodeLifetime int `json:"auth_code_lifetime,omitempty"`
 }
 
 func (c Client) ValidateClient() error {
 	return validation.ValidateStruct(&c,
 		validation.Field(&c.Name, validation.Required.Error("name is required"), validation.Length(3, 32).Error("name must be between 3 and 32 characters")),
-		validation.Field(&c.ClientType, validation.Required.Error("client_type is required"), validation.In("confidential", "public").Error("client type must be either confidential or public")),
+		validation.Field(&c.ClientType, validation.Required.Error("client_type is required"), validation.In(constant.ConfidentialClient, constant.PublicClient).Error("client type must be either confidential or public")),
 		validation.Field(&c.RedirectURIs, validation.Required.Error("redirect_uris is required")),
 		validation.Field(&c.Scopes, validation.Required.Error("scopes is required")),
 		validation.Field(&c.LogoURL, validation.Required.Error("logo_url is required"), is.URL.Error("invalid logo_url")),
+		validation.Field(&c.ResponseTypes, validation.Each(validation.In(constant.ResponseTypeCode, constant.ResponseTypeIDToken, constant.ResponseTypeCodeIDToken, constant.ResponseTypeCodeToken).Error("unsupported response type"))),
+		validation.Field(&c.IDTokenSignedResponseAlg, validation.In(constant.SigningAlgorithmPS512, constant.SigningAlgorithmRS256, constant.SigningAlgorithmES256, constant.SigningAlgorithmEdDSA).Error("unsupported id_token_signed_response_alg")),
+		validation.Field(&c.GrantTypes, validation.Each(validation.In(constant.AuthorizationCode, constant.RefreshToken, constant.ClientCredentials, constant.DeviceCode, constant.TokenExchange).Error("unsupported grant type"))),
+		validation.Field(&c.TokenEndpointAuthMethod,
+			validation.In(constant.ClientSecretBasic, constant.PrivateKeyJWT, constant.TLSClientAuth, constant.NoneAuthMethod).Error("unsupported token_endpoint_auth_method"),
+			validation.When(c.ClientType == constant.PublicClient, validation.In(constant.NoneAuthMethod).Error("public clients can only use the none token_endpoint_auth_method")),
+			validation.When(c.ClientType == constant.ConfidentialClient, validation.NotIn(constant.NoneAuthMethod).Error("confidential clients must authenticate on the token endpoint")),
+		),
+		validation.Field(&c.JWKS, validation.When(c.TokenEndpointAuthMethod == constant.PrivateKeyJWT, validation.Required.Error("jwks is required for private_key_jwt")), validation.By(jwksValidate)),
+		validation.Field(&c.TLSClientCertificateThumbprint, validation.When(c.TokenEndpointAuthMethod == constant.TLSClientAuth, validation.Required.Error("tls_client_certificate_thumbprint is required for tls_client_auth"))),
+		validation.Field(&c.BackchannelLogoutURI, is.URL.Error("invalid backchannel_logout_uri")),
+		validation.Field(&c.FrontchannelLogoutURI, is.URL.Error("invalid frontchannel_logout_uri")),
+		validation.Field(&c.AccessTokenLifetime, validation.Min(0).Error("access_token_lifetime must not be negative")),
+		validation.Field(&c.RefreshTokenLifetime, validation.Min(0).Error("refresh_token_lifetime must not be negative")),
+		validation.Field(&c.RefreshTokenIdleLifetime, validation.Min(0).Error("refresh_token_idle_lifetime must not be negative")),
+		validation.Field(&c.RefreshTokenAbsoluteLifetime, validation.Min(0).Error("refresh_token_absolute_lifetime must not be negative")),
+		validation.Field(&c.IDTokenLifetime, validation.Min(0).Error("id_token_lifetime must not be negative")),
+		validation.Field(&c.AuthCodeLifetime, validation.Min(0).Error("auth_code_lifetime must not be negative")),
 	)
 
 }
 
+func jwksValidate(value interface{}) error {
+	jwks, ok := value.(*JWKS)
+	if !ok || jwks == nil {
+		return nil
+	}
+
+	if len(jwks.Keys) == 0 {
+		return fmt.Errorf("jwks must have at least one key")
+	}
+	for _, key := range jwks.Keys {
+		if _, err := key.PublicKey(); err != nil {
+			return fmt.Errorf("invalid key %s: %w", key.Kid, err)
+		}
+	}
+
+	return nil
+}
+
+// AuthenticationMethod returns how the client authenticates on the token endpoint,
+// falling back to the default of its client type if it's not set.
+func (c Client) AuthenticationMethod() string {
+	if c.TokenEndpointAuthMethod != "" {
+		return c.TokenEndpointAuthMethod
+	}
+	if c.ClientType == constant.PublicClient {
+		return constant.NoneAuthMethod
+	}
+
+	return constant.ClientSecretBasic
+}
+
+// PKCERequired tells if the client must use PKCE on the authorization code flow.
+func (c Client) PKCERequired() bool {
+	return c.RequirePKCE || c.ClientType == constant.PublicClient
+}
+
+// AllowsResponseType tells if the client is allowed to use the given response type.
+// A client with no response types is only allowed to use code.
+func (c Client) AllowsResponseType(responseType string) bool {
+	if len(c.ResponseTypes) == 0 {
+		return responseType == constant.ResponseTypeCode
+	}
+	for _, rt := range c.ResponseTypes {
+		if rt == responseType {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsGrantType tells if the client is allowed to use the given grant type.
+// A client with no grant types, as the clients registered before grant types were recorded, may only use the code and refresh token grants,
+// the other grants have to be enabled on the client explicitly.
+func (c Client) AllowsGrantType(grantType string) bool {
+	if len(c.GrantTypes) == 0 {
+		return grantType == constant.AuthorizationCode || grantType == constant.RefreshToken
+	}
+	for _, gt := range c.GrantTypes {
+		if gt == grantType {
+			return true
+		}
+	}
+	return false
+}
+
 // ValidateURI :- is not currently recommended
 func ValidateURI(uris interface{}) error {
 	urisArray, ok := uris.([]string)
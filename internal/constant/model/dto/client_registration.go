@@ -0,0 +1,148 @@
+package dto
+
+import (
+	"time"
+
+	"sso/internal/constant"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
+)
+
+// InitialAccessToken is a token an admin issues to let a client register itself on the registration endpoint.
+type InitialAccessToken struct {
+	// ID is the unique identifier of the initial access token.
+	ID uuid.UUID `json:"id"`
+	// Token is the initial access token itself.
+	// It is only returned when the token is issued, the sso keeps only its hash.
+	Token string `json:"token,omitempty"`
+	// TokenHash is the hash of the token.
+	TokenHash string `json:"-"`
+	// CreatedBy is the id of the admin that issued the token.
+	CreatedBy uuid.UUID `json:"created_by"`
+	// ExpiresAt is the time after which the token can no longer be used to register clients.
+	ExpiresAt time.Time `json:"expires_at"`
+	// CreatedAt is the time the token was issued at.
+	CreatedAt time.Time `json:"created_at"`
+	// Scopes is the space separated list of scopes the clients registered with the token may request.
+	Scopes string `json:"scopes"`
+	// GrantTypes is the list of grant types the clients registered with the token may use.
+	GrantTypes []string `json:"grant_types"`
+}
+
+type InitialAccessTokenRequest struct {
+	// ExpiresAt is the time after which the token can no longer be used to register clients.
+	ExpiresAt time.Time `json:"expires_at"`
+	// Scopes is the space separated list of scopes the clients registered with the token may request.
+	Scopes string `json:"scopes"`
+	// GrantTypes is the list of grant types the clients registered with the token may use.
+	// It is set to authorization_code and refresh_token by default.
+	GrantTypes []string `json:"grant_types,omitempty"`
+}
+
+func (i InitialAccessTokenRequest) Validate() error {
+	return validation.ValidateStruct(&i,
+		validation.Field(&i.ExpiresAt, validation.Required.Error("expires_at is required"), validation.Min(time.Now()).Error("expires_at must be in the future")),
+		validation.Field(&i.Scopes, validation.Required.Error("scopes is required")),
+		validation.Field(&i.GrantTypes, validation.Each(validation.In(constant.AuthorizationCode, constant.RefreshToken, constant.ClientCredentials, constant.DeviceCode, constant.TokenExchange).Error("unsupported grant type"))),
+	)
+}
+
+// ClientRegistrationRequest is the client metadata of RFC 7591 a client registers itself with.
+type ClientRegistrationRequest struct {
+	// ClientName is the name of the client that will be displayed to the user.
+	ClientName string `json:"client_name"`
+	// RedirectURIs is the list of redirect URIs of the client.
+	RedirectURIs []string `json:"redirect_uris"`
+	// GrantTypes is the list of grant types the client may use on the token endpoint.
+	// It is set to authorization_code by default.
+	GrantTypes []string `json:"grant_types,omitempty"`
+	// ResponseTypes is the list of response types the client may use on the authorization endpoint.
+	// It is set to code by default.
+	ResponseTypes []string `json:"response_types,omitempty"`
+	// TokenEndpointAuthMethod is how the client authenticates on the token endpoint.
+	// none registers a public client, every other method a confidential client.
+	// It is set to client_secret_basic by default.
+	TokenEndpointAuthMethod string `json:"token_endpoint_auth_method,omitempty"`
+	// JWKS is the set of public keys the client signs its client assertions with.
+	// It is required for private_key_jwt.
+	JWKS *JWKS `json:"jwks,omitempty"`
+	// TLSClientCertificateThumbprint is the base64url encoded SHA-256 thumbprint of the certificate
+	// the client presents on the TLS connection. It is required for tls_client_auth.
+	TLSClientCertificateThumbprint string `json:"tls_client_certificate_thumbprint,omitempty"`
+	// RequirePushedAuthorizationRequests makes the client push its authorization requests to the par endpoint.
+	RequirePushedAuthorizationRequests bool `json:"require_pushed_authorization_requests,omitempty"`
+	// BackchannelLogoutURI is the url the sso posts a logout token to when the user logs out.
+	BackchannelLogoutURI string `json:"backchannel_logout_uri,omitempty"`
+	// FrontchannelLogoutURI is the url the logout page of the sso loads in an iframe when the user logs out.
+	FrontchannelLogoutURI string `json:"frontchannel_logout_uri,omitempty"`
+	// Scope is the space separated list of scopes the client may request.
+	Scope string `json:"scope"`
+	// LogoURI is the URL of the client's logo.
+	LogoURI string `json:"logo_uri"`
+	// IDTokenSignedResponseAlg is the algorithm the id tokens issued to the client are signed with.
+	// It is set to PS512 by default.
+	IDTokenSignedResponseAlg string `json:"id_token_signed_response_alg,omitempty"`
+}
+
+func (c ClientRegistrationRequest) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.ClientName, validation.Required.Error("client_name is required"), validation.Length(3, 32).Error("client_name must be between 3 and 32 characters")),
+		validation.Field(&c.RedirectURIs, validation.Required.Error("redirect_uris is required")),
+		validation.Field(&c.GrantTypes, validation.Each(validation.In(constant.AuthorizationCode, constant.RefreshToken, constant.ClientCredentials, constant.DeviceCode, constant.TokenExchange).Error("unsupported grant type"))),
+		validation.Field(&c.ResponseTypes, validation.Each(validation.In(constant.ResponseTypeCode, constant.ResponseTypeIDToken, constant.ResponseTypeCodeIDToken, constant.ResponseTypeCodeToken).Error("unsupported response type"))),
+		validation.Field(&c.TokenEndpointAuthMethod, validation.In(constant.ClientSecretBasic, constant.PrivateKeyJWT, constant.TLSClientAuth, constant.NoneAuthMethod).Error("unsupported token_endpoint_auth_method")),
+		validation.Field(&c.Scope, validation.Required.Error("scope is required")),
+		validation.Field(&c.LogoURI, validation.Required.Error("logo_uri is required"), is.URL.Error("invalid logo_uri")),
+		validation.Field(&c.IDTokenSignedResponseAlg, validation.In(constant.SigningAlgorithmPS512, constant.SigningAlgorithmRS256, constant.SigningAlgorithmES256, constant.SigningAlgorithmEdDSA).Error("unsupported id_token_signed_response_alg")),
+		validation.Field(&c.BackchannelLogoutURI, is.URL.Error("invalid backchannel_logout_uri")),
+		validation.Field(&c.FrontchannelLogoutURI, is.URL.Error("invalid frontchannel_logout_uri")),
+	)
+}
+
+// Client returns the client the metadata describes, filling in the defaults of the metadata left out.
+func (c ClientRegistrationRequest) Client() Client {
+	client := Client{
+		Name:                               c.ClientName,
+		ClientType:                         constant.ConfidentialClient,
+		RedirectURIs:                       c.RedirectURIs,
+		Scopes:                             c.Scope,
+		LogoURL:                            c.LogoURI,
+		ResponseTypes:                      c.ResponseTypes,
+		GrantTypes:                         c.GrantTypes,
+		IDTokenSignedResponseAlg:           c.IDTokenSignedResponseAlg,
+		TokenEndpointAuthMethod:            c.TokenEndpointAuthMethod,
+		JWKS:                               c.JWKS,
+		TLSClientCertificateThumbprint:     c.TLSClientCertificateThumbprint,
+		RequirePushedAuthorizationRequests: c.RequirePushedAuthorizationRequests,
+		BackchannelLogoutURI:               c.BackchannelLogoutURI,
+		FrontchannelLogoutURI:              c.FrontchannelLogoutURI,
+	}
+	if c.TokenEndpointAuthMethod == constant.NoneAuthMethod {
+		client.ClientType = constant.PublicClient
+	}
+	if len(client.GrantTypes) == 0 {
+		client.GrantTypes = []string{constant.AuthorizationCode}
+	}
+
+	return client
+}
+
+// ClientRegistrationResponse is the client information response of RFC 7591 and RFC 7592.
+type ClientRegistrationResponse struct {
+	// ClientID is the id of the registered client.
+	ClientID string `json:"client_id"`
+	// ClientSecret is the secret of the client, only confidential clients get a secret.
+	ClientSecret string `json:"client_secret,omitempty"`
+	// ClientIDIssuedAt is the time the client was registered at, in seconds since the epoch.
+	ClientIDIssuedAt int64 `json:"client_id_issued_at"`
+	// ClientSecretExpiresAt is the time the client secret expires at, 0 as it doesn't expire.
+	ClientSecretExpiresAt int64 `json:"client_secret_expires_at"`
+	// RegistrationAccessToken is the token the client reads, updates and deletes its registration with.
+	// It is only returned when the client registers.
+	RegistrationAccessToken string `json:"registration_access_token,omitempty"`
+	// RegistrationClientURI is the url the client manages its registration on.
+	RegistrationClientURI string `json:"registration_client_uri"`
+	ClientRegistrationRequest
+}
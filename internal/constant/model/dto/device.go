@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+)
+
+type DeviceAuthorizationRequest struct {
+	// Scope is the space-delimited list of scopes the device is requesting access to.
+	// It defaults to the scopes registered for the client.
+	Scope string `form:"scope" json:"scope"`
+}
+
+type DeviceAuthorizationResponse struct {
+	// DeviceCode is the code the device polls the token endpoint with.
+	DeviceCode string `json:"device_code"`
+	// UserCode is the code the user enters on the verification page.
+	UserCode string `json:"user_code"`
+	// VerificationURI is the url of the page the user enters the user code on.
+	VerificationURI string `json:"verification_uri"`
+	// VerificationURIComplete is the verification uri with the user code already filled in.
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	// ExpiresIn is the lifetime of the device code in seconds.
+	ExpiresIn int `json:"expires_in"`
+	// Interval is the minimum number of seconds the device must wait between polling requests.
+	Interval int `json:"interval"`
+}
+
+type DeviceAuthorization struct {
+	// DeviceCode is the code the device polls the token endpoint with.
+	DeviceCode string `json:"device_code"`
+	// UserCode is the code the user enters on the verification page.
+	UserCode string `json:"user_code"`
+	// ClientID is the id of the client the device authorization is issued to.
+	ClientID uuid.UUID `json:"client_id"`
+	// Scope is the space-delimited list of scopes the device is requesting access to.
+	// Once the user approves it, it's the part of the scopes the user approved.
+	Scope string `json:"scope"`
+	// RequestedScope is the scope the device requested when the user approved only part of it.
+	RequestedScope string `json:"requested_scope,omitempty"`
+	// Status is the state of the authorization, it can be pending, approved or denied.
+	Status string `json:"status"`
+	// UserID is the id of the user who approved the authorization.
+	UserID uuid.UUID `json:"user_id,omitempty"`
+	// Authentication is how and when the user who approved the authorization authenticated.
+	Authentication Authentication `json:"authentication,omitempty"`
+	// Interval is the minimum number of seconds the device must wait between polling requests.
+	Interval int `json:"interval"`
+	// LastPolledAt is the last time the device polled the token endpoint.
+	LastPolledAt time.Time `json:"last_polled_at,omitempty"`
+}
+
+type VerifyDeviceRequest struct {
+	// UserCode is the code displayed on the device.
+	UserCode string `form:"user_code" json:"user_code"`
+}
+
+func (v VerifyDeviceRequest) Validate() error {
+	return validation.ValidateStruct(&v,
+		validation.Field(&v.UserCode, validation.Required.Error("user_code is required")),
+	)
+}
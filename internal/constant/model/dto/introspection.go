@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"sso/internal/constant"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type IntrospectionRequest struct {
+	// Token is the access token or refresh token to be introspected.
+	Token string `form:"token" json:"token"`
+	// TokenTypeHint is a hint about the type of the token, it can be access_token or refresh_token.
+	TokenTypeHint string `form:"token_type_hint" json:"token_type_hint"`
+}
+
+func (i IntrospectionRequest) Validate() error {
+	return validation.ValidateStruct(&i,
+		validation.Field(&i.Token, validation.Required.Error("token is required")),
+		validation.Field(&i.TokenTypeHint, validation.In(constant.AccessTokenHint, constant.RefreshTokenHint).Error("invalid token_type_hint")),
+	)
+}
+
+type IntrospectionResponse struct {
+	// Active tells if the token is currently active.
+	Active bool `json:"active"`
+	// Scope is the space-delimited list of scopes the token is issued for.
+	Scope string `json:"scope,omitempty"`
+	// ClientID is the id of the client the token is issued to.
+	ClientID string `json:"client_id,omitempty"`
+	// Sub is the subject of the token.
+	Sub string `json:"sub,omitempty"`
+	// Exp is the time the token expires at, in seconds since the unix epoch.
+	Exp int64 `json:"exp,omitempty"`
+	// TokenType is the type of the token, it can be access_token or refresh_token.
+	TokenType string `json:"token_type,omitempty"`
+	// Aud is the audience of the token, resource servers check they are in it.
+	Aud []string `json:"aud,omitempty"`
+	// Act is the party the token is delegated to, it's only set on tokens issued on the token exchange grant.
+	Act *Actor `json:"act,omitempty"`
+	// AuthorizationDetails is the specific actions the user approved the client to take with the token.
+	AuthorizationDetails []AuthorizationDetail `json:"authorization_details,omitempty"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+// LogoutToken is the claims of the token posted to the backchannel_logout_uri of a client when the user logs out.
+type LogoutToken struct {
+	// SID is the id of the sso session that ended.
+	SID string `json:"sid,omitempty"`
+	// Events holds the back-channel logout event, it's what tells a logout token from the other tokens.
+	Events map[string]struct{} `json:"events"`
+	jwt.RegisteredClaims
+}
+
+// LogoutResponse is returned when the user logs out of the sso.
+type LogoutResponse struct {
+	// FrontChannelLogoutURIs are the urls of the clients the logout page should load in iframes
+	// so the clients can clear the session of the user on the browser.
+	FrontChannelLogoutURIs []string `json:"frontchannel_logout_uris,omitempty"`
+}
+
+// BackChannelLogoutDelivery is the outcome of posting a logout token to a client.
+type BackChannelLogoutDelivery struct {
+	// ID is the unique identifier of the delivery.
+	ID uuid.UUID `json:"id"`
+	// ClientID is the id of the client the logout token is posted to.
+	ClientID uuid.UUID `json:"client_id"`
+	// UserID is the id of the user who logged out.
+	UserID uuid.UUID `json:"user_id"`
+	// SessionID is the id of the sso session that ended.
+	SessionID string `json:"session_id"`
+	// Status is DELIVERED when the client acknowledged the logout token and FAILED otherwise.
+	Status string `json:"status"`
+	// Attempts is the number of times the logout token was posted.
+	Attempts int `json:"attempts"`
+	// Error is why the last attempt failed.
+	Error string `json:"error,omitempty"`
+	// CreatedAt is the time the delivery was recorded at.
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,195 @@
+package dto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type OpenIDConfiguration struct {
+	// Issuer is the identifier of the sso, it's the value of the iss claim of the issued tokens.
+	Issuer string `json:"issuer"`
+	// AuthorizationEndpoint is the url of the authorization endpoint.
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	// TokenEndpoint is the url of the token endpoint.
+	TokenEndpoint string `json:"token_endpoint"`
+	// UserInfoEndpoint is the url of the userinfo endpoint.
+	UserInfoEndpoint string `json:"userinfo_endpoint"`
+	// JWKSURI is the url of the json web key set the tokens can be verified with.
+	JWKSURI string `json:"jwks_uri"`
+	// EndSessionEndpoint is the url of the rp initiated logout endpoint.
+	EndSessionEndpoint string `json:"end_session_endpoint"`
+	// CheckSessionIframe is the url of the page relying parties embed to check the session_state of the user.
+	CheckSessionIframe string `json:"check_session_iframe"`
+	// BackchannelLogoutSupported tells if the sso posts logout tokens to the backchannel_logout_uri of clients.
+	BackchannelLogoutSupported bool `json:"backchannel_logout_supported"`
+	// BackchannelLogoutSessionSupported tells if the logout tokens carry the sid of the ended session.
+	BackchannelLogoutSessionSupported bool `json:"backchannel_logout_session_supported"`
+	// FrontchannelLogoutSupported tells if the sso renders the frontchannel_logout_uri of clients on logout.
+	FrontchannelLogoutSupported bool `json:"frontchannel_logout_supported"`
+	// FrontchannelLogoutSessionSupported tells if the iss and sid are passed to the frontchannel_logout_uri.
+	FrontchannelLogoutSessionSupported bool `json:"frontchannel_logout_session_supported"`
+	// DeviceAuthorizationEndpoint is the url of the device authorization endpoint.
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	// IntrospectionEndpoint is the url of the token introspection endpoint.
+	IntrospectionEndpoint string `json:"introspection_endpoint"`
+	// RevocationEndpoint is the url of the token revocation endpoint.
+	RevocationEndpoint string `json:"revocation_endpoint"`
+	// RegistrationEndpoint is the url of the dynamic client registration endpoint.
+	RegistrationEndpoint string `json:"registration_endpoint"`
+	// PushedAuthorizationRequestEndpoint is the url of the par endpoint.
+	PushedAuthorizationRequestEndpoint string `json:"pushed_authorization_request_endpoint"`
+	// RequirePushedAuthorizationRequests tells if every client must use the par endpoint, clients can still be required to individually.
+	RequirePushedAuthorizationRequests bool `json:"require_pushed_authorization_requests"`
+	// ScopesSupported is the list of scopes the sso supports.
+	ScopesSupported []string `json:"scopes_supported"`
+	// ResponseTypesSupported is the list of response_type values the sso supports.
+	ResponseTypesSupported []string `json:"response_types_supported"`
+	// ACRValuesSupported is the list of authentication context class references the sso supports.
+	ACRValuesSupported []string `json:"acr_values_supported"`
+	// ResponseModesSupported is the list of response_mode values the sso supports.
+	ResponseModesSupported []string `json:"response_modes_supported"`
+	// GrantTypesSupported is the list of grant_type values the sso supports.
+	GrantTypesSupported []string `json:"grant_types_supported"`
+	// SubjectTypesSupported is the list of subject identifier types the sso supports.
+	SubjectTypesSupported []string `json:"subject_types_supported"`
+	// IDTokenSigningAlgValuesSupported is the list of algorithms the id token can be signed with.
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+	// RequestParameterSupported tells if the authorization endpoint accepts request objects on the request parameter.
+	RequestParameterSupported bool `json:"request_parameter_supported"`
+	// RequestObjectSigningAlgValuesSupported is the list of algorithms request objects can be signed with.
+	RequestObjectSigningAlgValuesSupported []string `json:"request_object_signing_alg_values_supported"`
+	// TokenEndpointAuthMethodsSupported is the list of client authentication methods the token endpoint supports.
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	// TokenEndpointAuthSigningAlgValuesSupported is the list of algorithms private_key_jwt client assertions can be signed with.
+	TokenEndpointAuthSigningAlgValuesSupported []string `json:"token_endpoint_auth_signing_alg_values_supported"`
+	// CodeChallengeMethodsSupported is the list of PKCE code challenge methods the sso supports.
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported"`
+	// ClaimsSupported is the list of claims the sso may supply values for.
+	ClaimsSupported []string `json:"claims_supported"`
+}
+
+type JWK struct {
+	// Kty is the key type.
+	Kty string `json:"kty"`
+	// Use is the intended use of the key.
+	Use string `json:"use"`
+	// Kid is the id of the key. It's set on the header of the tokens signed with this key.
+	Kid string `json:"kid"`
+	// Alg is the algorithm the key is used with.
+	Alg string `json:"alg"`
+	// N is the modulus of the rsa public key.
+	N string `json:"n,omitempty"`
+	// E is the exponent of the rsa public key.
+	E string `json:"e,omitempty"`
+	// Crv is the curve of the ecdsa or ed25519 public key.
+	Crv string `json:"crv,omitempty"`
+	// X is the x coordinate of the ecdsa public key, or the ed25519 public key itself.
+	X string `json:"x,omitempty"`
+	// Y is the y coordinate of the ecdsa public key.
+	Y string `json:"y,omitempty"`
+}
+
+// PublicKey decodes the rsa, ecdsa or ed25519 public key the jwk holds.
+func (j JWK) PublicKey() (crypto.PublicKey, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(j.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(j.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent: %w", err)
+		}
+		if len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("invalid rsa key")
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch j.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", j.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(j.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %w", err)
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("the point is not on the curve")
+		}
+
+		return key, nil
+	case "OKP":
+		if j.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %s", j.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid ed25519 key")
+		}
+
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", j.Kty)
+	}
+}
+
+type JWKS struct {
+	// Keys is the list of public keys.
+	Keys []JWK `json:"keys"`
+}
+
+// Key returns the key with the given kid.
+// If the kid is empty the key is only found when the set holds a single key.
+func (j JWKS) Key(kid string) (JWK, bool) {
+	if kid == "" {
+		if len(j.Keys) == 1 {
+			return j.Keys[0], true
+		}
+		return JWK{}, false
+	}
+
+	for _, key := range j.Keys {
+		if key.Kid == kid {
+			return key, true
+		}
+	}
+
+	return JWK{}, false
+}
+
+// VerificationKey returns the public key of the set a token is signed with, picked by the kid on the header of the token.
+// It's meant to be the key func of the parser verifying the tokens a client signs with its registered keys.
+func (j JWKS) VerificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := j.Key(kid)
+	if !ok {
+		return nil, fmt.Errorf("no key found for kid %q", kid)
+	}
+	if key.Alg != "" && key.Alg != token.Method.Alg() {
+		return nil, fmt.Errorf("the key is not used with %s", token.Method.Alg())
+	}
+
+	return key.PublicKey()
+}
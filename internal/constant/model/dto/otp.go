@@ -5,4 +5,4 @@ type OTP struct {
 	OTP         string `json:"otp"`
 	Verified    bool   `json:"verified"`
 	Type        string `json:"type"`
-}
\ No newline at end of file
+}
@@ -21,7 +21,13 @@ type ResourceServer struct {
 	// Scopes is the scopes of this resource server
 	Scopes []Scope `json:"scopes,omitempty"`
 	// Secret is the secret of the resource server that will be used for authentication on the sso.
+	// It is stored hashed, the plain secret is only returned when it is rotated.
 	Secret string `json:"secret,omitempty"`
+	// PreviousSecret is the hash of the secret the current one replaced.
+	// It is accepted until PreviousSecretExpiresAt so the resource server can switch to the new secret.
+	PreviousSecret string `json:"-"`
+	// PreviousSecretExpiresAt is the time the previous secret stops being accepted.
+	PreviousSecretExpiresAt time.Time `json:"-"`
 }
 
 func (r ResourceServer) Validate() error {
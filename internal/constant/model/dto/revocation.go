@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"sso/internal/constant"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type RevocationRequest struct {
+	// Token is the access token or refresh token to be revoked.
+	Token string `form:"token" json:"token"`
+	// TokenTypeHint is a hint about the type of the token, it can be access_token or refresh_token.
+	TokenTypeHint string `form:"token_type_hint" json:"token_type_hint"`
+}
+
+func (r RevocationRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Token, validation.Required.Error("token is required")),
+		validation.Field(&r.TokenTypeHint, validation.In(constant.AccessTokenHint, constant.RefreshTokenHint).Error("invalid token_type_hint")),
+	)
+}
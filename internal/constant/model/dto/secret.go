@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type RotateSecretRequest struct {
+	// PreviousSecretExpiresAt is the time the previous secret stops being accepted,
+	// until then both the previous and the new secret are valid.
+	// If it's not set the previous secret stops being accepted immediately.
+	PreviousSecretExpiresAt time.Time `json:"previous_secret_expires_at"`
+}
+
+func (r RotateSecretRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.PreviousSecretExpiresAt, validation.When(!r.PreviousSecretExpiresAt.IsZero(), validation.Min(time.Now()).Error("previous_secret_expires_at must be in the future"))),
+	)
+}
+
+// RotatedSecret is the result of a secret rotation.
+// The secret is only returned here, it can't be read again.
+type RotatedSecret struct {
+	// Secret is the new secret.
+	Secret string `json:"secret"`
+	// PreviousSecretExpiresAt is the time the previous secret stops being accepted.
+	PreviousSecretExpiresAt time.Time `json:"previous_secret_expires_at"`
+}
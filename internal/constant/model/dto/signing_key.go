@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"time"
+
+	"sso/internal/constant"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+)
+
+// SigningKey is a key pair the sso signs and verifies tokens with.
+type SigningKey struct {
+	// ID is the unique identifier of the signing key.
+	ID uuid.UUID `json:"id"`
+	// Kid is the key id that is set on the header of the tokens signed with this key.
+	Kid string `json:"kid"`
+	// Algorithm is the algorithm the key signs tokens with.
+	Algorithm string `json:"algorithm"`
+	// PrivateKey is the pem encoded private key.
+	PrivateKey string `json:"-"`
+	// PublicKey is the pem encoded public key.
+	PublicKey string `json:"public_key"`
+	// ActivatesAt is the time from which the key is used to sign tokens.
+	ActivatesAt time.Time `json:"activates_at"`
+	// RetiresAt is the time from which tokens signed with the key are no longer accepted.
+	RetiresAt *time.Time `json:"retires_at,omitempty"`
+	// CreatedAt is the time the key was created at.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsRetired checks if the key is retired at the given time.
+func (s SigningKey) IsRetired(at time.Time) bool {
+	return s.RetiresAt != nil && !s.RetiresAt.After(at)
+}
+
+// IsActive checks if the key can be used to sign tokens at the given time.
+func (s SigningKey) IsActive(at time.Time) bool {
+	return !s.ActivatesAt.After(at) && !s.IsRetired(at)
+}
+
+type RotateSigningKeyRequest struct {
+	// ActivatesAt is the time the new key starts to sign tokens.
+	// If it's not set the new key is activated immediately.
+	ActivatesAt time.Time `json:"activates_at"`
+	// Algorithm is the algorithm the new key signs tokens with, a key of the matching kind is generated for it.
+	// If it's not set a key for PS512 is generated.
+	Algorithm string `json:"algorithm"`
+}
+
+func (r RotateSigningKeyRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Algorithm, validation.In(constant.SigningAlgorithmPS512, constant.SigningAlgorithmRS256, constant.SigningAlgorithmES256, constant.SigningAlgorithmEdDSA).Error("unsupported algorithm")),
+		validation.Field(&r.ActivatesAt, validation.When(!r.ActivatesAt.IsZero(), validation.Min(time.Now()).Error("activates_at must be in the future"))),
+	)
+}
+
+type RetireSigningKeyRequest struct {
+	// RetiresAt is the time the key stops being accepted.
+	// If it's not set the key is retired immediately.
+	RetiresAt time.Time `json:"retires_at"`
+}
+
+func (r RetireSigningKeyRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.RetiresAt, validation.When(!r.RetiresAt.IsZero(), validation.Min(time.Now()).Error("retires_at must be in the future"))),
+	)
+}
@@ -22,9 +22,54 @@ type AccessToken struct {
 	CreatedAt time.Time  `json:"-"`
 	UpdatedAt time.Time  `json:"-"`
 	DeletedAt *time.Time `json:"-"`
+	// Actor is the party acting on behalf of the subject, it's only set on tokens issued on the token exchange grant.
+	Actor *Actor `json:"act,omitempty"`
+	// AuthorizationDetails is the specific actions the user approved the client to take.
+	AuthorizationDetails []AuthorizationDetail `json:"authorization_details,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// Client returns the id of the client the access token is issued to.
+// Tokens restricted to resource servers carry it on the client_id claim as their audience is the resource servers.
+func (a AccessToken) Client() string {
+	if a.ClientID != "" {
+		return a.ClientID
+	}
+	if len(a.Audience) > 0 {
+		return a.Audience[0]
+	}
+	return ""
+}
+
+// Actor identifies the party a token is delegated to, it's set as the act claim.
+// The actor of a token that was itself delegated is nested in it.
+type Actor struct {
+	// Subject is the id of the acting party.
+	Subject string `json:"sub"`
+	// Actor is the party that acted before this one.
+	Actor *Actor `json:"act,omitempty"`
+}
+
+// InternalAccessToken is the claims of the access token the sso issues for its own session.
+type InternalAccessToken struct {
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
+	AMR      []string         `json:"amr,omitempty"`
+	SID      string           `json:"sid,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Authentication returns how and when the user of the session authenticated.
+func (i InternalAccessToken) Authentication() Authentication {
+	authentication := Authentication{
+		Methods:   i.AMR,
+		SessionID: i.SID,
+	}
+	if i.AuthTime != nil {
+		authentication.Time = i.AuthTime.Time
+	}
+	return authentication
+}
+
 type TokenResponse struct {
 	// AccessToken is the access token for the current login
 	AccessToken string `form:"access_token" query:"access_token" json:"access_token,omitempty"`
@@ -36,23 +81,82 @@ type TokenResponse struct {
 	TokenType string `form:"token_type" query:"token_type" json:"token_type,omitempty"`
 	// ExpiresAt is time the access token is going to be expired.
 	ExpiresIn string `json:"expires_in"`
+	// Scope is the scope of the access token, it's only set when the token is restricted to resource servers.
+	Scope string `json:"scope,omitempty"`
+	// AuthorizationDetails is the authorization details the access token is issued for.
+	AuthorizationDetails []AuthorizationDetail `json:"authorization_details,omitempty"`
+	// IssuedTokenType is the type of the token issued on the token exchange grant.
+	IssuedTokenType string `json:"issued_token_type,omitempty"`
 }
 
 type IDTokenPayload struct {
-	FirstName       string `json:"first_name"`
-	MiddleName      string `json:"middle_name"`
-	LastName        string `json:"last_name"`
-	Picture         string `json:"picture"`
-	Email           string `json:"email"`
-	PhoneNumber     string `json:"phone"`
-	AuthorizedParty string `json:"azp"`
+	FirstName       string           `json:"first_name"`
+	MiddleName      string           `json:"middle_name"`
+	LastName        string           `json:"last_name"`
+	Picture         string           `json:"picture"`
+	Email           string           `json:"email"`
+	PhoneNumber     string           `json:"phone"`
+	AuthorizedParty string           `json:"azp"`
+	Nonce           string           `json:"nonce,omitempty"`
+	AuthTime        *jwt.NumericDate `json:"auth_time,omitempty"`
+	ACR             string           `json:"acr,omitempty"`
+	AMR             []string         `json:"amr,omitempty"`
+	AccessTokenHash string           `json:"at_hash,omitempty"`
+	CodeHash        string           `json:"c_hash,omitempty"`
+	SID             string           `json:"sid,omitempty"`
 
 	jwt.RegisteredClaims
 }
 
+// IDTokenOptions holds the claims of an id token that depend on the authorization it is issued for.
+type IDTokenOptions struct {
+	// Nonce is the nonce of the authorization request.
+	Nonce string
+	// Authentication is how and when the user authenticated.
+	Authentication Authentication
+	// AccessToken is the access token issued along with the id token, its hash is set as at_hash.
+	AccessToken string
+	// Code is the authorization code issued along with the id token, its hash is set as c_hash.
+	Code string
+	// SigningAlgorithm is the algorithm the id token is signed with, PS512 is used if it's not set.
+	SigningAlgorithm string
+}
+
+// AccessTokenOptions holds the claims of an access token that depend on the authorization it is issued for.
+type AccessTokenOptions struct {
+	// Resources is the names of the resource servers the access token is restricted to, they are set as its audience.
+	// The audience is the client when it's not restricted to any.
+	Resources []string
+	// AuthorizationDetails is the authorization details the user approved.
+	AuthorizationDetails []AuthorizationDetail
+}
+
+// Authentication tells how and when a user authenticated to the sso.
+type Authentication struct {
+	// Time is the time the user authenticated at.
+	Time time.Time `json:"auth_time,omitempty"`
+	// Methods is the list of methods the user authenticated with, like pwd, otp or fed.
+	Methods []string `json:"amr,omitempty"`
+	// SessionID is the id of the sso session the user authenticated in, it's set as the sid claim.
+	SessionID string `json:"sid,omitempty"`
+}
+
+// ACR returns the authentication context class reference the authentication satisfies.
+func (a Authentication) ACR() string {
+	if len(a.Methods) == 0 {
+		return ""
+	}
+	for _, method := range a.Methods {
+		if method == constant.AMRFederated {
+			return constant.ACRFederated
+		}
+	}
+	return constant.ACRSingleFactor
+}
+
 type AccessTokenRequest struct {
 	// GrantType is the type of flow the client is following to get access token.
-	// It can be either authorization_code or refresh_token.
+	// It can be authorization_code, refresh_token, client_credentials or urn:ietf:params:oauth:grant-type:device_code.
 	GrantType string `json:"grant_type" form:"grant_type"`
 	// Authorization code generated by the authorization server.
 	Code string `json:"code" form:"code"`
@@ -61,14 +165,46 @@ type AccessTokenRequest struct {
 	// RefreshToken is the opaque string that was given by the auth server when issuing the access token.
 	// it's used to refresh the access token.
 	RefreshToken string `json:"refresh_token"`
+	// CodeVerifier is the PKCE secret the code challenge of the authorization request was derived from.
+	CodeVerifier string `json:"code_verifier" form:"code_verifier"`
+	// ClientID is the id of the client, public clients use it to identify themselves.
+	ClientID string `json:"client_id" form:"client_id"`
+	// Scope is the space-delimited list of scopes requested on the client_credentials grant.
+	// It defaults to the scopes registered for the client.
+	Scope string `json:"scope" form:"scope"`
+	// DeviceCode is the device code issued by the device authorization endpoint.
+	DeviceCode string `json:"device_code" form:"device_code"`
+	// SubjectToken is the access token the token exchange grant issues a token on behalf of.
+	SubjectToken string `json:"subject_token" form:"subject_token"`
+	// SubjectTokenType is the type of the subject token, only access tokens can be exchanged.
+	SubjectTokenType string `json:"subject_token_type" form:"subject_token_type"`
+	// ActorToken is the access token of the party acting on behalf of the subject, it defaults to the client.
+	ActorToken string `json:"actor_token" form:"actor_token"`
+	// ActorTokenType is the type of the actor token, it's required if an actor token is sent.
+	ActorTokenType string `json:"actor_token_type" form:"actor_token_type"`
+	// RequestedTokenType is the type of token requested on the token exchange grant, only access tokens are issued.
+	RequestedTokenType string `json:"requested_token_type" form:"requested_token_type"`
+	// Audience is the name of the resource server the exchanged token is to be used at.
+	Audience string `json:"audience" form:"audience"`
+	// Resource is the names of the resource servers the access token is requested for,
+	// the token is restricted to them and its scope to their scopes.
+	Resource []string `json:"resource" form:"resource"`
 }
 
 func (a AccessTokenRequest) Validate() error {
 	return validation.ValidateStruct(&a,
 		validation.Field(&a.Code, validation.When(a.GrantType == constant.AuthorizationCode, validation.Required.Error("code is required"))),
 		validation.Field(&a.RedirectURI, validation.When(a.GrantType == constant.AuthorizationCode, validation.Required.Error("redirect_uri is required"))),
-		validation.Field(&a.GrantType, validation.Required.Error("grant_type is required"), validation.In(constant.AuthorizationCode, constant.RefreshToken)),
+		validation.Field(&a.GrantType, validation.Required.Error("grant_type is required"), validation.In(constant.AuthorizationCode, constant.RefreshToken, constant.ClientCredentials, constant.DeviceCode, constant.TokenExchange).Error("unsupported grant_type")),
 		validation.Field(&a.RefreshToken, validation.When(a.GrantType == constant.RefreshToken, validation.Required.Error("refresh_token is required"))),
+		validation.Field(&a.DeviceCode, validation.When(a.GrantType == constant.DeviceCode, validation.Required.Error("device_code is required"))),
+		validation.Field(&a.SubjectToken, validation.When(a.GrantType == constant.TokenExchange, validation.Required.Error("subject_token is required"))),
+		validation.Field(&a.SubjectTokenType, validation.When(a.GrantType == constant.TokenExchange, validation.Required.Error("subject_token_type is required"), validation.In(constant.AccessTokenTypeURI).Error("unsupported subject_token_type"))),
+		validation.Field(&a.ActorTokenType, validation.When(a.ActorToken != "", validation.Required.Error("actor_token_type is required"), validation.In(constant.AccessTokenTypeURI).Error("unsupported actor_token_type"))),
+		validation.Field(&a.RequestedTokenType, validation.In(constant.AccessTokenTypeURI).Error("unsupported requested_token_type")),
+		validation.Field(&a.Audience, validation.When(a.GrantType == constant.TokenExchange, validation.Required.Error("audience is required"))),
+		validation.Field(&a.CodeVerifier, validation.Length(43, 128).Error("code_verifier must be between 43 and 128 characters")),
+		validation.Field(&a.Resource, validation.Each(validation.Required.Error("resource must not be empty"))),
 	)
 }
 
@@ -86,6 +222,8 @@ type RefreshToken struct {
 	ClientID uuid.UUID `json:"client_id"`
 	// Scope is the scope the client is authorized to access.
 	Scope string `json:"scope"`
+	// RequestedScope is the scope the client requested, the user may have granted only part of it.
+	RequestedScope string `json:"requested_scope,omitempty"`
 	// RedirectUri is the list of redirect uri of the client.
 	RedirectUri string `json:"redirect_uri"`
 	// ExpiresAt is time the refresh token is going to be expired.
@@ -93,6 +231,11 @@ type RefreshToken struct {
 	// CreatedAt is the time when the refresh token is created.
 	// It is automatically set when the refresh token is created.
 	CreatedAt time.Time `json:"created_at"`
+	// UpdatedAt is the time the refresh token was last rotated at, or created at if it never was.
+	UpdatedAt time.Time `json:"updated_at"`
+	// Authentication is how and when the user authenticated on the grant of the refresh token,
+	// the id tokens issued on refresh carry it.
+	Authentication Authentication `json:"authentication,omitempty"`
 }
 
 type InternalRefreshToken struct {
@@ -115,6 +258,8 @@ type InternalRefreshToken struct {
 	CreatedAt time.Time `json:"created_at"`
 	// UpdatedAt is the time this refresh token was last updated at
 	UpdatedAt time.Time `json:"updated_at"`
+	// Authentication is how and when the user authenticated to start this session.
+	Authentication Authentication `json:"authentication"`
 }
 
 // InternalRefreshTokenRequest
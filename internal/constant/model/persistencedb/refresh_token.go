@@ -0,0 +1,131 @@
+package persistencedb
+
+import (
+	"context"
+	"database/sql"
+
+	"sso/internal/constant"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+
+	"github.com/google/uuid"
+)
+
+// RotateRefreshTokenTX replaces the refresh token of a family with a new one
+// and keeps the old token as superseded so that its reuse can be detected.
+func (q *PersistenceDB) RotateRefreshTokenTX(ctx context.Context, newRefreshToken, oldRefreshToken string) (db.RefreshToken, error) {
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return db.RefreshToken{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+	query := q.Queries.WithTx(tx)
+
+	refreshToken, err := query.UpdateOAuthRefreshToken(ctx, db.UpdateOAuthRefreshTokenParams{
+		RefreshToken:   newRefreshToken,
+		RefreshToken_2: oldRefreshToken,
+	})
+	if err != nil {
+		return db.RefreshToken{}, err
+	}
+
+	if err := query.SaveSupersededRefreshToken(ctx, db.SaveSupersededRefreshTokenParams{
+		RefreshToken: oldRefreshToken,
+		FamilyID:     refreshToken.ID,
+	}); err != nil {
+		return db.RefreshToken{}, err
+	}
+
+	return refreshToken, tx.Commit(ctx)
+}
+
+// RevokeRefreshTokenFamilyTX deletes a refresh token family along with its superseded tokens
+// and records the revocation in the auth history.
+func (q *PersistenceDB) RevokeRefreshTokenFamilyTX(ctx context.Context, family dto.RefreshToken) error {
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+	query := q.Queries.WithTx(tx)
+
+	if err := query.RemoveRefreshToken(ctx, family.RefreshToken); err != nil {
+		return err
+	}
+
+	if _, err := query.CreateAuthHistory(ctx, db.CreateAuthHistoryParams{
+		Code:        family.Code,
+		UserID:      uuid.NullUUID{UUID: family.UserID, Valid: true},
+		Scope:       sql.NullString{String: family.Scope, Valid: family.Scope != ""},
+		RedirectUri: sql.NullString{String: family.RedirectUri, Valid: family.RedirectUri != ""},
+		ClientID:    family.ClientID,
+		Status:      constant.Revoke,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RotateInternalRefreshTokenTX replaces the internal refresh token of a family with a new one
+// and keeps the old token as superseded so that its reuse can be detected.
+func (q *PersistenceDB) RotateInternalRefreshTokenTX(ctx context.Context, newRefreshToken, oldRefreshToken string) (db.Internalrefreshtoken, error) {
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return db.Internalrefreshtoken{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+	query := q.Queries.WithTx(tx)
+
+	refreshToken, err := query.UpdateInternalRefreshToken(ctx, db.UpdateInternalRefreshTokenParams{
+		RefreshToken:   oldRefreshToken,
+		RefreshToken_2: newRefreshToken,
+	})
+	if err != nil {
+		return db.Internalrefreshtoken{}, err
+	}
+
+	if err := query.SaveSupersededInternalRefreshToken(ctx, db.SaveSupersededInternalRefreshTokenParams{
+		RefreshToken: oldRefreshToken,
+		FamilyID:     refreshToken.ID,
+	}); err != nil {
+		return db.Internalrefreshtoken{}, err
+	}
+
+	return refreshToken, tx.Commit(ctx)
+}
+
+// RevokeInternalRefreshTokenFamilyTX deletes an internal refresh token family along with its superseded tokens
+// and records the revocation in the auth history.
+// Internal refresh tokens are issued by the sso itself, so the history is recorded against the nil client id.
+func (q *PersistenceDB) RevokeInternalRefreshTokenFamilyTX(ctx context.Context, family dto.InternalRefreshToken) error {
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+	query := q.Queries.WithTx(tx)
+
+	if err := query.RemoveInternalRefreshToken(ctx, family.RefreshToken); err != nil {
+		return err
+	}
+
+	if _, err := query.CreateAuthHistory(ctx, db.CreateAuthHistoryParams{
+		Code:     family.ID.String(),
+		UserID:   uuid.NullUUID{UUID: family.UserID, Valid: true},
+		ClientID: uuid.Nil,
+		Status:   constant.Revoke,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
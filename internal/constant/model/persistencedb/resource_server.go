@@ -145,3 +145,41 @@ func (p *PersistenceDB) GetAllResourceServers(ctx context.Context, pgnFlt db_pgn
 	}
 	return servers, totalCount - reducer, nil
 }
+
+func (db *PersistenceDB) SetAuthorizationDetailTypesWithTX(ctx context.Context, rsID uuid.UUID, types []dto.AuthorizationDetailType) ([]dto.AuthorizationDetailType, error) {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func(tx pgx.Tx) {
+		_ = tx.Rollback(ctx)
+	}(tx)
+
+	query := db.Queries.WithTx(tx)
+	if err := query.DeleteAuthorizationDetailTypesByResourceServerID(ctx, rsID); err != nil {
+		return nil, err
+	}
+
+	detailTypes := make([]dto.AuthorizationDetailType, 0, len(types))
+	for i := 0; i < len(types); i++ {
+		detailType, err := query.CreateAuthorizationDetailType(ctx, db2.CreateAuthorizationDetailTypeParams{
+			Type:             types[i].Type,
+			Description:      types[i].Description,
+			ResourceServerID: rsID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		detailTypes = append(detailTypes, dto.AuthorizationDetailType{
+			ID:          detailType.ID,
+			Type:        detailType.Type,
+			Description: detailType.Description,
+			CreatedAt:   detailType.CreatedAt,
+		})
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return detailTypes, nil
+}
@@ -66,6 +66,11 @@ var (
 		Name:     "get all resource servers",
 		Category: "resource_server",
 	}
+	RotateResourceServerSecret = Permission{
+		ID:       "rotate_resource_server_secret",
+		Name:     "rotate the secret of a resource server",
+		Category: "resource_server",
+	}
 	GetClient = Permission{
 		ID:       "get_client",
 		Name:     "get a client",
@@ -76,6 +81,11 @@ var (
 		Name:     "update a client",
 		Category: "client",
 	}
+	RotateClientSecret = Permission{
+		ID:       "rotate_client_secret",
+		Name:     "rotate the secret of a client",
+		Category: "client",
+	}
 	GetAllPermissions = Permission{
 		ID:       "get_all_permissions",
 		Name:     "Get all permissions",
@@ -176,4 +186,24 @@ var (
 		Name:     "delete user",
 		Category: "user",
 	}
+	GetAllSigningKeys = Permission{
+		ID:       "get_all_signing_keys",
+		Name:     "get all signing keys",
+		Category: "signing_key",
+	}
+	RotateSigningKey = Permission{
+		ID:       "rotate_signing_key",
+		Name:     "rotate the signing key",
+		Category: "signing_key",
+	}
+	RetireSigningKey = Permission{
+		ID:       "retire_signing_key",
+		Name:     "retire a signing key",
+		Category: "signing_key",
+	}
+	IssueInitialAccessToken = Permission{
+		ID:       "issue_initial_access_token",
+		Name:     "issue an initial access token for client registration",
+		Category: "client",
+	}
 )
@@ -0,0 +1,20 @@
+-- name: CreateAuthorizationDetailType :one
+INSERT INTO authorization_detail_types (
+    type,
+    description,
+    resource_server_id
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: DeleteAuthorizationDetailTypesByResourceServerID :exec
+DELETE
+FROM authorization_detail_types
+WHERE resource_server_id = $1;
+
+-- name: GetAuthorizationDetailTypes :many
+SELECT authorization_detail_types.*, resource_servers.name AS resource_server_name
+FROM authorization_detail_types
+         JOIN resource_servers ON resource_servers.id = authorization_detail_types.resource_server_id
+WHERE authorization_detail_types.type = ANY (@types::varchar[]);
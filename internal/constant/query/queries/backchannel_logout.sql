@@ -0,0 +1,12 @@
+-- name: CreateBackchannelLogoutDelivery :one
+INSERT INTO backchannel_logout_deliveries (
+    client_id,
+    user_id,
+    session_id,
+    status,
+    attempts,
+    error
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
@@ -5,9 +5,26 @@ INSERT INTO clients (
     redirect_uris,
     scopes,
     secret,
-    logo_url
+    logo_url,
+    require_pkce,
+    response_types,
+    id_token_signed_response_alg,
+    grant_types,
+    token_endpoint_auth_method,
+    jwks,
+    tls_client_certificate_thumbprint,
+    require_pushed_authorization_requests,
+    backchannel_logout_uri,
+    frontchannel_logout_uri,
+    access_token_lifetime,
+    refresh_token_lifetime,
+    refresh_token_idle_lifetime,
+    refresh_token_absolute_lifetime,
+    id_token_lifetime,
+    auth_code_lifetime,
+    optional_scopes
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23
 ) RETURNING *;
 
 -- name: DeleteClient :one
@@ -25,7 +42,11 @@ SET
  scopes = coalesce(sqlc.narg('scopes'), scopes),
  secret = coalesce(sqlc.narg('secret'), secret),
  logo_url = coalesce(sqlc.narg('logo_url'), logo_url),
- status = coalesce(sqlc.narg('status'), status)
+ status = coalesce(sqlc.narg('status'), status),
+ require_pkce = coalesce(sqlc.narg('require_pkce'), require_pkce),
+ response_types = coalesce(sqlc.narg('response_types'), response_types),
+ id_token_signed_response_alg = coalesce(sqlc.narg('id_token_signed_response_alg'), id_token_signed_response_alg),
+ grant_types = coalesce(sqlc.narg('grant_types'), grant_types)
 WHERE id = sqlc.arg('id')
 RETURNING *;
 
@@ -36,7 +57,32 @@ SET
  client_type = $3,
  redirect_uris = $4,
  scopes = $5,
- logo_url = $6
+ logo_url = $6,
+ require_pkce = $7,
+ response_types = $8,
+ id_token_signed_response_alg = $9,
+ grant_types = $10,
+ token_endpoint_auth_method = $11,
+ jwks = $12,
+ tls_client_certificate_thumbprint = $13,
+ require_pushed_authorization_requests = $14,
+ backchannel_logout_uri = $15,
+ frontchannel_logout_uri = $16,
+ access_token_lifetime = $17,
+ refresh_token_lifetime = $18,
+ refresh_token_idle_lifetime = $19,
+ refresh_token_absolute_lifetime = $20,
+ id_token_lifetime = $21,
+ auth_code_lifetime = $22,
+ optional_scopes = $23
 WHERE id = $1
 RETURNING *;
 
+-- name: RotateClientSecret :one
+UPDATE clients
+SET
+ previous_secret = secret,
+ previous_secret_expires_at = $2,
+ secret = $3
+WHERE id = $1
+RETURNING id;
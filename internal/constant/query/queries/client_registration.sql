@@ -0,0 +1,19 @@
+-- name: CreateInitialAccessToken :one
+INSERT INTO initial_access_tokens (token_hash, created_by, expires_at, scopes, grant_types)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetInitialAccessToken :one
+SELECT *
+FROM initial_access_tokens
+WHERE token_hash = $1;
+
+-- name: CreateClientRegistrationToken :one
+INSERT INTO client_registration_tokens (client_id, token_hash)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetClientRegistrationToken :one
+SELECT *
+FROM client_registration_tokens
+WHERE client_id = $1;
@@ -4,9 +4,11 @@ INSERT INTO internalrefreshtokens (
     user_id,
     refresh_token,
     ip_address,
-    user_agent
+    user_agent,
+    auth_time,
+    amr
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6, $7
 )
 RETURNING *;
 
@@ -26,4 +28,12 @@ Update internalrefreshtokens set expires_at = $2, refresh_token= $3 WHERE id= $1
 DELETE FROM internalrefreshtokens WHERE id = $1;
 
 -- name: UpdateInternalRefreshToken :one
-UPDATE internalrefreshtokens SET refresh_token=$2, updated_at=now() WHERE refresh_token=$1 RETURNING *;
\ No newline at end of file
+UPDATE internalrefreshtokens SET refresh_token=$2, updated_at=now() WHERE refresh_token=$1 RETURNING *;
+
+-- name: SaveSupersededInternalRefreshToken :exec
+INSERT INTO superseded_internalrefreshtokens (refresh_token, family_id) VALUES ($1, $2);
+
+-- name: GetInternalRefreshTokenBySupersededToken :one
+SELECT internalrefreshtokens.* FROM superseded_internalrefreshtokens
+JOIN internalrefreshtokens ON superseded_internalrefreshtokens.family_id = internalrefreshtokens.id
+WHERE superseded_internalrefreshtokens.refresh_token = $1;
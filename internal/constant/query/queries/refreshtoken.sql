@@ -5,8 +5,11 @@ INSERT INTO refresh_tokens (expires_at,
                             redirect_uri,
                             client_id,
                             refresh_token,
-                            code)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+                            code,
+                            requested_scope,
+                            auth_time,
+                            amr)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 RETURNING *;
 
 -- name: RemoveRefreshTokenByCode :exec
@@ -22,6 +25,14 @@ WHERE refresh_token = $1;
 -- name: GetRefreshTokenByUserIDAndClientID :one
 SELECT *
 FROM refresh_tokens
+WHERE user_id = $1
+  AND client_id = $2
+ORDER BY updated_at DESC
+LIMIT 1;
+
+-- name: RemoveRefreshTokensByUserIDAndClientID :exec
+DELETE
+FROM refresh_tokens
 WHERE user_id = $1
   AND client_id = $2;
 
@@ -31,7 +42,8 @@ FROM refresh_tokens
 WHERE refresh_token = $1;
 
 -- name: GetAuthorizedClientsForUser :many
-SELECT refresh_tokens.scope,
+SELECT DISTINCT ON (clients.id) refresh_tokens.scope,
+       refresh_tokens.requested_scope,
        refresh_tokens.expires_at,
        refresh_tokens.created_at,
        refresh_tokens.updated_at,
@@ -42,10 +54,11 @@ SELECT refresh_tokens.scope,
 FROM refresh_tokens
          JOIN clients ON refresh_tokens.client_id = clients.id
 WHERE user_id = $1
-  AND refresh_tokens.scope NOT ILIKE 'openid';
+  AND refresh_tokens.scope NOT ILIKE 'openid'
+ORDER BY clients.id, refresh_tokens.updated_at DESC;
 
 -- name: GetOpenIDAuthorizedClientsForUser :many
-SELECT refresh_tokens.scope,
+SELECT DISTINCT ON (clients.id) refresh_tokens.scope,
        refresh_tokens.expires_at,
        refresh_tokens.created_at,
        refresh_tokens.updated_at,
@@ -56,10 +69,36 @@ SELECT refresh_tokens.scope,
 FROM refresh_tokens
          JOIN clients ON refresh_tokens.client_id = clients.id
 WHERE user_id = $1
-  AND refresh_tokens.scope ILIKE '%openid%';
+  AND refresh_tokens.scope ILIKE '%openid%'
+ORDER BY clients.id, refresh_tokens.updated_at DESC;
+
+-- name: GetLogoutClientsForUser :many
+SELECT DISTINCT clients.id,
+       clients.backchannel_logout_uri,
+       clients.frontchannel_logout_uri,
+       clients.id_token_signed_response_alg
+FROM refresh_tokens
+         JOIN clients ON refresh_tokens.client_id = clients.id
+WHERE user_id = $1
+  AND refresh_tokens.expires_at > now()
+  AND (clients.refresh_token_idle_lifetime <= 0
+    OR refresh_tokens.updated_at + make_interval(secs => clients.refresh_token_idle_lifetime) > now())
+  AND (clients.refresh_token_absolute_lifetime <= 0
+    OR refresh_tokens.created_at + make_interval(secs => clients.refresh_token_absolute_lifetime) > now())
+  AND (clients.backchannel_logout_uri != '' OR clients.frontchannel_logout_uri != '');
 
 -- name: UpdateOAuthRefreshToken :one
 UPDATE refresh_tokens
 SET refresh_token = $1, updated_at = now()
 WHERE refresh_token = $2
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: SaveSupersededRefreshToken :exec
+INSERT INTO superseded_refresh_tokens (refresh_token, family_id)
+VALUES ($1, $2);
+
+-- name: GetRefreshTokenBySupersededToken :one
+SELECT refresh_tokens.*
+FROM superseded_refresh_tokens
+         JOIN refresh_tokens ON superseded_refresh_tokens.family_id = refresh_tokens.id
+WHERE superseded_refresh_tokens.refresh_token = $1;
@@ -17,4 +17,14 @@ WHERE name = $1;
 -- name: GetResourceServerByID :one
 SELECT *
 FROM resource_servers
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: RotateResourceServerSecret :one
+UPDATE resource_servers
+SET
+ previous_secret = secret,
+ previous_secret_expires_at = $2,
+ secret = $3,
+ updated_at = now()
+WHERE id = $1
+RETURNING id;
@@ -23,4 +23,7 @@ UPDATE scopes
 SET 
  description = $2
 WHERE name = $1
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: GetScopeNamesByStatus :many
+SELECT name FROM scopes WHERE status = $1;
@@ -0,0 +1,20 @@
+-- name: CreateSigningKey :one
+INSERT INTO signing_keys (kid, algorithm, private_key, public_key, activates_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetAllSigningKeys :many
+SELECT *
+FROM signing_keys
+ORDER BY activates_at DESC;
+
+-- name: GetSigningKeyByKid :one
+SELECT *
+FROM signing_keys
+WHERE kid = $1;
+
+-- name: RetireSigningKey :one
+UPDATE signing_keys
+SET retires_at = $2
+WHERE kid = $1
+RETURNING *;
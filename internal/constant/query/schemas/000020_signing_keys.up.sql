@@ -0,0 +1,11 @@
+CREATE TABLE signing_keys
+(
+    id           uuid PRIMARY KEY     DEFAULT gen_random_uuid(),
+    kid          varchar     NOT NULL UNIQUE,
+    algorithm    varchar     NOT NULL,
+    private_key  varchar     NOT NULL,
+    public_key   varchar     NOT NULL,
+    activates_at timestamptz NOT NULL DEFAULT now(),
+    retires_at   timestamptz,
+    created_at   timestamptz NOT NULL DEFAULT now()
+);
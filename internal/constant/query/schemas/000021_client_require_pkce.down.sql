@@ -0,0 +1,2 @@
+ALTER TABLE clients
+    DROP COLUMN require_pkce;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE clients
+    ADD COLUMN require_pkce bool NOT NULL default false;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DELETE FROM auth_histories WHERE user_id IS NULL;
+ALTER TABLE auth_histories
+    ALTER COLUMN user_id SET NOT NULL;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE auth_histories
+    ALTER COLUMN user_id DROP NOT NULL;
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS superseded_internalrefreshtokens;
+DROP TABLE IF EXISTS superseded_refresh_tokens;
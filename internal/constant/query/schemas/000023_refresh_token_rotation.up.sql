@@ -0,0 +1,13 @@
+CREATE TABLE superseded_refresh_tokens (
+    refresh_token varchar(255) PRIMARY KEY,
+    family_id UUID NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT superseded_refresh_tokens_family_id_fkey FOREIGN KEY (family_id) REFERENCES refresh_tokens (id) ON DELETE CASCADE
+);
+
+CREATE TABLE superseded_internalrefreshtokens (
+    refresh_token varchar(255) PRIMARY KEY,
+    family_id UUID NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT superseded_internalrefreshtokens_family_id_fkey FOREIGN KEY (family_id) REFERENCES internalrefreshtokens (id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE clients
+    DROP COLUMN response_types;
\ No newline at end of file
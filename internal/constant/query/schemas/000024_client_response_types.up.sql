@@ -0,0 +1,2 @@
+ALTER TABLE clients
+    ADD COLUMN response_types varchar NOT NULL default 'code';
\ No newline at end of file
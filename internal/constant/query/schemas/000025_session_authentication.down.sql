@@ -0,0 +1,3 @@
+ALTER TABLE internalrefreshtokens
+    DROP COLUMN auth_time,
+    DROP COLUMN amr;
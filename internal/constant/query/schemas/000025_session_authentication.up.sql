@@ -0,0 +1,3 @@
+ALTER TABLE internalrefreshtokens
+    ADD COLUMN auth_time timestamptz NOT NULL default now(),
+    ADD COLUMN amr varchar NOT NULL default '';
@@ -0,0 +1,2 @@
+ALTER TABLE clients
+    DROP COLUMN id_token_signed_response_alg;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE clients
+    ADD COLUMN id_token_signed_response_alg varchar NOT NULL default 'PS512';
\ No newline at end of file
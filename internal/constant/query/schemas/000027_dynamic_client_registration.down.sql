@@ -0,0 +1,5 @@
+DROP TABLE client_registration_tokens;
+DROP TABLE initial_access_tokens;
+
+ALTER TABLE clients
+    DROP COLUMN grant_types;
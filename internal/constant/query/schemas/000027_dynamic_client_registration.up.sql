@@ -0,0 +1,18 @@
+ALTER TABLE clients
+    ADD COLUMN grant_types varchar NOT NULL default '';
+
+CREATE TABLE initial_access_tokens
+(
+    id         uuid PRIMARY KEY     DEFAULT gen_random_uuid(),
+    token_hash varchar     NOT NULL UNIQUE,
+    created_by uuid        NOT NULL,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE client_registration_tokens
+(
+    client_id  uuid PRIMARY KEY REFERENCES clients (id) ON DELETE CASCADE,
+    token_hash varchar     NOT NULL UNIQUE,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
@@ -0,0 +1,7 @@
+ALTER TABLE resource_servers
+    DROP COLUMN previous_secret,
+    DROP COLUMN previous_secret_expires_at;
+
+ALTER TABLE clients
+    DROP COLUMN previous_secret,
+    DROP COLUMN previous_secret_expires_at;
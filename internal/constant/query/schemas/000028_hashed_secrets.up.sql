@@ -0,0 +1,19 @@
+ALTER TABLE clients
+    ADD COLUMN previous_secret varchar NOT NULL default '',
+    ADD COLUMN previous_secret_expires_at timestamptz;
+
+ALTER TABLE resource_servers
+    ADD COLUMN previous_secret varchar NOT NULL default '',
+    ADD COLUMN previous_secret_expires_at timestamptz;
+
+UPDATE clients
+SET secret = concat(sha256(concat(salts.salt, clients.secret)), '.', salts.salt)
+FROM (SELECT id, substr(md5(random()::string), 1, 16) AS salt FROM clients) AS salts
+WHERE clients.id = salts.id
+  AND clients.secret != '';
+
+UPDATE resource_servers
+SET secret = concat(sha256(concat(salts.salt, resource_servers.secret)), '.', salts.salt)
+FROM (SELECT id, substr(md5(random()::string), 1, 16) AS salt FROM resource_servers) AS salts
+WHERE resource_servers.id = salts.id
+  AND resource_servers.secret != '';
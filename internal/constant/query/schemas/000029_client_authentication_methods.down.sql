@@ -0,0 +1,4 @@
+ALTER TABLE clients
+    DROP COLUMN token_endpoint_auth_method,
+    DROP COLUMN jwks,
+    DROP COLUMN tls_client_certificate_thumbprint;
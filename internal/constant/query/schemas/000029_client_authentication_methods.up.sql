@@ -0,0 +1,8 @@
+ALTER TABLE clients
+    ADD COLUMN token_endpoint_auth_method varchar NOT NULL default 'client_secret_basic',
+    ADD COLUMN jwks varchar NOT NULL default '',
+    ADD COLUMN tls_client_certificate_thumbprint varchar NOT NULL default '';
+
+UPDATE clients
+SET token_endpoint_auth_method = 'none'
+WHERE client_type = 'public';
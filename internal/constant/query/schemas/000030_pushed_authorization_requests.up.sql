@@ -0,0 +1,2 @@
+ALTER TABLE clients
+    ADD COLUMN require_pushed_authorization_requests bool NOT NULL default false;
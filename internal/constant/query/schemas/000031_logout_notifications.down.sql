@@ -0,0 +1,6 @@
+DROP TABLE backchannel_logout_deliveries;
+
+ALTER TABLE clients
+    DROP COLUMN backchannel_logout_uri;
+ALTER TABLE clients
+    DROP COLUMN frontchannel_logout_uri;
@@ -0,0 +1,16 @@
+ALTER TABLE clients
+    ADD COLUMN backchannel_logout_uri varchar NOT NULL default '';
+ALTER TABLE clients
+    ADD COLUMN frontchannel_logout_uri varchar NOT NULL default '';
+
+CREATE TABLE backchannel_logout_deliveries
+(
+    id         uuid PRIMARY KEY     DEFAULT gen_random_uuid(),
+    client_id  uuid        NOT NULL REFERENCES clients (id) ON DELETE CASCADE,
+    user_id    uuid        NOT NULL,
+    session_id varchar     NOT NULL DEFAULT '',
+    status     varchar     NOT NULL,
+    attempts   int         NOT NULL DEFAULT 0,
+    error      varchar     NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now()
+);
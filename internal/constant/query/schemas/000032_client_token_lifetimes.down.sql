@@ -0,0 +1,12 @@
+ALTER TABLE clients
+    DROP COLUMN access_token_lifetime;
+ALTER TABLE clients
+    DROP COLUMN refresh_token_lifetime;
+ALTER TABLE clients
+    DROP COLUMN refresh_token_idle_lifetime;
+ALTER TABLE clients
+    DROP COLUMN refresh_token_absolute_lifetime;
+ALTER TABLE clients
+    DROP COLUMN id_token_lifetime;
+ALTER TABLE clients
+    DROP COLUMN auth_code_lifetime;
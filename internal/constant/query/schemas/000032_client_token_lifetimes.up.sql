@@ -0,0 +1,12 @@
+ALTER TABLE clients
+    ADD COLUMN access_token_lifetime int NOT NULL default 0;
+ALTER TABLE clients
+    ADD COLUMN refresh_token_lifetime int NOT NULL default 0;
+ALTER TABLE clients
+    ADD COLUMN refresh_token_idle_lifetime int NOT NULL default 0;
+ALTER TABLE clients
+    ADD COLUMN refresh_token_absolute_lifetime int NOT NULL default 0;
+ALTER TABLE clients
+    ADD COLUMN id_token_lifetime int NOT NULL default 0;
+ALTER TABLE clients
+    ADD COLUMN auth_code_lifetime int NOT NULL default 0;
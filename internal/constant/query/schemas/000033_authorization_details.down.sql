@@ -0,0 +1 @@
+DROP TABLE authorization_detail_types;
@@ -0,0 +1,8 @@
+CREATE TABLE authorization_detail_types
+(
+    id                 uuid PRIMARY KEY     DEFAULT gen_random_uuid(),
+    type               varchar     NOT NULL UNIQUE,
+    description        varchar     NOT NULL,
+    resource_server_id uuid        NOT NULL REFERENCES resource_servers (id) ON DELETE CASCADE,
+    created_at         timestamptz NOT NULL DEFAULT now()
+);
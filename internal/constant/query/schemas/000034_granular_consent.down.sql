@@ -0,0 +1,4 @@
+ALTER TABLE clients
+    DROP COLUMN optional_scopes;
+ALTER TABLE refresh_tokens
+    DROP COLUMN requested_scope;
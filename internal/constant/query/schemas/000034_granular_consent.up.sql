@@ -0,0 +1,4 @@
+ALTER TABLE clients
+    ADD COLUMN optional_scopes varchar NOT NULL default '';
+ALTER TABLE refresh_tokens
+    ADD COLUMN requested_scope varchar(255);
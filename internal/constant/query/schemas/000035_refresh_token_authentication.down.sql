@@ -0,0 +1,4 @@
+ALTER TABLE refresh_tokens
+    DROP COLUMN auth_time;
+ALTER TABLE refresh_tokens
+    DROP COLUMN amr;
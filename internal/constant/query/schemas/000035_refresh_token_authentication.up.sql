@@ -0,0 +1,4 @@
+ALTER TABLE refresh_tokens
+    ADD COLUMN auth_time timestamptz;
+ALTER TABLE refresh_tokens
+    ADD COLUMN amr varchar(255);
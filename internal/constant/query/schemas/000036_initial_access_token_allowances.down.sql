@@ -0,0 +1,4 @@
+ALTER TABLE initial_access_tokens
+    DROP COLUMN grant_types;
+ALTER TABLE initial_access_tokens
+    DROP COLUMN scopes;
@@ -0,0 +1,4 @@
+ALTER TABLE initial_access_tokens
+    ADD COLUMN scopes varchar NOT NULL default '';
+ALTER TABLE initial_access_tokens
+    ADD COLUMN grant_types varchar NOT NULL default '';
@@ -5,9 +5,14 @@ import (
 )
 
 const (
-	ConsentKey   = "consent:%v"
-	AuthCodeKey  = "authcode:%v"
-	ResetCodeKey = "resetCode:%v"
+	ConsentKey         = "consent:%v"
+	AuthCodeKey        = "authcode:%v"
+	ResetCodeKey       = "resetCode:%v"
+	DeviceKey          = "device:%v"
+	UserCodeKey        = "userCode:%v"
+	RevokedTokenKey    = "revokedToken:%v"
+	ClientAssertionKey = "clientAssertion:%v:%v"
+	RequestObjectKey   = "requestObject:%v:%v"
 )
 
 const (
@@ -22,6 +27,8 @@ type URLs struct {
 	ErrorURL   *url.URL
 	ConsentURL *url.URL
 	LogoutURL  *url.URL
+	IssuerURL  *url.URL
+	DeviceURL  *url.URL
 }
 
 type UploadParams struct {
@@ -2,6 +2,7 @@ package client
 
 import (
 	"net/http"
+	"sso/internal/constant"
 	"sso/internal/constant/permissions"
 	"sso/internal/glue/routing"
 	"sso/internal/handler/middleware"
@@ -74,6 +75,67 @@ func InitRoute(group *gin.RouterGroup, client rest.Client, authMiddleware middle
 			},
 			Permission: permissions.UpdateClient,
 		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/:id/secret/rotate",
+			Handler: client.RotateClientSecret,
+			Middlewares: []gin.HandlerFunc{
+				authMiddleware.Authentication(),
+				authMiddleware.AccessControl(),
+			},
+			Permission: permissions.RotateClientSecret,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/initialAccessTokens",
+			Handler: client.IssueInitialAccessToken,
+			Middlewares: []gin.HandlerFunc{
+				authMiddleware.Authentication(),
+				authMiddleware.AccessControl(),
+			},
+			Permission: permissions.IssueInitialAccessToken,
+		},
 	}
 	routing.RegisterRoutes(clients, clientRoutes, enforcer)
+
+	registration := group.Group(constant.OAuth2BasePath + constant.RegistrationEndpoint)
+	registrationRoutes := []routing.Router{
+		{
+			Method:  http.MethodPost,
+			Path:    "",
+			Handler: client.RegisterClient,
+			Middlewares: []gin.HandlerFunc{
+				authMiddleware.InitialAccessTokenAuth(),
+			},
+			UnAuthorize: true,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/:client_id",
+			Handler: client.GetClientRegistration,
+			Middlewares: []gin.HandlerFunc{
+				authMiddleware.RegistrationAccessTokenAuth(),
+			},
+			UnAuthorize: true,
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/:client_id",
+			Handler: client.UpdateClientRegistration,
+			Middlewares: []gin.HandlerFunc{
+				authMiddleware.RegistrationAccessTokenAuth(),
+			},
+			UnAuthorize: true,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/:client_id",
+			Handler: client.DeleteClientRegistration,
+			Middlewares: []gin.HandlerFunc{
+				authMiddleware.RegistrationAccessTokenAuth(),
+			},
+			UnAuthorize: true,
+		},
+	}
+	routing.RegisterRoutes(registration, registrationRoutes, enforcer)
 }
@@ -2,6 +2,7 @@ package oauth2
 
 import (
 	"net/http"
+	"sso/internal/constant"
 	"sso/internal/glue/routing"
 	"sso/internal/handler/middleware"
 	"sso/internal/handler/rest"
@@ -11,11 +12,11 @@ import (
 )
 
 func InitRoute(group *gin.RouterGroup, handler rest.OAuth2, authMiddleware middleware.AuthMiddleware, enforcer *casbin.Enforcer) {
-	oauth2Group := group.Group("/oauth")
+	oauth2Group := group.Group(constant.OAuth2BasePath)
 	oauth2Routes := []routing.Router{
 		{
 			Method:      "GET",
-			Path:        "/authorize",
+			Path:        constant.AuthorizeEndpoint,
 			Handler:     handler.Authorize,
 			Middlewares: []gin.HandlerFunc{},
 			UnAuthorize: true,
@@ -47,16 +48,61 @@ func InitRoute(group *gin.RouterGroup, handler rest.OAuth2, authMiddleware middl
 		},
 		{
 			Method:  http.MethodPost,
-			Path:    "/token",
+			Path:    constant.TokenEndpoint,
 			Handler: handler.Token,
 			Middlewares: []gin.HandlerFunc{
-				authMiddleware.ClientBasicAuth(),
+				authMiddleware.ClientAuth(),
+			},
+			UnAuthorize: true,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    constant.DeviceAuthorizationEndpoint,
+			Handler: handler.DeviceAuthorization,
+			Middlewares: []gin.HandlerFunc{
+				authMiddleware.ClientAuth(),
+			},
+			UnAuthorize: true,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    constant.PushedAuthorizationEndpoint,
+			Handler: handler.PushAuthorizationRequest,
+			Middlewares: []gin.HandlerFunc{
+				authMiddleware.ClientAuth(),
+			},
+			UnAuthorize: true,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    constant.IntrospectionEndpoint,
+			Handler: handler.Introspect,
+			Middlewares: []gin.HandlerFunc{
+				authMiddleware.ClientOrResourceServerBasicAuth(),
+			},
+			UnAuthorize: true,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    constant.RevocationEndpoint,
+			Handler: handler.Revoke,
+			Middlewares: []gin.HandlerFunc{
+				authMiddleware.ClientAuth(),
+			},
+			UnAuthorize: true,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/verifyDevice",
+			Handler: handler.VerifyDevice,
+			Middlewares: []gin.HandlerFunc{
+				authMiddleware.Authentication(),
 			},
 			UnAuthorize: true,
 		},
 		{
 			Method:  http.MethodGet,
-			Path:    "/logout",
+			Path:    constant.LogoutEndpoint,
 			Handler: handler.Logout,
 			Middlewares: []gin.HandlerFunc{
 				authMiddleware.ClientBasicAuth(),
@@ -92,14 +138,40 @@ func InitRoute(group *gin.RouterGroup, handler rest.OAuth2, authMiddleware middl
 		},
 		{
 			Method:  http.MethodGet,
-			Path:    "/userinfo",
+			Path:    constant.UserInfoEndpoint,
 			Handler: handler.UserInfo,
 			Middlewares: []gin.HandlerFunc{
-				authMiddleware.Authentication(),
+				authMiddleware.AccessTokenAuthentication(),
 			},
 			UnAuthorize: true,
 		},
+		{
+			Method:      http.MethodGet,
+			Path:        constant.JWKSEndpoint,
+			Handler:     handler.JWKS,
+			UnAuthorize: true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        constant.CheckSessionEndpoint,
+			Handler:     handler.CheckSession,
+			UnAuthorize: true,
+		},
 	}
 	routing.RegisterRoutes(oauth2Group, oauth2Routes, enforcer)
 
 }
+
+// InitWellKnownRoute registers the discovery routes, which must be served relative to the issuer.
+func InitWellKnownRoute(router *gin.Engine, handler rest.OAuth2, enforcer *casbin.Enforcer) {
+	wellKnownGroup := router.Group(constant.WellKnownBasePath)
+	wellKnownRoutes := []routing.Router{
+		{
+			Method:      http.MethodGet,
+			Path:        constant.OpenIDConfigurationEndpoint,
+			Handler:     handler.OpenIDConfiguration,
+			UnAuthorize: true,
+		},
+	}
+	routing.RegisterRoutes(wellKnownGroup, wellKnownRoutes, enforcer)
+}
@@ -33,6 +33,16 @@ func InitRoute(group *gin.RouterGroup, resourceServer rest.ResourceServer, authM
 			},
 			Permission: permissions.GetAllResourceServers,
 		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/:id/secret/rotate",
+			Handler: resourceServer.RotateResourceServerSecret,
+			Middlewares: []gin.HandlerFunc{
+				authMiddleware.Authentication(),
+				authMiddleware.AccessControl(),
+			},
+			Permission: permissions.RotateResourceServerSecret,
+		},
 	}
 
 	routing.RegisterRoutes(resourceServers, resourceServerRoutes, enforcer)
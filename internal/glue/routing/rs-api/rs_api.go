@@ -30,6 +30,15 @@ func InitRoute(router *gin.RouterGroup, handler rest.RSAPI, authMiddleware middl
 			},
 			UnAuthorize: true,
 		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/authorization_detail_types",
+			Handler: handler.RegisterAuthorizationDetailTypes,
+			Middlewares: []gin.HandlerFunc{
+				authMiddleware.ResourceServerBasicAuth(),
+			},
+			UnAuthorize: true,
+		},
 	}
 
 	routing.RegisterRoutes(internal, internalRoutes, enforcer)
@@ -0,0 +1,49 @@
+package signing_key
+
+import (
+	"net/http"
+	"sso/internal/constant/permissions"
+	"sso/internal/glue/routing"
+	"sso/internal/handler/middleware"
+	"sso/internal/handler/rest"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/gin-gonic/gin"
+)
+
+func InitRoute(group *gin.RouterGroup, signingKey rest.SigningKey, authMiddleware middleware.AuthMiddleware, enforcer *casbin.Enforcer) {
+	signingKeys := group.Group("/signingKeys")
+	signingKeyRoutes := []routing.Router{
+		{
+			Method:  http.MethodGet,
+			Path:    "",
+			Handler: signingKey.GetAllSigningKeys,
+			Middlewares: []gin.HandlerFunc{
+				authMiddleware.Authentication(),
+				authMiddleware.AccessControl(),
+			},
+			Permission: permissions.GetAllSigningKeys,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/rotate",
+			Handler: signingKey.RotateSigningKey,
+			Middlewares: []gin.HandlerFunc{
+				authMiddleware.Authentication(),
+				authMiddleware.AccessControl(),
+			},
+			Permission: permissions.RotateSigningKey,
+		},
+		{
+			Method:  http.MethodPatch,
+			Path:    "/:kid/retire",
+			Handler: signingKey.RetireSigningKey,
+			Middlewares: []gin.HandlerFunc{
+				authMiddleware.Authentication(),
+				authMiddleware.AccessControl(),
+			},
+			Permission: permissions.RetireSigningKey,
+		},
+	}
+	routing.RegisterRoutes(signingKeys, signingKeyRoutes, enforcer)
+}
@@ -3,25 +3,33 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"time"
+
 	"sso/internal/constant"
 	"sso/internal/constant/errors"
+	"sso/internal/constant/model/dto"
 	"sso/internal/constant/permissions"
 	"sso/internal/module"
 	"sso/platform"
 	"sso/platform/logger"
+	"sso/platform/utils"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
 	"go.uber.org/zap"
 )
 
 type AuthMiddleware interface {
 	Authentication() gin.HandlerFunc
+	AccessTokenAuthentication() gin.HandlerFunc
 	AccessControl() gin.HandlerFunc
 	ClientBasicAuth() gin.HandlerFunc
+	ClientAuth() gin.HandlerFunc
 	MiniRideBasicAuth() gin.HandlerFunc
 	ResourceServerBasicAuth() gin.HandlerFunc
+	ClientOrResourceServerBasicAuth() gin.HandlerFunc
+	InitialAccessTokenAuth() gin.HandlerFunc
+	RegistrationAccessTokenAuth() gin.HandlerFunc
 }
 
 type MiniRideCredential struct {
@@ -32,6 +40,7 @@ type MiniRideCredential struct {
 type authMiddleware struct {
 	enforcer           *casbin.Enforcer
 	auth               module.OAuthModule
+	oauth2             module.OAuth2Module
 	token              platform.Token
 	client             module.ClientModule
 	miniRideCredential MiniRideCredential
@@ -41,10 +50,11 @@ type authMiddleware struct {
 }
 
 func InitAuthMiddleware(enforcer *casbin.Enforcer,
-	auth module.OAuthModule, token platform.Token, client module.ClientModule, miniRideCredential MiniRideCredential, role module.RoleModule, rsModule module.ResourceServerModule, logger logger.Logger) AuthMiddleware {
+	auth module.OAuthModule, oauth2 module.OAuth2Module, token platform.Token, client module.ClientModule, miniRideCredential MiniRideCredential, role module.RoleModule, rsModule module.ResourceServerModule, logger logger.Logger) AuthMiddleware {
 	return &authMiddleware{
 		enforcer,
 		auth,
+		oauth2,
 		token,
 		client,
 		miniRideCredential,
@@ -58,7 +68,7 @@ func (a *authMiddleware) Authentication() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		bearer := "Bearer "
 		authHeader := ctx.GetHeader("Authorization")
-		if authHeader == "" {
+		if len(authHeader) <= len(bearer) {
 			Err := errors.ErrInvalidToken.New("Unauthorized")
 			ctx.Error(Err)
 			ctx.Abort()
@@ -66,7 +76,7 @@ func (a *authMiddleware) Authentication() gin.HandlerFunc {
 		}
 
 		tokenString := authHeader[len(bearer):]
-		valid, claims := a.token.VerifyToken(jwt.SigningMethodPS512, tokenString)
+		valid, claims := a.token.VerifyToken(tokenString)
 		if !valid {
 			Err := errors.ErrAuthError.New("Unauthorized")
 			ctx.Error(Err)
@@ -74,6 +84,75 @@ func (a *authMiddleware) Authentication() gin.HandlerFunc {
 			return
 		}
 
+		revoked, err := a.oauth2.IsTokenRevoked(ctx.Request.Context(), claims.ID)
+		if err != nil {
+			ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+
+		if revoked {
+			Err := errors.ErrAuthError.New("Unauthorized")
+			ctx.Error(Err)
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		userStatus, err := a.auth.GetUserStatus(ctx.Request.Context(), claims.Subject)
+		if err != nil {
+			ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+
+		if userStatus != constant.Active {
+			Err := errors.ErrAuthError.Wrap(nil, "Your account has been deactivated, Please activate your account.")
+			ctx.Error(Err)
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		requestCtx := context.WithValue(ctx.Request.Context(), constant.Context("x-user-id"), claims.Subject)
+		requestCtx = context.WithValue(requestCtx, constant.Context("x-authentication"), claims.Authentication())
+		ctx.Request = ctx.Request.WithContext(requestCtx)
+		ctx.Next()
+	}
+}
+
+// AccessTokenAuthentication authenticates requests made with an access token issued to a client on behalf of a user.
+func (a *authMiddleware) AccessTokenAuthentication() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		bearer := "Bearer "
+		authHeader := ctx.GetHeader("Authorization")
+		if len(authHeader) <= len(bearer) {
+			Err := errors.ErrInvalidToken.New("Unauthorized")
+			ctx.Error(Err)
+			ctx.Abort()
+			return
+		}
+
+		tokenString := authHeader[len(bearer):]
+		valid, claims := a.token.VerifyAccessToken(tokenString)
+		if !valid || claims.Client() == "" || claims.Subject == claims.Client() {
+			Err := errors.ErrAuthError.New("Unauthorized")
+			ctx.Error(Err)
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		revoked, err := a.oauth2.IsTokenRevoked(ctx.Request.Context(), claims.ID)
+		if err != nil {
+			ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+
+		if revoked {
+			Err := errors.ErrAuthError.New("Unauthorized")
+			ctx.Error(Err)
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
 		userStatus, err := a.auth.GetUserStatus(ctx.Request.Context(), claims.Subject)
 		if err != nil {
 			ctx.Error(err)
@@ -91,6 +170,55 @@ func (a *authMiddleware) Authentication() gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// InitialAccessTokenAuth lets through the requests made with an initial access token an admin issued,
+// as the client registration endpoint requires.
+func (a *authMiddleware) InitialAccessTokenAuth() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		bearer := "Bearer "
+		authHeader := ctx.GetHeader("Authorization")
+		if len(authHeader) <= len(bearer) {
+			Err := errors.ErrInvalidToken.New("Unauthorized")
+			ctx.Error(Err)
+			ctx.Abort()
+			return
+		}
+
+		initialAccessToken, err := a.client.VerifyInitialAccessToken(ctx.Request.Context(), authHeader[len(bearer):])
+		if err != nil {
+			ctx.Error(err)
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), constant.Context("x-initial-access-token"), initialAccessToken))
+		ctx.Next()
+	}
+}
+
+// RegistrationAccessTokenAuth authenticates a dynamically registered client with the registration access token
+// it got when it registered, the client is read from the client_id path parameter.
+func (a *authMiddleware) RegistrationAccessTokenAuth() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		bearer := "Bearer "
+		authHeader := ctx.GetHeader("Authorization")
+		if len(authHeader) <= len(bearer) {
+			Err := errors.ErrInvalidToken.New("Unauthorized")
+			ctx.Error(Err)
+			ctx.Abort()
+			return
+		}
+
+		client, err := a.client.VerifyRegistrationAccessToken(ctx.Request.Context(), ctx.Param("client_id"), authHeader[len(bearer):])
+		if err != nil {
+			ctx.Error(err)
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), constant.Context("x-client"), client))
+		ctx.Next()
+	}
+}
+
 func (a *authMiddleware) AccessControl() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		requestCtx := ctx.Request.Context()
@@ -172,13 +300,69 @@ func (a *authMiddleware) ClientBasicAuth() gin.HandlerFunc {
 			return
 		}
 
-		if ok := client.Secret == secret; !ok {
-			err = errors.ErrAcessError.Wrap(err, "unauthorized_client")
-			a.logger.Info(ctx, "unauthorized_client", zap.Error(err), zap.String("client-secret", client.Secret), zap.String("provided-secret", secret))
+		if method := client.AuthenticationMethod(); method == constant.PrivateKeyJWT || method == constant.TLSClientAuth {
+			err = errors.ErrAcessError.New("unauthorized_client")
+			a.logger.Info(ctx, "client tried to authenticate with a secret instead of its registered method", zap.Error(err), zap.String("client-id", clientId), zap.String("method", method))
 			ctx.Error(err)
 			ctx.AbortWithStatus(http.StatusForbidden)
 			return
 		}
+
+		if !secretMatches(secret, client.Secret, client.PreviousSecret, client.PreviousSecretExpiresAt) {
+			err = errors.ErrAcessError.New("unauthorized_client")
+			a.logger.Info(ctx, "unauthorized_client", zap.Error(err), zap.String("client-id", clientId))
+			ctx.Error(err)
+			ctx.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), constant.Context("x-client"), client))
+		ctx.Next()
+	}
+}
+
+// ClientAuth authenticates confidential clients with basic auth, a private_key_jwt client assertion
+// or the certificate of the TLS connection, and lets public clients identify themselves with the client_id form parameter.
+func (a *authMiddleware) ClientAuth() gin.HandlerFunc {
+	clientBasicAuth := a.ClientBasicAuth()
+	return func(ctx *gin.Context) {
+		if _, _, ok := ctx.Request.BasicAuth(); ok {
+			clientBasicAuth(ctx)
+			return
+		}
+
+		var client *dto.Client
+		var err error
+		clientId := ctx.PostForm("client_id")
+		switch {
+		case ctx.PostForm("client_assertion") != "":
+			client, err = a.client.VerifyClientAssertion(ctx.Request.Context(), ctx.PostForm("client_assertion_type"), ctx.PostForm("client_assertion"))
+			if err == nil && clientId != "" && clientId != client.ID.String() {
+				err = errors.ErrAuthError.New("client_id does not match the client assertion")
+			}
+		case ctx.Request.TLS != nil && len(ctx.Request.TLS.PeerCertificates) > 0 && clientId != "":
+			client, err = a.client.VerifyClientCertificate(ctx.Request.Context(), clientId, ctx.Request.TLS.PeerCertificates[0])
+		case clientId != "":
+			client, err = a.client.GetClientByID(ctx.Request.Context(), clientId)
+			if err == nil && client.AuthenticationMethod() != constant.NoneAuthMethod {
+				err = errors.ErrAcessError.New("unauthorized_client")
+				a.logger.Info(ctx, "confidential client tried to authenticate without credentials", zap.Error(err), zap.String("client-id", clientId))
+			}
+		default:
+			err = errors.ErrAuthError.New("client authentication is required")
+			a.logger.Info(ctx, "no client credentials were provided", zap.Error(err))
+		}
+		if err != nil {
+			ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+
+		if client.Status != constant.Active {
+			Err := errors.ErrAuthError.Wrap(nil, "Your account has been deactivated, Please activate your account.")
+			ctx.Error(Err)
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
 		ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), constant.Context("x-client"), client))
 		ctx.Next()
 	}
@@ -225,12 +409,11 @@ func (a *authMiddleware) ResourceServerBasicAuth() gin.HandlerFunc {
 			return
 		}
 
-		if ok := rs.Secret == secret; !ok {
-			err = errors.ErrAcessError.Wrap(err, "unauthorized")
+		if !secretMatches(secret, rs.Secret, rs.PreviousSecret, rs.PreviousSecretExpiresAt) {
+			err = errors.ErrAcessError.New("unauthorized")
 			a.logger.Info(ctx, "resource server authentication failed. invalid secret!",
 				zap.Error(err),
-				zap.String("rs-secret", rs.Secret),
-				zap.String("provided-secret", secret))
+				zap.String("rs-id", rsID))
 			_ = ctx.Error(err)
 			ctx.Abort()
 			return
@@ -239,3 +422,36 @@ func (a *authMiddleware) ResourceServerBasicAuth() gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// ClientOrResourceServerBasicAuth authenticates the request as a client if the basic auth username is a client id,
+// otherwise as a resource server.
+func (a *authMiddleware) ClientOrResourceServerBasicAuth() gin.HandlerFunc {
+	clientBasicAuth := a.ClientBasicAuth()
+	resourceServerBasicAuth := a.ResourceServerBasicAuth()
+	return func(ctx *gin.Context) {
+		id, _, ok := ctx.Request.BasicAuth()
+		if !ok {
+			err := errors.ErrAcessError.New("could not extract credentials")
+			a.logger.Info(ctx, "client or resource server authentication failed", zap.Error(err))
+			_ = ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+
+		if _, err := a.client.GetClientByID(ctx.Request.Context(), id); err == nil {
+			clientBasicAuth(ctx)
+			return
+		}
+		resourceServerBasicAuth(ctx)
+	}
+}
+
+// secretMatches checks the secret against the hash of the current secret
+// and, during a rotation, against the hash of the previous one until it expires.
+func secretMatches(secret, secretHash, previousSecretHash string, previousSecretExpiresAt time.Time) bool {
+	if utils.CompareSecret(secretHash, secret) {
+		return true
+	}
+
+	return previousSecretHash != "" && time.Now().Before(previousSecretExpiresAt) && utils.CompareSecret(previousSecretHash, secret)
+}
@@ -137,6 +137,9 @@ func (c *client) GetAllClientByID(ctx *gin.Context) {
 		return
 	}
 
+	// only the hash of the secret is known after the client is created
+	client.Secret = ""
+
 	c.logger.Info(ctx, "client fetched", zap.Any("client-id", clientID))
 	constant.SuccessResponse(ctx, http.StatusOK, client, nil)
 }
@@ -207,3 +210,193 @@ func (c *client) UpdateClient(ctx *gin.Context) {
 	c.logger.Info(ctx, "client status changed", zap.Any("param", clientParam))
 	constant.SuccessResponse(ctx, http.StatusOK, nil, nil)
 }
+
+// RotateClientSecret replaces the secret of a client
+// @Summary      rotate client secret
+// @Description  generates a new secret for the client, the previous secret stays valid until previous_secret_expires_at.
+// @Description  the new secret is only returned in this response.
+// @Tags         client
+// @Accept       json
+// @Produce      json
+// @param id path string true "id"
+// @param request body dto.RotateSecretRequest true "request"
+// @Success      200  {object}  dto.RotatedSecret
+// @Failure      400  {object}  model.ErrorResponse
+// @Router       /clients/{id}/secret/rotate [post]
+// @Security	BearerAuth
+func (c *client) RotateClientSecret(ctx *gin.Context) {
+	request := dto.RotateSecretRequest{}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		c.logger.Info(ctx, "couldn't bind to dto.RotateSecretRequest body", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+
+	rotatedSecret, err := c.clientModule.RotateClientSecret(ctx.Request.Context(), ctx.Param("id"), request)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	constant.SuccessResponse(ctx, http.StatusOK, rotatedSecret, nil)
+}
+
+// IssueInitialAccessToken issues a token a client can register itself with.
+// @Summary      issue initial access token
+// @Description  issues an initial access token that lets a client register itself on the registration endpoint until it expires.
+// @Tags         client
+// @Accept       json
+// @Produce      json
+// @param request body dto.InitialAccessTokenRequest true "request"
+// @Success      201  {object}  dto.InitialAccessToken
+// @Failure      400  {object}  model.ErrorResponse
+// @Router       /clients/initialAccessTokens [post]
+// @Security	BearerAuth
+func (c *client) IssueInitialAccessToken(ctx *gin.Context) {
+	request := dto.InitialAccessTokenRequest{}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		c.logger.Info(ctx, "couldn't bind to dto.InitialAccessTokenRequest body", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+
+	token, err := c.clientModule.IssueInitialAccessToken(ctx.Request.Context(), request)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	constant.SuccessResponse(ctx, http.StatusCreated, token, nil)
+}
+
+// RegisterClient lets a client register itself as described on RFC 7591.
+// @Summary      dynamic client registration
+// @Description  registers a client with the given metadata, the request has to be made with an initial access token.
+// @Description  the client may only request the scopes and grant types the initial access token allows.
+// @Tags         client
+// @Accept       json
+// @Produce      json
+// @param metadata body dto.ClientRegistrationRequest true "metadata"
+// @Success      201  {object}  dto.ClientRegistrationResponse
+// @Failure      400  {object}  model.ErrorResponse "invalid client metadata"
+// @Failure      401  {object}  model.ErrorResponse "invalid initial access token"
+// @Router       /oauth/register [post]
+// @Security	BearerAuth
+func (c *client) RegisterClient(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	initialAccessToken, ok := requestCtx.Value(constant.Context("x-initial-access-token")).(*dto.InitialAccessToken)
+	if !ok {
+		err := errors.ErrAuthError.New("initial access token is required")
+		c.logger.Info(ctx, "no initial access token was found on the request context", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+
+	request := dto.ClientRegistrationRequest{}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid client metadata")
+		c.logger.Info(ctx, "couldn't bind to dto.ClientRegistrationRequest body", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+
+	resp, err := c.clientModule.RegisterClient(requestCtx, *initialAccessToken, request)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, resp)
+}
+
+// GetClientRegistration returns the registration of a client as described on RFC 7592.
+// @Summary      read client registration
+// @Description  returns the current metadata of the client, the request has to be made with the registration access token of the client.
+// @Tags         client
+// @Produce      json
+// @param client_id path string true "client_id"
+// @Success      200  {object}  dto.ClientRegistrationResponse
+// @Failure      401  {object}  model.ErrorResponse "invalid registration access token"
+// @Router       /oauth/register/{client_id} [get]
+// @Security	BearerAuth
+func (c *client) GetClientRegistration(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	registeredClient, ok := requestCtx.Value(constant.Context("x-client")).(*dto.Client)
+	if !ok {
+		err := errors.ErrAuthError.New("client authentication is required")
+		c.logger.Info(ctx, "no client was found on the request context", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.clientModule.GetClientRegistration(requestCtx, *registeredClient))
+}
+
+// UpdateClientRegistration replaces the metadata of a client as described on RFC 7592.
+// @Summary      update client registration
+// @Description  replaces the metadata of the client, the request has to be made with the registration access token of the client.
+// @Tags         client
+// @Accept       json
+// @Produce      json
+// @param client_id path string true "client_id"
+// @param metadata body dto.ClientRegistrationRequest true "metadata"
+// @Success      200  {object}  dto.ClientRegistrationResponse
+// @Failure      400  {object}  model.ErrorResponse "invalid client metadata"
+// @Failure      401  {object}  model.ErrorResponse "invalid registration access token"
+// @Router       /oauth/register/{client_id} [put]
+// @Security	BearerAuth
+func (c *client) UpdateClientRegistration(ctx *gin.Context) {
+	request := dto.ClientRegistrationRequest{}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid client metadata")
+		c.logger.Info(ctx, "couldn't bind to dto.ClientRegistrationRequest body", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+
+	requestCtx := ctx.Request.Context()
+	registeredClient, ok := requestCtx.Value(constant.Context("x-client")).(*dto.Client)
+	if !ok {
+		err := errors.ErrAuthError.New("client authentication is required")
+		c.logger.Info(ctx, "no client was found on the request context", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+
+	resp, err := c.clientModule.UpdateClientRegistration(requestCtx, *registeredClient, request)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// DeleteClientRegistration deletes a client as described on RFC 7592.
+// @Summary      delete client registration
+// @Description  deletes the client, the request has to be made with the registration access token of the client.
+// @Tags         client
+// @param client_id path string true "client_id"
+// @Success      204
+// @Failure      401  {object}  model.ErrorResponse "invalid registration access token"
+// @Router       /oauth/register/{client_id} [delete]
+// @Security	BearerAuth
+func (c *client) DeleteClientRegistration(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+	registeredClient, ok := requestCtx.Value(constant.Context("x-client")).(*dto.Client)
+	if !ok {
+		err := errors.ErrAuthError.New("client authentication is required")
+		c.logger.Info(ctx, "no client was found on the request context", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err := c.clientModule.DeleteClientRegistration(requestCtx, *registeredClient); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -205,12 +205,12 @@ func (o *oauth) ResetPassword(ctx *gin.Context) {
 
 // Logout logs out a user.
 // @Summary      logout  user.
-// @Description  logout user.
+// @Description  logout user, the clients the user is signed in to are notified of the logout.
 // @Tags         auth
 // @param tokenParam body dto.InternalRefreshTokenRequestBody true "logoutParam"
 // @Accept       json
 // @Produce      json
-// @Success      200
+// @Success      200  {object}  dto.LogoutResponse
 // @Failure      401  {object}  model.ErrorResponse "unauthorized"
 // @Failure      400  {object}  model.ErrorResponse "invalid input"
 // @Router       /logout [post]
@@ -222,7 +222,7 @@ func (o *oauth) Logout(ctx *gin.Context) {
 		_ = ctx.Error(errors.ErrInvalidUserInput.Wrap(err, "invalid input"))
 		return
 	}
-	err := o.oauthModule.Logout(ctx.Request.Context(), refreshTokenRequest)
+	resp, err := o.oauthModule.Logout(ctx.Request.Context(), refreshTokenRequest)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
@@ -230,7 +230,7 @@ func (o *oauth) Logout(ctx *gin.Context) {
 
 	// change opbs
 	utils.SetOPBSCookie(ctx, utils.GenerateNewOPBS(), o.options.OPBSCookie)
-	constant.SuccessResponse(ctx, http.StatusOK, nil, nil)
+	constant.SuccessResponse(ctx, http.StatusOK, resp, nil)
 }
 
 // RefreshToken refreshs a user access token.
@@ -255,7 +255,9 @@ func (o *oauth) RefreshToken(ctx *gin.Context) {
 	resp, err := o.oauthModule.RefreshToken(ctx.Request.Context(), refreshToken)
 	if err != nil {
 		_ = ctx.Error(err)
+		// the session ended, so the relying parties checking the session_state are told it changed
 		utils.RemoveRefreshTokenCookie(ctx, o.options.RefreshTokenCookie)
+		utils.SetOPBSCookie(ctx, utils.GenerateNewOPBS(), o.options.OPBSCookie)
 		return
 	}
 
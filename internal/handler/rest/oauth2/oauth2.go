@@ -1,7 +1,9 @@
 package oauth2
 
 import (
+	_ "embed"
 	"net/http"
+	"net/url"
 	"sso/internal/constant"
 	"sso/internal/constant/errors"
 	"sso/internal/constant/model/dto"
@@ -10,13 +12,17 @@ import (
 	"sso/internal/module"
 	"sso/platform/logger"
 	"sso/platform/utils"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// checkSessionPage is the op iframe relying parties post their session_state to.
+//
+//go:embed check_session.html
+var checkSessionPage []byte
+
 type oauth2 struct {
 	logger       logger.Logger
 	oauth2Module module.OAuth2Module
@@ -60,6 +66,12 @@ func InitOAuth2(logger logger.Logger, oauth2Module module.OAuth2Module, options
 // @param  state query string true "state"
 // @param scope query string true "scope"
 // @param redirect_uri query string true "redirect_uri"
+// @param nonce query string false "nonce"
+// @param response_mode query string false "response_mode"
+// @param request_uri query string false "request_uri of a pushed authorization request"
+// @param request query string false "request object, a JWT signed by the client holding the parameters of the request"
+// @param prompt query string true "prompt, none issues the response without showing the user any page"
+// @param max_age query int false "maximum number of seconds since the user last authenticated"
 // @Success      200
 // @Failure      400  {object}  model.ErrorResponse
 // @Header       200,400            {string}  Location  "redirect_uri"
@@ -75,7 +87,7 @@ func (o *oauth2) Authorize(ctx *gin.Context) {
 
 		ctx.Redirect(
 			http.StatusFound,
-			o.oauth2Module.Authorize(requestCtx, authRequestParam, "", err))
+			o.oauth2Module.Authorize(requestCtx, authRequestParam, "", dto.BrowserSession{}, err))
 		return
 	}
 
@@ -86,11 +98,14 @@ func (o *oauth2) Authorize(ctx *gin.Context) {
 
 		ctx.Redirect(
 			http.StatusFound,
-			o.oauth2Module.Authorize(requestCtx, authRequestParam, "", err))
+			o.oauth2Module.Authorize(requestCtx, authRequestParam, "", dto.BrowserSession{}, err))
 		return
 	}
 
-	requestOrigin := strings.TrimSuffix(ctx.Request.Header.Get("Referer"), "/")
+	requestOrigin := ""
+	if referer, err := url.Parse(ctx.Request.Header.Get("Referer")); err == nil && referer.Host != "" {
+		requestOrigin = referer.Scheme + "://" + referer.Host
+	}
 	// FIXME: a better solution?
 	//if requestOrigin == "" {
 	//	err := errors.ErrInvalidUserInput.New("invalid request origin")
@@ -102,9 +117,17 @@ func (o *oauth2) Authorize(ctx *gin.Context) {
 	//	return
 	//}
 
+	session := dto.BrowserSession{}
+	if refreshToken, err := ctx.Cookie("ab_fen"); err == nil {
+		session.RefreshToken = refreshToken
+	}
+	if opbs, err := ctx.Cookie("opbs"); err == nil {
+		session.OPBS = opbs
+	}
+
 	ctx.Redirect(
 		http.StatusFound,
-		o.oauth2Module.Authorize(requestCtx, authRequestParam, requestOrigin, nil))
+		o.oauth2Module.Authorize(requestCtx, authRequestParam, requestOrigin, session, nil))
 }
 
 // GetConsentByID is used to get consent by id.
@@ -136,6 +159,7 @@ func (o *oauth2) GetConsentByID(ctx *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @param consent_id body string true "consent_id"
+// @param scope body string false "the approved scopes, every requested scope is approved when it's not set"
 // @success 	 200 {object} dto.RedirectResponse "redirect response"
 // @Failure      400  {object}  model.ErrorResponse "invalid input"
 // @Header       200,400            {string}  Location  "redirect_uri"
@@ -151,7 +175,7 @@ func (o *oauth2) ApproveConsent(ctx *gin.Context) {
 		o.logger.Info(ctx, "invalid input", zap.Error(err))
 		constant.SuccessResponse(ctx, http.StatusOK,
 			dto.RedirectResponse{
-				Location: o.oauth2Module.ApproveConsent(requestCtx, consentResultRsp.ConsentID, uuid.UUID{}, "", err),
+				Location: o.oauth2Module.ApproveConsent(requestCtx, consentResultRsp.ConsentID, consentResultRsp.Scope, uuid.UUID{}, "", err),
 			}, nil)
 		return
 	}
@@ -162,7 +186,7 @@ func (o *oauth2) ApproveConsent(ctx *gin.Context) {
 		o.logger.Error(ctx, "no user_id was found on gin context", zap.Error(err), zap.String("request-uri", ctx.Request.RequestURI))
 		constant.SuccessResponse(ctx, http.StatusOK,
 			dto.RedirectResponse{
-				Location: o.oauth2Module.ApproveConsent(requestCtx, consentResultRsp.ConsentID, uuid.UUID{}, "", err),
+				Location: o.oauth2Module.ApproveConsent(requestCtx, consentResultRsp.ConsentID, consentResultRsp.Scope, uuid.UUID{}, "", err),
 			}, nil)
 		return
 	}
@@ -172,7 +196,7 @@ func (o *oauth2) ApproveConsent(ctx *gin.Context) {
 		o.logger.Error(ctx, "error while parsing x-user-id from request context", zap.Error(err), zap.String("x-user-id", userIDString))
 		constant.SuccessResponse(ctx, http.StatusOK,
 			dto.RedirectResponse{
-				Location: o.oauth2Module.ApproveConsent(requestCtx, consentResultRsp.ConsentID, uuid.UUID{}, "", err),
+				Location: o.oauth2Module.ApproveConsent(requestCtx, consentResultRsp.ConsentID, consentResultRsp.Scope, uuid.UUID{}, "", err),
 			}, nil)
 		return
 	}
@@ -181,7 +205,7 @@ func (o *oauth2) ApproveConsent(ctx *gin.Context) {
 		o.logger.Info(ctx, "empty consent id", zap.Error(err))
 		constant.SuccessResponse(ctx, http.StatusOK,
 			dto.RedirectResponse{
-				Location: o.oauth2Module.ApproveConsent(requestCtx, consentResultRsp.ConsentID, userID, "", err),
+				Location: o.oauth2Module.ApproveConsent(requestCtx, consentResultRsp.ConsentID, consentResultRsp.Scope, userID, "", err),
 			}, nil)
 		return
 	}
@@ -192,14 +216,14 @@ func (o *oauth2) ApproveConsent(ctx *gin.Context) {
 		o.logger.Warn(ctx, "no opbs value was found while approving authorize request", zap.Error(err))
 		constant.SuccessResponse(ctx, http.StatusOK,
 			dto.RedirectResponse{
-				Location: o.oauth2Module.ApproveConsent(requestCtx, consentResultRsp.ConsentID, userID, "", err),
+				Location: o.oauth2Module.ApproveConsent(requestCtx, consentResultRsp.ConsentID, consentResultRsp.Scope, userID, "", err),
 			}, nil)
 		return
 	}
 
 	constant.SuccessResponse(ctx, http.StatusOK,
 		dto.RedirectResponse{
-			Location: o.oauth2Module.ApproveConsent(requestCtx, consentResultRsp.ConsentID, userID, opbs.Value, nil),
+			Location: o.oauth2Module.ApproveConsent(requestCtx, consentResultRsp.ConsentID, consentResultRsp.Scope, userID, opbs.Value, nil),
 		}, nil)
 }
 
@@ -248,6 +272,7 @@ func (o *oauth2) RejectConsent(ctx *gin.Context) {
 // Token is used to exchange the authorization code for access token.
 // @Summary      exchange token.
 // @Description  is used to exchange token.
+// @Description  confidential clients authenticate with basic auth, public clients send their client_id and a PKCE code_verifier instead.
 // @Tags         OAuth2
 // @Accept       json
 // @Produce      json
@@ -402,3 +427,253 @@ func (o *oauth2) UserInfo(ctx *gin.Context) {
 
 	constant.SuccessResponse(ctx, http.StatusOK, userInfoRsp, nil)
 }
+
+// OpenIDConfiguration returns the OpenID provider metadata.
+// @Summary      returns the OpenID provider metadata.
+// @Description  It returns the discovery document clients use to configure themselves.
+// @Description  The response is not wrapped, as required by OpenID Connect Discovery.
+// @Tags         OAuth2
+// @Produce      json
+// @Success      200  {object}  dto.OpenIDConfiguration
+// @Failure      500  {object}  model.ErrorResponse
+// @Router       /.well-known/openid-configuration [get]
+func (o *oauth2) OpenIDConfiguration(ctx *gin.Context) {
+	configuration, err := o.oauth2Module.OpenIDConfiguration(ctx.Request.Context())
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, configuration)
+}
+
+// CheckSession serves the op iframe of OpenID Connect Session Management.
+// @Summary      check session iframe.
+// @Description  It serves the page relying parties embed in an iframe and post "client_id session_state" to.
+// @Description  The page answers "changed" once the opbs cookie changed since the session_state was issued, "unchanged" otherwise.
+// @Tags         OAuth2
+// @Produce      html
+// @Success      200
+// @Router       /oauth/check_session [get]
+func (o *oauth2) CheckSession(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", checkSessionPage)
+}
+
+// JWKS returns the public keys tokens are signed with.
+// @Summary      returns the json web key set.
+// @Description  It returns the public keys clients use to verify the tokens issued by the sso.
+// @Tags         OAuth2
+// @Produce      json
+// @Success      200  {object}  dto.JWKS
+// @Router       /oauth/jwks [get]
+func (o *oauth2) JWKS(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, o.oauth2Module.JWKS(ctx.Request.Context()))
+}
+
+// DeviceAuthorization is used by input constrained devices to start the device authorization flow.
+// @Summary      device authorization.
+// @Description  it issues the device_code the device polls the token endpoint with and the user_code the user enters on the verification page.
+// @Tags         OAuth2
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @param scope formData string false "scope"
+// @param client_id formData string false "client_id of public clients"
+// @Success      200  {object}  dto.DeviceAuthorizationResponse
+// @Failure      400  {object}  model.ErrorResponse "invalid input"
+// @Failure      401  {object}  model.ErrorResponse "unauthorized"
+// @Router       /oauth/device_authorization [post]
+// @Security	BasicAuth
+func (o *oauth2) DeviceAuthorization(ctx *gin.Context) {
+	deviceAuthorizationParam := dto.DeviceAuthorizationRequest{}
+	if err := ctx.ShouldBind(&deviceAuthorizationParam); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		o.logger.Info(ctx, "invalid input", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+
+	requestCtx := ctx.Request.Context()
+	client, ok := requestCtx.Value(constant.Context("x-client")).(*dto.Client)
+	if !ok {
+		err := errors.ErrAuthError.New("client authentication is required")
+		o.logger.Info(ctx, "no client was found on the request context", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+
+	resp, err := o.oauth2Module.DeviceAuthorization(requestCtx, *client, deviceAuthorizationParam)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// PushAuthorizationRequest is used by clients to push the parameters of an authorization request
+// instead of sending them on the query of the authorization endpoint.
+// @Summary      Pushed Authorization Request.
+// @Description  it stores the authorization request and returns the request_uri the client sends to the authorization endpoint along with its client_id.
+// @Tags         OAuth2
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @param response_type formData string true "response_type"
+// @param client_id formData string false "client_id"
+// @param redirect_uri formData string true "redirect_uri"
+// @param scope formData string true "scope"
+// @param state formData string false "state"
+// @param prompt formData string true "prompt"
+// @param code_challenge formData string false "code_challenge"
+// @param code_challenge_method formData string false "code_challenge_method"
+// @param nonce formData string false "nonce"
+// @param response_mode formData string false "response_mode"
+// @param request formData string false "request object, a JWT signed by the client holding the parameters of the request"
+// @Success      201  {object}  dto.PushedAuthorizationResponse
+// @Failure      400  {object}  model.ErrorResponse "invalid input"
+// @Failure      401  {object}  model.ErrorResponse "unauthorized"
+// @Router       /oauth/par [post]
+// @Security	BasicAuth
+func (o *oauth2) PushAuthorizationRequest(ctx *gin.Context) {
+	authRequestParam := dto.AuthorizationRequestParam{}
+	if err := ctx.ShouldBind(&authRequestParam); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		o.logger.Info(ctx, "invalid input", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+	if clientID := ctx.PostForm("client_id"); clientID != "" {
+		var err error
+		if authRequestParam.ClientID, err = uuid.Parse(clientID); err != nil {
+			err := errors.ErrInvalidUserInput.Wrap(err, "invalid client_id")
+			o.logger.Info(ctx, "invalid client_id", zap.Error(err), zap.String("client_id", clientID))
+			_ = ctx.Error(err)
+			return
+		}
+	}
+
+	requestCtx := ctx.Request.Context()
+	client, ok := requestCtx.Value(constant.Context("x-client")).(*dto.Client)
+	if !ok {
+		err := errors.ErrAuthError.New("client authentication is required")
+		o.logger.Info(ctx, "no client was found on the request context", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+
+	resp, err := o.oauth2Module.PushAuthorizationRequest(requestCtx, *client, authRequestParam)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, resp)
+}
+
+// VerifyDevice is used to start the consent of a device authorization with the user_code displayed on the device.
+// @Summary      Device Verification.
+// @Description  it finds the device authorization of the user_code and returns the consent page of it.
+// @Tags         OAuth2
+// @Accept       json
+// @Produce      json
+// @param user_code body dto.VerifyDeviceRequest true "user_code"
+// @success 	 200 {object} dto.RedirectResponse "redirect response"
+// @Router       /oauth/verifyDevice [POST]
+// @Security	BearerAuth
+func (o *oauth2) VerifyDevice(ctx *gin.Context) {
+	requestCtx := ctx.Request.Context()
+
+	verifyDeviceParam := dto.VerifyDeviceRequest{}
+	if err := ctx.ShouldBind(&verifyDeviceParam); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		o.logger.Info(ctx, "invalid input", zap.Error(err))
+		constant.SuccessResponse(ctx, http.StatusOK,
+			dto.RedirectResponse{
+				Location: o.oauth2Module.VerifyDevice(requestCtx, verifyDeviceParam, err),
+			}, nil)
+		return
+	}
+
+	constant.SuccessResponse(ctx, http.StatusOK,
+		dto.RedirectResponse{
+			Location: o.oauth2Module.VerifyDevice(requestCtx, verifyDeviceParam, nil),
+		}, nil)
+}
+
+// Introspect is used by resource servers and confidential clients to check if a token is active.
+// @Summary      token introspection.
+// @Description  it returns if the access token or refresh token is active along with its scope, client_id, sub and exp.
+// @Tags         OAuth2
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @param token formData string true "token"
+// @param token_type_hint formData string false "token_type_hint"
+// @Success      200  {object}  dto.IntrospectionResponse
+// @Failure      400  {object}  model.ErrorResponse "invalid input"
+// @Failure      403  {object}  model.ErrorResponse "unauthorized"
+// @Router       /oauth/introspect [post]
+// @Security	BasicAuth
+func (o *oauth2) Introspect(ctx *gin.Context) {
+	introspectionParam := dto.IntrospectionRequest{}
+	if err := ctx.ShouldBind(&introspectionParam); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		o.logger.Info(ctx, "invalid input", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+
+	requestCtx := ctx.Request.Context()
+	if client, ok := requestCtx.Value(constant.Context("x-client")).(*dto.Client); ok && client.ClientType != constant.ConfidentialClient {
+		err := errors.ErrAcessError.New("unauthorized_client")
+		o.logger.Info(ctx, "public client requested token introspection", zap.Error(err), zap.String("client-id", client.ID.String()))
+		_ = ctx.Error(err)
+		return
+	}
+
+	resp, err := o.oauth2Module.Introspect(requestCtx, introspectionParam)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// Revoke is used by clients to revoke an access token or a refresh token issued to them.
+// @Summary      token revocation.
+// @Description  it revokes the given access token or refresh token, invalid tokens are ignored.
+// @Tags         OAuth2
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @param token formData string true "token"
+// @param token_type_hint formData string false "token_type_hint"
+// @Success      200  {boolean} true
+// @Failure      400  {object}  model.ErrorResponse "invalid input"
+// @Failure      403  {object}  model.ErrorResponse "unauthorized"
+// @Router       /oauth/revoke [post]
+// @Security	BasicAuth
+func (o *oauth2) Revoke(ctx *gin.Context) {
+	revocationParam := dto.RevocationRequest{}
+	if err := ctx.ShouldBind(&revocationParam); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		o.logger.Info(ctx, "invalid input", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+
+	requestCtx := ctx.Request.Context()
+	client, ok := requestCtx.Value(constant.Context("x-client")).(*dto.Client)
+	if !ok {
+		err := errors.ErrInternalServerError.New("could not get client")
+		o.logger.Error(ctx, "could not get client from context", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err := o.oauth2Module.Revoke(requestCtx, *client, revocationParam); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	constant.SuccessResponse(ctx, http.StatusOK, nil, nil)
+}
@@ -87,3 +87,34 @@ func (r *resourceServer) GetAllResourceServers(ctx *gin.Context) {
 
 	constant.SuccessResponse(ctx, http.StatusOK, resourceServers, metaData)
 }
+
+// RotateResourceServerSecret replaces the secret of a resource server
+// @Summary      rotate resource server secret
+// @Description  generates a new secret for the resource server, the previous secret stays valid until previous_secret_expires_at.
+// @Description  the new secret is only returned in this response.
+// @Tags         resourceServer
+// @Accept       json
+// @Produce      json
+// @param id path string true "id"
+// @param request body dto.RotateSecretRequest true "request"
+// @Success      200  {object}  dto.RotatedSecret
+// @Failure      400  {object}  model.ErrorResponse
+// @Router       /resourceServers/{id}/secret/rotate [post]
+// @Security	BearerAuth
+func (r *resourceServer) RotateResourceServerSecret(ctx *gin.Context) {
+	request := dto.RotateSecretRequest{}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		r.logger.Info(ctx, "couldn't bind to dto.RotateSecretRequest body", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+
+	rotatedSecret, err := r.resourceServerModule.RotateResourceServerSecret(ctx.Request.Context(), ctx.Param("id"), request)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	constant.SuccessResponse(ctx, http.StatusOK, rotatedSecret, nil)
+}
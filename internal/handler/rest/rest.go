@@ -25,6 +25,14 @@ type OAuth2 interface {
 	GetAuthorizedClients(ctx *gin.Context)
 	GetOpenIDAuthorizedClients(ctx *gin.Context)
 	UserInfo(ctx *gin.Context)
+	OpenIDConfiguration(ctx *gin.Context)
+	JWKS(ctx *gin.Context)
+	CheckSession(ctx *gin.Context)
+	DeviceAuthorization(ctx *gin.Context)
+	PushAuthorizationRequest(ctx *gin.Context)
+	VerifyDevice(ctx *gin.Context)
+	Introspect(ctx *gin.Context)
+	Revoke(ctx *gin.Context)
 }
 type User interface {
 	CreateUser(ctx *gin.Context)
@@ -44,6 +52,12 @@ type Client interface {
 	GetAllClientByID(ctx *gin.Context)
 	UpdateClientStatus(ctx *gin.Context)
 	UpdateClient(ctx *gin.Context)
+	RotateClientSecret(ctx *gin.Context)
+	IssueInitialAccessToken(ctx *gin.Context)
+	RegisterClient(ctx *gin.Context)
+	GetClientRegistration(ctx *gin.Context)
+	UpdateClientRegistration(ctx *gin.Context)
+	DeleteClientRegistration(ctx *gin.Context)
 }
 
 type Scope interface {
@@ -71,6 +85,7 @@ type MiniRide interface {
 type ResourceServer interface {
 	CreateResourceServer(ctx *gin.Context)
 	GetAllResourceServers(ctx *gin.Context)
+	RotateResourceServerSecret(ctx *gin.Context)
 }
 
 type Role interface {
@@ -94,8 +109,15 @@ type IdentityProvider interface {
 type RSAPI interface {
 	GetUserByPhoneOrID(ctx *gin.Context)
 	GetUsersByPhoneOrID(ctx *gin.Context)
+	RegisterAuthorizationDetailTypes(ctx *gin.Context)
 }
 
 type Asset interface {
 	UploadAsset(ctx *gin.Context)
 }
+
+type SigningKey interface {
+	GetAllSigningKeys(ctx *gin.Context)
+	RotateSigningKey(ctx *gin.Context)
+	RetireSigningKey(ctx *gin.Context)
+}
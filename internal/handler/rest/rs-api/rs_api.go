@@ -5,6 +5,7 @@ import (
 
 	"sso/internal/constant"
 	"sso/internal/constant/errors"
+	"sso/internal/constant/model/dto"
 	"sso/internal/constant/model/dto/request_models"
 	"sso/internal/handler/rest"
 	"sso/internal/module"
@@ -93,3 +94,35 @@ func (i *rsAPI) GetUsersByPhoneOrID(ctx *gin.Context) {
 	i.logger.Info(ctx, "users detail fetched")
 	constant.SuccessResponse(ctx, http.StatusOK, user, nil)
 }
+
+// RegisterAuthorizationDetailTypes registers the authorization detail types the resource server accepts.
+// @Summary      registers the authorization detail types of the resource server
+// @Description  registers the authorization detail types the resource server accepts.
+// @Description  They replace the types the resource server registered before, and a type can only be registered by one resource server.
+// @Tags         internal
+// @Accept       json
+// @Produce      json
+// @param types body dto.AuthorizationDetailTypesRequest true "types"
+// @Success      200  {object}  []dto.AuthorizationDetailType
+// @Failure      400  {object}  model.ErrorResponse
+// @Failure      401  {object}  model.ErrorResponse
+// @Router       /internal/authorization_detail_types [put]
+// @Security	BasicAuth
+func (i *rsAPI) RegisterAuthorizationDetailTypes(ctx *gin.Context) {
+	var req dto.AuthorizationDetailTypesRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid request body")
+		i.logger.Info(ctx, "invalid request body for authorization detail types")
+		_ = ctx.Error(err)
+		return
+	}
+
+	types, err := i.rsAPI.RegisterAuthorizationDetailTypes(ctx.Request.Context(), req)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	i.logger.Info(ctx, "authorization detail types registered")
+	constant.SuccessResponse(ctx, http.StatusOK, types, nil)
+}
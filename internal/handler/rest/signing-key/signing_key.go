@@ -0,0 +1,111 @@
+package signing_key
+
+import (
+	"net/http"
+
+	"sso/internal/constant"
+	"sso/internal/constant/errors"
+	"sso/internal/constant/model/dto"
+	"sso/internal/handler/rest"
+	"sso/internal/module"
+	"sso/platform/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type signingKey struct {
+	logger           logger.Logger
+	signingKeyModule module.SigningKeyModule
+}
+
+func Init(logger logger.Logger, signingKeyModule module.SigningKeyModule) rest.SigningKey {
+	return &signingKey{
+		logger:           logger,
+		signingKeyModule: signingKeyModule,
+	}
+}
+
+// GetAllSigningKeys is used to get the signing keys.
+// @Summary get signing keys
+// @Description get all the signing keys, including the scheduled and retired ones.
+// @ID get-signing-keys
+// @Tags signingKey
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} []dto.SigningKey
+// @Failure 400 {object} model.ErrorResponse
+// @Router /signingKeys [get]
+// @Security BearerAuth
+func (s *signingKey) GetAllSigningKeys(ctx *gin.Context) {
+	keys, err := s.signingKeyModule.GetAllSigningKeys(ctx.Request.Context())
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	constant.SuccessResponse(ctx, http.StatusOK, keys, nil)
+}
+
+// RotateSigningKey is used to schedule a new signing key.
+// @Summary rotate signing key
+// @Description generate a new signing key that starts signing tokens at activates_at.
+// @Description The previous keys keep verifying tokens until they are retired.
+// @ID rotate-signing-key
+// @Tags signingKey
+// @Accept  json
+// @Produce  json
+// @Param request body dto.RotateSigningKeyRequest true "request"
+// @Success 201 {object} dto.SigningKey
+// @Failure 400 {object} model.ErrorResponse
+// @Router /signingKeys/rotate [post]
+// @Security BearerAuth
+func (s *signingKey) RotateSigningKey(ctx *gin.Context) {
+	var request dto.RotateSigningKeyRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		s.logger.Info(ctx, "could not bind to dto.RotateSigningKeyRequest", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+
+	key, err := s.signingKeyModule.RotateSigningKey(ctx.Request.Context(), request)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	constant.SuccessResponse(ctx, http.StatusCreated, key, nil)
+}
+
+// RetireSigningKey is used to schedule the retirement of a signing key.
+// @Summary retire signing key
+// @Description schedule a signing key to stop being accepted at retires_at.
+// @ID retire-signing-key
+// @Tags signingKey
+// @Accept  json
+// @Produce  json
+// @Param kid path string true "kid"
+// @Param request body dto.RetireSigningKeyRequest true "request"
+// @Success 200 {object} dto.SigningKey
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /signingKeys/{kid}/retire [patch]
+// @Security BearerAuth
+func (s *signingKey) RetireSigningKey(ctx *gin.Context) {
+	var request dto.RetireSigningKeyRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		s.logger.Info(ctx, "could not bind to dto.RetireSigningKeyRequest", zap.Error(err))
+		_ = ctx.Error(err)
+		return
+	}
+
+	key, err := s.signingKeyModule.RetireSigningKey(ctx.Request.Context(), ctx.Param("kid"), request)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	constant.SuccessResponse(ctx, http.StatusOK, key, nil)
+}
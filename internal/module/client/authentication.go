@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/x509"
+	"path"
+
+	"sso/internal/constant"
+	"sso/internal/constant/errors"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/joomcode/errorx"
+	"go.uber.org/zap"
+)
+
+// clientAssertionAlgorithms are the algorithms a client may sign its client assertions with.
+var clientAssertionAlgorithms = []string{
+	constant.SigningAlgorithmPS512, constant.SigningAlgorithmRS256, constant.SigningAlgorithmES256, constant.SigningAlgorithmEdDSA,
+}
+
+// VerifyClientAssertion authenticates a client with the private_key_jwt method of RFC 7523.
+// The assertion must be signed with one of the keys the client registered, issued by the client to the sso,
+// and its jti is recorded until it expires so the same assertion can't be used twice.
+func (c *clientModule) VerifyClientAssertion(ctx context.Context, assertionType, assertion string) (*dto.Client, error) {
+	if assertionType != constant.ClientAssertionTypeJWTBearer {
+		err := errors.ErrInvalidUserInput.New("unsupported client_assertion_type")
+		c.logger.Info(ctx, "unsupported client assertion type", zap.String("client-assertion-type", assertionType))
+		return nil, err
+	}
+
+	unverified, _, err := jwt.NewParser().ParseUnverified(assertion, &jwt.RegisteredClaims{})
+	if err != nil {
+		err := errors.ErrAuthError.Wrap(err, "invalid client assertion")
+		c.logger.Info(ctx, "could not parse client assertion", zap.Error(err))
+		return nil, err
+	}
+	unverifiedClaims, _ := unverified.Claims.(*jwt.RegisteredClaims)
+
+	client, err := c.GetClientByID(ctx, unverifiedClaims.Subject)
+	if err != nil {
+		if errorx.IsOfType(err, errors.ErrNoRecordFound) {
+			err := errors.ErrAuthError.Wrap(err, "invalid client assertion")
+			c.logger.Info(ctx, "client assertion of unknown client", zap.Error(err), zap.String("client-id", unverifiedClaims.Subject))
+			return nil, err
+		}
+		return nil, err
+	}
+	if client.AuthenticationMethod() != constant.PrivateKeyJWT || client.JWKS == nil {
+		err := errors.ErrAuthError.New("invalid client assertion")
+		c.logger.Info(ctx, "client does not authenticate with private_key_jwt", zap.String("client-id", client.ID.String()))
+		return nil, err
+	}
+
+	claims := &jwt.RegisteredClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods(clientAssertionAlgorithms))
+	if _, err := parser.ParseWithClaims(assertion, claims, client.JWKS.VerificationKey); err != nil {
+		err := errors.ErrAuthError.Wrap(err, "invalid client assertion")
+		c.logger.Info(ctx, "could not verify client assertion", zap.Error(err), zap.String("client-id", client.ID.String()))
+		return nil, err
+	}
+
+	clientID := client.ID.String()
+	if claims.Issuer != clientID || claims.Subject != clientID || claims.ExpiresAt == nil || claims.ID == "" ||
+		!(claims.VerifyAudience(c.urls.IssuerURL.String(), true) || claims.VerifyAudience(c.tokenEndpointURL(), true)) {
+		err := errors.ErrAuthError.New("invalid client assertion")
+		c.logger.Info(ctx, "client assertion has invalid claims", zap.String("client-id", clientID), zap.Any("claims", claims))
+		return nil, err
+	}
+
+	first, err := c.clientAssertionCache.UseClientAssertion(ctx, clientID, claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return nil, err
+	}
+	if !first {
+		err := errors.ErrAuthError.New("client assertion was already used")
+		c.logger.Warn(ctx, "client assertion replayed", zap.String("client-id", clientID), zap.String("jti", claims.ID))
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// VerifyClientCertificate authenticates a client with the tls_client_auth method of RFC 8705,
+// matching the certificate the client presented on the TLS connection against the one it registered.
+func (c *clientModule) VerifyClientCertificate(ctx context.Context, clientID string, certificate *x509.Certificate) (*dto.Client, error) {
+	client, err := c.GetClientByID(ctx, clientID)
+	if err != nil {
+		if errorx.IsOfType(err, errors.ErrNoRecordFound) {
+			err := errors.ErrAuthError.Wrap(err, "invalid client certificate")
+			c.logger.Info(ctx, "client certificate of unknown client", zap.Error(err), zap.String("client-id", clientID))
+			return nil, err
+		}
+		return nil, err
+	}
+	if client.AuthenticationMethod() != constant.TLSClientAuth || client.TLSClientCertificateThumbprint == "" {
+		err := errors.ErrAuthError.New("invalid client certificate")
+		c.logger.Info(ctx, "client does not authenticate with tls_client_auth", zap.String("client-id", clientID))
+		return nil, err
+	}
+
+	thumbprint := utils.CertificateThumbprint(certificate)
+	if subtle.ConstantTimeCompare([]byte(thumbprint), []byte(client.TLSClientCertificateThumbprint)) != 1 {
+		err := errors.ErrAuthError.New("invalid client certificate")
+		c.logger.Info(ctx, "client certificate does not match", zap.String("client-id", clientID), zap.String("thumbprint", thumbprint))
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// tokenEndpointURL returns the absolute url of the token endpoint, one of the audiences a client assertion may be issued to.
+func (c *clientModule) tokenEndpointURL() string {
+	endpointURL := *c.urls.IssuerURL
+	endpointURL.Path = path.Join(endpointURL.Path, constant.APIBasePath, constant.OAuth2BasePath, constant.TokenEndpoint)
+
+	return endpointURL.String()
+}
@@ -2,10 +2,13 @@ package client
 
 import (
 	"context"
+	"time"
 
+	"sso/internal/constant"
 	"sso/internal/constant/errors"
 	"sso/internal/constant/model"
 	"sso/internal/constant/model/dto"
+	"sso/internal/constant/state"
 	"sso/internal/module"
 	"sso/internal/storage"
 	"sso/platform/logger"
@@ -17,14 +20,22 @@ import (
 )
 
 type clientModule struct {
-	logger            logger.Logger
-	clientPersistence storage.ClientPersistence
+	logger                logger.Logger
+	clientPersistence     storage.ClientPersistence
+	signingKeyPersistence storage.SigningKeyPersistence
+	scopePersistence      storage.ScopePersistence
+	clientAssertionCache  storage.ClientAssertionCache
+	urls                  state.URLs
 }
 
-func InitClient(log logger.Logger, clientPersistence storage.ClientPersistence) module.ClientModule {
+func InitClient(log logger.Logger, clientPersistence storage.ClientPersistence, signingKeyPersistence storage.SigningKeyPersistence, scopePersistence storage.ScopePersistence, clientAssertionCache storage.ClientAssertionCache, urls state.URLs) module.ClientModule {
 	return &clientModule{
-		logger:            log,
-		clientPersistence: clientPersistence,
+		logger:                log,
+		clientPersistence:     clientPersistence,
+		signingKeyPersistence: signingKeyPersistence,
+		scopePersistence:      scopePersistence,
+		clientAssertionCache:  clientAssertionCache,
+		urls:                  urls,
 	}
 }
 
@@ -36,9 +47,26 @@ func (c *clientModule) Create(ctx context.Context, clientParam dto.Client) (*dto
 	}
 
 	// TODO: check scope on the resource server
-	clientParam.Secret = utils.GenerateRandomString(25, true)
+	secret := utils.GenerateRandomString(25, true)
+	clientParam.Secret = utils.HashSecret(secret)
+	if len(clientParam.ResponseTypes) == 0 {
+		clientParam.ResponseTypes = []string{constant.ResponseTypeCode}
+	}
+	if clientParam.IDTokenSignedResponseAlg == "" {
+		clientParam.IDTokenSignedResponseAlg = constant.SigningAlgorithmPS512
+	}
+	if err := c.checkSigningAlgorithm(ctx, clientParam.IDTokenSignedResponseAlg); err != nil {
+		return nil, err
+	}
+	clientParam.TokenEndpointAuthMethod = clientParam.AuthenticationMethod()
+
+	client, err := c.clientPersistence.Create(ctx, clientParam)
+	if err != nil {
+		return nil, err
+	}
+	client.Secret = secret
 
-	return c.clientPersistence.Create(ctx, clientParam)
+	return client, nil
 }
 
 func (c *clientModule) GetClientByID(ctx context.Context, id string) (*dto.Client, error) {
@@ -132,6 +160,66 @@ func (c *clientModule) UpdateClient(ctx context.Context, client dto.Client, id s
 	}
 
 	client.ID = clientID
+	if len(client.ResponseTypes) == 0 {
+		client.ResponseTypes = []string{constant.ResponseTypeCode}
+	}
+	if client.IDTokenSignedResponseAlg == "" {
+		client.IDTokenSignedResponseAlg = constant.SigningAlgorithmPS512
+	}
+	if err := c.checkSigningAlgorithm(ctx, client.IDTokenSignedResponseAlg); err != nil {
+		return err
+	}
+	client.TokenEndpointAuthMethod = client.AuthenticationMethod()
 
 	return c.clientPersistence.UpdateClient(ctx, client)
 }
+
+func (c *clientModule) RotateClientSecret(ctx context.Context, id string, request dto.RotateSecretRequest) (*dto.RotatedSecret, error) {
+	clientID, err := uuid.Parse(id)
+	if err != nil {
+		err := errors.ErrNoRecordFound.Wrap(err, "client not found")
+		c.logger.Info(ctx, "parse error", zap.Error(err), zap.String("client-id", id))
+		return nil, err
+	}
+
+	if err := request.Validate(); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		c.logger.Info(ctx, "invalid input", zap.Error(err))
+		return nil, err
+	}
+
+	previousSecretExpiresAt := request.PreviousSecretExpiresAt
+	if previousSecretExpiresAt.IsZero() {
+		previousSecretExpiresAt = time.Now()
+	}
+
+	secret := utils.GenerateRandomString(25, true)
+	if err := c.clientPersistence.RotateClientSecret(ctx, clientID, utils.HashSecret(secret), previousSecretExpiresAt); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info(ctx, "client secret rotated", zap.String("client-id", id), zap.Time("previous-secret-expires-at", previousSecretExpiresAt))
+	return &dto.RotatedSecret{
+		Secret:                  secret,
+		PreviousSecretExpiresAt: previousSecretExpiresAt,
+	}, nil
+}
+
+// checkSigningAlgorithm makes sure there is an active signing key the id tokens of the client can be signed with.
+func (c *clientModule) checkSigningAlgorithm(ctx context.Context, algorithm string) error {
+	keys, err := c.signingKeyPersistence.GetAllSigningKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, key := range keys {
+		if key.Algorithm == algorithm && key.IsActive(now) {
+			return nil
+		}
+	}
+
+	err = errors.ErrInvalidUserInput.New("no active signing key for the id_token_signed_response_alg")
+	c.logger.Info(ctx, "no active signing key for the algorithm", zap.Error(err), zap.String("algorithm", algorithm))
+	return err
+}
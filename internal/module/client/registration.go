@@ -0,0 +1,269 @@
+package client
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"path"
+	"time"
+
+	"sso/internal/constant"
+	"sso/internal/constant/errors"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+
+	"github.com/google/uuid"
+	"github.com/joomcode/errorx"
+	"go.uber.org/zap"
+)
+
+func (c *clientModule) IssueInitialAccessToken(ctx context.Context, request dto.InitialAccessTokenRequest) (*dto.InitialAccessToken, error) {
+	if err := request.Validate(); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		c.logger.Info(ctx, "invalid input", zap.Error(err))
+		return nil, err
+	}
+
+	if err := c.checkScopesExist(ctx, utils.StringToArray(request.Scopes)); err != nil {
+		return nil, err
+	}
+	if len(request.GrantTypes) == 0 {
+		request.GrantTypes = []string{constant.AuthorizationCode, constant.RefreshToken}
+	}
+
+	id, ok := ctx.Value(constant.Context("x-user-id")).(string)
+	if !ok {
+		err := errors.ErrInvalidUserInput.New("invalid user id")
+		c.logger.Info(ctx, "invalid user id", zap.Error(err), zap.Any("user_id", id))
+		return nil, err
+	}
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		err := errors.ErrNoRecordFound.Wrap(err, "user not found")
+		c.logger.Info(ctx, "parse error", zap.Error(err), zap.String("user id", id))
+		return nil, err
+	}
+
+	token := utils.GenerateRandomString(40, false)
+	initialAccessToken, err := c.clientPersistence.CreateInitialAccessToken(ctx, dto.InitialAccessToken{
+		TokenHash:  utils.HashToken(token),
+		CreatedBy:  userID,
+		ExpiresAt:  request.ExpiresAt,
+		Scopes:     request.Scopes,
+		GrantTypes: request.GrantTypes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	initialAccessToken.Token = token
+
+	c.logger.Info(ctx, "initial access token issued", zap.String("id", initialAccessToken.ID.String()), zap.Time("expires-at", initialAccessToken.ExpiresAt))
+	return initialAccessToken, nil
+}
+
+func (c *clientModule) VerifyInitialAccessToken(ctx context.Context, token string) (*dto.InitialAccessToken, error) {
+	initialAccessToken, err := c.clientPersistence.GetInitialAccessToken(ctx, utils.HashToken(token))
+	if err != nil {
+		if errorx.IsOfType(err, errors.ErrNoRecordFound) {
+			err := errors.ErrAuthError.New("invalid initial access token")
+			c.logger.Info(ctx, "unknown initial access token", zap.Error(err))
+			return nil, err
+		}
+		return nil, err
+	}
+
+	if !initialAccessToken.ExpiresAt.After(time.Now()) {
+		err := errors.ErrAuthError.New("invalid initial access token")
+		c.logger.Info(ctx, "initial access token expired", zap.Error(err), zap.String("id", initialAccessToken.ID.String()))
+		return nil, err
+	}
+
+	return initialAccessToken, nil
+}
+
+func (c *clientModule) RegisterClient(ctx context.Context, initialAccessToken dto.InitialAccessToken, request dto.ClientRegistrationRequest) (*dto.ClientRegistrationResponse, error) {
+	if err := request.Validate(); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid client metadata")
+		c.logger.Info(ctx, "invalid client metadata", zap.Error(err))
+		return nil, err
+	}
+
+	// a self registered client gets no more than the admin that issued the initial access token allowed.
+	newClient := request.Client()
+	scopes := utils.StringToArray(newClient.Scopes)
+	allowedScopes := utils.StringToArray(initialAccessToken.Scopes)
+	for _, scope := range scopes {
+		if !utils.ContainsValue(scope, allowedScopes) {
+			err := errors.ErrInvalidUserInput.New(fmt.Sprintf("scope %s is not allowed by the initial access token", scope))
+			c.logger.Info(ctx, "scope not allowed for the client", zap.Error(err), zap.String("initial-access-token-id", initialAccessToken.ID.String()))
+			return nil, err
+		}
+	}
+	for _, grantType := range newClient.GrantTypes {
+		if !utils.ContainsValue(grantType, initialAccessToken.GrantTypes) {
+			err := errors.ErrInvalidUserInput.New(fmt.Sprintf("grant type %s is not allowed by the initial access token", grantType))
+			c.logger.Info(ctx, "grant type not allowed for the client", zap.Error(err), zap.String("initial-access-token-id", initialAccessToken.ID.String()))
+			return nil, err
+		}
+	}
+	if err := c.checkScopesExist(ctx, scopes); err != nil {
+		return nil, err
+	}
+
+	client, err := c.Create(ctx, newClient)
+	if err != nil {
+		return nil, err
+	}
+
+	registrationAccessToken := utils.GenerateRandomString(40, false)
+	if err := c.clientPersistence.SaveRegistrationAccessToken(ctx, client.ID, utils.HashToken(registrationAccessToken)); err != nil {
+		return nil, err
+	}
+
+	response := c.registrationResponse(*client)
+	if client.AuthenticationMethod() == constant.ClientSecretBasic {
+		response.ClientSecret = client.Secret
+	}
+	response.RegistrationAccessToken = registrationAccessToken
+
+	c.logger.Info(ctx, "client registered", zap.String("client-id", client.ID.String()))
+	return response, nil
+}
+
+func (c *clientModule) VerifyRegistrationAccessToken(ctx context.Context, clientID, token string) (*dto.Client, error) {
+	invalidToken := errors.ErrAuthError.New("invalid registration access token")
+
+	id, err := uuid.Parse(clientID)
+	if err != nil {
+		c.logger.Info(ctx, "parse error", zap.Error(err), zap.String("client-id", clientID))
+		return nil, invalidToken
+	}
+
+	tokenHash, err := c.clientPersistence.GetRegistrationAccessTokenHash(ctx, id)
+	if err != nil {
+		if errorx.IsOfType(err, errors.ErrNoRecordFound) {
+			return nil, invalidToken
+		}
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(tokenHash), []byte(utils.HashToken(token))) != 1 {
+		c.logger.Info(ctx, "registration access token mismatch", zap.Error(invalidToken), zap.String("client-id", clientID))
+		return nil, invalidToken
+	}
+
+	return c.clientPersistence.GetClientByID(ctx, id)
+}
+
+func (c *clientModule) GetClientRegistration(_ context.Context, client dto.Client) *dto.ClientRegistrationResponse {
+	return c.registrationResponse(client)
+}
+
+func (c *clientModule) UpdateClientRegistration(ctx context.Context, client dto.Client, request dto.ClientRegistrationRequest) (*dto.ClientRegistrationResponse, error) {
+	if err := request.Validate(); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid client metadata")
+		c.logger.Info(ctx, "invalid client metadata", zap.Error(err))
+		return nil, err
+	}
+
+	updatedClient := request.Client()
+	updatedClient.ID = client.ID
+	updatedClient.Status = client.Status
+	updatedClient.CreatedAt = client.CreatedAt
+	updatedClient.RequirePKCE = client.RequirePKCE
+	// the token lifetimes are a policy of the sso for the client, the client can't change them.
+	updatedClient.AccessTokenLifetime = client.AccessTokenLifetime
+	updatedClient.RefreshTokenLifetime = client.RefreshTokenLifetime
+	updatedClient.RefreshTokenIdleLifetime = client.RefreshTokenIdleLifetime
+	updatedClient.RefreshTokenAbsoluteLifetime = client.RefreshTokenAbsoluteLifetime
+	updatedClient.IDTokenLifetime = client.IDTokenLifetime
+	updatedClient.AuthCodeLifetime = client.AuthCodeLifetime
+	// so are the scopes and the privileged grant types, they were bounded by the initial access token,
+	// and the optional scopes an admin set for the client.
+	updatedClient.Scopes = client.Scopes
+	updatedClient.OptionalScopes = client.OptionalScopes
+	grantTypes := []string{}
+	for _, grantType := range updatedClient.GrantTypes {
+		if !utils.ContainsValue(grantType, privilegedGrantTypes) {
+			grantTypes = append(grantTypes, grantType)
+		}
+	}
+	for _, grantType := range client.GrantTypes {
+		if utils.ContainsValue(grantType, privilegedGrantTypes) {
+			grantTypes = append(grantTypes, grantType)
+		}
+	}
+	updatedClient.GrantTypes = grantTypes
+	if err := c.UpdateClient(ctx, updatedClient, client.ID.String()); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info(ctx, "client registration updated", zap.String("client-id", client.ID.String()))
+	return c.registrationResponse(updatedClient), nil
+}
+
+func (c *clientModule) DeleteClientRegistration(ctx context.Context, client dto.Client) error {
+	if err := c.clientPersistence.DeleteClientByID(ctx, client.ID); err != nil {
+		return err
+	}
+
+	c.logger.Info(ctx, "client registration deleted", zap.String("client-id", client.ID.String()))
+	return nil
+}
+
+// checkScopesExist fails if any of the scopes is not defined on the sso.
+func (c *clientModule) checkScopesExist(ctx context.Context, scopes []string) error {
+	listedScopes, err := c.scopePersistence.GetListedScopes(ctx, scopes...)
+	if err != nil {
+		return err
+	}
+
+	for _, scope := range scopes {
+		found := false
+		for _, listedScope := range listedScopes {
+			if listedScope.Name == scope {
+				found = true
+				break
+			}
+		}
+		if !found {
+			err := errors.ErrInvalidUserInput.New(fmt.Sprintf("scope %s does not exist", scope))
+			c.logger.Info(ctx, "unknown scope", zap.Error(err), zap.String("scope", scope))
+			return err
+		}
+	}
+
+	return nil
+}
+
+// privilegedGrantTypes are the grant types that give a client access beyond the users that log in to it.
+var privilegedGrantTypes = []string{constant.ClientCredentials, constant.DeviceCode, constant.TokenExchange}
+
+// registrationResponse describes the registered client as the client information response of RFC 7591.
+func (c *clientModule) registrationResponse(client dto.Client) *dto.ClientRegistrationResponse {
+	registrationClientURI := *c.urls.IssuerURL
+	registrationClientURI.Path = path.Join(registrationClientURI.Path, constant.APIBasePath, constant.OAuth2BasePath, constant.RegistrationEndpoint, client.ID.String())
+
+	response := &dto.ClientRegistrationResponse{
+		ClientID:              client.ID.String(),
+		ClientIDIssuedAt:      client.CreatedAt.Unix(),
+		RegistrationClientURI: registrationClientURI.String(),
+		ClientRegistrationRequest: dto.ClientRegistrationRequest{
+			ClientName:                         client.Name,
+			RedirectURIs:                       client.RedirectURIs,
+			GrantTypes:                         client.GrantTypes,
+			ResponseTypes:                      client.ResponseTypes,
+			TokenEndpointAuthMethod:            client.AuthenticationMethod(),
+			Scope:                              client.Scopes,
+			LogoURI:                            client.LogoURL,
+			IDTokenSignedResponseAlg:           client.IDTokenSignedResponseAlg,
+			JWKS:                               client.JWKS,
+			TLSClientCertificateThumbprint:     client.TLSClientCertificateThumbprint,
+			RequirePushedAuthorizationRequests: client.RequirePushedAuthorizationRequests,
+			BackchannelLogoutURI:               client.BackchannelLogoutURI,
+			FrontchannelLogoutURI:              client.FrontchannelLogoutURI,
+		},
+	}
+
+	return response
+}
@@ -2,6 +2,7 @@ package module
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/json"
 	"mime/multipart"
 
@@ -21,7 +22,7 @@ type OAuthModule interface {
 	ComparePassword(hashedPwd, plainPassword string) bool
 	RequestOTP(ctx context.Context, phone string, rqType string) error
 	GetUserStatus(ctx context.Context, Id string) (string, error)
-	Logout(ctx context.Context, param dto.InternalRefreshTokenRequestBody) error
+	Logout(ctx context.Context, param dto.InternalRefreshTokenRequestBody) (*dto.LogoutResponse, error)
 	RefreshToken(ctx context.Context, refreshToken string) (*dto.TokenResponse, error)
 	LoginWithIdentityProvider(ctx context.Context, login request_models.LoginWithIP, userDeviceAddress dto.UserDeviceAddress) (dto.TokenResponse, error)
 	GetAllIdentityProviders(ctx context.Context) ([]dto.IdentityProvider, error)
@@ -30,9 +31,10 @@ type OAuthModule interface {
 }
 
 type OAuth2Module interface {
-	Authorize(ctx context.Context, authRequestParma dto.AuthorizationRequestParam, requestOrigin string, bindError *errorx.Error) string
+	Authorize(ctx context.Context, authRequestParma dto.AuthorizationRequestParam, requestOrigin string, session dto.BrowserSession, bindError *errorx.Error) string
+	PushAuthorizationRequest(ctx context.Context, client dto.Client, param dto.AuthorizationRequestParam) (*dto.PushedAuthorizationResponse, error)
 	GetConsentByID(ctx context.Context, consentID string) (dto.ConsentResponse, error)
-	ApproveConsent(ctx context.Context, consentID string, userID uuid.UUID, opbs string, bindError *errorx.Error) string
+	ApproveConsent(ctx context.Context, consentID, scope string, userID uuid.UUID, opbs string, bindError *errorx.Error) string
 	RejectConsent(ctx context.Context, consentID, failureReason string, bindError *errorx.Error) string
 	Token(ctx context.Context, client dto.Client, param dto.AccessTokenRequest) (*dto.TokenResponse, error)
 	Logout(ctx context.Context, logoutReqParam dto.LogoutRequest, bindError *errorx.Error) string
@@ -40,6 +42,13 @@ type OAuth2Module interface {
 	GetAuthorizedClients(ctx context.Context) ([]dto.AuthorizedClientsResponse, error)
 	GetOpenIDAuthorizedClients(ctx context.Context) ([]dto.AuthorizedClientsResponse, error)
 	UserInfo(ctx context.Context) (*dto.UserInfo, error)
+	OpenIDConfiguration(ctx context.Context) (dto.OpenIDConfiguration, error)
+	JWKS(ctx context.Context) dto.JWKS
+	DeviceAuthorization(ctx context.Context, client dto.Client, param dto.DeviceAuthorizationRequest) (*dto.DeviceAuthorizationResponse, error)
+	VerifyDevice(ctx context.Context, param dto.VerifyDeviceRequest, bindError *errorx.Error) string
+	Introspect(ctx context.Context, param dto.IntrospectionRequest) (*dto.IntrospectionResponse, error)
+	Revoke(ctx context.Context, client dto.Client, param dto.RevocationRequest) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
 }
 type UserModule interface {
 	Create(ctx context.Context, user dto.CreateUser) (*dto.User, error)
@@ -59,6 +68,16 @@ type ClientModule interface {
 	GetAllClients(ctx context.Context, filtersQuery db_pgnflt.PgnFltQueryParams) ([]dto.Client, *model.MetaData, error)
 	UpdateClientStatus(ctx context.Context, updateClientStatusParam dto.UpdateClientStatus, id string) error
 	UpdateClient(ctx context.Context, client dto.Client, id string) error
+	RotateClientSecret(ctx context.Context, id string, request dto.RotateSecretRequest) (*dto.RotatedSecret, error)
+	IssueInitialAccessToken(ctx context.Context, request dto.InitialAccessTokenRequest) (*dto.InitialAccessToken, error)
+	VerifyInitialAccessToken(ctx context.Context, token string) (*dto.InitialAccessToken, error)
+	RegisterClient(ctx context.Context, initialAccessToken dto.InitialAccessToken, request dto.ClientRegistrationRequest) (*dto.ClientRegistrationResponse, error)
+	VerifyRegistrationAccessToken(ctx context.Context, clientID, token string) (*dto.Client, error)
+	GetClientRegistration(ctx context.Context, client dto.Client) *dto.ClientRegistrationResponse
+	UpdateClientRegistration(ctx context.Context, client dto.Client, request dto.ClientRegistrationRequest) (*dto.ClientRegistrationResponse, error)
+	DeleteClientRegistration(ctx context.Context, client dto.Client) error
+	VerifyClientAssertion(ctx context.Context, assertionType, assertion string) (*dto.Client, error)
+	VerifyClientCertificate(ctx context.Context, clientID string, certificate *x509.Certificate) (*dto.Client, error)
 }
 
 type ScopeModule interface {
@@ -84,6 +103,7 @@ type ResourceServerModule interface {
 	CreateResourceServer(ctx context.Context, server dto.ResourceServer) (dto.ResourceServer, error)
 	GetAllResourceServers(ctx context.Context, filtersQuery db_pgnflt.PgnFltQueryParams) ([]dto.ResourceServer, *model.MetaData, error)
 	GetResourceServerByID(ctx context.Context, rsID string) (*dto.ResourceServer, error)
+	RotateResourceServerSecret(ctx context.Context, rsID string, request dto.RotateSecretRequest) (*dto.RotatedSecret, error)
 }
 
 type MiniRideModule interface {
@@ -118,8 +138,16 @@ type RSAPI interface {
 	GetUsersByIDOrPhone(ctx context.Context,
 		request request_models.RSAPIUsersRequest,
 	) (*dto.RSAPIUsersResponse, error)
+	RegisterAuthorizationDetailTypes(ctx context.Context, request dto.AuthorizationDetailTypesRequest) ([]dto.AuthorizationDetailType, error)
 }
 
 type Asset interface {
 	UploadAsset(ctx context.Context, param dto.UploadAssetRequest) (string, error)
 }
+
+type SigningKeyModule interface {
+	LoadSigningKeys(ctx context.Context) error
+	GetAllSigningKeys(ctx context.Context) ([]dto.SigningKey, error)
+	RotateSigningKey(ctx context.Context, request dto.RotateSigningKeyRequest) (dto.SigningKey, error)
+	RetireSigningKey(ctx context.Context, kid string, request dto.RetireSigningKeyRequest) (dto.SigningKey, error)
+}
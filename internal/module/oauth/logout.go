@@ -0,0 +1,132 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"sso/internal/constant"
+	"sso/internal/constant/model/dto"
+	"sso/platform/logger"
+	"sso/platform/routine"
+	"sso/platform/utils"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// notifyLogout tells the clients the user is signed in to that the session ended.
+// Logout tokens are posted to the backchannel_logout_uri of the clients in the background,
+// and the frontchannel_logout_uri of the clients are returned for the logout page to load.
+func (o *oauth) notifyLogout(ctx context.Context, session dto.InternalRefreshToken) *dto.LogoutResponse {
+	response := &dto.LogoutResponse{}
+	clients, err := o.oauthPersistence.GetLogoutClients(ctx, session.UserID)
+	if err != nil {
+		return response
+	}
+
+	sessionID := session.ID.String()
+	for _, client := range clients {
+		if client.FrontchannelLogoutURI != "" {
+			frontChannelLogoutURI, err := url.Parse(client.FrontchannelLogoutURI)
+			if err != nil {
+				o.logger.Warn(ctx, "invalid frontchannel logout uri", zap.Error(err), zap.String("client-id", client.ID.String()))
+			} else {
+				response.FrontChannelLogoutURIs = append(response.FrontChannelLogoutURIs,
+					utils.GenerateRedirectString(frontChannelLogoutURI, map[string]string{
+						"iss": o.urls.IssuerURL.String(),
+						"sid": sessionID,
+					}))
+			}
+		}
+
+		if client.BackchannelLogoutURI != "" {
+			client := client
+			routine.ExecuteRoutine(ctx, routine.Routine{
+				Name: "back-channel logout",
+				Operation: func(ctx context.Context, log logger.Logger) {
+					o.deliverLogoutToken(ctx, log, client, session.UserID, sessionID)
+				},
+			}, o.logger)
+		}
+	}
+
+	return response
+}
+
+// deliverLogoutToken posts a logout token to the backchannel_logout_uri of the client,
+// retrying until the client acknowledges it or the attempts run out, and records the outcome.
+func (o *oauth) deliverLogoutToken(ctx context.Context, log logger.Logger, client dto.Client, userID uuid.UUID, sessionID string) {
+	delivery := dto.BackChannelLogoutDelivery{
+		ClientID:  client.ID,
+		UserID:    userID,
+		SessionID: sessionID,
+		Status:    constant.LogoutFailed,
+	}
+
+	logoutToken, err := o.token.GenerateLogoutToken(ctx, userID.String(), client.ID.String(), sessionID,
+		client.IDTokenSignedResponseAlg, o.options.LogoutTokenExpireTime)
+	if err != nil {
+		delivery.Error = err.Error()
+		_ = o.oauthPersistence.SaveBackChannelLogoutDelivery(ctx, delivery)
+		return
+	}
+
+	for delivery.Attempts < o.options.BackChannelLogoutAttempts {
+		if delivery.Attempts > 0 && !sleep(ctx, o.options.BackChannelLogoutRetryInterval) {
+			break
+		}
+
+		delivery.Attempts++
+		if err := o.postLogoutToken(ctx, client.BackchannelLogoutURI, logoutToken); err != nil {
+			delivery.Error = err.Error()
+			log.Warn(ctx, "could not deliver logout token", zap.Error(err),
+				zap.String("client-id", client.ID.String()),
+				zap.Int("attempt", delivery.Attempts))
+			continue
+		}
+
+		delivery.Status = constant.LogoutDelivered
+		delivery.Error = ""
+		break
+	}
+
+	_ = o.oauthPersistence.SaveBackChannelLogoutDelivery(ctx, delivery)
+}
+
+// postLogoutToken posts the logout token to the uri, the client acknowledges it with a 200 or a 204.
+func (o *oauth) postLogoutToken(ctx context.Context, uri, logoutToken string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri,
+		strings.NewReader(url.Values{"logout_token": {logoutToken}}.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := o.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("client responded with status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// sleep waits for the duration, it returns false if the context is done before then.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
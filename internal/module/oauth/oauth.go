@@ -3,6 +3,7 @@ package oauth
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
 	"sso/internal/constant"
@@ -35,6 +36,8 @@ type oauth struct {
 	options          Options
 	selfIP           platform.IdentityProvider
 	resetCodeCache   storage.ResetCodeCache
+	urls             state.URLs
+	httpClient       *http.Client
 }
 
 type Options struct {
@@ -42,6 +45,12 @@ type Options struct {
 	RefreshTokenExpireTime time.Duration
 	IDTokenExpireTime      time.Duration
 	ExcludedPhones         state.ExcludedPhones
+	// LogoutTokenExpireTime is how long the logout tokens posted to clients are valid for.
+	LogoutTokenExpireTime time.Duration
+	// BackChannelLogoutAttempts is how many times a logout token is posted to a client before the delivery fails.
+	BackChannelLogoutAttempts int
+	// BackChannelLogoutRetryInterval is how long to wait before posting a logout token again.
+	BackChannelLogoutRetryInterval time.Duration
 }
 
 func SetOptions(options Options) Options {
@@ -54,6 +63,15 @@ func SetOptions(options Options) Options {
 	if options.IDTokenExpireTime == 0 {
 		options.IDTokenExpireTime = time.Minute * 10
 	}
+	if options.LogoutTokenExpireTime == 0 {
+		options.LogoutTokenExpireTime = time.Minute * 2
+	}
+	if options.BackChannelLogoutAttempts == 0 {
+		options.BackChannelLogoutAttempts = 3
+	}
+	if options.BackChannelLogoutRetryInterval == 0 {
+		options.BackChannelLogoutRetryInterval = time.Second * 5
+	}
 	if options.ExcludedPhones.DefaultOTP == "" {
 		options.ExcludedPhones.DefaultOTP = "000000"
 	}
@@ -68,6 +86,7 @@ func InitOAuth(logger logger.Logger,
 	smsClient platform.SMSClient,
 	selfIP platform.IdentityProvider,
 	resetCodeCache storage.ResetCodeCache,
+	urls state.URLs,
 	options Options) module.OAuthModule {
 	return &oauth{
 		logger:           logger,
@@ -79,6 +98,8 @@ func InitOAuth(logger logger.Logger,
 		smsClient:        smsClient,
 		selfIP:           selfIP,
 		resetCodeCache:   resetCodeCache,
+		urls:             urls,
+		httpClient:       &http.Client{Timeout: 10 * time.Second},
 		options:          options,
 	}
 }
@@ -155,45 +176,52 @@ func (o *oauth) Login(ctx context.Context, userParam dto.LoginCredential, userDe
 		return nil, err
 	}
 
+	authentication := dto.Authentication{
+		Time: time.Now(),
+	}
 	if userParam.Email != "" && userParam.Password != "" {
 		if !o.ComparePassword(user.Password, userParam.Password) {
 			err := errors.ErrInvalidUserInput.New("Invalid credentials")
 			o.logger.Info(ctx, "invalid credentials", zap.Error(err))
 			return nil, err
 		}
+		authentication.Methods = []string{constant.AMRPassword}
 	} else if userParam.Phone != "" && userParam.OTP != "" {
 		err := o.VerifyOTP(ctx, userParam.Phone, userParam.OTP)
 		if err != nil {
 			return nil, err
 		}
-
+		authentication.Methods = []string{constant.AMROTP}
 	}
 
-	accessToken, err := o.token.GenerateAccessToken(ctx, user.ID.String(), o.options.AccessTokenExpireTime)
+	refreshToken, err := o.oauthPersistence.SaveInternalRefreshToken(ctx, dto.InternalRefreshToken{
+		RefreshToken:   o.token.GenerateRefreshToken(ctx),
+		UserID:         user.ID,
+		UserAgent:      userDeviceAddress.UserAgent,
+		IPAddress:      userDeviceAddress.IPAddress,
+		ExpiresAt:      time.Now().Add(o.options.RefreshTokenExpireTime),
+		Authentication: authentication,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken := o.token.GenerateRefreshToken(ctx)
-	err = o.oauthPersistence.SaveInternalRefreshToken(ctx, dto.InternalRefreshToken{
-		RefreshToken: refreshToken,
-		UserID:       user.ID,
-		UserAgent:    userDeviceAddress.UserAgent,
-		IPAddress:    userDeviceAddress.IPAddress,
-		ExpiresAt:    time.Now().Add(o.options.RefreshTokenExpireTime),
-	})
+	accessToken, err := o.token.GenerateAccessToken(ctx, user.ID.String(), refreshToken.Authentication, o.options.AccessTokenExpireTime)
 	if err != nil {
 		return nil, err
 	}
 
-	idToken, err := o.token.GenerateIdToken(ctx, user, "sso", o.options.IDTokenExpireTime)
+	idToken, err := o.token.GenerateIdToken(ctx, user, "sso", dto.IDTokenOptions{
+		Authentication: refreshToken.Authentication,
+		AccessToken:    accessToken,
+	}, o.options.IDTokenExpireTime)
 	if err != nil {
 		return nil, err
 	}
 
 	accessTokenResponse := dto.TokenResponse{
 		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+		RefreshToken: refreshToken.RefreshToken,
 		IDToken:      idToken,
 		TokenType:    constant.BearerToken,
 		ExpiresIn:    fmt.Sprintf("%vs", o.options.AccessTokenExpireTime.Seconds()),
@@ -234,27 +262,30 @@ func (o *oauth) GetUserStatus(ctx context.Context, Id string) (string, error) {
 	return status, nil
 }
 
-func (o *oauth) Logout(ctx context.Context, param dto.InternalRefreshTokenRequestBody) error {
+func (o *oauth) Logout(ctx context.Context, param dto.InternalRefreshTokenRequestBody) (*dto.LogoutResponse, error) {
 	if err := param.Validate(); err != nil {
 		err = errors.ErrInvalidUserInput.Wrap(err, "invalid input")
 		o.logger.Info(ctx, "invalid input", zap.Error(err))
-		return nil
+		return nil, nil
 	}
 	oldRefreshToken, err := o.oauthPersistence.GetInternalRefreshToken(ctx, param.RefreshToken)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := o.oauthPersistence.RemoveInternalRefreshToken(ctx, oldRefreshToken.RefreshToken); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return o.notifyLogout(ctx, *oldRefreshToken), nil
 }
 
 func (o *oauth) RefreshToken(ctx context.Context, refreshToken string) (*dto.TokenResponse, error) {
 	oldRefreshToken, err := o.oauthPersistence.GetInternalRefreshToken(ctx, refreshToken)
 	if err != nil {
+		if errorx.IsOfType(err, errors.ErrNoRecordFound) {
+			return nil, o.detectRefreshTokenReuse(ctx, refreshToken, err)
+		}
 		return nil, err
 	}
 
@@ -268,7 +299,7 @@ func (o *oauth) RefreshToken(ctx context.Context, refreshToken string) (*dto.Tok
 		return nil, err
 	}
 
-	accessToken, err := o.token.GenerateAccessToken(ctx, oldRefreshToken.UserID.String(), o.options.AccessTokenExpireTime)
+	accessToken, err := o.token.GenerateAccessToken(ctx, oldRefreshToken.UserID.String(), oldRefreshToken.Authentication, o.options.AccessTokenExpireTime)
 	if err != nil {
 		return nil, err
 	}
@@ -278,12 +309,15 @@ func (o *oauth) RefreshToken(ctx context.Context, refreshToken string) (*dto.Tok
 		return nil, err
 	}
 
-	idToken, err := o.token.GenerateIdToken(ctx, user, "sso", o.options.IDTokenExpireTime)
+	idToken, err := o.token.GenerateIdToken(ctx, user, "sso", dto.IDTokenOptions{
+		Authentication: oldRefreshToken.Authentication,
+		AccessToken:    accessToken,
+	}, o.options.IDTokenExpireTime)
 	if err != nil {
 		return nil, err
 	}
 
-	newRefreshToken, err := o.oauthPersistence.UpdateInternalRefreshToken(ctx, oldRefreshToken.RefreshToken, oldRefreshToken.RefreshToken)
+	newRefreshToken, err := o.oauthPersistence.RotateInternalRefreshToken(ctx, oldRefreshToken.RefreshToken, o.token.GenerateRefreshToken(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -297,6 +331,28 @@ func (o *oauth) RefreshToken(ctx context.Context, refreshToken string) (*dto.Tok
 	}, nil
 }
 
+// detectRefreshTokenReuse checks if an unknown internal refresh token was superseded by a rotation.
+// A superseded token being presented again means it has leaked, so the whole family is revoked.
+func (o *oauth) detectRefreshTokenReuse(ctx context.Context, refreshToken string, notFoundErr error) error {
+	family, err := o.oauthPersistence.GetInternalRefreshTokenFamily(ctx, refreshToken)
+	if err != nil {
+		if errorx.IsOfType(err, errors.ErrNoRecordFound) {
+			return notFoundErr
+		}
+		return err
+	}
+
+	if err := o.oauthPersistence.RevokeInternalRefreshTokenFamily(ctx, *family); err != nil {
+		return err
+	}
+
+	err = errors.ErrAuthError.New("refresh token reuse detected")
+	o.logger.Warn(ctx, "superseded internal refresh token was presented, refresh token family revoked", zap.Error(err),
+		zap.String("family-id", family.ID.String()),
+		zap.String("user-id", family.UserID.String()))
+	return err
+}
+
 func (o *oauth) LoginWithIdentityProvider(ctx context.Context, login request_models.LoginWithIP, userDeviceAddress dto.UserDeviceAddress) (dto.TokenResponse, error) {
 	// validate
 	if err := login.Validate(); err != nil {
@@ -404,33 +460,39 @@ func (o *oauth) LoginWithIdentityProvider(ctx context.Context, login request_mod
 		}
 	}
 
-	internalAccessToken, err := o.token.GenerateAccessToken(ctx, user.ID.String(), o.options.AccessTokenExpireTime)
+	authentication := dto.Authentication{
+		Time:    time.Now(),
+		Methods: []string{constant.AMRFederated},
+	}
+	internalRefreshToken, err := o.oauthPersistence.SaveInternalRefreshToken(ctx, dto.InternalRefreshToken{
+		RefreshToken:   o.token.GenerateRefreshToken(ctx),
+		UserID:         user.ID,
+		UserAgent:      userDeviceAddress.UserAgent,
+		IPAddress:      userDeviceAddress.IPAddress,
+		ExpiresAt:      time.Now().Add(o.options.RefreshTokenExpireTime),
+		Authentication: authentication,
+	})
+
 	if err != nil {
 		return dto.TokenResponse{}, err
 	}
 
-	internalRefreshToken := o.token.GenerateRefreshToken(ctx)
-
-	err = o.oauthPersistence.SaveInternalRefreshToken(ctx, dto.InternalRefreshToken{
-		RefreshToken: internalRefreshToken,
-		UserID:       user.ID,
-		UserAgent:    userDeviceAddress.UserAgent,
-		IPAddress:    userDeviceAddress.IPAddress,
-		ExpiresAt:    time.Now().Add(o.options.RefreshTokenExpireTime),
-	})
-
+	internalAccessToken, err := o.token.GenerateAccessToken(ctx, user.ID.String(), internalRefreshToken.Authentication, o.options.AccessTokenExpireTime)
 	if err != nil {
 		return dto.TokenResponse{}, err
 	}
 
-	idToken, err := o.token.GenerateIdToken(ctx, user, "sso", o.options.IDTokenExpireTime)
+	idToken, err := o.token.GenerateIdToken(ctx, user, "sso", dto.IDTokenOptions{
+		Authentication: internalRefreshToken.Authentication,
+		AccessToken:    internalAccessToken,
+	}, o.options.IDTokenExpireTime)
 	if err != nil {
 		return dto.TokenResponse{}, err
 	}
 
 	accessTokenResponse := dto.TokenResponse{
 		AccessToken:  internalAccessToken,
-		RefreshToken: internalRefreshToken,
+		RefreshToken: internalRefreshToken.RefreshToken,
 		IDToken:      idToken,
 		TokenType:    constant.BearerToken,
 		ExpiresIn:    fmt.Sprintf("%vs", o.options.AccessTokenExpireTime.Seconds()),
@@ -0,0 +1,40 @@
+package oauth2
+
+import (
+	"context"
+
+	"sso/internal/constant/errors"
+	"sso/internal/constant/model/dto"
+
+	"go.uber.org/zap"
+)
+
+// authorizationDetailTypes returns the registered types of the authorization details the client asks for,
+// every type must be registered by a resource server for the request to be allowed.
+func (o *oauth2) authorizationDetailTypes(ctx context.Context, details []dto.AuthorizationDetail) ([]dto.AuthorizationDetailType, error) {
+	requested := dto.AuthorizationDetailTypes(details)
+	registered, err := o.resourceServers.GetAuthorizationDetailTypes(ctx, requested)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(registered) != len(requested) {
+		err := errors.ErrInvalidUserInput.New("invalid authorization details")
+		o.logger.Info(ctx, "authorization details of unregistered types requested", zap.Error(err), zap.Strings("types", requested))
+		return nil, err
+	}
+
+	return registered, nil
+}
+
+// consentAuthorizationDetails parses the authorization details of the consent,
+// they were checked when the consent was created so a failure here is not caused by the user.
+func (o *oauth2) consentAuthorizationDetails(ctx context.Context, consent dto.Consent) ([]dto.AuthorizationDetail, error) {
+	details, err := dto.ParseAuthorizationDetails(consent.AuthorizationDetails)
+	if err != nil {
+		err := errors.ErrInternalServerError.Wrap(err, "invalid authorization details on consent")
+		o.logger.Error(ctx, "unable to parse the authorization details of the consent", zap.Error(err), zap.String("consent-id", consent.ID.String()))
+		return nil, err
+	}
+	return details, nil
+}
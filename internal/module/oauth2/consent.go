@@ -0,0 +1,72 @@
+package oauth2
+
+import (
+	"context"
+
+	"sso/internal/constant"
+	"sso/internal/constant/errors"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+
+	"go.uber.org/zap"
+)
+
+// scopeRequired tells if the user must approve the scope to approve a consent for the client.
+// openid is always required as the response type of the request may depend on it.
+func scopeRequired(client dto.Client, scope string) bool {
+	return scope == constant.OpenID || !utils.ContainsValue(scope, utils.StringToArray(client.OptionalScopes))
+}
+
+// consentScopes tells which of the requested scopes are required for the client and which the user already granted it.
+func consentScopes(client dto.Client, requested []dto.Scope, granted []string) []dto.ConsentScope {
+	scopes := make([]dto.ConsentScope, 0, len(requested))
+	for _, scope := range requested {
+		scopes = append(scopes, dto.ConsentScope{
+			Scope:    scope,
+			Required: scopeRequired(client, scope.Name),
+			Granted:  utils.ContainsValue(scope.Name, granted),
+		})
+	}
+	return scopes
+}
+
+// approvedScope returns the part of the scope of the consent the user approved, in the order it was requested.
+// The user approves every requested scope when they don't tell which, otherwise they must approve every required scope
+// and may only approve the requested ones.
+func (o *oauth2) approvedScope(ctx context.Context, consent dto.Consent, scope string) (string, error) {
+	if scope == "" {
+		return consent.Scope, nil
+	}
+
+	client, err := o.clientPersistence.GetClientByID(ctx, consent.ClientID)
+	if err != nil {
+		return "", err
+	}
+
+	requestedScopes := utils.StringToArray(consent.Scope)
+	approvedScopes := utils.StringToArray(scope)
+	for _, s := range approvedScopes {
+		if !utils.ContainsValue(s, requestedScopes) {
+			err := errors.ErrInvalidUserInput.New("invalid scope")
+			o.logger.Info(ctx, "approved a scope that was not requested", zap.Error(err),
+				zap.String("consent-id", consent.ID.String()), zap.String("scope", s))
+			return "", err
+		}
+	}
+
+	scopes := []string{}
+	for _, s := range requestedScopes {
+		if utils.ContainsValue(s, approvedScopes) {
+			scopes = append(scopes, s)
+			continue
+		}
+		if scopeRequired(*client, s) {
+			err := errors.ErrInvalidUserInput.New("required scope is not approved")
+			o.logger.Info(ctx, "required scope was left out of the approval", zap.Error(err),
+				zap.String("consent-id", consent.ID.String()), zap.String("scope", s))
+			return "", err
+		}
+	}
+
+	return utils.ArrayToString(scopes), nil
+}
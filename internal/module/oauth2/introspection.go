@@ -0,0 +1,142 @@
+package oauth2
+
+import (
+	"context"
+	"sso/internal/constant"
+	"sso/internal/constant/errors"
+	"sso/internal/constant/model/dto"
+
+	"github.com/google/uuid"
+	"github.com/joomcode/errorx"
+	"go.uber.org/zap"
+)
+
+func (o *oauth2) Introspect(ctx context.Context, param dto.IntrospectionRequest) (*dto.IntrospectionResponse, error) {
+	if err := param.Validate(); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		o.logger.Info(ctx, "invalid input", zap.Error(err))
+		return nil, err
+	}
+
+	introspectors := []func(ctx context.Context, token string) (*dto.IntrospectionResponse, error){
+		o.introspectAccessToken,
+		o.introspectRefreshToken,
+	}
+	if param.TokenTypeHint == constant.RefreshTokenHint {
+		introspectors[0], introspectors[1] = introspectors[1], introspectors[0]
+	}
+
+	for _, introspect := range introspectors {
+		resp, err := introspect(ctx, param.Token)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Active {
+			return resp, nil
+		}
+	}
+
+	return &dto.IntrospectionResponse{Active: false}, nil
+}
+
+func (o *oauth2) introspectAccessToken(ctx context.Context, token string) (*dto.IntrospectionResponse, error) {
+	valid, claims := o.token.VerifyAccessToken(token)
+	if !valid {
+		return &dto.IntrospectionResponse{Active: false}, nil
+	}
+
+	revoked, err := o.IsTokenRevoked(ctx, claims.ID)
+	if err != nil || revoked {
+		return &dto.IntrospectionResponse{Active: false}, err
+	}
+
+	clientID := claims.Client()
+	active, err := o.subjectActive(ctx, claims.Subject, clientID)
+	if err != nil || !active {
+		return &dto.IntrospectionResponse{Active: false}, err
+	}
+
+	resp := &dto.IntrospectionResponse{
+		Active:    true,
+		Scope:     claims.Scope,
+		ClientID:  clientID,
+		Sub:       claims.Subject,
+		TokenType: constant.AccessTokenHint,
+		Aud:       claims.Audience,
+		Act:       claims.Actor,
+
+		AuthorizationDetails: claims.AuthorizationDetails,
+	}
+	if claims.ExpiresAt != nil {
+		resp.Exp = claims.ExpiresAt.Unix()
+	}
+
+	return resp, nil
+}
+
+func (o *oauth2) introspectRefreshToken(ctx context.Context, token string) (*dto.IntrospectionResponse, error) {
+	refreshToken, err := o.oauth2Persistence.GetRefreshToken(ctx, token)
+	if err != nil {
+		if errorx.IsOfType(err, errors.ErrNoRecordFound) {
+			return &dto.IntrospectionResponse{Active: false}, nil
+		}
+		return nil, err
+	}
+
+	client, err := o.clientPersistence.GetClientByID(ctx, refreshToken.ClientID)
+	if err != nil {
+		if errorx.IsOfType(err, errors.ErrNoRecordFound) {
+			return &dto.IntrospectionResponse{Active: false}, nil
+		}
+		return nil, err
+	}
+	if refreshTokenExpired(*client, *refreshToken) {
+		return &dto.IntrospectionResponse{Active: false}, nil
+	}
+
+	active, err := o.subjectActive(ctx, refreshToken.UserID.String(), refreshToken.ClientID.String())
+	if err != nil || !active {
+		return &dto.IntrospectionResponse{Active: false}, err
+	}
+
+	return &dto.IntrospectionResponse{
+		Active:    true,
+		Scope:     refreshToken.Scope,
+		ClientID:  refreshToken.ClientID.String(),
+		Sub:       refreshToken.UserID.String(),
+		Exp:       refreshTokenExpiry(*client, *refreshToken).Unix(),
+		TokenType: constant.RefreshTokenHint,
+	}, nil
+}
+
+// subjectActive tells if the subject of a token is still active.
+// The subject is the client itself for tokens issued on the client_credentials grant, otherwise it's a user.
+func (o *oauth2) subjectActive(ctx context.Context, subject, clientID string) (bool, error) {
+	subjectID, err := uuid.Parse(subject)
+	if err != nil {
+		o.logger.Info(ctx, "token subject is not a valid id", zap.Error(err), zap.String("subject", subject))
+		return false, nil
+	}
+
+	var status string
+	if subject == clientID {
+		client, err := o.clientPersistence.GetClientByID(ctx, subjectID)
+		if err != nil {
+			if errorx.IsOfType(err, errors.ErrNoRecordFound) {
+				return false, nil
+			}
+			return false, err
+		}
+		status = client.Status
+	} else {
+		status, err = o.oauthPersistence.GetUserStatus(ctx, subjectID)
+		if err != nil {
+			if errorx.IsOfType(err, errors.ErrNoRecordFound) {
+				return false, nil
+			}
+			return false, err
+		}
+	}
+
+	return status == constant.Active, nil
+}
@@ -0,0 +1,68 @@
+package oauth2
+
+import (
+	"time"
+
+	"sso/internal/constant/model/dto"
+)
+
+// clientOptions returns the options with the token lifetimes set on the client applied over the sso wide ones.
+func (o *oauth2) clientOptions(client dto.Client) Options {
+	options := o.options
+	if client.AccessTokenLifetime > 0 {
+		options.AccessTokenExpireTime = seconds(client.AccessTokenLifetime)
+	}
+	if client.RefreshTokenLifetime > 0 {
+		options.RefreshTokenExpireTime = seconds(client.RefreshTokenLifetime)
+	}
+	if client.IDTokenLifetime > 0 {
+		options.IDTokenExpireTime = seconds(client.IDTokenLifetime)
+	}
+	return options
+}
+
+// authCodeExpiresAt returns when the auth code issued to the client expires,
+// the zero time leaves it to the default lifetime of the auth code cache.
+func authCodeExpiresAt(client dto.Client) time.Time {
+	if client.AuthCodeLifetime <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(seconds(client.AuthCodeLifetime))
+}
+
+// refreshTokenExpiresAt returns when the refresh token issued to the client now expires,
+// it never outlives the absolute lifetime of the client.
+func (o *oauth2) refreshTokenExpiresAt(client dto.Client) time.Time {
+	lifetime := o.clientOptions(client).RefreshTokenExpireTime
+	if client.RefreshTokenAbsoluteLifetime > 0 && seconds(client.RefreshTokenAbsoluteLifetime) < lifetime {
+		lifetime = seconds(client.RefreshTokenAbsoluteLifetime)
+	}
+	return time.Now().Add(lifetime)
+}
+
+// refreshTokenExpiry returns the time the refresh token expires, the earliest of its own expiry,
+// the end of the idle lifetime of the client since it was last used and the end of the absolute lifetime of the client.
+func refreshTokenExpiry(client dto.Client, refreshToken dto.RefreshToken) time.Time {
+	expiry := refreshToken.ExpiresAt
+	if client.RefreshTokenIdleLifetime > 0 {
+		if idleExpiry := refreshToken.UpdatedAt.Add(seconds(client.RefreshTokenIdleLifetime)); idleExpiry.Before(expiry) {
+			expiry = idleExpiry
+		}
+	}
+	if client.RefreshTokenAbsoluteLifetime > 0 {
+		if absoluteExpiry := refreshToken.CreatedAt.Add(seconds(client.RefreshTokenAbsoluteLifetime)); absoluteExpiry.Before(expiry) {
+			expiry = absoluteExpiry
+		}
+	}
+	return expiry
+}
+
+// refreshTokenExpired tells if the refresh token expired, went unused for longer than the idle lifetime of the client
+// or outlived the absolute lifetime of the client.
+func refreshTokenExpired(client dto.Client, refreshToken dto.RefreshToken) bool {
+	return time.Now().After(refreshTokenExpiry(client, refreshToken))
+}
+
+func seconds(s int) time.Duration {
+	return time.Duration(s) * time.Second
+}
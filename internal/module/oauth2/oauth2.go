@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"path"
 	"sso/internal/constant"
 	"sso/internal/constant/errors"
 	"sso/internal/constant/model/dto"
@@ -14,12 +15,12 @@ import (
 	"sso/platform"
 	"sso/platform/logger"
 	"sso/platform/utils"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/joomcode/errorx"
 
-	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -28,6 +29,9 @@ type Options struct {
 	AccessTokenExpireTime  time.Duration
 	RefreshTokenExpireTime time.Duration
 	IDTokenExpireTime      time.Duration
+	DeviceCodeExpireTime   time.Duration
+	DevicePollInterval     time.Duration
+	ConsentExpireTime      time.Duration
 }
 
 func SetOptions(options Options) Options {
@@ -40,38 +44,55 @@ func SetOptions(options Options) Options {
 	if options.IDTokenExpireTime == 0 {
 		options.IDTokenExpireTime = time.Minute * 10
 	}
+	if options.DeviceCodeExpireTime == 0 {
+		options.DeviceCodeExpireTime = time.Minute * 10
+	}
+	if options.DevicePollInterval == 0 {
+		options.DevicePollInterval = time.Second * 5
+	}
+	if options.ConsentExpireTime == 0 {
+		options.ConsentExpireTime = time.Hour
+	}
 	return options
 }
 
 type oauth2 struct {
-	logger            logger.Logger
-	oauth2Persistence storage.OAuth2Persistence
-	oauthPersistence  storage.OAuthPersistence
-	clientPersistence storage.ClientPersistence
-	consentCache      storage.ConsentCache
-	authCodeCache     storage.AuthCodeCache
-	token             platform.Token
-	options           Options
-	scopePersistence  storage.ScopePersistence
-	urls              state.URLs
-}
-
-func InitOAuth2(logger logger.Logger, oauth2Persistence storage.OAuth2Persistence, oauthPersistence storage.OAuthPersistence, clientPersistence storage.ClientPersistence, consentCache storage.ConsentCache, authCodeCache storage.AuthCodeCache, token platform.Token, options Options, scope storage.ScopePersistence, urls state.URLs) module.OAuth2Module {
+	logger             logger.Logger
+	oauth2Persistence  storage.OAuth2Persistence
+	oauthPersistence   storage.OAuthPersistence
+	clientPersistence  storage.ClientPersistence
+	consentCache       storage.ConsentCache
+	authCodeCache      storage.AuthCodeCache
+	deviceCache        storage.DeviceCache
+	revokedTokenCache  storage.RevokedTokenCache
+	requestObjectCache storage.RequestObjectCache
+	token              platform.Token
+	options            Options
+	scopePersistence   storage.ScopePersistence
+	resourceServers    storage.ResourceServerPersistence
+	urls               state.URLs
+}
+
+func InitOAuth2(logger logger.Logger, oauth2Persistence storage.OAuth2Persistence, oauthPersistence storage.OAuthPersistence, clientPersistence storage.ClientPersistence, consentCache storage.ConsentCache, authCodeCache storage.AuthCodeCache, deviceCache storage.DeviceCache, revokedTokenCache storage.RevokedTokenCache, requestObjectCache storage.RequestObjectCache, token platform.Token, options Options, scope storage.ScopePersistence, resourceServers storage.ResourceServerPersistence, urls state.URLs) module.OAuth2Module {
 	return &oauth2{
-		logger:            logger,
-		oauth2Persistence: oauth2Persistence,
-		oauthPersistence:  oauthPersistence,
-		clientPersistence: clientPersistence,
-		consentCache:      consentCache,
-		authCodeCache:     authCodeCache,
-		token:             token,
-		options:           options,
-		scopePersistence:  scope,
-		urls:              urls,
+		logger:             logger,
+		oauth2Persistence:  oauth2Persistence,
+		oauthPersistence:   oauthPersistence,
+		clientPersistence:  clientPersistence,
+		consentCache:       consentCache,
+		authCodeCache:      authCodeCache,
+		deviceCache:        deviceCache,
+		revokedTokenCache:  revokedTokenCache,
+		requestObjectCache: requestObjectCache,
+		token:              token,
+		options:            options,
+		scopePersistence:   scope,
+		resourceServers:    resourceServers,
+		urls:               urls,
 	}
 }
 
-func (o *oauth2) Authorize(ctx context.Context, authRequestParm dto.AuthorizationRequestParam, requestOrigin string, bindError *errorx.Error) string {
+func (o *oauth2) Authorize(ctx context.Context, authRequestParm dto.AuthorizationRequestParam, requestOrigin string, session dto.BrowserSession, bindError *errorx.Error) string {
 	if bindError != nil {
 		o.logger.Info(ctx, "error while binding to query", zap.Error(bindError))
 		return utils.GenerateRedirectString(o.urls.ErrorURL, map[string]string{
@@ -80,6 +101,38 @@ func (o *oauth2) Authorize(ctx context.Context, authRequestParm dto.Authorizatio
 		})
 	}
 
+	if authRequestParm.RequestURI != "" && authRequestParm.Request != "" {
+		o.logger.Info(ctx, "both request and request_uri were sent", zap.String("client-id", authRequestParm.ClientID.String()))
+		return utils.GenerateRedirectString(o.urls.ErrorURL, map[string]string{
+			"error":             "invalid_request",
+			"error_description": "request and request_uri can not be used together",
+		})
+	}
+
+	pushed := authRequestParm.RequestURI != ""
+	if pushed {
+		pushedRequest, err := o.pushedAuthorizationRequest(ctx, authRequestParm)
+		if err != nil {
+			return utils.GenerateRedirectString(o.urls.ErrorURL, map[string]string{
+				"error":             "invalid_request_uri",
+				"error_description": errorx.Cast(err).Message(),
+			})
+		}
+		authRequestParm = pushedRequest
+	}
+
+	if authRequestParm.Request != "" {
+		requestParam, err := o.requestObject(ctx, authRequestParm)
+		if err != nil {
+			return utils.GenerateRedirectString(o.urls.ErrorURL, map[string]string{
+				"error":             "invalid_request_object",
+				"error_description": errorx.Cast(err).Message(),
+			})
+		}
+		authRequestParm = requestParam
+	}
+
+	authRequestParm.NormalizeResponseType()
 	if er := authRequestParm.Validate(); er != nil {
 		err := errors.ErrInvalidUserInput.Wrap(er, "invalid input")
 		o.logger.Info(ctx, "invalid input", zap.Error(err))
@@ -115,29 +168,57 @@ func (o *oauth2) Authorize(ctx context.Context, authRequestParm dto.Authorizatio
 		})
 	}
 
-	scopes, err := o.scopePersistence.GetScopeNameOnly(ctx, strings.Split(authRequestParm.Scope, " ")...)
-	if (err != nil || scopes == "") && !client.FirstParty {
-		err := errors.ErrInvalidUserInput.New("invalid scope")
-		o.logger.Info(ctx, "invalid scope", zap.Error(err))
+	responseMode := authRequestParm.GetResponseMode()
+	if client.RequirePushedAuthorizationRequests && !pushed {
+		err := errors.ErrInvalidUserInput.New("pushed authorization request is required")
+		o.logger.Info(ctx, "client requires pushed authorization requests", zap.Error(err), zap.String("client-id", client.ID.String()))
 
-		return utils.GenerateRedirectString(redirectURI, map[string]string{
-			"error":             "invalid_scope",
-			"error_description": "invalid scope",
+		return o.authorizationResponse(redirectURI, responseMode, map[string]string{
+			"error":             "invalid_request",
+			"error_description": "pushed authorization request is required",
+			"state":             authRequestParm.State,
 		})
 	}
 
+	scopes, errorCode, err := o.checkAuthorizationRequest(ctx, *client, authRequestParm)
+	if err != nil {
+		return o.authorizationResponse(redirectURI, responseMode, map[string]string{
+			"error":             errorCode,
+			"error_description": errorx.Cast(err).Message(),
+			"state":             authRequestParm.State,
+		})
+	}
+
+	// the session_state is checked against the origin of the relying party page embedding the check session iframe,
+	// which is the origin of the redirect uri when the user agent didn't tell the origin of the request.
+	if requestOrigin == "" {
+		requestOrigin = redirectURI.Scheme + "://" + redirectURI.Host
+	}
+
 	consent := dto.Consent{
 		ID: uuid.New(),
 		AuthorizationRequestParam: dto.AuthorizationRequestParam{
-			ClientID:     client.ID,
-			Scope:        scopes,
-			RedirectURI:  authRequestParm.RedirectURI,
-			State:        authRequestParm.State,
-			ResponseType: authRequestParm.ResponseType,
-			Prompt:       authRequestParm.Prompt,
+			ClientID:             client.ID,
+			Scope:                scopes,
+			RedirectURI:          authRequestParm.RedirectURI,
+			State:                authRequestParm.State,
+			ResponseType:         authRequestParm.ResponseType,
+			Prompt:               authRequestParm.Prompt,
+			CodeChallenge:        authRequestParm.CodeChallenge,
+			CodeChallengeMethod:  authRequestParm.CodeChallengeMethod,
+			Nonce:                authRequestParm.Nonce,
+			ResponseMode:         responseMode,
+			MaxAge:               authRequestParm.MaxAge,
+			Resource:             authRequestParm.Resource,
+			AuthorizationDetails: authRequestParm.AuthorizationDetails,
 		},
 		RequestOrigin: requestOrigin,
+		CreatedAt:     time.Now(),
+	}
+	if authRequestParm.Prompt == constant.PromptNone {
+		return o.authorizeWithoutPrompt(ctx, consent, redirectURI, session)
 	}
+
 	if err := o.consentCache.SaveConsent(ctx, consent); err != nil {
 		return utils.GenerateRedirectString(o.urls.ErrorURL, map[string]string{
 			"error":             "server_error",
@@ -145,12 +226,40 @@ func (o *oauth2) Authorize(ctx context.Context, authRequestParm dto.Authorizatio
 		})
 	}
 
+	// the consent page has the user log in again when the session is older than the max_age of the request
+	prompt := authRequestParm.Prompt
+	if authRequestParm.MaxAge != nil {
+		if refreshToken := o.browserSession(ctx, session); refreshToken == nil || !authenticatedWithin(refreshToken.Authentication, authRequestParm.MaxAge, consent.CreatedAt) {
+			prompt = constant.PromptLogin
+		}
+	}
+
 	return utils.GenerateRedirectString(o.urls.ConsentURL, map[string]string{
 		"consentId": consent.ID.String(),
-		"prompt":    authRequestParm.Prompt,
+		"prompt":    prompt,
 	})
 }
 
+// authorizationResponse returns the redirect uri with the given parameters added
+// to its query or fragment, depending on the response mode. Empty parameters are left out.
+func (o *oauth2) authorizationResponse(redirectURI *url.URL, responseMode string, params map[string]string) string {
+	for k, v := range params {
+		if v == "" {
+			delete(params, k)
+		}
+	}
+	if responseMode == constant.ResponseModeFragment {
+		return utils.GenerateFragmentRedirectString(redirectURI, params)
+	}
+	return utils.GenerateRedirectString(redirectURI, params)
+}
+
+// authentication returns how and when the user of the request authenticated, as set by the authentication middleware.
+func authentication(ctx context.Context) dto.Authentication {
+	authentication, _ := ctx.Value(constant.Context("x-authentication")).(dto.Authentication)
+	return authentication
+}
+
 // ContainsRedirectURL
 func (o *oauth2) ContainsRedirectURL(redirectURIs []string, redirectURI string) bool {
 	for _, ru := range redirectURIs {
@@ -169,7 +278,7 @@ func (o *oauth2) GetConsentByID(ctx context.Context, consentID string) (dto.Cons
 		return dto.ConsentResponse{}, err
 	}
 
-	consent, err := o.consentCache.GetConsent(ctx, consentID)
+	consent, err := o.getConsent(ctx, consentID)
 	if err != nil {
 		return dto.ConsentResponse{}, err
 	}
@@ -204,8 +313,20 @@ func (o *oauth2) GetConsentByID(ctx context.Context, consentID string) (dto.Cons
 		return dto.ConsentResponse{}, err
 	}
 
+	details, err := o.consentAuthorizationDetails(ctx, consent)
+	if err != nil {
+		return dto.ConsentResponse{}, err
+	}
+	var detailTypes []dto.AuthorizationDetailType
+	if len(details) > 0 {
+		if detailTypes, err = o.authorizationDetailTypes(ctx, details); err != nil {
+			return dto.ConsentResponse{}, err
+		}
+	}
+
 	grantedScopes := utils.StringToArray(refreshToken.Scope)
-	if check {
+	// authorization details are specific to the transaction, so they are never previously approved.
+	if check && len(details) == 0 {
 		for _, rs := range requestedscopes {
 			if !utils.ContainsValue(rs.Name, grantedScopes) {
 				clientStatus = false
@@ -216,7 +337,7 @@ func (o *oauth2) GetConsentByID(ctx context.Context, consentID string) (dto.Cons
 		clientStatus = false
 	}
 	return dto.ConsentResponse{
-		Scopes:        requestedscopes,
+		Scopes:        consentScopes(*client, requestedscopes, grantedScopes),
 		ClientName:    client.Name,
 		ClientLogo:    client.LogoURL,
 		ClientType:    client.ClientType,
@@ -224,10 +345,13 @@ func (o *oauth2) GetConsentByID(ctx context.Context, consentID string) (dto.Cons
 		ClientID:      client.ID,
 		Approved:      clientStatus,
 		UserID:        user.ID,
+
+		AuthorizationDetails:     details,
+		AuthorizationDetailTypes: detailTypes,
 	}, nil
 }
 
-func (o *oauth2) ApproveConsent(ctx context.Context, consentID string, userID uuid.UUID, opbs string, bindError *errorx.Error) string {
+func (o *oauth2) ApproveConsent(ctx context.Context, consentID, scope string, userID uuid.UUID, opbs string, bindError *errorx.Error) string {
 	if bindError != nil {
 		o.logger.Info(ctx, "error while binding to query", zap.Error(bindError))
 		return utils.GenerateRedirectString(o.urls.ErrorURL, map[string]string{
@@ -235,7 +359,7 @@ func (o *oauth2) ApproveConsent(ctx context.Context, consentID string, userID uu
 		})
 	}
 	// check if consent is valid
-	consent, err := o.consentCache.GetConsent(ctx, consentID)
+	consent, err := o.getConsent(ctx, consentID)
 	if err != nil {
 		return utils.GenerateRedirectString(o.urls.ErrorURL, map[string]string{
 			"error":       "consent not found",
@@ -243,6 +367,23 @@ func (o *oauth2) ApproveConsent(ctx context.Context, consentID string, userID uu
 		})
 	}
 
+	approvedScope, err := o.approvedScope(ctx, consent, scope)
+	if err != nil {
+		errx := errorx.Cast(err)
+		return utils.GenerateRedirectString(o.urls.ErrorURL, map[string]string{
+			"error":       errx.Message(),
+			"description": errx.Error(),
+		})
+	}
+	if approvedScope != consent.Scope {
+		consent.RequestedScope = consent.Scope
+		consent.Scope = approvedScope
+	}
+
+	if consent.DeviceCode != "" {
+		return o.completeDeviceConsent(ctx, consent, constant.DeviceAuthorizationApproved, userID)
+	}
+
 	redirectURI, err := url.Parse(consent.RedirectURI)
 	if err != nil {
 		o.logger.Error(ctx, "invalid redirectURI was found", zap.Error(err), zap.String("redirect_uri", consent.RedirectURI))
@@ -251,33 +392,110 @@ func (o *oauth2) ApproveConsent(ctx context.Context, consentID string, userID uu
 		})
 	}
 
-	authCode := dto.AuthCode{
-		Code:        utils.GenerateTimeStampedRandomString(25, false),
-		Scope:       consent.Scope,
-		RedirectURI: consent.RedirectURI,
-		ClientID:    consent.ClientID,
-		UserID:      userID,
-		State:       consent.State,
+	if !authenticatedWithin(authentication(ctx), consent.MaxAge, consent.CreatedAt) {
+		o.logger.Info(ctx, "consent approved by a user who did not authenticate within max_age",
+			zap.String("client-id", consent.ClientID.String()), zap.String("user-id", userID.String()))
+		return o.authorizationResponse(redirectURI, consent.GetResponseMode(), map[string]string{
+			"error":             "login_required",
+			"error_description": "the user must authenticate again",
+			"state":             consent.State,
+		})
 	}
-	if err := o.authCodeCache.SaveAuthCode(ctx, authCode); err != nil {
+
+	params, err := o.authorizationResponseParams(ctx, consent, userID)
+	if err != nil {
 		errx := errorx.Cast(err)
 		return utils.GenerateRedirectString(o.urls.ErrorURL, map[string]string{
 			"error":       errx.Message(),
 			"description": errx.Error(),
 		})
 	}
+	params["state"] = consent.State
 
-	queries := map[string]string{}
-	queries["code"] = authCode.Code
-	if consent.State != "" {
-		queries["state"] = consent.State
+	// calculate session state
+	sessionState := utils.CalculateSessionState(consent.ClientID.String(), consent.RequestOrigin, opbs, utils.GenerateRandomString(20, false))
+	params["session_state"] = sessionState
+
+	return o.authorizationResponse(redirectURI, consent.GetResponseMode(), params)
+}
+
+// authorizationResponseParams issues what the response type of the consent asks for:
+// an authorization code, an access token and an id token carrying the nonce of the request.
+func (o *oauth2) authorizationResponseParams(ctx context.Context, consent dto.Consent, userID uuid.UUID) (map[string]string, error) {
+	client, err := o.clientPersistence.GetClientByID(ctx, consent.ClientID)
+	if err != nil {
+		return nil, err
 	}
+	options := o.clientOptions(*client)
 
-	// calculate session state
-	sessionState := utils.CalculateSessionState(authCode.ClientID.String(), consent.RequestOrigin, opbs, utils.GenerateRandomString(20, false))
-	queries["session_state"] = sessionState
+	params := map[string]string{}
+	details, err := o.consentAuthorizationDetails(ctx, consent)
+	if err != nil {
+		return nil, err
+	}
+
+	if consent.HasResponseType(constant.ResponseTypeCode) {
+		authCode := dto.AuthCode{
+			Code:                 utils.GenerateTimeStampedRandomString(25, false),
+			Scope:                consent.Scope,
+			RequestedScope:       consent.RequestedScope,
+			RedirectURI:          consent.RedirectURI,
+			ClientID:             consent.ClientID,
+			UserID:               userID,
+			State:                consent.State,
+			CodeChallenge:        consent.CodeChallenge,
+			CodeChallengeMethod:  consent.CodeChallengeMethod,
+			Nonce:                consent.Nonce,
+			Resources:            consent.Resource,
+			AuthorizationDetails: details,
+			Authentication:       authentication(ctx),
+			ExpiresAt:            authCodeExpiresAt(*client),
+		}
+		if err := o.authCodeCache.SaveAuthCode(ctx, authCode); err != nil {
+			return nil, err
+		}
+		params["code"] = authCode.Code
+	}
 
-	return utils.GenerateRedirectString(redirectURI, queries)
+	if consent.HasResponseType(constant.ResponseTypeToken) {
+		scope := consent.Scope
+		if len(consent.Resource) > 0 {
+			if scope, err = o.resourceScope(ctx, consent.Scope, consent.Resource); err != nil {
+				return nil, err
+			}
+			params["scope"] = scope
+		}
+		accessToken, err := o.token.GenerateAccessTokenForClient(ctx, userID.String(), consent.ClientID.String(), scope, dto.AccessTokenOptions{
+			Resources:            consent.Resource,
+			AuthorizationDetails: details,
+		}, options.AccessTokenExpireTime)
+		if err != nil {
+			return nil, err
+		}
+		params["access_token"] = accessToken
+		params["token_type"] = constant.BearerToken
+		params["expires_in"] = strconv.Itoa(int(options.AccessTokenExpireTime.Seconds()))
+	}
+
+	if consent.HasResponseType(constant.ResponseTypeIDToken) {
+		user, err := o.oauthPersistence.GetUserByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		idToken, err := o.token.GenerateIdToken(ctx, user, consent.ClientID.String(), dto.IDTokenOptions{
+			Nonce:            consent.Nonce,
+			Authentication:   authentication(ctx),
+			AccessToken:      params["access_token"],
+			Code:             params["code"],
+			SigningAlgorithm: client.IDTokenSignedResponseAlg,
+		}, options.IDTokenExpireTime)
+		if err != nil {
+			return nil, err
+		}
+		params["id_token"] = idToken
+	}
+
+	return params, nil
 }
 
 func (o *oauth2) RejectConsent(ctx context.Context, consentID, failureReason string, bindError *errorx.Error) string {
@@ -288,7 +506,7 @@ func (o *oauth2) RejectConsent(ctx context.Context, consentID, failureReason str
 		})
 	}
 	// check if consent is valid
-	consent, err := o.consentCache.GetConsent(ctx, consentID)
+	consent, err := o.getConsent(ctx, consentID)
 	if err != nil {
 		return utils.GenerateRedirectString(o.urls.ErrorURL, map[string]string{
 			"error":       "consent not found",
@@ -296,6 +514,10 @@ func (o *oauth2) RejectConsent(ctx context.Context, consentID, failureReason str
 		})
 	}
 
+	if consent.DeviceCode != "" {
+		return o.completeDeviceConsent(ctx, consent, constant.DeviceAuthorizationDenied, uuid.Nil)
+	}
+
 	redirectURI, err := url.Parse(consent.RedirectURI)
 	if err != nil {
 		o.logger.Error(ctx, "invalid redirectURI was found", zap.Error(err), zap.String("redirect_uri", consent.RedirectURI))
@@ -304,16 +526,14 @@ func (o *oauth2) RejectConsent(ctx context.Context, consentID, failureReason str
 		})
 	}
 
-	queries := map[string]string{}
 	if failureReason == "" {
 		failureReason = "unknown error"
 	}
-	queries["error"] = failureReason
-	if consent.State != "" {
-		queries["state"] = consent.State
-	}
 
-	return utils.GenerateRedirectString(redirectURI, queries)
+	return o.authorizationResponse(redirectURI, consent.GetResponseMode(), map[string]string{
+		"error": failureReason,
+		"state": consent.State,
+	})
 }
 
 func (o *oauth2) Token(ctx context.Context, client dto.Client, param dto.AccessTokenRequest) (*dto.TokenResponse, error) {
@@ -326,10 +546,23 @@ func (o *oauth2) Token(ctx context.Context, client dto.Client, param dto.AccessT
 	grantTypes := map[string]func(ctx context.Context, client dto.Client, param dto.AccessTokenRequest) (*dto.TokenResponse, error){
 		constant.AuthorizationCode: o.authorizationCodeGrant,
 		constant.RefreshToken:      o.refreshToken,
+		constant.ClientCredentials: o.clientCredentialsGrant,
+		constant.DeviceCode:        o.deviceCodeGrant,
+		constant.TokenExchange:     o.tokenExchangeGrant,
 	}
 
 	// Grant processing
-	grantHandler := grantTypes[param.GrantType]
+	grantHandler, ok := grantTypes[param.GrantType]
+	if !ok {
+		err := errors.ErrInvalidUserInput.New("unsupported grant_type")
+		o.logger.Info(ctx, "unsupported grant type", zap.Error(err), zap.String("grant-type", param.GrantType))
+		return nil, err
+	}
+	if !client.AllowsGrantType(param.GrantType) {
+		err := errors.ErrAcessError.New("unauthorized_client")
+		o.logger.Info(ctx, "grant type is not registered for the client", zap.Error(err), zap.String("grant-type", param.GrantType), zap.String("client-id", client.ID.String()))
+		return nil, err
+	}
 	resp, err := grantHandler(ctx, client, param)
 	if err != nil {
 		return nil, err
@@ -390,47 +623,81 @@ func (o *oauth2) authorizationCodeGrant(ctx context.Context, client dto.Client,
 		}
 	}
 
-	accessToken, err := o.token.GenerateAccessTokenForClient(ctx, authcode.UserID.String(), client.ID.String(), authcode.Scope, o.options.AccessTokenExpireTime)
-	if err != nil {
+	if err := o.verifyCodeVerifier(ctx, client, authcode, param.CodeVerifier); err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := o.oauth2Persistence.GetRefreshTokenOfClientByUserID(ctx, authcode.UserID, authcode.ClientID)
+	authcode.Resources, err = o.tokenResources(ctx, param.Resource, authcode.Resources)
 	if err != nil {
-		if !errorx.IsOfType(err, errors.ErrNoRecordFound) {
-			return nil, err
-		}
-		refreshToken, err = o.oauth2Persistence.PersistRefreshToken(ctx, dto.RefreshToken{
-			UserID:       authcode.UserID,
-			RefreshToken: o.token.GenerateRefreshToken(ctx),
-			ClientID:     authcode.ClientID,
-			Scope:        authcode.Scope,
-			RedirectUri:  authcode.RedirectURI,
-			Code:         authcode.Code,
-			ExpiresAt:    time.Now().Add(o.options.RefreshTokenExpireTime),
-		})
-		if err != nil {
-			return nil, err
-		}
-		if _, err := o.oauth2Persistence.AddAuthHistory(
-			ctx,
-			dto.AuthHistory{
-				Code:        authcode.Code,
-				UserID:      authcode.UserID,
-				ClientID:    authcode.ClientID,
-				Scope:       authcode.Scope,
-				RedirectUri: authcode.RedirectURI,
-				Status:      constant.Grant,
-			},
-		); err != nil {
+		return nil, err
+	}
+
+	return o.issueTokens(ctx, client, authcode)
+}
+
+// issueTokens issues the access token, refresh token and, for openid scopes, id token of an authorization the user granted.
+// The access token is restricted to the resource servers of the authorization, if any.
+func (o *oauth2) issueTokens(ctx context.Context, client dto.Client, authcode dto.AuthCode) (*dto.TokenResponse, error) {
+	options := o.clientOptions(client)
+	accessTokenScope := authcode.Scope
+	if len(authcode.Resources) > 0 {
+		var err error
+		if accessTokenScope, err = o.resourceScope(ctx, authcode.Scope, authcode.Resources); err != nil {
 			return nil, err
 		}
 	}
+
+	accessToken, err := o.token.GenerateAccessTokenForClient(ctx, authcode.UserID.String(), client.ID.String(), accessTokenScope, dto.AccessTokenOptions{
+		Resources:            authcode.Resources,
+		AuthorizationDetails: authcode.AuthorizationDetails,
+	}, options.AccessTokenExpireTime)
+	if err != nil {
+		return nil, err
+	}
+
+	// every grant starts a refresh token family of its own, sharing one between the logins of the user to the client
+	// would make the rotation on one of them look like a reuse on the others.
+	requestedScope := authcode.RequestedScope
+	if requestedScope == "" {
+		requestedScope = authcode.Scope
+	}
+	refreshToken, err := o.oauth2Persistence.PersistRefreshToken(ctx, dto.RefreshToken{
+		UserID:         authcode.UserID,
+		RefreshToken:   o.token.GenerateRefreshToken(ctx),
+		ClientID:       authcode.ClientID,
+		Scope:          authcode.Scope,
+		RequestedScope: requestedScope,
+		RedirectUri:    authcode.RedirectURI,
+		Code:           authcode.Code,
+		ExpiresAt:      o.refreshTokenExpiresAt(client),
+		Authentication: authcode.Authentication,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := o.oauth2Persistence.AddAuthHistory(
+		ctx,
+		dto.AuthHistory{
+			Code:        authcode.Code,
+			UserID:      authcode.UserID,
+			ClientID:    authcode.ClientID,
+			Scope:       authcode.Scope,
+			RedirectUri: authcode.RedirectURI,
+			Status:      constant.Grant,
+		},
+	); err != nil {
+		return nil, err
+	}
 	tokenResponse := &dto.TokenResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken.RefreshToken,
 		TokenType:    constant.BearerToken,
-		ExpiresIn:    fmt.Sprintf("%vs", o.options.AccessTokenExpireTime.Seconds()),
+		ExpiresIn:    fmt.Sprintf("%vs", options.AccessTokenExpireTime.Seconds()),
+
+		AuthorizationDetails: authcode.AuthorizationDetails,
+	}
+	if len(authcode.Resources) > 0 {
+		tokenResponse.Scope = accessTokenScope
 	}
 	if utils.ContainsValue(constant.OpenID, utils.StringToArray(authcode.Scope)) {
 
@@ -439,7 +706,12 @@ func (o *oauth2) authorizationCodeGrant(ctx context.Context, client dto.Client,
 			return nil, err
 		}
 
-		idToken, err := o.token.GenerateIdToken(ctx, user, client.ID.String(), o.options.IDTokenExpireTime)
+		idToken, err := o.token.GenerateIdToken(ctx, user, client.ID.String(), dto.IDTokenOptions{
+			Nonce:            authcode.Nonce,
+			Authentication:   authcode.Authentication,
+			AccessToken:      accessToken,
+			SigningAlgorithm: client.IDTokenSignedResponseAlg,
+		}, options.IDTokenExpireTime)
 		if err != nil {
 			return nil, err
 		}
@@ -449,9 +721,45 @@ func (o *oauth2) authorizationCodeGrant(ctx context.Context, client dto.Client,
 	return tokenResponse, nil
 }
 
+// verifyCodeVerifier checks the code verifier against the code challenge the auth code was issued with.
+func (o *oauth2) verifyCodeVerifier(ctx context.Context, client dto.Client, authcode dto.AuthCode, codeVerifier string) error {
+	if authcode.CodeChallenge == "" {
+		if client.PKCERequired() {
+			err := errors.ErrAuthError.New("code was issued without code_challenge")
+			o.logger.Warn(ctx, "pkce is required for the client", zap.Error(err), zap.String("client-id", client.ID.String()))
+			return err
+		}
+		if codeVerifier != "" {
+			err := errors.ErrAuthError.New("code was issued without code_challenge")
+			o.logger.Info(ctx, "code verifier given for code without challenge", zap.Error(err), zap.String("client-id", client.ID.String()))
+			return err
+		}
+		return nil
+	}
+
+	if codeVerifier == "" {
+		err := errors.ErrInvalidUserInput.New("code_verifier is required")
+		o.logger.Info(ctx, "missing code verifier", zap.Error(err), zap.String("client-id", client.ID.String()))
+		return err
+	}
+
+	if authcode.CodeChallengeMethod != constant.CodeChallengeS256 || !utils.VerifyS256CodeChallenge(authcode.CodeChallenge, codeVerifier) {
+		err := errors.ErrAuthError.New("invalid code_verifier")
+		o.logger.Info(ctx, "code verifier mismatch", zap.Error(err), zap.String("client-id", client.ID.String()))
+		return err
+	}
+
+	return nil
+}
+
+// refreshToken issues a new access token for the grant of the refresh token and rotates it.
+// Authorization details are specific to the transaction they were approved for, so they are not carried on refreshed access tokens.
 func (o *oauth2) refreshToken(ctx context.Context, client dto.Client, param dto.AccessTokenRequest) (*dto.TokenResponse, error) {
 	oldRefreshToken, err := o.oauth2Persistence.GetRefreshToken(ctx, param.RefreshToken)
 	if err != nil {
+		if errorx.IsOfType(err, errors.ErrNoRecordFound) {
+			return nil, o.detectRefreshTokenReuse(ctx, param.RefreshToken, err)
+		}
 		return nil, err
 	}
 	if oldRefreshToken.ClientID != client.ID {
@@ -460,34 +768,45 @@ func (o *oauth2) refreshToken(ctx context.Context, client dto.Client, param dto.
 		return nil, err
 	}
 
-	if time.Now().After(oldRefreshToken.ExpiresAt) {
+	if refreshTokenExpired(client, *oldRefreshToken) {
 		if err := o.oauth2Persistence.RemoveRefreshToken(ctx, oldRefreshToken.RefreshToken); err != nil {
 			return nil, err
 		}
+		if _, err := o.oauth2Persistence.AddAuthHistory(
+			ctx,
+			dto.AuthHistory{
+				Code:        oldRefreshToken.Code,
+				UserID:      oldRefreshToken.UserID,
+				ClientID:    oldRefreshToken.ClientID,
+				Scope:       oldRefreshToken.Scope,
+				RedirectUri: oldRefreshToken.RedirectUri,
+				Status:      constant.Revoke,
+			},
+		); err != nil {
+			return nil, err
+		}
 
 		err := errors.ErrAuthError.New("refresh token expired")
 		o.logger.Warn(ctx, "token expired", zap.Error(err), zap.String("refresh token", oldRefreshToken.RefreshToken))
 		return nil, err
 	}
 
-	accessToken, err := o.token.GenerateAccessTokenForClient(ctx, oldRefreshToken.UserID.String(), oldRefreshToken.ClientID.String(), oldRefreshToken.Scope, o.options.AccessTokenExpireTime)
+	options := o.clientOptions(client)
+	accessTokenScope := oldRefreshToken.Scope
+	if len(param.Resource) > 0 {
+		if accessTokenScope, err = o.resourceScope(ctx, oldRefreshToken.Scope, param.Resource); err != nil {
+			return nil, err
+		}
+	}
+
+	accessToken, err := o.token.GenerateAccessTokenForClient(ctx, oldRefreshToken.UserID.String(), oldRefreshToken.ClientID.String(), accessTokenScope, dto.AccessTokenOptions{
+		Resources: param.Resource,
+	}, options.AccessTokenExpireTime)
 	if err != nil {
 		return nil, err
 	}
 
-	//if err := o.oauth2Persistence.RemoveRefreshToken(ctx, oldRefreshToken.RefreshToken); err != nil {
-	//	return nil, err
-	//}
-
-	//newRefreshToken, err := o.oauth2Persistence.PersistRefreshToken(ctx, dto.RefreshToken{
-	//	UserID:       oldRefreshToken.UserID,
-	//	RefreshToken: o.token.GenerateRefreshToken(ctx),
-	//	ClientID:     oldRefreshToken.ClientID,
-	//	Scope:        oldRefreshToken.Scope,
-	//	RedirectUri:  oldRefreshToken.RedirectUri,
-	//	ExpiresAt:    time.Now().Add(o.options.RefreshTokenExpireTime),
-	//})
-	newRefreshToken, err := o.oauth2Persistence.UpdateRefreshToken(ctx, oldRefreshToken.RefreshToken, oldRefreshToken.RefreshToken)
+	newRefreshToken, err := o.oauth2Persistence.RotateRefreshToken(ctx, o.token.GenerateRefreshToken(ctx), oldRefreshToken.RefreshToken)
 	if err != nil {
 		return nil, err
 	}
@@ -495,7 +814,10 @@ func (o *oauth2) refreshToken(ctx context.Context, client dto.Client, param dto.
 		AccessToken:  accessToken,
 		RefreshToken: newRefreshToken.RefreshToken,
 		TokenType:    constant.BearerToken,
-		ExpiresIn:    fmt.Sprintf("%vs", o.options.AccessTokenExpireTime.Seconds()),
+		ExpiresIn:    fmt.Sprintf("%vs", options.AccessTokenExpireTime.Seconds()),
+	}
+	if len(param.Resource) > 0 {
+		tokenResponse.Scope = accessTokenScope
 	}
 
 	if utils.ContainsValue(constant.OpenID, utils.StringToArray(newRefreshToken.Scope)) {
@@ -505,7 +827,11 @@ func (o *oauth2) refreshToken(ctx context.Context, client dto.Client, param dto.
 			return nil, err
 		}
 
-		idToken, err := o.token.GenerateIdToken(ctx, user, client.ID.String(), o.options.IDTokenExpireTime)
+		idToken, err := o.token.GenerateIdToken(ctx, user, client.ID.String(), dto.IDTokenOptions{
+			Authentication:   newRefreshToken.Authentication,
+			AccessToken:      accessToken,
+			SigningAlgorithm: client.IDTokenSignedResponseAlg,
+		}, options.IDTokenExpireTime)
 		if err != nil {
 			return nil, err
 		}
@@ -515,6 +841,292 @@ func (o *oauth2) refreshToken(ctx context.Context, client dto.Client, param dto.
 	return tokenResponse, nil
 }
 
+// detectRefreshTokenReuse checks if an unknown refresh token was superseded by a rotation.
+// A superseded token being presented again means it has leaked, so the whole family is revoked.
+func (o *oauth2) detectRefreshTokenReuse(ctx context.Context, refreshToken string, notFoundErr error) error {
+	family, err := o.oauth2Persistence.GetRefreshTokenFamily(ctx, refreshToken)
+	if err != nil {
+		if errorx.IsOfType(err, errors.ErrNoRecordFound) {
+			return notFoundErr
+		}
+		return err
+	}
+
+	if err := o.oauth2Persistence.RevokeRefreshTokenFamily(ctx, *family); err != nil {
+		return err
+	}
+
+	err = errors.ErrAuthError.New("refresh token reuse detected")
+	o.logger.Warn(ctx, "superseded refresh token was presented, refresh token family revoked", zap.Error(err),
+		zap.String("family-id", family.ID.String()),
+		zap.String("client-id", family.ClientID.String()),
+		zap.String("user-id", family.UserID.String()))
+	return err
+}
+
+func (o *oauth2) clientCredentialsGrant(ctx context.Context, client dto.Client, param dto.AccessTokenRequest) (*dto.TokenResponse, error) {
+	if client.ClientType != constant.ConfidentialClient {
+		err := errors.ErrAcessError.New("unauthorized_client")
+		o.logger.Info(ctx, "public client requested client credentials grant", zap.Error(err), zap.String("client-id", client.ID.String()))
+		return nil, err
+	}
+
+	options := o.clientOptions(client)
+	scope, err := o.clientScope(ctx, client, param.Scope)
+	if err != nil {
+		return nil, err
+	}
+	if len(param.Resource) > 0 {
+		if scope, err = o.resourceScope(ctx, scope, param.Resource); err != nil {
+			return nil, err
+		}
+	}
+
+	accessToken, err := o.token.GenerateAccessTokenForClient(ctx, client.ID.String(), client.ID.String(), scope, dto.AccessTokenOptions{
+		Resources: param.Resource,
+	}, options.AccessTokenExpireTime)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := o.oauth2Persistence.AddAuthHistory(
+		ctx,
+		dto.AuthHistory{
+			ClientID: client.ID,
+			Scope:    scope,
+			Status:   constant.Grant,
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	tokenResponse := &dto.TokenResponse{
+		AccessToken: accessToken,
+		TokenType:   constant.BearerToken,
+		ExpiresIn:   fmt.Sprintf("%vs", options.AccessTokenExpireTime.Seconds()),
+	}
+	if len(param.Resource) > 0 {
+		tokenResponse.Scope = scope
+	}
+	return tokenResponse, nil
+}
+
+// clientScope checks the requested scopes are registered for the client, it defaults to the scopes of the client.
+func (o *oauth2) clientScope(ctx context.Context, client dto.Client, requestedScope string) (string, error) {
+	if requestedScope == "" {
+		return client.Scopes, nil
+	}
+
+	clientScopes := utils.StringToArray(client.Scopes)
+	for _, s := range utils.StringToArray(requestedScope) {
+		if !utils.ContainsValue(s, clientScopes) {
+			err := errors.ErrInvalidUserInput.New("invalid scope")
+			o.logger.Info(ctx, "scope not registered for the client", zap.Error(err), zap.String("scope", s), zap.String("client-id", client.ID.String()))
+			return "", err
+		}
+	}
+
+	return requestedScope, nil
+}
+
+func (o *oauth2) deviceCodeGrant(ctx context.Context, client dto.Client, param dto.AccessTokenRequest) (*dto.TokenResponse, error) {
+	deviceAuthorization, err := o.deviceCache.GetDeviceAuthorization(ctx, param.DeviceCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if deviceAuthorization.ClientID != client.ID {
+		err := errors.ErrAuthError.New("client id mismatch")
+		o.logger.Warn(ctx, "client id mismatch", zap.Error(err), zap.String("device-client-id", deviceAuthorization.ClientID.String()), zap.String("given-client-id", client.ID.String()))
+		return nil, err
+	}
+
+	switch deviceAuthorization.Status {
+	case constant.DeviceAuthorizationApproved:
+		// the device may poll again before the tokens are issued, only the poll that claims the authorization gets them.
+		if err := o.deviceCache.ClaimDeviceAuthorization(ctx, deviceAuthorization); err != nil {
+			return nil, err
+		}
+
+		return o.issueTokens(ctx, client, dto.AuthCode{
+			Code:           deviceAuthorization.DeviceCode,
+			ClientID:       deviceAuthorization.ClientID,
+			Scope:          deviceAuthorization.Scope,
+			RequestedScope: deviceAuthorization.RequestedScope,
+			UserID:         deviceAuthorization.UserID,
+			Resources:      param.Resource,
+			Authentication: deviceAuthorization.Authentication,
+		})
+	case constant.DeviceAuthorizationDenied:
+		if err := o.deviceCache.DeleteDeviceAuthorization(ctx, deviceAuthorization); err != nil {
+			return nil, err
+		}
+
+		err := errors.ErrAcessError.New("access_denied")
+		o.logger.Info(ctx, "device authorization denied", zap.Error(err), zap.String("client-id", client.ID.String()))
+		return nil, err
+	}
+
+	now := time.Now()
+	slowDown := now.Sub(deviceAuthorization.LastPolledAt) < time.Duration(deviceAuthorization.Interval)*time.Second
+	if slowDown {
+		deviceAuthorization.Interval += int(o.options.DevicePollInterval.Seconds())
+	}
+	if err := o.deviceCache.RecordDevicePoll(ctx, deviceAuthorization.DeviceCode, now, deviceAuthorization.Interval); err != nil {
+		return nil, err
+	}
+
+	if slowDown {
+		err := errors.ErrInvalidUserInput.New("slow_down")
+		o.logger.Info(ctx, "device polling too fast", zap.Error(err), zap.String("client-id", client.ID.String()), zap.Int("interval", deviceAuthorization.Interval))
+		return nil, err
+	}
+
+	return nil, errors.ErrInvalidUserInput.New("authorization_pending")
+}
+
+func (o *oauth2) DeviceAuthorization(ctx context.Context, client dto.Client, param dto.DeviceAuthorizationRequest) (*dto.DeviceAuthorizationResponse, error) {
+	if !client.AllowsGrantType(constant.DeviceCode) {
+		err := errors.ErrAcessError.New("unauthorized_client")
+		o.logger.Info(ctx, "device code grant is not registered for the client", zap.Error(err), zap.String("client-id", client.ID.String()))
+		return nil, err
+	}
+
+	scope, err := o.clientScope(ctx, client, param.Scope)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceAuthorization := dto.DeviceAuthorization{
+		DeviceCode: utils.GenerateRandomString(40, false),
+		UserCode:   utils.GenerateUserCode(),
+		ClientID:   client.ID,
+		Scope:      scope,
+		Status:     constant.DeviceAuthorizationPending,
+		Interval:   int(o.options.DevicePollInterval.Seconds()),
+	}
+	if err := o.deviceCache.SaveDeviceAuthorization(ctx, deviceAuthorization); err != nil {
+		return nil, err
+	}
+
+	verificationURI := *o.urls.DeviceURL
+	verificationURIComplete := *o.urls.DeviceURL
+	return &dto.DeviceAuthorizationResponse{
+		DeviceCode:      deviceAuthorization.DeviceCode,
+		UserCode:        deviceAuthorization.UserCode,
+		VerificationURI: verificationURI.String(),
+		VerificationURIComplete: utils.GenerateRedirectString(&verificationURIComplete, map[string]string{
+			"user_code": deviceAuthorization.UserCode,
+		}),
+		ExpiresIn: int(o.options.DeviceCodeExpireTime.Seconds()),
+		Interval:  deviceAuthorization.Interval,
+	}, nil
+}
+
+func (o *oauth2) VerifyDevice(ctx context.Context, param dto.VerifyDeviceRequest, bindError *errorx.Error) string {
+	errorURL := *o.urls.ErrorURL
+	if bindError != nil {
+		o.logger.Info(ctx, "error while binding to query", zap.Error(bindError))
+		return utils.GenerateRedirectString(&errorURL, map[string]string{
+			"error":             bindError.Message(),
+			"error_description": bindError.Error(),
+		})
+	}
+
+	if err := param.Validate(); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		o.logger.Info(ctx, "invalid input", zap.Error(err))
+		return utils.GenerateRedirectString(&errorURL, map[string]string{
+			"error":             "invalid_request",
+			"error_description": "user_code is required",
+		})
+	}
+
+	userCode := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(param.UserCode))
+	if len(userCode) == 8 {
+		userCode = userCode[:4] + "-" + userCode[4:]
+	}
+
+	deviceAuthorization, err := o.deviceCache.GetDeviceAuthorizationByUserCode(ctx, userCode)
+	if err != nil {
+		return utils.GenerateRedirectString(&errorURL, map[string]string{
+			"error":             "invalid_user_code",
+			"error_description": "user code not found or expired",
+		})
+	}
+
+	if deviceAuthorization.Status != constant.DeviceAuthorizationPending {
+		o.logger.Info(ctx, "user code already used", zap.String("user-code", userCode), zap.String("status", deviceAuthorization.Status))
+		return utils.GenerateRedirectString(&errorURL, map[string]string{
+			"error":             "invalid_user_code",
+			"error_description": "user code already used",
+		})
+	}
+
+	consent := dto.Consent{
+		ID: uuid.New(),
+		AuthorizationRequestParam: dto.AuthorizationRequestParam{
+			ClientID: deviceAuthorization.ClientID,
+			Scope:    deviceAuthorization.Scope,
+			Prompt:   constant.PromptConsent,
+		},
+		DeviceCode: deviceAuthorization.DeviceCode,
+		CreatedAt:  time.Now(),
+	}
+	if err := o.consentCache.SaveConsent(ctx, consent); err != nil {
+		return utils.GenerateRedirectString(&errorURL, map[string]string{
+			"error":             "server_error",
+			"error_description": "failed to save consent",
+		})
+	}
+
+	consentURL := *o.urls.ConsentURL
+	return utils.GenerateRedirectString(&consentURL, map[string]string{
+		"consentId": consent.ID.String(),
+		"prompt":    constant.PromptConsent,
+	})
+}
+
+// completeDeviceConsent records the decision of the user on the device authorization the consent is given for.
+func (o *oauth2) completeDeviceConsent(ctx context.Context, consent dto.Consent, status string, userID uuid.UUID) string {
+	deviceURL := *o.urls.DeviceURL
+	deviceAuthorization, err := o.deviceCache.GetDeviceAuthorization(ctx, consent.DeviceCode)
+	if err != nil {
+		return utils.GenerateRedirectString(&deviceURL, map[string]string{
+			"error": "expired_token",
+		})
+	}
+
+	if deviceAuthorization.Status != constant.DeviceAuthorizationPending {
+		return utils.GenerateRedirectString(&deviceURL, map[string]string{
+			"error": "user code already used",
+		})
+	}
+
+	deviceAuthorization.Status = status
+	deviceAuthorization.UserID = userID
+	if consent.RequestedScope != "" {
+		deviceAuthorization.RequestedScope = consent.RequestedScope
+		deviceAuthorization.Scope = consent.Scope
+	}
+	deviceAuthorization.Authentication = authentication(ctx)
+	if err := o.deviceCache.CompleteDeviceAuthorization(ctx, deviceAuthorization); err != nil {
+		errx := errorx.Cast(err)
+		return utils.GenerateRedirectString(&deviceURL, map[string]string{
+			"error":       errx.Message(),
+			"description": errx.Error(),
+		})
+	}
+
+	if err := o.consentCache.DeleteConsent(ctx, consent.ID.String()); err != nil {
+		o.logger.Warn(ctx, "could not delete device consent", zap.Error(err), zap.String("consent-id", consent.ID.String()))
+	}
+
+	return utils.GenerateRedirectString(&deviceURL, map[string]string{
+		"status": status,
+	})
+}
+
 func (o *oauth2) Logout(ctx context.Context, logoutReqParam dto.LogoutRequest, bindError *errorx.Error) string {
 	if bindError != nil {
 		o.logger.Info(ctx, "error while binding to query", zap.Error(bindError))
@@ -533,7 +1145,7 @@ func (o *oauth2) Logout(ctx context.Context, logoutReqParam dto.LogoutRequest, b
 		})
 	}
 
-	isValid, idToken := o.token.VerifyIdToken(jwt.SigningMethodPS512, logoutReqParam.IDTokenHint)
+	isValid, idToken := o.token.VerifyIdToken(logoutReqParam.IDTokenHint)
 	if !isValid {
 		err := errors.ErrInvalidUserInput.New("id_token is invalid")
 		o.logger.Info(ctx, "invalid id_token", zap.Error(err), zap.Any("id_token", logoutReqParam.IDTokenHint))
@@ -597,8 +1209,8 @@ func (o *oauth2) RevokeClient(ctx context.Context, clientBody request_models.Rev
 		return err
 	}
 
-	// delete the refresh token
-	err = o.oauth2Persistence.RemoveRefreshToken(ctx, refreshToken.RefreshToken)
+	// delete the refresh tokens of every login of the user to the client
+	err = o.oauth2Persistence.RemoveRefreshTokensOfClientByUserID(ctx, userID, clientID)
 	if err != nil {
 		return err
 	}
@@ -672,3 +1284,59 @@ func (o *oauth2) UserInfo(ctx context.Context) (*dto.UserInfo, error) {
 
 	return o.oauth2Persistence.UserInfo(ctx, userID)
 }
+
+func (o *oauth2) OpenIDConfiguration(ctx context.Context) (dto.OpenIDConfiguration, error) {
+	scopes, err := o.scopePersistence.GetScopeNamesByStatus(ctx, constant.Active)
+	if err != nil {
+		return dto.OpenIDConfiguration{}, err
+	}
+	if !utils.ContainsValue(constant.OpenID, scopes) {
+		scopes = append([]string{constant.OpenID}, scopes...)
+	}
+
+	return dto.OpenIDConfiguration{
+		Issuer:                                     o.urls.IssuerURL.String(),
+		AuthorizationEndpoint:                      o.endpointURL(constant.AuthorizeEndpoint),
+		TokenEndpoint:                              o.endpointURL(constant.TokenEndpoint),
+		UserInfoEndpoint:                           o.endpointURL(constant.UserInfoEndpoint),
+		JWKSURI:                                    o.endpointURL(constant.JWKSEndpoint),
+		EndSessionEndpoint:                         o.endpointURL(constant.LogoutEndpoint),
+		CheckSessionIframe:                         o.endpointURL(constant.CheckSessionEndpoint),
+		BackchannelLogoutSupported:                 true,
+		BackchannelLogoutSessionSupported:          true,
+		FrontchannelLogoutSupported:                true,
+		FrontchannelLogoutSessionSupported:         true,
+		ScopesSupported:                            scopes,
+		ResponseTypesSupported:                     []string{constant.ResponseTypeCode, constant.ResponseTypeIDToken, constant.ResponseTypeCodeIDToken, constant.ResponseTypeCodeToken},
+		ResponseModesSupported:                     []string{constant.ResponseModeQuery, constant.ResponseModeFragment},
+		ACRValuesSupported:                         []string{constant.ACRSingleFactor, constant.ACRFederated},
+		DeviceAuthorizationEndpoint:                o.endpointURL(constant.DeviceAuthorizationEndpoint),
+		IntrospectionEndpoint:                      o.endpointURL(constant.IntrospectionEndpoint),
+		RevocationEndpoint:                         o.endpointURL(constant.RevocationEndpoint),
+		RegistrationEndpoint:                       o.endpointURL(constant.RegistrationEndpoint),
+		GrantTypesSupported:                        []string{constant.AuthorizationCode, constant.RefreshToken, constant.ClientCredentials, constant.DeviceCode, constant.TokenExchange},
+		SubjectTypesSupported:                      []string{"public"},
+		IDTokenSigningAlgValuesSupported:           o.token.SigningAlgorithms(),
+		TokenEndpointAuthMethodsSupported:          []string{constant.ClientSecretBasic, constant.PrivateKeyJWT, constant.TLSClientAuth, constant.NoneAuthMethod},
+		RequestParameterSupported:                  true,
+		RequestObjectSigningAlgValuesSupported:     requestObjectAlgorithms,
+		TokenEndpointAuthSigningAlgValuesSupported: []string{constant.SigningAlgorithmPS512, constant.SigningAlgorithmRS256, constant.SigningAlgorithmES256, constant.SigningAlgorithmEdDSA},
+		CodeChallengeMethodsSupported:              []string{constant.CodeChallengeS256},
+		ClaimsSupported: []string{
+			"iss", "sub", "aud", "exp", "iat", "azp", "nonce", "auth_time", "acr", "amr",
+			"first_name", "middle_name", "last_name", "picture", "email", "phone",
+		},
+	}, nil
+}
+
+func (o *oauth2) JWKS(ctx context.Context) dto.JWKS {
+	return o.token.JWKS(ctx)
+}
+
+// endpointURL returns the absolute url of the given oauth2 endpoint under the issuer.
+func (o *oauth2) endpointURL(endpoint string) string {
+	endpointURL := *o.urls.IssuerURL
+	endpointURL.Path = path.Join(endpointURL.Path, constant.APIBasePath, constant.OAuth2BasePath, endpoint)
+
+	return endpointURL.String()
+}
@@ -0,0 +1,172 @@
+package oauth2
+
+import (
+	"context"
+	"strings"
+
+	"sso/internal/constant"
+	"sso/internal/constant/errors"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+
+	"github.com/google/uuid"
+	"github.com/joomcode/errorx"
+	"go.uber.org/zap"
+)
+
+// PushAuthorizationRequest stores the authorization request of an authenticated client in the consent cache
+// so the client can send just the request_uri referencing it to the authorization endpoint.
+// The request is checked here as it would be on the authorization endpoint, and checked again when it's referenced.
+// A request object pushed along is verified here and stored as the parameters it holds.
+func (o *oauth2) PushAuthorizationRequest(ctx context.Context, client dto.Client, param dto.AuthorizationRequestParam) (*dto.PushedAuthorizationResponse, error) {
+	if param.RequestURI != "" {
+		err := errors.ErrInvalidUserInput.New("request_uri is not allowed on a pushed authorization request")
+		o.logger.Info(ctx, "request_uri was pushed", zap.Error(err), zap.String("client-id", client.ID.String()))
+		return nil, err
+	}
+	if param.ClientID != uuid.Nil && param.ClientID != client.ID {
+		err := errors.ErrInvalidUserInput.New("client_id does not match the authenticated client")
+		o.logger.Info(ctx, "client_id does not match the authenticated client", zap.Error(err),
+			zap.String("client-id", client.ID.String()), zap.String("requested-client-id", param.ClientID.String()))
+		return nil, err
+	}
+	param.ClientID = client.ID
+
+	if param.Request != "" {
+		requestParam, err := o.requestObject(ctx, param)
+		if err != nil {
+			return nil, err
+		}
+		param = requestParam
+	}
+
+	param.NormalizeResponseType()
+	if err := param.Validate(); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		o.logger.Info(ctx, "invalid input", zap.Error(err))
+		return nil, err
+	}
+
+	if !o.ContainsRedirectURL(client.RedirectURIs, param.RedirectURI) {
+		err := errors.ErrInvalidUserInput.New("invalid redirect uri")
+		o.logger.Info(ctx, "invalid redirect uri", zap.Error(err))
+		return nil, err
+	}
+
+	if _, _, err := o.checkAuthorizationRequest(ctx, client, param); err != nil {
+		return nil, err
+	}
+
+	consent := dto.Consent{
+		ID:                        uuid.New(),
+		AuthorizationRequestParam: param,
+		Pushed:                    true,
+	}
+	if err := o.consentCache.SaveConsent(ctx, consent); err != nil {
+		return nil, err
+	}
+
+	return &dto.PushedAuthorizationResponse{
+		RequestURI: constant.RequestURIPrefix + consent.ID.String(),
+		ExpiresIn:  int(o.options.ConsentExpireTime.Seconds()),
+	}, nil
+}
+
+// pushedAuthorizationRequest returns the pushed authorization request the request_uri references.
+// A request_uri can only be used once, and only by the client that pushed the request.
+func (o *oauth2) pushedAuthorizationRequest(ctx context.Context, authRequestParm dto.AuthorizationRequestParam) (dto.AuthorizationRequestParam, error) {
+	consentID := strings.TrimPrefix(authRequestParm.RequestURI, constant.RequestURIPrefix)
+	if consentID == authRequestParm.RequestURI {
+		err := errors.ErrInvalidUserInput.New("invalid request_uri")
+		o.logger.Info(ctx, "request_uri is not a pushed authorization request", zap.Error(err), zap.String("request-uri", authRequestParm.RequestURI))
+		return dto.AuthorizationRequestParam{}, err
+	}
+
+	consent, err := o.consentCache.GetConsent(ctx, consentID)
+	if err != nil {
+		if errorx.IsOfType(err, errors.ErrNoRecordFound) {
+			return dto.AuthorizationRequestParam{}, errors.ErrInvalidUserInput.Wrap(err, "invalid request_uri")
+		}
+		return dto.AuthorizationRequestParam{}, err
+	}
+	if !consent.Pushed || consent.ClientID != authRequestParm.ClientID {
+		err := errors.ErrInvalidUserInput.New("invalid request_uri")
+		o.logger.Info(ctx, "request_uri was not pushed by the client", zap.Error(err),
+			zap.String("request-uri", authRequestParm.RequestURI), zap.String("client-id", authRequestParm.ClientID.String()))
+		return dto.AuthorizationRequestParam{}, err
+	}
+
+	if err := o.consentCache.DeleteConsent(ctx, consentID); err != nil {
+		return dto.AuthorizationRequestParam{}, err
+	}
+
+	return consent.AuthorizationRequestParam, nil
+}
+
+// checkAuthorizationRequest checks the authorization request is allowed for the client,
+// returning the scopes to ask consent for, or the error code and the error to respond with.
+func (o *oauth2) checkAuthorizationRequest(ctx context.Context, client dto.Client, authRequestParm dto.AuthorizationRequestParam) (string, string, error) {
+	if !client.AllowsResponseType(authRequestParm.ResponseType) {
+		err := errors.ErrAcessError.New("response type is not allowed for the client")
+		o.logger.Info(ctx, "response type not allowed for the client", zap.Error(err), zap.String("client-id", client.ID.String()), zap.String("response-type", authRequestParm.ResponseType))
+		return "", "unauthorized_client", err
+	}
+
+	if authRequestParm.HasResponseType(constant.ResponseTypeCode) && client.PKCERequired() && authRequestParm.CodeChallenge == "" {
+		err := errors.ErrInvalidUserInput.New("code_challenge is required")
+		o.logger.Info(ctx, "pkce is required for the client", zap.Error(err), zap.String("client-id", client.ID.String()))
+		return "", "invalid_request", err
+	}
+
+	if authRequestParm.HasResponseType(constant.ResponseTypeIDToken) && !utils.ContainsValue(constant.OpenID, utils.StringToArray(authRequestParm.Scope)) {
+		err := errors.ErrInvalidUserInput.New("openid scope is required")
+		o.logger.Info(ctx, "openid scope is required for the response type", zap.Error(err), zap.String("response-type", authRequestParm.ResponseType))
+		return "", "invalid_scope", err
+	}
+
+	scopes, err := o.scopePersistence.GetScopeNameOnly(ctx, strings.Split(authRequestParm.Scope, " ")...)
+	if (err != nil || scopes == "") && !client.FirstParty {
+		err := errors.ErrInvalidUserInput.New("invalid scope")
+		o.logger.Info(ctx, "invalid scope", zap.Error(err))
+		return "", "invalid_scope", err
+	}
+
+	if len(authRequestParm.Resource) > 0 {
+		if _, err := o.resourceScope(ctx, scopes, authRequestParm.Resource); err != nil {
+			return "", "invalid_target", err
+		}
+	}
+
+	if authRequestParm.AuthorizationDetails != "" {
+		details, err := dto.ParseAuthorizationDetails(authRequestParm.AuthorizationDetails)
+		if err != nil {
+			err := errors.ErrInvalidUserInput.Wrap(err, "invalid authorization details")
+			o.logger.Info(ctx, "invalid authorization details", zap.Error(err))
+			return "", "invalid_authorization_details", err
+		}
+		if _, err := o.authorizationDetailTypes(ctx, details); err != nil {
+			if errorx.IsOfType(err, errors.ErrInvalidUserInput) {
+				return "", "invalid_authorization_details", err
+			}
+			return "", "server_error", err
+		}
+	}
+
+	return scopes, "", nil
+}
+
+// getConsent returns the consent the user is asked for,
+// pushed authorization requests are not consents until they are referenced on the authorization endpoint.
+func (o *oauth2) getConsent(ctx context.Context, consentID string) (dto.Consent, error) {
+	consent, err := o.consentCache.GetConsent(ctx, consentID)
+	if err != nil {
+		return dto.Consent{}, err
+	}
+	if consent.Pushed {
+		err := errors.ErrNoRecordFound.New("consent not found")
+		o.logger.Info(ctx, "pushed authorization request used as a consent", zap.Error(err), zap.String("consentID", consentID))
+		return dto.Consent{}, err
+	}
+
+	return consent, nil
+}
@@ -0,0 +1,119 @@
+package oauth2
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"sso/internal/constant"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// browserSession returns the internal refresh token of the sso session the user agent holds,
+// or nil when it holds none or the session has expired.
+func (o *oauth2) browserSession(ctx context.Context, session dto.BrowserSession) *dto.InternalRefreshToken {
+	if session.RefreshToken == "" {
+		return nil
+	}
+
+	refreshToken, err := o.oauthPersistence.GetInternalRefreshToken(ctx, session.RefreshToken)
+	if err != nil {
+		return nil
+	}
+	if time.Now().After(refreshToken.ExpiresAt) {
+		o.logger.Info(ctx, "browser session expired", zap.String("user-id", refreshToken.UserID.String()))
+		return nil
+	}
+
+	return refreshToken
+}
+
+// authenticatedWithin tells if the user authenticated no more than max_age seconds before the request was made,
+// any authentication is recent enough when the request has no max_age.
+// A max_age of 0 takes only an authentication made after the request.
+// auth_time is kept in seconds, so the time of the request is truncated to seconds as well.
+func authenticatedWithin(authentication dto.Authentication, maxAge *int, requestedAt time.Time) bool {
+	if maxAge == nil {
+		return true
+	}
+	notBefore := requestedAt.Truncate(time.Second).Add(-time.Duration(*maxAge) * time.Second)
+	return !authentication.Time.IsZero() && !authentication.Time.Before(notBefore)
+}
+
+// grantedScopes tells if the user already granted the client every scope of the consent.
+func (o *oauth2) grantedScopes(ctx context.Context, userID, clientID uuid.UUID, scope string) (bool, error) {
+	granted, refreshToken, err := o.oauth2Persistence.CheckIfUserGrantedClient(ctx, userID, clientID)
+	if err != nil || !granted {
+		return false, err
+	}
+
+	grantedScopes := utils.StringToArray(refreshToken.Scope)
+	for _, s := range utils.StringToArray(scope) {
+		if !utils.ContainsValue(s, grantedScopes) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// authorizeWithoutPrompt responds to a prompt=none request without showing the user any page.
+// The response is issued right away when the user agent holds a session recent enough for the max_age of the request
+// and the user already granted the client the requested scopes, otherwise login_required or consent_required is returned.
+func (o *oauth2) authorizeWithoutPrompt(ctx context.Context, consent dto.Consent, redirectURI *url.URL, session dto.BrowserSession) string {
+	responseMode := consent.GetResponseMode()
+	refreshToken := o.browserSession(ctx, session)
+	if refreshToken == nil || !authenticatedWithin(refreshToken.Authentication, consent.MaxAge, consent.CreatedAt) {
+		o.logger.Info(ctx, "prompt=none request without a recent enough session", zap.String("client-id", consent.ClientID.String()))
+		return o.authorizationResponse(redirectURI, responseMode, map[string]string{
+			"error":             "login_required",
+			"error_description": "the user is not logged in",
+			"state":             consent.State,
+		})
+	}
+
+	granted, err := o.grantedScopes(ctx, refreshToken.UserID, consent.ClientID, consent.Scope)
+	if err != nil {
+		return o.authorizationResponse(redirectURI, responseMode, map[string]string{
+			"error":             "server_error",
+			"error_description": "failed to check the grants of the user",
+			"state":             consent.State,
+		})
+	}
+	if !granted {
+		o.logger.Info(ctx, "prompt=none request for scopes the user has not granted",
+			zap.String("client-id", consent.ClientID.String()), zap.String("user-id", refreshToken.UserID.String()))
+		return o.authorizationResponse(redirectURI, responseMode, map[string]string{
+			"error":             "consent_required",
+			"error_description": "the user has not granted the requested scopes",
+			"state":             consent.State,
+		})
+	}
+
+	if consent.AuthorizationDetails != "" {
+		o.logger.Info(ctx, "prompt=none request with authorization details",
+			zap.String("client-id", consent.ClientID.String()), zap.String("user-id", refreshToken.UserID.String()))
+		return o.authorizationResponse(redirectURI, responseMode, map[string]string{
+			"error":             "consent_required",
+			"error_description": "the user must approve the authorization details",
+			"state":             consent.State,
+		})
+	}
+
+	ctx = context.WithValue(ctx, constant.Context("x-authentication"), refreshToken.Authentication)
+	params, err := o.authorizationResponseParams(ctx, consent, refreshToken.UserID)
+	if err != nil {
+		return o.authorizationResponse(redirectURI, responseMode, map[string]string{
+			"error":             "server_error",
+			"error_description": "failed to issue the authorization response",
+			"state":             consent.State,
+		})
+	}
+	params["state"] = consent.State
+	params["session_state"] = utils.CalculateSessionState(consent.ClientID.String(), consent.RequestOrigin, session.OPBS, utils.GenerateRandomString(20, false))
+
+	return o.authorizationResponse(redirectURI, responseMode, params)
+}
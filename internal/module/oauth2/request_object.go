@@ -0,0 +1,75 @@
+package oauth2
+
+import (
+	"context"
+
+	"sso/internal/constant"
+	"sso/internal/constant/errors"
+	"sso/internal/constant/model/dto"
+
+	"github.com/golang-jwt/jwt/v4"
+	"go.uber.org/zap"
+)
+
+// requestObjectAlgorithms are the algorithms a client may sign its request objects with.
+var requestObjectAlgorithms = []string{
+	constant.SigningAlgorithmPS512, constant.SigningAlgorithmRS256, constant.SigningAlgorithmES256, constant.SigningAlgorithmEdDSA,
+}
+
+// requestObject verifies the request object of the authorization request against the keys the client registered
+// and returns the request with the parameters the request object holds taking precedence over the others.
+// The request object must be issued by the client to the sso, must expire and can only be used once.
+func (o *oauth2) requestObject(ctx context.Context, authRequestParm dto.AuthorizationRequestParam) (dto.AuthorizationRequestParam, error) {
+	client, err := o.clientPersistence.GetClientByID(ctx, authRequestParm.ClientID)
+	if err != nil {
+		return dto.AuthorizationRequestParam{}, errors.ErrInvalidUserInput.Wrap(err, "client not found")
+	}
+	if client.JWKS == nil {
+		err := errors.ErrInvalidUserInput.New("the client has no registered keys to verify the request object with")
+		o.logger.Info(ctx, "request object of a client without keys", zap.Error(err), zap.String("client-id", client.ID.String()))
+		return dto.AuthorizationRequestParam{}, err
+	}
+
+	claims := dto.RequestObject{}
+	parser := jwt.NewParser(jwt.WithValidMethods(requestObjectAlgorithms))
+	if _, err := parser.ParseWithClaims(authRequestParm.Request, &claims, client.JWKS.VerificationKey); err != nil {
+		message := "invalid request object signature"
+		if validationErr, ok := err.(*jwt.ValidationError); ok && validationErr.Errors&jwt.ValidationErrorExpired != 0 {
+			message = "request object is expired"
+		}
+		err := errors.ErrInvalidUserInput.Wrap(err, message)
+		o.logger.Info(ctx, "could not verify request object", zap.Error(err), zap.String("client-id", client.ID.String()))
+		return dto.AuthorizationRequestParam{}, err
+	}
+
+	clientID := client.ID.String()
+	switch {
+	case claims.ExpiresAt == nil:
+		err = errors.ErrInvalidUserInput.New("request object must expire")
+	case claims.ID == "":
+		err = errors.ErrInvalidUserInput.New("request object must have a jti")
+	case !claims.VerifyAudience(o.urls.IssuerURL.String(), true):
+		err = errors.ErrInvalidUserInput.New("invalid request object audience")
+	case claims.Issuer != clientID:
+		err = errors.ErrInvalidUserInput.New("invalid request object issuer")
+	case claims.ClientID != "" && claims.ClientID != clientID:
+		err = errors.ErrInvalidUserInput.New("client_id does not match the request object")
+	}
+	if err != nil {
+		o.logger.Info(ctx, "request object has invalid claims", zap.Error(err), zap.String("client-id", clientID), zap.Any("claims", claims.RegisteredClaims))
+		return dto.AuthorizationRequestParam{}, err
+	}
+
+	first, err := o.requestObjectCache.UseRequestObject(ctx, clientID, claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return dto.AuthorizationRequestParam{}, err
+	}
+	if !first {
+		err := errors.ErrInvalidUserInput.New("request object was already used")
+		o.logger.Warn(ctx, "request object replayed", zap.Error(err), zap.String("client-id", clientID), zap.String("jti", claims.ID))
+		return dto.AuthorizationRequestParam{}, err
+	}
+
+	claims.Apply(&authRequestParm)
+	return authRequestParm, nil
+}
@@ -0,0 +1,69 @@
+package oauth2
+
+import (
+	"context"
+
+	"sso/internal/constant/errors"
+	"sso/platform/utils"
+
+	"github.com/joomcode/errorx"
+	"go.uber.org/zap"
+)
+
+// resourceScope returns the part of the scope that belongs to the resource servers the access token is requested for,
+// the access token issued for them is restricted to it so the resource servers only see the scopes they enforce.
+// The resources must be registered resource servers and at least one of the scopes must belong to them.
+func (o *oauth2) resourceScope(ctx context.Context, scope string, resources []string) (string, error) {
+	requestedScopes := utils.StringToArray(scope)
+	resourceScopes := []string{}
+	for _, resource := range resources {
+		if _, err := o.resourceServers.GetResourceServerByName(ctx, resource); err != nil {
+			if errorx.IsOfType(err, errors.ErrNoRecordFound) {
+				err := errors.ErrInvalidUserInput.New("invalid target")
+				o.logger.Info(ctx, "access token requested for an unknown resource server", zap.Error(err), zap.String("resource", resource))
+				return "", err
+			}
+			return "", err
+		}
+
+		scopes, err := o.scopePersistence.GetScopesByResourceServerName(ctx, resource)
+		if err != nil {
+			return "", err
+		}
+		for _, s := range scopes {
+			if utils.ContainsValue(s.Name, requestedScopes) && !utils.ContainsValue(s.Name, resourceScopes) {
+				resourceScopes = append(resourceScopes, s.Name)
+			}
+		}
+	}
+
+	if len(resourceScopes) == 0 {
+		err := errors.ErrInvalidUserInput.New("invalid target")
+		o.logger.Info(ctx, "none of the requested scopes belong to the resource servers", zap.Error(err),
+			zap.String("scope", scope), zap.Strings("resources", resources))
+		return "", err
+	}
+
+	return utils.ArrayToString(resourceScopes), nil
+}
+
+// tokenResources returns the resource servers the access token issued on the token endpoint is restricted to.
+// The resources requested on the token endpoint must be within the ones the grant was authorized for, if it was restricted to any,
+// and they default to them.
+func (o *oauth2) tokenResources(ctx context.Context, requested, authorized []string) ([]string, error) {
+	if len(requested) == 0 {
+		return authorized, nil
+	}
+	if len(authorized) == 0 {
+		return requested, nil
+	}
+
+	for _, resource := range requested {
+		if !utils.ContainsValue(resource, authorized) {
+			err := errors.ErrInvalidUserInput.New("invalid target")
+			o.logger.Info(ctx, "resource was not authorized for the grant", zap.Error(err), zap.String("resource", resource))
+			return nil, err
+		}
+	}
+	return requested, nil
+}
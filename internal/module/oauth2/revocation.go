@@ -0,0 +1,115 @@
+package oauth2
+
+import (
+	"context"
+	"sso/internal/constant"
+	"sso/internal/constant/errors"
+	"sso/internal/constant/model/dto"
+
+	"github.com/joomcode/errorx"
+	"go.uber.org/zap"
+)
+
+// Revoke revokes a refresh token or an access token issued to the client.
+// Tokens that are invalid or already revoked are ignored as required by RFC 7009.
+func (o *oauth2) Revoke(ctx context.Context, client dto.Client, param dto.RevocationRequest) error {
+	if err := param.Validate(); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		o.logger.Info(ctx, "invalid input", zap.Error(err))
+		return err
+	}
+
+	revokers := []func(ctx context.Context, client dto.Client, token string) (bool, error){
+		o.revokeAccessToken,
+		o.revokeRefreshToken,
+	}
+	if param.TokenTypeHint == constant.RefreshTokenHint {
+		revokers[0], revokers[1] = revokers[1], revokers[0]
+	}
+
+	for _, revoke := range revokers {
+		revoked, err := revoke(ctx, client, param.Token)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return nil
+		}
+	}
+
+	o.logger.Info(ctx, "revocation requested for unknown token", zap.String("client-id", client.ID.String()))
+	return nil
+}
+
+func (o *oauth2) revokeAccessToken(ctx context.Context, client dto.Client, token string) (bool, error) {
+	valid, claims := o.token.VerifyAccessToken(token)
+	if !valid {
+		return false, nil
+	}
+
+	if claims.Client() != client.ID.String() {
+		err := errors.ErrAcessError.New("unauthorized_client")
+		o.logger.Info(ctx, "client tried to revoke access token issued to another client", zap.Error(err),
+			zap.String("client-id", client.ID.String()),
+			zap.Strings("audience", claims.Audience))
+		return false, err
+	}
+
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		o.logger.Warn(ctx, "access token can not be revoked as it has no jti or exp",
+			zap.String("client-id", client.ID.String()),
+			zap.String("subject", claims.Subject))
+		return true, nil
+	}
+
+	if err := o.revokedTokenCache.RevokeToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (o *oauth2) revokeRefreshToken(ctx context.Context, client dto.Client, token string) (bool, error) {
+	refreshToken, err := o.oauth2Persistence.GetRefreshToken(ctx, token)
+	if err != nil {
+		if errorx.IsOfType(err, errors.ErrNoRecordFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if refreshToken.ClientID != client.ID {
+		err := errors.ErrAcessError.New("unauthorized_client")
+		o.logger.Info(ctx, "client tried to revoke refresh token issued to another client", zap.Error(err),
+			zap.String("client-id", client.ID.String()),
+			zap.String("token-client-id", refreshToken.ClientID.String()))
+		return false, err
+	}
+
+	if err := o.oauth2Persistence.RemoveRefreshToken(ctx, token); err != nil {
+		return false, err
+	}
+	if _, err := o.oauth2Persistence.AddAuthHistory(
+		ctx,
+		dto.AuthHistory{
+			Code:        refreshToken.Code,
+			UserID:      refreshToken.UserID,
+			ClientID:    refreshToken.ClientID,
+			Scope:       refreshToken.Scope,
+			RedirectUri: refreshToken.RedirectUri,
+			Status:      constant.Revoke,
+		},
+	); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (o *oauth2) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	if jti == "" {
+		return false, nil
+	}
+
+	return o.revokedTokenCache.IsTokenRevoked(ctx, jti)
+}
@@ -0,0 +1,159 @@
+package oauth2
+
+import (
+	"context"
+	"fmt"
+
+	"sso/internal/constant"
+	"sso/internal/constant/errors"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// tokenExchangeGrant issues an access token on behalf of the subject of the subject token,
+// narrowed to the scopes of the resource server named by the audience.
+// It lets a service call another one on behalf of a user without forwarding the access token of the user,
+// the service is set as the actor of the issued token. The subject token must be issued to the service
+// and an actor token sent with it must be of the service itself.
+func (o *oauth2) tokenExchangeGrant(ctx context.Context, client dto.Client, param dto.AccessTokenRequest) (*dto.TokenResponse, error) {
+	if client.ClientType != constant.ConfidentialClient || !utils.ContainsValue(constant.TokenExchange, client.GrantTypes) {
+		err := errors.ErrAcessError.New("unauthorized_client")
+		o.logger.Info(ctx, "client is not allowed to exchange tokens", zap.Error(err), zap.String("client-id", client.ID.String()))
+		return nil, err
+	}
+
+	subjectToken, err := o.exchangeableToken(ctx, param.SubjectToken)
+	if err != nil {
+		return nil, err
+	}
+	// only the tokens issued to the service or for it as their audience can be exchanged by it
+	if subjectToken.ClientID != client.ID.String() && !utils.ContainsValue(client.ID.String(), subjectToken.Audience) {
+		err := errors.ErrInvalidUserInput.New("invalid token")
+		o.logger.Info(ctx, "subject token is not issued to the client", zap.Error(err),
+			zap.String("client-id", client.ID.String()), zap.Strings("audience", subjectToken.Audience))
+		return nil, err
+	}
+
+	actor := dto.Actor{
+		Subject: client.ID.String(),
+		Actor:   subjectToken.Actor,
+	}
+	if param.ActorToken != "" {
+		actorToken, err := o.exchangeableToken(ctx, param.ActorToken)
+		if err != nil {
+			return nil, err
+		}
+		// the service can't claim to act as another party
+		if actorToken.Subject != client.ID.String() {
+			err := errors.ErrInvalidUserInput.New("invalid token")
+			o.logger.Info(ctx, "actor token is not of the client", zap.Error(err),
+				zap.String("client-id", client.ID.String()), zap.String("subject", actorToken.Subject))
+			return nil, err
+		}
+		actor.Subject = actorToken.Subject
+	}
+
+	scope, err := o.exchangeScope(ctx, client, subjectToken.Scope, param.Audience, param.Scope)
+	if err != nil {
+		return nil, err
+	}
+
+	options := o.clientOptions(client)
+	accessToken, err := o.token.GenerateExchangedAccessToken(ctx, subjectToken.Subject, client.ID.String(), param.Audience, scope, actor, options.AccessTokenExpireTime)
+	if err != nil {
+		return nil, err
+	}
+
+	authHistory := dto.AuthHistory{
+		ClientID: client.ID,
+		Scope:    scope,
+		Status:   constant.Grant,
+	}
+	if subjectToken.Subject != subjectToken.Client() {
+		authHistory.UserID, _ = uuid.Parse(subjectToken.Subject)
+	}
+	if _, err := o.oauth2Persistence.AddAuthHistory(ctx, authHistory); err != nil {
+		return nil, err
+	}
+
+	return &dto.TokenResponse{
+		AccessToken:     accessToken,
+		TokenType:       constant.BearerToken,
+		ExpiresIn:       fmt.Sprintf("%vs", options.AccessTokenExpireTime.Seconds()),
+		IssuedTokenType: constant.AccessTokenTypeURI,
+	}, nil
+}
+
+// exchangeableToken verifies the access token sent on the token exchange grant is still active.
+func (o *oauth2) exchangeableToken(ctx context.Context, token string) (*dto.AccessToken, error) {
+	valid, claims := o.token.VerifyAccessToken(token)
+	if !valid {
+		err := errors.ErrInvalidUserInput.New("invalid token")
+		o.logger.Info(ctx, "invalid token sent on token exchange", zap.Error(err))
+		return nil, err
+	}
+
+	revoked, err := o.IsTokenRevoked(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	active, err := o.subjectActive(ctx, claims.Subject, claims.Client())
+	if err != nil {
+		return nil, err
+	}
+	if revoked || !active {
+		err := errors.ErrInvalidUserInput.New("invalid token")
+		o.logger.Info(ctx, "inactive token sent on token exchange", zap.Error(err), zap.String("subject", claims.Subject))
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// exchangeScope returns the scope of the exchanged token.
+// Only the scopes of the resource server that are registered for the client and were granted on the subject token can be exchanged to,
+// the requested scope must be within them and it defaults to all of them.
+func (o *oauth2) exchangeScope(ctx context.Context, client dto.Client, subjectScope, audience, requestedScope string) (string, error) {
+	resourceServerScopes, err := o.scopePersistence.GetScopesByResourceServerName(ctx, audience)
+	if err != nil {
+		return "", err
+	}
+	if len(resourceServerScopes) == 0 {
+		err := errors.ErrInvalidUserInput.New("invalid target")
+		o.logger.Info(ctx, "token exchange requested for an unknown resource server", zap.Error(err), zap.String("audience", audience))
+		return "", err
+	}
+
+	clientScopes := utils.StringToArray(client.Scopes)
+	subjectScopes := utils.StringToArray(subjectScope)
+	exchangeableScopes := []string{}
+	for _, s := range resourceServerScopes {
+		if utils.ContainsValue(s.Name, clientScopes) && utils.ContainsValue(s.Name, subjectScopes) {
+			exchangeableScopes = append(exchangeableScopes, s.Name)
+		}
+	}
+
+	if requestedScope == "" {
+		if len(exchangeableScopes) == 0 {
+			err := errors.ErrInvalidUserInput.New("invalid scope")
+			o.logger.Info(ctx, "no scope of the resource server can be exchanged to", zap.Error(err),
+				zap.String("client-id", client.ID.String()), zap.String("audience", audience))
+			return "", err
+		}
+		return utils.ArrayToString(exchangeableScopes), nil
+	}
+
+	for _, s := range utils.StringToArray(requestedScope) {
+		if !utils.ContainsValue(s, exchangeableScopes) {
+			err := errors.ErrInvalidUserInput.New("invalid scope")
+			o.logger.Info(ctx, "requested scope can not be exchanged to", zap.Error(err),
+				zap.String("client-id", client.ID.String()), zap.String("scope", s))
+			return "", err
+		}
+	}
+
+	return requestedScope, nil
+}
@@ -2,6 +2,7 @@ package resource_server
 
 import (
 	"context"
+	"time"
 
 	"sso/internal/constant/errors"
 	"sso/internal/constant/model"
@@ -9,6 +10,7 @@ import (
 	"sso/internal/module"
 	"sso/internal/storage"
 	"sso/platform/logger"
+	"sso/platform/utils"
 
 	"github.com/google/uuid"
 	db_pgnflt "gitlab.com/2ftimeplc/2fbackend/repo/db-pgnflt"
@@ -92,3 +94,34 @@ func (r *resourceServerModule) GetResourceServerByID(ctx context.Context, rsID s
 
 	return r.resourceServerPersistence.GetResourceServerByID(ctx, userID)
 }
+
+func (r *resourceServerModule) RotateResourceServerSecret(ctx context.Context, rsID string, request dto.RotateSecretRequest) (*dto.RotatedSecret, error) {
+	id, err := uuid.Parse(rsID)
+	if err != nil {
+		err := errors.ErrNoRecordFound.Wrap(err, "resource server not found")
+		r.logger.Info(ctx, "parse error", zap.Error(err), zap.String("resource-server-id", rsID))
+		return nil, err
+	}
+
+	if err := request.Validate(); err != nil {
+		err = errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		r.logger.Info(ctx, "invalid input", zap.Error(err))
+		return nil, err
+	}
+
+	previousSecretExpiresAt := request.PreviousSecretExpiresAt
+	if previousSecretExpiresAt.IsZero() {
+		previousSecretExpiresAt = time.Now()
+	}
+
+	secret := utils.GenerateRandomString(25, true)
+	if err := r.resourceServerPersistence.RotateResourceServerSecret(ctx, id, utils.HashSecret(secret), previousSecretExpiresAt); err != nil {
+		return nil, err
+	}
+
+	r.logger.Info(ctx, "resource server secret rotated", zap.String("resource-server-id", rsID), zap.Time("previous-secret-expires-at", previousSecretExpiresAt))
+	return &dto.RotatedSecret{
+		Secret:                  secret,
+		PreviousSecretExpiresAt: previousSecretExpiresAt,
+	}, nil
+}
@@ -3,6 +3,7 @@ package rs_api
 import (
 	"context"
 
+	"sso/internal/constant"
 	"sso/internal/constant/errors"
 	"sso/internal/constant/model/dto"
 	"sso/internal/constant/model/dto/request_models"
@@ -16,16 +17,19 @@ import (
 )
 
 type rsAPI struct {
-	logger          logger.Logger
-	userPersistence storage.UserPersistence
+	logger                    logger.Logger
+	userPersistence           storage.UserPersistence
+	resourceServerPersistence storage.ResourceServerPersistence
 }
 
 func Init(
 	logger logger.Logger,
-	userPersistence storage.UserPersistence) module.RSAPI {
+	userPersistence storage.UserPersistence,
+	resourceServerPersistence storage.ResourceServerPersistence) module.RSAPI {
 	return &rsAPI{
-		logger:          logger,
-		userPersistence: userPersistence,
+		logger:                    logger,
+		userPersistence:           userPersistence,
+		resourceServerPersistence: resourceServerPersistence,
 	}
 }
 
@@ -88,3 +92,45 @@ func (r *rsAPI) GetUsersByIDOrPhone(ctx context.Context,
 
 	return &res, nil
 }
+
+func (r *rsAPI) RegisterAuthorizationDetailTypes(ctx context.Context, req dto.AuthorizationDetailTypesRequest) ([]dto.AuthorizationDetailType, error) {
+	rs, ok := ctx.Value(constant.Context("x-rs")).(*dto.ResourceServer)
+	if !ok {
+		err := errors.ErrInternalServerError.New("could not get resource server")
+		r.logger.Error(ctx, "resource server not found on context", zap.Error(err))
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		r.logger.Info(ctx, "invalid input", zap.Error(err), zap.Any("request", req))
+		return nil, err
+	}
+
+	types := make([]string, 0, len(req.Types))
+	for _, t := range req.Types {
+		types = append(types, t.Type)
+	}
+	registered, err := r.resourceServerPersistence.GetAuthorizationDetailTypes(ctx, types)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range registered {
+		if t.ResourceServerName != rs.Name {
+			err := errors.ErrInvalidUserInput.New("authorization detail type is taken")
+			r.logger.Info(ctx, "authorization detail type is registered by another resource server", zap.Error(err),
+				zap.String("type", t.Type), zap.String("rs-id", rs.ID.String()))
+			return nil, err
+		}
+	}
+
+	detailTypes, err := r.resourceServerPersistence.SetAuthorizationDetailTypes(ctx, rs.ID, req.Types)
+	if err != nil {
+		return nil, err
+	}
+	for i := range detailTypes {
+		detailTypes[i].ResourceServerName = rs.Name
+	}
+
+	return detailTypes, nil
+}
@@ -0,0 +1,248 @@
+package signing_key
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"time"
+
+	"sso/internal/constant"
+	"sso/internal/constant/errors"
+	"sso/internal/constant/model/dto"
+	"sso/internal/module"
+	"sso/internal/storage"
+	"sso/platform"
+	"sso/platform/logger"
+	"sso/platform/utils"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/joomcode/errorx"
+	"go.uber.org/zap"
+)
+
+type Options struct {
+	// KeySize is the size in bits of the rsa keys generated on rotation.
+	KeySize int
+	// DefaultPrivateKeyPath is the path of the pem encoded private key
+	// that seeds the key ring when no key is persisted yet.
+	DefaultPrivateKeyPath string
+	// EncryptionKey is the 32 bytes long key the private keys are encrypted with at rest.
+	EncryptionKey string
+}
+
+func SetOptions(options Options) Options {
+	if options.KeySize == 0 {
+		options.KeySize = 2048
+	}
+	return options
+}
+
+type signingKeyModule struct {
+	logger                logger.Logger
+	signingKeyPersistence storage.SigningKeyPersistence
+	token                 platform.Token
+	options               Options
+}
+
+func InitSigningKey(logger logger.Logger, signingKeyPersistence storage.SigningKeyPersistence, token platform.Token, options Options) module.SigningKeyModule {
+	return &signingKeyModule{
+		logger:                logger,
+		signingKeyPersistence: signingKeyPersistence,
+		token:                 token,
+		options:               options,
+	}
+}
+
+func (s *signingKeyModule) LoadSigningKeys(ctx context.Context) error {
+	keys, err := s.signingKeyPersistence.GetAllSigningKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		key, err := s.seedDefaultKey(ctx)
+		switch {
+		case err == nil:
+			keys = append(keys, key)
+		case errorx.IsOfType(err, errors.ErrDataExists):
+			// another instance seeded the key ring at the same time, the key it persisted is used
+			if keys, err = s.signingKeyPersistence.GetAllSigningKeys(ctx); err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+	}
+
+	for k := range keys {
+		keys[k].PrivateKey, err = utils.Decrypt(keys[k].PrivateKey, s.options.EncryptionKey)
+		if err != nil {
+			err := errors.ErrInternalServerError.Wrap(err, "could not decrypt signing key")
+			s.logger.Error(ctx, "error decrypting signing key", zap.Error(err), zap.String("kid", keys[k].Kid))
+			return err
+		}
+	}
+
+	return s.token.SetSigningKeys(ctx, keys)
+}
+
+// seedDefaultKey persists the configured key pair so the tokens issued before the key ring existed stay valid.
+func (s *signingKeyModule) seedDefaultKey(ctx context.Context) (dto.SigningKey, error) {
+	privateKeyPEM, err := os.ReadFile(s.options.DefaultPrivateKeyPath)
+	if err != nil {
+		err := errors.ErrInternalServerError.Wrap(err, "could not read default signing key")
+		s.logger.Error(ctx, "error reading default private key", zap.Error(err), zap.String("path", s.options.DefaultPrivateKeyPath))
+		return dto.SigningKey{}, err
+	}
+
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privateKeyPEM)
+	if err != nil {
+		err := errors.ErrInternalServerError.Wrap(err, "could not parse default signing key")
+		s.logger.Error(ctx, "error parsing default private key", zap.Error(err))
+		return dto.SigningKey{}, err
+	}
+
+	s.logger.Info(ctx, "no signing key found, seeding the key ring with the default key")
+	return s.persistKey(ctx, privateKey, constant.SigningAlgorithmPS512, time.Now())
+}
+
+// generateKey generates a key of the kind the algorithm signs with.
+func (s *signingKeyModule) generateKey(algorithm string) (crypto.Signer, error) {
+	switch algorithm {
+	case constant.SigningAlgorithmES256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case constant.SigningAlgorithmEdDSA:
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		return privateKey, err
+	default:
+		return rsa.GenerateKey(rand.Reader, s.options.KeySize)
+	}
+}
+
+func (s *signingKeyModule) persistKey(ctx context.Context, privateKey crypto.Signer, algorithm string, activatesAt time.Time) (dto.SigningKey, error) {
+	privateKeyBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		err := errors.ErrInternalServerError.Wrap(err, "could not encode signing key")
+		s.logger.Error(ctx, "error encoding private key", zap.Error(err))
+		return dto.SigningKey{}, err
+	}
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		err := errors.ErrInternalServerError.Wrap(err, "could not encode signing key")
+		s.logger.Error(ctx, "error encoding public key", zap.Error(err))
+		return dto.SigningKey{}, err
+	}
+
+	privateKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateKeyBytes}))
+	encryptedPrivateKey, err := utils.Encrypt(privateKeyPEM, s.options.EncryptionKey)
+	if err != nil {
+		err := errors.ErrInternalServerError.Wrap(err, "could not encrypt signing key")
+		s.logger.Error(ctx, "error encrypting private key", zap.Error(err))
+		return dto.SigningKey{}, err
+	}
+
+	key, err := s.signingKeyPersistence.CreateSigningKey(ctx, dto.SigningKey{
+		Kid:         utils.Thumbprint(privateKey.Public()),
+		Algorithm:   algorithm,
+		PrivateKey:  encryptedPrivateKey,
+		PublicKey:   string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes})),
+		ActivatesAt: activatesAt,
+	})
+	if err != nil {
+		return dto.SigningKey{}, err
+	}
+
+	return key, nil
+}
+
+func (s *signingKeyModule) GetAllSigningKeys(ctx context.Context) ([]dto.SigningKey, error) {
+	return s.signingKeyPersistence.GetAllSigningKeys(ctx)
+}
+
+func (s *signingKeyModule) RotateSigningKey(ctx context.Context, request dto.RotateSigningKeyRequest) (dto.SigningKey, error) {
+	if err := request.Validate(); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		s.logger.Info(ctx, "invalid input", zap.Error(err))
+		return dto.SigningKey{}, err
+	}
+	if request.ActivatesAt.IsZero() {
+		request.ActivatesAt = time.Now()
+	}
+	if request.Algorithm == "" {
+		request.Algorithm = constant.SigningAlgorithmPS512
+	}
+
+	privateKey, err := s.generateKey(request.Algorithm)
+	if err != nil {
+		err := errors.ErrInternalServerError.Wrap(err, "could not generate signing key")
+		s.logger.Error(ctx, "error generating signing key", zap.Error(err))
+		return dto.SigningKey{}, err
+	}
+
+	key, err := s.persistKey(ctx, privateKey, request.Algorithm, request.ActivatesAt)
+	if err != nil {
+		return dto.SigningKey{}, err
+	}
+
+	if err := s.LoadSigningKeys(ctx); err != nil {
+		return dto.SigningKey{}, err
+	}
+
+	s.logger.Info(ctx, "signing key rotated", zap.String("kid", key.Kid), zap.String("algorithm", key.Algorithm), zap.Time("activates-at", key.ActivatesAt))
+	return key, nil
+}
+
+func (s *signingKeyModule) RetireSigningKey(ctx context.Context, kid string, request dto.RetireSigningKeyRequest) (dto.SigningKey, error) {
+	if err := request.Validate(); err != nil {
+		err := errors.ErrInvalidUserInput.Wrap(err, "invalid input")
+		s.logger.Info(ctx, "invalid input", zap.Error(err))
+		return dto.SigningKey{}, err
+	}
+	if request.RetiresAt.IsZero() {
+		request.RetiresAt = time.Now()
+	}
+
+	retired, err := s.signingKeyPersistence.GetSigningKeyByKid(ctx, kid)
+	if err != nil {
+		return dto.SigningKey{}, err
+	}
+
+	keys, err := s.signingKeyPersistence.GetAllSigningKeys(ctx)
+	if err != nil {
+		return dto.SigningKey{}, err
+	}
+
+	// the session and access tokens are signed with PS512 and the id tokens with the algorithm of the client,
+	// so another key of the same algorithm has to be able to sign them once this one is retired
+	replaced := false
+	for _, key := range keys {
+		if key.Kid != kid && key.Algorithm == retired.Algorithm && key.IsActive(request.RetiresAt) {
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		err := errors.ErrInvalidUserInput.New("no other signing key will be active, rotate the signing key first")
+		s.logger.Info(ctx, "retiring the signing key leaves no active key", zap.Error(err), zap.String("kid", kid))
+		return dto.SigningKey{}, err
+	}
+
+	key, err := s.signingKeyPersistence.RetireSigningKey(ctx, kid, request.RetiresAt)
+	if err != nil {
+		return dto.SigningKey{}, err
+	}
+
+	if err := s.LoadSigningKeys(ctx); err != nil {
+		return dto.SigningKey{}, err
+	}
+
+	s.logger.Info(ctx, "signing key retired", zap.String("kid", key.Kid), zap.Time("retires-at", request.RetiresAt))
+	return key, nil
+}
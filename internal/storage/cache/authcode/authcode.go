@@ -62,8 +62,12 @@ func (c *AuthCode) SaveAuthCode(ctx context.Context, authCode dto.AuthCode) erro
 		c.logger.Error(ctx, "could not marshal authcode", zap.Error(err), zap.Any("authCode", authCode))
 		return err
 	}
+	expireOn := c.expireOn
+	if !authCode.ExpiresAt.IsZero() {
+		expireOn = time.Until(authCode.ExpiresAt)
+	}
 	authCodeKey := fmt.Sprintf(state.AuthCodeKey, authCode.Code)
-	err = c.client.Set(ctx, authCodeKey, authCodeValue, c.expireOn).Err()
+	err = c.client.Set(ctx, authCodeKey, authCodeValue, expireOn).Err()
 	if err != nil {
 		err := errors.ErrCacheSetError.Wrap(err, "could not set authcode")
 		c.logger.Error(ctx, "could not set authcode", zap.Error(err), zap.Any("authCode", authCode))
@@ -0,0 +1,45 @@
+package clientassertion
+
+import (
+	"context"
+	"fmt"
+	"sso/internal/constant/errors"
+	"sso/internal/constant/state"
+	"sso/internal/storage"
+	"sso/platform/logger"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+type ClientAssertion struct {
+	logger logger.Logger
+	client *redis.Client
+}
+
+func InitClientAssertionCache(client *redis.Client, log logger.Logger) storage.ClientAssertionCache {
+	return &ClientAssertion{
+		logger: log,
+		client: client,
+	}
+}
+
+// UseClientAssertion records the jti of a client assertion until the assertion expires.
+// It reports false if the jti was already recorded for the client, meaning the assertion is replayed.
+func (c *ClientAssertion) UseClientAssertion(ctx context.Context, clientID, jti string, expiresAt time.Time) (bool, error) {
+	expireOn := time.Until(expiresAt)
+	if expireOn <= 0 {
+		return false, nil
+	}
+
+	clientAssertionKey := fmt.Sprintf(state.ClientAssertionKey, clientID, jti)
+	first, err := c.client.SetNX(ctx, clientAssertionKey, expiresAt.Unix(), expireOn).Result()
+	if err != nil {
+		err := errors.ErrCacheSetError.Wrap(err, "could not set client assertion")
+		c.logger.Error(ctx, "could not set client assertion", zap.Error(err), zap.String("client-id", clientID), zap.String("jti", jti))
+		return false, err
+	}
+
+	return first, nil
+}
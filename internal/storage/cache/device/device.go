@@ -0,0 +1,235 @@
+package device
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sso/internal/constant"
+	"sso/internal/constant/errors"
+	"sso/internal/constant/model/dto"
+	"sso/internal/constant/state"
+	"sso/internal/storage"
+	"sso/platform/logger"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/joomcode/errorx"
+	"go.uber.org/zap"
+)
+
+// maxUpdateRetries is how many times an update of a device authorization is tried
+// when it keeps being changed by a concurrent request.
+const maxUpdateRetries = 3
+
+type deviceCache struct {
+	logger   logger.Logger
+	client   *redis.Client
+	expireOn time.Duration
+}
+
+func InitDeviceCache(client *redis.Client, log logger.Logger, expireOn time.Duration) storage.DeviceCache {
+	if expireOn == 0 {
+		expireOn = time.Minute * 10
+	}
+	return &deviceCache{
+		logger:   log,
+		client:   client,
+		expireOn: expireOn,
+	}
+}
+
+func (d *deviceCache) SaveDeviceAuthorization(ctx context.Context, deviceAuthorization dto.DeviceAuthorization) error {
+	deviceValue, err := json.Marshal(deviceAuthorization)
+	if err != nil {
+		err := errors.ErrCacheSetError.Wrap(err, "could not marshal device authorization")
+		d.logger.Error(ctx, "could not marshal device authorization", zap.Error(err), zap.Any("device-authorization", deviceAuthorization))
+		return err
+	}
+
+	_, err = d.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, fmt.Sprintf(state.DeviceKey, deviceAuthorization.DeviceCode), deviceValue, d.expireOn)
+		pipe.Set(ctx, fmt.Sprintf(state.UserCodeKey, deviceAuthorization.UserCode), deviceAuthorization.DeviceCode, d.expireOn)
+		return nil
+	})
+	if err != nil {
+		err := errors.ErrCacheSetError.Wrap(err, "could not set device authorization")
+		d.logger.Error(ctx, "could not set device authorization", zap.Error(err), zap.Any("device-authorization", deviceAuthorization))
+		return err
+	}
+
+	return nil
+}
+
+func (d *deviceCache) GetDeviceAuthorization(ctx context.Context, deviceCode string) (dto.DeviceAuthorization, error) {
+	deviceResult, err := d.client.Get(ctx, fmt.Sprintf(state.DeviceKey, deviceCode)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			err := errors.ErrInvalidUserInput.Wrap(err, "expired_token")
+			d.logger.Info(ctx, "device code not found", zap.Error(err), zap.String("device-code", deviceCode))
+			return dto.DeviceAuthorization{}, err
+		}
+
+		err := errors.ErrCacheGetError.Wrap(err, "could not get from device cache")
+		d.logger.Error(ctx, "could not read from device cache", zap.Error(err))
+		return dto.DeviceAuthorization{}, err
+	}
+
+	var deviceAuthorization dto.DeviceAuthorization
+	if err := json.Unmarshal([]byte(deviceResult), &deviceAuthorization); err != nil {
+		err := errors.ErrCacheGetError.Wrap(err, "could not unmarshal device authorization")
+		d.logger.Error(ctx, "could not unmarshal device authorization", zap.Error(err), zap.String("device-code", deviceCode))
+		return dto.DeviceAuthorization{}, err
+	}
+
+	return deviceAuthorization, nil
+}
+
+func (d *deviceCache) GetDeviceAuthorizationByUserCode(ctx context.Context, userCode string) (dto.DeviceAuthorization, error) {
+	deviceCode, err := d.client.Get(ctx, fmt.Sprintf(state.UserCodeKey, userCode)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			err := errors.ErrNoRecordFound.Wrap(err, "invalid user code")
+			d.logger.Info(ctx, "user code not found", zap.Error(err), zap.String("user-code", userCode))
+			return dto.DeviceAuthorization{}, err
+		}
+
+		err := errors.ErrCacheGetError.Wrap(err, "could not get from device cache")
+		d.logger.Error(ctx, "could not read from device cache", zap.Error(err))
+		return dto.DeviceAuthorization{}, err
+	}
+
+	return d.GetDeviceAuthorization(ctx, deviceCode)
+}
+
+// RecordDevicePoll records when the device last polled and the interval it must keep from now on.
+// Nothing is recorded once the user decided on the authorization, so a poll never overwrites the decision.
+func (d *deviceCache) RecordDevicePoll(ctx context.Context, deviceCode string, polledAt time.Time, interval int) error {
+	err := d.update(ctx, deviceCode, func(deviceAuthorization *dto.DeviceAuthorization) (bool, error) {
+		if deviceAuthorization.Status != constant.DeviceAuthorizationPending {
+			return false, nil
+		}
+		deviceAuthorization.LastPolledAt = polledAt
+		deviceAuthorization.Interval = interval
+		return true, nil
+	})
+	if err == redis.TxFailedErr {
+		// the authorization changed while the poll was being recorded, the next poll sees the change.
+		d.logger.Info(ctx, "device authorization changed while recording a poll", zap.String("device-code", deviceCode))
+		return nil
+	}
+
+	return err
+}
+
+// CompleteDeviceAuthorization records the decision of the user on a pending device authorization.
+func (d *deviceCache) CompleteDeviceAuthorization(ctx context.Context, deviceAuthorization dto.DeviceAuthorization) error {
+	var err error
+	for i := 0; i < maxUpdateRetries; i++ {
+		err = d.update(ctx, deviceAuthorization.DeviceCode, func(current *dto.DeviceAuthorization) (bool, error) {
+			if current.Status != constant.DeviceAuthorizationPending {
+				err := errors.ErrInvalidUserInput.New("user code already used")
+				d.logger.Info(ctx, "device authorization already completed", zap.Error(err), zap.String("device-code", deviceAuthorization.DeviceCode))
+				return false, err
+			}
+			deviceAuthorization.LastPolledAt = current.LastPolledAt
+			deviceAuthorization.Interval = current.Interval
+			*current = deviceAuthorization
+			return true, nil
+		})
+		if err != redis.TxFailedErr {
+			return err
+		}
+	}
+
+	err = errors.ErrCacheSetError.Wrap(err, "could not update device authorization")
+	d.logger.Error(ctx, "device authorization kept changing while completing it", zap.Error(err), zap.String("device-code", deviceAuthorization.DeviceCode))
+	return err
+}
+
+// ClaimDeviceAuthorization removes the device authorization tokens are about to be issued for,
+// it fails when the authorization was already claimed so the tokens are issued only once.
+func (d *deviceCache) ClaimDeviceAuthorization(ctx context.Context, deviceAuthorization dto.DeviceAuthorization) error {
+	deleted, err := d.client.Del(ctx, fmt.Sprintf(state.DeviceKey, deviceAuthorization.DeviceCode)).Result()
+	if err != nil {
+		err := errors.ErrCacheDel.Wrap(err, "could not claim device authorization")
+		d.logger.Error(ctx, "could not claim device authorization", zap.Error(err), zap.String("device-code", deviceAuthorization.DeviceCode))
+		return err
+	}
+	if deleted != 1 {
+		err := errors.ErrInvalidUserInput.New("expired_token")
+		d.logger.Info(ctx, "device authorization already claimed", zap.Error(err), zap.String("device-code", deviceAuthorization.DeviceCode))
+		return err
+	}
+
+	if err := d.client.Del(ctx, fmt.Sprintf(state.UserCodeKey, deviceAuthorization.UserCode)).Err(); err != nil {
+		d.logger.Warn(ctx, "could not delete user code", zap.Error(err), zap.String("user-code", deviceAuthorization.UserCode))
+	}
+
+	return nil
+}
+
+// update applies the change to the device authorization without touching its expiry,
+// the change is dropped with redis.TxFailedErr when the authorization is changed by someone else meanwhile.
+func (d *deviceCache) update(ctx context.Context, deviceCode string, change func(deviceAuthorization *dto.DeviceAuthorization) (bool, error)) error {
+	deviceKey := fmt.Sprintf(state.DeviceKey, deviceCode)
+	err := d.client.Watch(ctx, func(tx *redis.Tx) error {
+		deviceResult, err := tx.Get(ctx, deviceKey).Result()
+		if err != nil {
+			if err == redis.Nil {
+				err := errors.ErrInvalidUserInput.Wrap(err, "expired_token")
+				d.logger.Info(ctx, "device code not found", zap.Error(err), zap.String("device-code", deviceCode))
+				return err
+			}
+
+			err := errors.ErrCacheGetError.Wrap(err, "could not get from device cache")
+			d.logger.Error(ctx, "could not read from device cache", zap.Error(err))
+			return err
+		}
+
+		var deviceAuthorization dto.DeviceAuthorization
+		if err := json.Unmarshal([]byte(deviceResult), &deviceAuthorization); err != nil {
+			err := errors.ErrCacheGetError.Wrap(err, "could not unmarshal device authorization")
+			d.logger.Error(ctx, "could not unmarshal device authorization", zap.Error(err), zap.String("device-code", deviceCode))
+			return err
+		}
+
+		changed, err := change(&deviceAuthorization)
+		if err != nil || !changed {
+			return err
+		}
+
+		deviceValue, err := json.Marshal(deviceAuthorization)
+		if err != nil {
+			err := errors.ErrCacheSetError.Wrap(err, "could not marshal device authorization")
+			d.logger.Error(ctx, "could not marshal device authorization", zap.Error(err), zap.Any("device-authorization", deviceAuthorization))
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SetXX(ctx, deviceKey, deviceValue, redis.KeepTTL)
+			return nil
+		})
+		return err
+	}, deviceKey)
+	if err != nil && err != redis.TxFailedErr && errorx.Cast(err) == nil {
+		err := errors.ErrCacheSetError.Wrap(err, "could not update device authorization")
+		d.logger.Error(ctx, "could not update device authorization", zap.Error(err), zap.String("device-code", deviceCode))
+		return err
+	}
+
+	return err
+}
+
+func (d *deviceCache) DeleteDeviceAuthorization(ctx context.Context, deviceAuthorization dto.DeviceAuthorization) error {
+	err := d.client.Del(ctx,
+		fmt.Sprintf(state.DeviceKey, deviceAuthorization.DeviceCode),
+		fmt.Sprintf(state.UserCodeKey, deviceAuthorization.UserCode),
+	).Err()
+	if err != nil {
+		err := errors.ErrCacheDel.Wrap(err, "could not delete device authorization")
+		d.logger.Error(ctx, "could not delete device authorization", zap.Error(err), zap.String("device-code", deviceAuthorization.DeviceCode))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package requestobject
+
+import (
+	"context"
+	"fmt"
+	"sso/internal/constant/errors"
+	"sso/internal/constant/state"
+	"sso/internal/storage"
+	"sso/platform/logger"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+type RequestObject struct {
+	logger logger.Logger
+	client *redis.Client
+}
+
+func InitRequestObjectCache(client *redis.Client, log logger.Logger) storage.RequestObjectCache {
+	return &RequestObject{
+		logger: log,
+		client: client,
+	}
+}
+
+// UseRequestObject records the jti of a request object until the request object expires.
+// It reports false if the jti was already recorded for the client, meaning the request object is replayed.
+func (r *RequestObject) UseRequestObject(ctx context.Context, clientID, jti string, expiresAt time.Time) (bool, error) {
+	expireOn := time.Until(expiresAt)
+	if expireOn <= 0 {
+		return false, nil
+	}
+
+	requestObjectKey := fmt.Sprintf(state.RequestObjectKey, clientID, jti)
+	first, err := r.client.SetNX(ctx, requestObjectKey, expiresAt.Unix(), expireOn).Result()
+	if err != nil {
+		err := errors.ErrCacheSetError.Wrap(err, "could not set request object")
+		r.logger.Error(ctx, "could not set request object", zap.Error(err), zap.String("client-id", clientID), zap.String("jti", jti))
+		return false, err
+	}
+
+	return first, nil
+}
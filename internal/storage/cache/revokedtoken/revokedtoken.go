@@ -0,0 +1,56 @@
+package revokedtoken
+
+import (
+	"context"
+	"fmt"
+	"sso/internal/constant/errors"
+	"sso/internal/constant/state"
+	"sso/internal/storage"
+	"sso/platform/logger"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+type RevokedToken struct {
+	logger logger.Logger
+	client *redis.Client
+}
+
+func InitRevokedTokenCache(client *redis.Client, log logger.Logger) storage.RevokedTokenCache {
+	return &RevokedToken{
+		logger: log,
+		client: client,
+	}
+}
+
+// RevokeToken adds the jti to the denylist until the token it identifies expires.
+func (c *RevokedToken) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	expireOn := time.Until(expiresAt)
+	if expireOn <= 0 {
+		return nil
+	}
+
+	revokedTokenKey := fmt.Sprintf(state.RevokedTokenKey, jti)
+	err := c.client.Set(ctx, revokedTokenKey, expiresAt.Unix(), expireOn).Err()
+	if err != nil {
+		err := errors.ErrCacheSetError.Wrap(err, "could not set revoked token")
+		c.logger.Error(ctx, "could not set revoked token", zap.Error(err), zap.String("jti", jti))
+		return err
+	}
+
+	return nil
+}
+
+func (c *RevokedToken) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	revokedTokenKey := fmt.Sprintf(state.RevokedTokenKey, jti)
+	count, err := c.client.Exists(ctx, revokedTokenKey).Result()
+	if err != nil {
+		err := errors.ErrCacheGetError.Wrap(err, "could not read from revoked token cache")
+		c.logger.Error(ctx, "could not read from revoked token cache", zap.Error(err), zap.String("jti", jti))
+		return false, err
+	}
+
+	return count > 0, nil
+}
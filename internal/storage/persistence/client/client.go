@@ -3,6 +3,9 @@ package client
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
 
 	"sso/internal/constant/errors"
 	"sso/internal/constant/errors/sqlcerr"
@@ -32,12 +35,29 @@ func InitClient(log logger.Logger, db *db.Queries) storage.ClientPersistence {
 
 func (c *clientPersistence) Create(ctx context.Context, clientParam dto.Client) (*dto.Client, error) {
 	client, err := c.db.CreateClient(ctx, db.CreateClientParams{
-		Name:         clientParam.Name,
-		ClientType:   clientParam.ClientType,
-		RedirectUris: utils.ArrayToString(clientParam.RedirectURIs),
-		Scopes:       clientParam.Scopes,
-		Secret:       clientParam.Secret,
-		LogoUrl:      clientParam.LogoURL,
+		Name:                               clientParam.Name,
+		ClientType:                         clientParam.ClientType,
+		RedirectUris:                       utils.ArrayToString(clientParam.RedirectURIs),
+		Scopes:                             clientParam.Scopes,
+		Secret:                             clientParam.Secret,
+		LogoUrl:                            clientParam.LogoURL,
+		RequirePkce:                        clientParam.RequirePKCE,
+		ResponseTypes:                      strings.Join(clientParam.ResponseTypes, ","),
+		IDTokenSignedResponseAlg:           clientParam.IDTokenSignedResponseAlg,
+		GrantTypes:                         strings.Join(clientParam.GrantTypes, ","),
+		TokenEndpointAuthMethod:            clientParam.TokenEndpointAuthMethod,
+		Jwks:                               marshalJWKS(clientParam.JWKS),
+		TlsClientCertificateThumbprint:     clientParam.TLSClientCertificateThumbprint,
+		RequirePushedAuthorizationRequests: clientParam.RequirePushedAuthorizationRequests,
+		BackchannelLogoutUri:               clientParam.BackchannelLogoutURI,
+		FrontchannelLogoutUri:              clientParam.FrontchannelLogoutURI,
+		AccessTokenLifetime:                int32(clientParam.AccessTokenLifetime),
+		RefreshTokenLifetime:               int32(clientParam.RefreshTokenLifetime),
+		RefreshTokenIdleLifetime:           int32(clientParam.RefreshTokenIdleLifetime),
+		RefreshTokenAbsoluteLifetime:       int32(clientParam.RefreshTokenAbsoluteLifetime),
+		IDTokenLifetime:                    int32(clientParam.IDTokenLifetime),
+		AuthCodeLifetime:                   int32(clientParam.AuthCodeLifetime),
+		OptionalScopes:                     clientParam.OptionalScopes,
 	})
 	if err != nil {
 		err := errors.ErrWriteError.Wrap(err, "couldn't create client")
@@ -45,14 +65,32 @@ func (c *clientPersistence) Create(ctx context.Context, clientParam dto.Client)
 		return nil, err
 	}
 	return &dto.Client{
-		ID:           client.ID,
-		Name:         client.Name,
-		ClientType:   client.ClientType,
-		RedirectURIs: utils.StringToArray(client.RedirectUris),
-		Scopes:       client.Scopes,
-		Secret:       client.Secret,
-		LogoURL:      client.LogoUrl,
-		Status:       client.Status,
+		ID:                                 client.ID,
+		Name:                               client.Name,
+		ClientType:                         client.ClientType,
+		RedirectURIs:                       utils.StringToArray(client.RedirectUris),
+		Scopes:                             client.Scopes,
+		Secret:                             client.Secret,
+		LogoURL:                            client.LogoUrl,
+		Status:                             client.Status,
+		CreatedAt:                          client.CreatedAt,
+		RequirePKCE:                        client.RequirePkce,
+		ResponseTypes:                      commaSeparated(client.ResponseTypes),
+		IDTokenSignedResponseAlg:           client.IDTokenSignedResponseAlg,
+		GrantTypes:                         commaSeparated(client.GrantTypes),
+		TokenEndpointAuthMethod:            client.TokenEndpointAuthMethod,
+		JWKS:                               unmarshalJWKS(client.Jwks),
+		TLSClientCertificateThumbprint:     client.TlsClientCertificateThumbprint,
+		RequirePushedAuthorizationRequests: client.RequirePushedAuthorizationRequests,
+		BackchannelLogoutURI:               client.BackchannelLogoutUri,
+		FrontchannelLogoutURI:              client.FrontchannelLogoutUri,
+		AccessTokenLifetime:                int(client.AccessTokenLifetime),
+		RefreshTokenLifetime:               int(client.RefreshTokenLifetime),
+		RefreshTokenIdleLifetime:           int(client.RefreshTokenIdleLifetime),
+		RefreshTokenAbsoluteLifetime:       int(client.RefreshTokenAbsoluteLifetime),
+		IDTokenLifetime:                    int(client.IDTokenLifetime),
+		AuthCodeLifetime:                   int(client.AuthCodeLifetime),
+		OptionalScopes:                     client.OptionalScopes,
 	}, nil
 }
 
@@ -71,15 +109,35 @@ func (c *clientPersistence) GetClientByID(ctx context.Context, id uuid.UUID) (*d
 	}
 
 	return &dto.Client{
-		ID:           client.ID,
-		Name:         client.Name,
-		Status:       client.Status,
-		Secret:       client.Secret,
-		Scopes:       client.Scopes,
-		RedirectURIs: utils.StringToArray(client.RedirectUris),
-		ClientType:   client.ClientType,
-		LogoURL:      client.LogoUrl,
-		FirstParty:   client.FirstParty,
+		ID:                                 client.ID,
+		Name:                               client.Name,
+		Status:                             client.Status,
+		Secret:                             client.Secret,
+		Scopes:                             client.Scopes,
+		RedirectURIs:                       utils.StringToArray(client.RedirectUris),
+		ClientType:                         client.ClientType,
+		LogoURL:                            client.LogoUrl,
+		FirstParty:                         client.FirstParty,
+		CreatedAt:                          client.CreatedAt,
+		RequirePKCE:                        client.RequirePkce,
+		ResponseTypes:                      commaSeparated(client.ResponseTypes),
+		IDTokenSignedResponseAlg:           client.IDTokenSignedResponseAlg,
+		GrantTypes:                         commaSeparated(client.GrantTypes),
+		PreviousSecret:                     client.PreviousSecret,
+		PreviousSecretExpiresAt:            client.PreviousSecretExpiresAt.Time,
+		TokenEndpointAuthMethod:            client.TokenEndpointAuthMethod,
+		JWKS:                               unmarshalJWKS(client.Jwks),
+		TLSClientCertificateThumbprint:     client.TlsClientCertificateThumbprint,
+		RequirePushedAuthorizationRequests: client.RequirePushedAuthorizationRequests,
+		BackchannelLogoutURI:               client.BackchannelLogoutUri,
+		FrontchannelLogoutURI:              client.FrontchannelLogoutUri,
+		AccessTokenLifetime:                int(client.AccessTokenLifetime),
+		RefreshTokenLifetime:               int(client.RefreshTokenLifetime),
+		RefreshTokenIdleLifetime:           int(client.RefreshTokenIdleLifetime),
+		RefreshTokenAbsoluteLifetime:       int(client.RefreshTokenAbsoluteLifetime),
+		IDTokenLifetime:                    int(client.IDTokenLifetime),
+		AuthCodeLifetime:                   int(client.AuthCodeLifetime),
+		OptionalScopes:                     client.OptionalScopes,
 	}, nil
 
 }
@@ -117,14 +175,31 @@ func (c *clientPersistence) GetAllClients(ctx context.Context, filters db_pgnflt
 	clientsDTO := make([]dto.Client, len(clients))
 	for k, v := range clients {
 		clientsDTO[k] = dto.Client{
-			ID:           v.ID,
-			Name:         v.Name,
-			Status:       v.Status,
-			Scopes:       v.Scopes,
-			RedirectURIs: utils.StringToArray(v.RedirectUris),
-			ClientType:   v.ClientType,
-			LogoURL:      v.LogoUrl,
-			CreatedAt:    v.CreatedAt,
+			ID:                                 v.ID,
+			Name:                               v.Name,
+			Status:                             v.Status,
+			Scopes:                             v.Scopes,
+			RedirectURIs:                       utils.StringToArray(v.RedirectUris),
+			ClientType:                         v.ClientType,
+			LogoURL:                            v.LogoUrl,
+			CreatedAt:                          v.CreatedAt,
+			RequirePKCE:                        v.RequirePkce,
+			ResponseTypes:                      commaSeparated(v.ResponseTypes),
+			IDTokenSignedResponseAlg:           v.IDTokenSignedResponseAlg,
+			GrantTypes:                         commaSeparated(v.GrantTypes),
+			TokenEndpointAuthMethod:            v.TokenEndpointAuthMethod,
+			JWKS:                               unmarshalJWKS(v.Jwks),
+			TLSClientCertificateThumbprint:     v.TlsClientCertificateThumbprint,
+			RequirePushedAuthorizationRequests: v.RequirePushedAuthorizationRequests,
+			BackchannelLogoutURI:               v.BackchannelLogoutUri,
+			FrontchannelLogoutURI:              v.FrontchannelLogoutUri,
+			AccessTokenLifetime:                int(v.AccessTokenLifetime),
+			RefreshTokenLifetime:               int(v.RefreshTokenLifetime),
+			RefreshTokenIdleLifetime:           int(v.RefreshTokenIdleLifetime),
+			RefreshTokenAbsoluteLifetime:       int(v.RefreshTokenAbsoluteLifetime),
+			IDTokenLifetime:                    int(v.IDTokenLifetime),
+			AuthCodeLifetime:                   int(v.AuthCodeLifetime),
+			OptionalScopes:                     v.OptionalScopes,
 		}
 	}
 	return clientsDTO, &model.MetaData{
@@ -157,12 +232,29 @@ func (c *clientPersistence) UpdateClientStatus(ctx context.Context, updateClient
 
 func (c *clientPersistence) UpdateClient(ctx context.Context, client dto.Client) error {
 	_, err := c.db.UpdateEntireClient(ctx, db.UpdateEntireClientParams{
-		Name:         client.Name,
-		LogoUrl:      client.LogoURL,
-		ClientType:   client.ClientType,
-		RedirectUris: utils.ArrayToString(client.RedirectURIs),
-		Scopes:       client.Scopes,
-		ID:           client.ID,
+		Name:                               client.Name,
+		LogoUrl:                            client.LogoURL,
+		ClientType:                         client.ClientType,
+		RedirectUris:                       utils.ArrayToString(client.RedirectURIs),
+		Scopes:                             client.Scopes,
+		RequirePkce:                        client.RequirePKCE,
+		ResponseTypes:                      strings.Join(client.ResponseTypes, ","),
+		IDTokenSignedResponseAlg:           client.IDTokenSignedResponseAlg,
+		GrantTypes:                         strings.Join(client.GrantTypes, ","),
+		TokenEndpointAuthMethod:            client.TokenEndpointAuthMethod,
+		Jwks:                               marshalJWKS(client.JWKS),
+		TlsClientCertificateThumbprint:     client.TLSClientCertificateThumbprint,
+		RequirePushedAuthorizationRequests: client.RequirePushedAuthorizationRequests,
+		BackchannelLogoutUri:               client.BackchannelLogoutURI,
+		FrontchannelLogoutUri:              client.FrontchannelLogoutURI,
+		AccessTokenLifetime:                int32(client.AccessTokenLifetime),
+		RefreshTokenLifetime:               int32(client.RefreshTokenLifetime),
+		RefreshTokenIdleLifetime:           int32(client.RefreshTokenIdleLifetime),
+		RefreshTokenAbsoluteLifetime:       int32(client.RefreshTokenAbsoluteLifetime),
+		IDTokenLifetime:                    int32(client.IDTokenLifetime),
+		AuthCodeLifetime:                   int32(client.AuthCodeLifetime),
+		OptionalScopes:                     client.OptionalScopes,
+		ID:                                 client.ID,
 	})
 
 	if err != nil {
@@ -179,3 +271,131 @@ func (c *clientPersistence) UpdateClient(ctx context.Context, client dto.Client)
 
 	return nil
 }
+
+func (c *clientPersistence) RotateClientSecret(ctx context.Context, clientID uuid.UUID, secretHash string, previousSecretExpiresAt time.Time) error {
+	_, err := c.db.RotateClientSecret(ctx, db.RotateClientSecretParams{
+		ID:                      clientID,
+		PreviousSecretExpiresAt: sql.NullTime{Time: previousSecretExpiresAt, Valid: true},
+		Secret:                  secretHash,
+	})
+	if err != nil {
+		if sqlcerr.Is(err, sqlcerr.ErrNoRows) {
+			err := errors.ErrNoRecordFound.Wrap(err, "client not found")
+			c.logger.Info(ctx, "client not found", zap.Error(err), zap.String("client-id", clientID.String()))
+			return err
+		}
+		err = errors.ErrUpdateError.Wrap(err, "error rotating client secret")
+		c.logger.Error(ctx, "error rotating client secret", zap.Error(err), zap.String("client-id", clientID.String()))
+		return err
+	}
+
+	return nil
+}
+
+// commaSeparated splits the comma separated response types or grant types of a client,
+// response types can not be space separated as they contain spaces themselves.
+func commaSeparated(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func (c *clientPersistence) CreateInitialAccessToken(ctx context.Context, token dto.InitialAccessToken) (*dto.InitialAccessToken, error) {
+	createdToken, err := c.db.CreateInitialAccessToken(ctx, db.CreateInitialAccessTokenParams{
+		TokenHash:  token.TokenHash,
+		CreatedBy:  token.CreatedBy,
+		ExpiresAt:  token.ExpiresAt,
+		Scopes:     token.Scopes,
+		GrantTypes: strings.Join(token.GrantTypes, ","),
+	})
+	if err != nil {
+		err := errors.ErrWriteError.Wrap(err, "could not create initial access token")
+		c.logger.Error(ctx, "unable to create initial access token", zap.Error(err), zap.String("created-by", token.CreatedBy.String()))
+		return nil, err
+	}
+
+	return toInitialAccessTokenDTO(createdToken), nil
+}
+
+func (c *clientPersistence) GetInitialAccessToken(ctx context.Context, tokenHash string) (*dto.InitialAccessToken, error) {
+	token, err := c.db.GetInitialAccessToken(ctx, tokenHash)
+	if err != nil {
+		if sqlcerr.Is(err, sqlcerr.ErrNoRows) {
+			err := errors.ErrNoRecordFound.Wrap(err, "initial access token not found")
+			c.logger.Info(ctx, "initial access token not found", zap.Error(err))
+			return nil, err
+		}
+		err = errors.ErrReadError.Wrap(err, "could not read initial access token")
+		c.logger.Error(ctx, "unable to read initial access token", zap.Error(err))
+		return nil, err
+	}
+
+	return toInitialAccessTokenDTO(token), nil
+}
+
+func (c *clientPersistence) SaveRegistrationAccessToken(ctx context.Context, clientID uuid.UUID, tokenHash string) error {
+	if _, err := c.db.CreateClientRegistrationToken(ctx, db.CreateClientRegistrationTokenParams{
+		ClientID:  clientID,
+		TokenHash: tokenHash,
+	}); err != nil {
+		err := errors.ErrWriteError.Wrap(err, "could not save registration access token")
+		c.logger.Error(ctx, "unable to save registration access token", zap.Error(err), zap.String("client-id", clientID.String()))
+		return err
+	}
+
+	return nil
+}
+
+func (c *clientPersistence) GetRegistrationAccessTokenHash(ctx context.Context, clientID uuid.UUID) (string, error) {
+	token, err := c.db.GetClientRegistrationToken(ctx, clientID)
+	if err != nil {
+		if sqlcerr.Is(err, sqlcerr.ErrNoRows) {
+			err := errors.ErrNoRecordFound.Wrap(err, "client was not registered dynamically")
+			c.logger.Info(ctx, "registration access token not found", zap.Error(err), zap.String("client-id", clientID.String()))
+			return "", err
+		}
+		err = errors.ErrReadError.Wrap(err, "could not read registration access token")
+		c.logger.Error(ctx, "unable to read registration access token", zap.Error(err), zap.String("client-id", clientID.String()))
+		return "", err
+	}
+
+	return token.TokenHash, nil
+}
+
+// marshalJWKS stores the key set of a client as json, a client with no key set is stored as an empty string.
+func marshalJWKS(jwks *dto.JWKS) string {
+	if jwks == nil {
+		return ""
+	}
+
+	value, err := json.Marshal(jwks)
+	if err != nil {
+		return ""
+	}
+	return string(value)
+}
+
+func unmarshalJWKS(value string) *dto.JWKS {
+	if value == "" {
+		return nil
+	}
+
+	jwks := &dto.JWKS{}
+	if err := json.Unmarshal([]byte(value), jwks); err != nil {
+		return nil
+	}
+	return jwks
+}
+
+func toInitialAccessTokenDTO(token db.InitialAccessToken) *dto.InitialAccessToken {
+	return &dto.InitialAccessToken{
+		ID:         token.ID,
+		TokenHash:  token.TokenHash,
+		CreatedBy:  token.CreatedBy,
+		ExpiresAt:  token.ExpiresAt,
+		CreatedAt:  token.CreatedAt,
+		Scopes:     token.Scopes,
+		GrantTypes: commaSeparated(token.GrantTypes),
+	}
+}
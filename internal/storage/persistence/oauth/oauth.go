@@ -2,6 +2,7 @@ package oauth
 
 import (
 	"context"
+	"strings"
 
 	"sso/internal/constant/errors"
 	"sso/internal/constant/errors/sqlcerr"
@@ -214,22 +215,38 @@ func (o *oauth) GetUserByID(ctx context.Context, Id uuid.UUID) (*dto.User, error
 	}, nil
 }
 
-func (o *oauth) SaveInternalRefreshToken(ctx context.Context, rf dto.InternalRefreshToken) error {
-	_, err := o.db.SaveInternalRefreshToken(ctx, db.SaveInternalRefreshTokenParams{
+func (o *oauth) SaveInternalRefreshToken(ctx context.Context, rf dto.InternalRefreshToken) (*dto.InternalRefreshToken, error) {
+	refreshToken, err := o.db.SaveInternalRefreshToken(ctx, db.SaveInternalRefreshTokenParams{
 		UserID:       rf.UserID,
 		RefreshToken: rf.RefreshToken,
 		IpAddress:    rf.IPAddress,
 		UserAgent:    rf.UserAgent,
 		ExpiresAt:    rf.ExpiresAt,
+		AuthTime:     rf.Authentication.Time,
+		Amr:          utils.ArrayToString(rf.Authentication.Methods),
 	})
 
 	if err != nil {
 		err = errors.ErrWriteError.Wrap(err, "could not save internal rf token")
 		o.logger.Error(ctx, "could not save internal refresh token", zap.Error(err), zap.Any("internalRefrshToken", rf))
-		return err
+		return nil, err
 	}
 
-	return nil
+	return &dto.InternalRefreshToken{
+		ID:           refreshToken.ID,
+		RefreshToken: refreshToken.RefreshToken,
+		UserID:       refreshToken.UserID,
+		ExpiresAt:    refreshToken.ExpiresAt,
+		UserAgent:    refreshToken.UserAgent,
+		IPAddress:    refreshToken.IpAddress,
+		CreatedAt:    refreshToken.CreatedAt,
+		UpdatedAt:    refreshToken.UpdatedAt,
+		Authentication: dto.Authentication{
+			Time:      refreshToken.AuthTime,
+			Methods:   strings.Fields(refreshToken.Amr),
+			SessionID: refreshToken.ID.String(),
+		},
+	}, nil
 }
 
 func (o *oauth) RemoveInternalRefreshToken(ctx context.Context, refreshToken string) error {
@@ -263,23 +280,25 @@ func (o *oauth) GetInternalRefreshToken(ctx context.Context, token string) (*dto
 		IPAddress:    refreshToken.IpAddress,
 		UserID:       refreshToken.UserID,
 		CreatedAt:    refreshToken.CreatedAt,
+		Authentication: dto.Authentication{
+			Time:      refreshToken.AuthTime,
+			Methods:   strings.Fields(refreshToken.Amr),
+			SessionID: refreshToken.ID.String(),
+		},
 	}, nil
 }
 
-func (o *oauth) UpdateInternalRefreshToken(ctx context.Context, oldToken, newToken string) (*dto.InternalRefreshToken, error) {
-	refreshToken, err := o.db.UpdateInternalRefreshToken(ctx, db.UpdateInternalRefreshTokenParams{
-		RefreshToken:   oldToken,
-		RefreshToken_2: newToken,
-	})
+func (o *oauth) RotateInternalRefreshToken(ctx context.Context, oldToken, newToken string) (*dto.InternalRefreshToken, error) {
+	refreshToken, err := o.db.RotateInternalRefreshTokenTX(ctx, newToken, oldToken)
 	if err != nil {
 		if sqlcerr.Is(err, sqlcerr.ErrNoRows) {
 			err := errors.ErrNoRecordFound.Wrap(err, "refresh token not found")
-			o.logger.Warn(ctx, "refresh token was not found while trying to update the refresh token")
+			o.logger.Warn(ctx, "refresh token was not found while trying to rotate the refresh token")
 
 			return nil, err
 		}
-		err := errors.ErrWriteError.Wrap(err, "unable to update the refresh token")
-		o.logger.Error(ctx, "error updating the user refresh token", zap.Error(err), zap.String("old-token", oldToken), zap.String("new-token", newToken))
+		err := errors.ErrWriteError.Wrap(err, "unable to rotate the refresh token")
+		o.logger.Error(ctx, "error rotating the user refresh token", zap.Error(err))
 		return nil, err
 	}
 	return &dto.InternalRefreshToken{
@@ -291,9 +310,55 @@ func (o *oauth) UpdateInternalRefreshToken(ctx context.Context, oldToken, newTok
 		IPAddress:    refreshToken.IpAddress,
 		CreatedAt:    refreshToken.CreatedAt,
 		UpdatedAt:    refreshToken.UpdatedAt,
+		Authentication: dto.Authentication{
+			Time:      refreshToken.AuthTime,
+			Methods:   strings.Fields(refreshToken.Amr),
+			SessionID: refreshToken.ID.String(),
+		},
 	}, nil
 }
 
+func (o *oauth) GetInternalRefreshTokenFamily(ctx context.Context, supersededToken string) (*dto.InternalRefreshToken, error) {
+	refreshToken, err := o.db.GetInternalRefreshTokenBySupersededToken(ctx, supersededToken)
+	if err != nil {
+		if sqlcerr.Is(err, sqlcerr.ErrNoRows) {
+			err := errors.ErrNoRecordFound.Wrap(err, "no refresh token family found")
+			o.logger.Info(ctx, "internal refresh token family not found", zap.Error(err))
+			return nil, err
+		}
+		err = errors.ErrReadError.Wrap(err, "could not read refresh token family")
+		o.logger.Error(ctx, "could not read internal refresh token family", zap.Error(err))
+		return nil, err
+	}
+	return &dto.InternalRefreshToken{
+		ID:           refreshToken.ID,
+		RefreshToken: refreshToken.RefreshToken,
+		UserID:       refreshToken.UserID,
+		ExpiresAt:    refreshToken.ExpiresAt,
+		UserAgent:    refreshToken.UserAgent,
+		IPAddress:    refreshToken.IpAddress,
+		CreatedAt:    refreshToken.CreatedAt,
+		UpdatedAt:    refreshToken.UpdatedAt,
+		Authentication: dto.Authentication{
+			Time:      refreshToken.AuthTime,
+			Methods:   strings.Fields(refreshToken.Amr),
+			SessionID: refreshToken.ID.String(),
+		},
+	}, nil
+}
+
+func (o *oauth) RevokeInternalRefreshTokenFamily(ctx context.Context, family dto.InternalRefreshToken) error {
+	if err := o.db.RevokeInternalRefreshTokenFamilyTX(ctx, family); err != nil {
+		err := errors.ErrDBDelError.Wrap(err, "could not revoke refresh token family")
+		o.logger.Error(ctx, "unable to revoke internal refresh token family", zap.Error(err),
+			zap.String("family-id", family.ID.String()),
+			zap.String("user-id", family.UserID.String()))
+		return err
+	}
+
+	return nil
+}
+
 func (o *oauth) GetInternalRefreshTokensByUserID(ctx context.Context, userID uuid.UUID) ([]dto.InternalRefreshToken, error) {
 	refreshTokens, err := o.db.GetInternalRefreshTokensByUserID(ctx, userID)
 	if err != nil {
@@ -318,12 +383,57 @@ func (o *oauth) GetInternalRefreshTokensByUserID(ctx context.Context, userID uui
 			UserID:       refreshTokens[i].UserID,
 			CreatedAt:    refreshTokens[i].CreatedAt,
 			UpdatedAt:    refreshTokens[i].UpdatedAt,
+			Authentication: dto.Authentication{
+				Time:      refreshTokens[i].AuthTime,
+				Methods:   strings.Fields(refreshTokens[i].Amr),
+				SessionID: refreshTokens[i].ID.String(),
+			},
 		}
 	}
 
 	return dtoRefreshTokens, nil
 }
 
+func (o *oauth) GetLogoutClients(ctx context.Context, userID uuid.UUID) ([]dto.Client, error) {
+	clients, err := o.db.GetLogoutClientsForUser(ctx, userID)
+	if err != nil {
+		err = errors.ErrReadError.Wrap(err, "could not read logout clients")
+		o.logger.Error(ctx, "could not read the clients to notify of logout", zap.Error(err), zap.String("user-id", userID.String()))
+		return nil, err
+	}
+
+	dtoClients := make([]dto.Client, len(clients))
+	for i := 0; i < len(clients); i++ {
+		dtoClients[i] = dto.Client{
+			ID:                       clients[i].ID,
+			BackchannelLogoutURI:     clients[i].BackchannelLogoutUri,
+			FrontchannelLogoutURI:    clients[i].FrontchannelLogoutUri,
+			IDTokenSignedResponseAlg: clients[i].IDTokenSignedResponseAlg,
+		}
+	}
+
+	return dtoClients, nil
+}
+
+func (o *oauth) SaveBackChannelLogoutDelivery(ctx context.Context, delivery dto.BackChannelLogoutDelivery) error {
+	if _, err := o.db.CreateBackchannelLogoutDelivery(ctx, db.CreateBackchannelLogoutDeliveryParams{
+		ClientID:  delivery.ClientID,
+		UserID:    delivery.UserID,
+		SessionID: delivery.SessionID,
+		Status:    delivery.Status,
+		Attempts:  int32(delivery.Attempts),
+		Error:     delivery.Error,
+	}); err != nil {
+		err = errors.ErrWriteError.Wrap(err, "could not save back-channel logout delivery")
+		o.logger.Error(ctx, "could not save back-channel logout delivery", zap.Error(err),
+			zap.String("client-id", delivery.ClientID.String()),
+			zap.String("user-id", delivery.UserID.String()))
+		return err
+	}
+
+	return nil
+}
+
 func (o *oauth) GetUserPassword(ctx context.Context, Id uuid.UUID) (string, error) {
 	user, err := o.db.GetUserById(ctx, Id)
 	if err != nil {
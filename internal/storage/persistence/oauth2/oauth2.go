@@ -2,12 +2,14 @@ package oauth2
 
 import (
 	"context"
+	"database/sql"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"sso/internal/constant/errors"
 	"sso/internal/constant/errors/sqlcerr"
 	"sso/internal/constant/model/db"
 	"sso/internal/constant/model/dto"
+	"sso/internal/constant/model/persistencedb"
 	"sso/internal/storage"
 	"sso/platform/logger"
 	"sso/platform/utils"
@@ -15,10 +17,10 @@ import (
 
 type oauth2 struct {
 	logger logger.Logger
-	db     *db.Queries
+	db     *persistencedb.PersistenceDB
 }
 
-func InitOAuth2(logger logger.Logger, db *db.Queries) storage.OAuth2Persistence {
+func InitOAuth2(logger logger.Logger, db *persistencedb.PersistenceDB) storage.OAuth2Persistence {
 	return &oauth2{
 		logger,
 		db,
@@ -61,13 +63,16 @@ func (o *oauth2) AuthHistoryExists(ctx context.Context, code string) (bool, erro
 
 func (o *oauth2) PersistRefreshToken(ctx context.Context, param dto.RefreshToken) (*dto.RefreshToken, error) {
 	refToken, err := o.db.SaveRefreshToken(ctx, db.SaveRefreshTokenParams{
-		ExpiresAt:    param.ExpiresAt,
-		UserID:       param.UserID,
-		ClientID:     param.ClientID,
-		Scope:        utils.StringOrNull(param.Scope),
-		RedirectUri:  utils.StringOrNull(param.RedirectUri),
-		RefreshToken: param.RefreshToken,
-		Code:         param.Code,
+		ExpiresAt:      param.ExpiresAt,
+		UserID:         param.UserID,
+		ClientID:       param.ClientID,
+		Scope:          utils.StringOrNull(param.Scope),
+		RedirectUri:    utils.StringOrNull(param.RedirectUri),
+		RefreshToken:   param.RefreshToken,
+		Code:           param.Code,
+		RequestedScope: utils.StringOrNull(param.RequestedScope),
+		AuthTime:       sql.NullTime{Time: param.Authentication.Time, Valid: !param.Authentication.Time.IsZero()},
+		Amr:            utils.StringOrNull(utils.ArrayToString(param.Authentication.Methods)),
 	})
 	if err != nil {
 		Err := errors.ErrWriteError.Wrap(err, "unable to persist the refresh token")
@@ -75,19 +80,30 @@ func (o *oauth2) PersistRefreshToken(ctx context.Context, param dto.RefreshToken
 		return nil, Err
 	}
 	return &dto.RefreshToken{
-		Code:         refToken.Code,
-		RefreshToken: refToken.RefreshToken,
-		RedirectUri:  refToken.RedirectUri.String,
-		Scope:        refToken.Scope.String,
-		UserID:       refToken.UserID,
-		ID:           refToken.ID,
-		ClientID:     refToken.ClientID,
+		Code:           refToken.Code,
+		RefreshToken:   refToken.RefreshToken,
+		RedirectUri:    refToken.RedirectUri.String,
+		Scope:          refToken.Scope.String,
+		RequestedScope: refToken.RequestedScope.String,
+		UserID:         refToken.UserID,
+		ID:             refToken.ID,
+		ClientID:       refToken.ClientID,
+		Authentication: refreshTokenAuthentication(refToken),
 	}, nil
 }
 
+// refreshTokenAuthentication returns the authentication of the user the refresh token was granted on.
+func refreshTokenAuthentication(refreshToken db.RefreshToken) dto.Authentication {
+	authentication := dto.Authentication{Time: refreshToken.AuthTime.Time}
+	if refreshToken.Amr.Valid {
+		authentication.Methods = utils.StringToArray(refreshToken.Amr.String)
+	}
+	return authentication
+}
+
 func (o *oauth2) AddAuthHistory(ctx context.Context, param dto.AuthHistory) (*dto.AuthHistory, error) {
 	authHist, err := o.db.CreateAuthHistory(ctx, db.CreateAuthHistoryParams{
-		UserID:      param.UserID,
+		UserID:      uuid.NullUUID{UUID: param.UserID, Valid: param.UserID != uuid.Nil},
 		ClientID:    param.ClientID,
 		Scope:       utils.StringOrNull(param.Scope),
 		RedirectUri: utils.StringOrNull(param.RedirectUri),
@@ -101,7 +117,7 @@ func (o *oauth2) AddAuthHistory(ctx context.Context, param dto.AuthHistory) (*dt
 	}
 	return &dto.AuthHistory{
 		ID:          authHist.ID,
-		UserID:      authHist.UserID,
+		UserID:      authHist.UserID.UUID,
 		ClientID:    authHist.ClientID,
 		RedirectUri: authHist.RedirectUri.String,
 		Scope:       authHist.Scope.String,
@@ -127,6 +143,18 @@ func (o *oauth2) RemoveRefreshToken(ctx context.Context, refresh_token string) e
 	return nil
 }
 
+func (o *oauth2) RemoveRefreshTokensOfClientByUserID(ctx context.Context, userID, clientID uuid.UUID) error {
+	if err := o.db.RemoveRefreshTokensByUserIDAndClientID(ctx, db.RemoveRefreshTokensByUserIDAndClientIDParams{
+		UserID:   userID,
+		ClientID: clientID,
+	}); err != nil {
+		err := errors.ErrDBDelError.Wrap(err, "could not delete the refresh tokens of the client")
+		o.logger.Error(ctx, "unable to delete the refresh tokens of the client", zap.Error(err), zap.Any("user-id", userID), zap.Any("client-id", clientID))
+		return err
+	}
+	return nil
+}
+
 func (o *oauth2) CheckIfUserGrantedClient(ctx context.Context, userID uuid.UUID, clientID uuid.UUID) (bool, dto.RefreshToken, error) {
 	refereshToken, err := o.db.GetRefreshTokenByUserIDAndClientID(ctx, db.GetRefreshTokenByUserIDAndClientIDParams{
 		UserID:   userID,
@@ -165,14 +193,18 @@ func (o *oauth2) GetRefreshToken(ctx context.Context, token string) (*dto.Refres
 		return nil, err
 	}
 	return &dto.RefreshToken{
-		ID:           refreshToken.ID,
-		Code:         refreshToken.Code,
-		RefreshToken: refreshToken.RefreshToken,
-		RedirectUri:  refreshToken.RedirectUri.String,
-		Scope:        refreshToken.Scope.String,
-		UserID:       refreshToken.UserID,
-		ClientID:     refreshToken.ClientID,
-		ExpiresAt:    refreshToken.ExpiresAt,
+		ID:             refreshToken.ID,
+		Code:           refreshToken.Code,
+		RefreshToken:   refreshToken.RefreshToken,
+		RedirectUri:    refreshToken.RedirectUri.String,
+		Scope:          refreshToken.Scope.String,
+		RequestedScope: refreshToken.RequestedScope.String,
+		UserID:         refreshToken.UserID,
+		ClientID:       refreshToken.ClientID,
+		ExpiresAt:      refreshToken.ExpiresAt,
+		CreatedAt:      refreshToken.CreatedAt,
+		UpdatedAt:      refreshToken.UpdatedAt,
+		Authentication: refreshTokenAuthentication(refreshToken),
 	}, nil
 }
 
@@ -192,14 +224,18 @@ func (o *oauth2) GetRefreshTokenOfClientByUserID(ctx context.Context, userID, cl
 		return nil, err
 	}
 	return &dto.RefreshToken{
-		ID:           refreshToken.ID,
-		Code:         refreshToken.Code,
-		RefreshToken: refreshToken.RefreshToken,
-		RedirectUri:  refreshToken.RedirectUri.String,
-		Scope:        refreshToken.Scope.String,
-		UserID:       refreshToken.UserID,
-		ClientID:     refreshToken.ClientID,
-		ExpiresAt:    refreshToken.ExpiresAt,
+		ID:             refreshToken.ID,
+		Code:           refreshToken.Code,
+		RefreshToken:   refreshToken.RefreshToken,
+		RedirectUri:    refreshToken.RedirectUri.String,
+		Scope:          refreshToken.Scope.String,
+		RequestedScope: refreshToken.RequestedScope.String,
+		UserID:         refreshToken.UserID,
+		ClientID:       refreshToken.ClientID,
+		ExpiresAt:      refreshToken.ExpiresAt,
+		CreatedAt:      refreshToken.CreatedAt,
+		UpdatedAt:      refreshToken.UpdatedAt,
+		Authentication: refreshTokenAuthentication(refreshToken),
 	}, nil
 }
 
@@ -218,28 +254,16 @@ func (o *oauth2) GetAuthorizedClients(ctx context.Context, userID uuid.UUID) ([]
 	}
 	authorizedClientsDTO := make([]dto.AuthorizedClientsResponse, len(authorizedClients))
 	for k, v := range authorizedClients {
-		var scopes []dto.Scope
-		for _, s := range utils.StringToArray(v.Scope.String) {
-			if s == "openid" {
-				continue
-			}
-			scope, err := o.db.GetScope(ctx, s)
-			if err != nil {
-				if sqlcerr.Is(err, sqlcerr.ErrNoRows) {
-					err := errors.ErrNoRecordFound.Wrap(err, "scope doesn't exist")
-					o.logger.Error(ctx, "scope row for given scope was not found", zap.Error(err), zap.Any("user-id", userID), zap.String("scope", s))
-					continue
-				} else {
-					err = errors.ErrReadError.Wrap(err, "error reading scope")
-					o.logger.Error(ctx, "error encountered while reading scope for authorized client", zap.Error(err), zap.Any("user-id", userID), zap.String("scope", s))
-					return nil, err
-				}
+		scopes, err := o.authorizedScopes(ctx, userID, v.Scope.String)
+		if err != nil {
+			return nil, err
+		}
+		// authorizations granted before the requested scope was kept were granted every scope they requested.
+		requestedScopes := scopes
+		if v.RequestedScope.Valid {
+			if requestedScopes, err = o.authorizedScopes(ctx, userID, v.RequestedScope.String); err != nil {
+				return nil, err
 			}
-			scopes = append(scopes, dto.Scope{
-				Name:               scope.Name,
-				Description:        scope.Description,
-				ResourceServerName: scope.ResourceServerName.String,
-			})
 		}
 		authorizedClientsDTO[k] = dto.AuthorizedClientsResponse{
 			Client: dto.Client{
@@ -248,15 +272,44 @@ func (o *oauth2) GetAuthorizedClients(ctx context.Context, userID uuid.UUID) ([]
 				ClientType: v.ClientType,
 				LogoURL:    v.LogoUrl,
 			},
-			AuthGivenAt:   v.CreatedAt,
-			AuthUpdatedAt: v.UpdatedAt,
-			AuthExpiresAt: v.ExpiresAt,
-			AuthScopes:    scopes,
+			AuthGivenAt:     v.CreatedAt,
+			AuthUpdatedAt:   v.UpdatedAt,
+			AuthExpiresAt:   v.ExpiresAt,
+			AuthScopes:      scopes,
+			RequestedScopes: requestedScopes,
 		}
 	}
 	return authorizedClientsDTO, nil
 }
 
+// authorizedScopes returns the scopes of an authorization of the user, skipping openid and the scopes that no longer exist.
+func (o *oauth2) authorizedScopes(ctx context.Context, userID uuid.UUID, authorizationScope string) ([]dto.Scope, error) {
+	var scopes []dto.Scope
+	for _, s := range utils.StringToArray(authorizationScope) {
+		if s == "openid" {
+			continue
+		}
+		scope, err := o.db.GetScope(ctx, s)
+		if err != nil {
+			if sqlcerr.Is(err, sqlcerr.ErrNoRows) {
+				err := errors.ErrNoRecordFound.Wrap(err, "scope doesn't exist")
+				o.logger.Error(ctx, "scope row for given scope was not found", zap.Error(err), zap.Any("user-id", userID), zap.String("scope", s))
+				continue
+			} else {
+				err = errors.ErrReadError.Wrap(err, "error reading scope")
+				o.logger.Error(ctx, "error encountered while reading scope for authorized client", zap.Error(err), zap.Any("user-id", userID), zap.String("scope", s))
+				return nil, err
+			}
+		}
+		scopes = append(scopes, dto.Scope{
+			Name:               scope.Name,
+			Description:        scope.Description,
+			ResourceServerName: scope.ResourceServerName.String,
+		})
+	}
+	return scopes, nil
+}
+
 func (o *oauth2) GetOpenIDAuthorizedClients(ctx context.Context, userID uuid.UUID) ([]dto.AuthorizedClientsResponse, error) {
 	authorizedClients, err := o.db.GetOpenIDAuthorizedClientsForUser(ctx, userID)
 	if err != nil {
@@ -312,29 +365,72 @@ func (o *oauth2) UserInfo(ctx context.Context, userID uuid.UUID) (*dto.UserInfo,
 	}, nil
 }
 
-func (o *oauth2) UpdateRefreshToken(ctx context.Context, newRefreshToken, oldRefreshToken string) (*dto.RefreshToken, error) {
-	refreshToken, err := o.db.UpdateOAuthRefreshToken(ctx, db.UpdateOAuthRefreshTokenParams{
-		RefreshToken:   newRefreshToken,
-		RefreshToken_2: oldRefreshToken,
-	})
+func (o *oauth2) RotateRefreshToken(ctx context.Context, newRefreshToken, oldRefreshToken string) (*dto.RefreshToken, error) {
+	refreshToken, err := o.db.RotateRefreshTokenTX(ctx, newRefreshToken, oldRefreshToken)
+	if err != nil {
+		if sqlcerr.Is(err, sqlcerr.ErrNoRows) {
+			err := errors.ErrNoRecordFound.Wrap(err, "no refresh token found")
+			o.logger.Info(ctx, "refresh token was rotated by another request", zap.Error(err))
+			return nil, err
+		}
+		err := errors.ErrUpdateError.Wrap(err, "error rotating refresh token")
+		o.logger.Error(ctx, "error while rotating refresh token for a client access token grant", zap.Error(err))
+		return nil, err
+	}
+
+	return &dto.RefreshToken{
+		ID:             refreshToken.ID,
+		RefreshToken:   refreshToken.RefreshToken,
+		Code:           refreshToken.Code,
+		UserID:         refreshToken.UserID,
+		ClientID:       refreshToken.ClientID,
+		Scope:          refreshToken.Scope.String,
+		RequestedScope: refreshToken.RequestedScope.String,
+		RedirectUri:    refreshToken.RedirectUri.String,
+		ExpiresAt:      refreshToken.ExpiresAt,
+		CreatedAt:      refreshToken.CreatedAt,
+		UpdatedAt:      refreshToken.UpdatedAt,
+		Authentication: refreshTokenAuthentication(refreshToken),
+	}, nil
+}
 
+func (o *oauth2) GetRefreshTokenFamily(ctx context.Context, supersededRefreshToken string) (*dto.RefreshToken, error) {
+	refreshToken, err := o.db.GetRefreshTokenBySupersededToken(ctx, supersededRefreshToken)
 	if err != nil {
-		err := errors.ErrUpdateError.Wrap(err, "error updating refresh token")
-		o.logger.Error(ctx, "error while updating refresh token for a client access token grant",
-			zap.Any("old-refresh-token", oldRefreshToken),
-			zap.Any("new-refresh-token", newRefreshToken))
+		if sqlcerr.Is(err, sqlcerr.ErrNoRows) {
+			err := errors.ErrNoRecordFound.Wrap(err, "no refresh token family found")
+			o.logger.Info(ctx, "refresh token family not found", zap.Error(err))
+			return nil, err
+		}
+		err = errors.ErrReadError.Wrap(err, "could not read refresh token family")
+		o.logger.Error(ctx, "could not read refresh token family", zap.Error(err))
 		return nil, err
 	}
 
 	return &dto.RefreshToken{
-		ID:           refreshToken.ID,
-		RefreshToken: refreshToken.RefreshToken,
-		Code:         refreshToken.Code,
-		UserID:       refreshToken.UserID,
-		ClientID:     refreshToken.ClientID,
-		Scope:        refreshToken.Scope.String,
-		RedirectUri:  refreshToken.RedirectUri.String,
-		ExpiresAt:    refreshToken.ExpiresAt,
-		CreatedAt:    refreshToken.CreatedAt,
+		ID:             refreshToken.ID,
+		Code:           refreshToken.Code,
+		RefreshToken:   refreshToken.RefreshToken,
+		RedirectUri:    refreshToken.RedirectUri.String,
+		Scope:          refreshToken.Scope.String,
+		RequestedScope: refreshToken.RequestedScope.String,
+		UserID:         refreshToken.UserID,
+		ClientID:       refreshToken.ClientID,
+		ExpiresAt:      refreshToken.ExpiresAt,
+		CreatedAt:      refreshToken.CreatedAt,
+		UpdatedAt:      refreshToken.UpdatedAt,
+		Authentication: refreshTokenAuthentication(refreshToken),
 	}, nil
 }
+
+func (o *oauth2) RevokeRefreshTokenFamily(ctx context.Context, family dto.RefreshToken) error {
+	if err := o.db.RevokeRefreshTokenFamilyTX(ctx, family); err != nil {
+		err := errors.ErrDBDelError.Wrap(err, "could not revoke refresh token family")
+		o.logger.Error(ctx, "unable to revoke refresh token family", zap.Error(err),
+			zap.String("family-id", family.ID.String()),
+			zap.String("client-id", family.ClientID.String()))
+		return err
+	}
+
+	return nil
+}
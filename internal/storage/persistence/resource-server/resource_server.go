@@ -2,10 +2,13 @@ package resource_server
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"sso/internal/constant/errors"
 	"sso/internal/constant/errors/sqlcerr"
 	"sso/internal/constant/model"
+	"sso/internal/constant/model/db"
 	"sso/internal/constant/model/dto"
 	"sso/internal/constant/model/persistencedb"
 	"sso/internal/storage"
@@ -95,10 +98,64 @@ func (r *resourceServerPersistence) GetResourceServerByID(ctx context.Context, r
 	}
 
 	return &dto.ResourceServer{
-		ID:        rs.ID,
-		Name:      rs.Name,
-		CreatedAt: rs.CreatedAt,
-		UpdatedAt: rs.UpdatedAt,
-		Secret:    rs.Secret,
+		ID:                      rs.ID,
+		Name:                    rs.Name,
+		CreatedAt:               rs.CreatedAt,
+		UpdatedAt:               rs.UpdatedAt,
+		Secret:                  rs.Secret,
+		PreviousSecret:          rs.PreviousSecret,
+		PreviousSecretExpiresAt: rs.PreviousSecretExpiresAt.Time,
 	}, nil
 }
+
+func (r *resourceServerPersistence) RotateResourceServerSecret(ctx context.Context, rsID uuid.UUID, secretHash string, previousSecretExpiresAt time.Time) error {
+	_, err := r.db.RotateResourceServerSecret(ctx, db.RotateResourceServerSecretParams{
+		ID:                      rsID,
+		PreviousSecretExpiresAt: sql.NullTime{Time: previousSecretExpiresAt, Valid: true},
+		Secret:                  secretHash,
+	})
+	if err != nil {
+		if sqlcerr.Is(err, sqlcerr.ErrNoRows) {
+			err = errors.ErrNoRecordFound.Wrap(err, "resource server not found")
+			r.logger.Info(ctx, "resource server was not found", zap.Error(err), zap.String("rs-id", rsID.String()))
+			return err
+		}
+		err = errors.ErrUpdateError.Wrap(err, "could not rotate resource server secret")
+		r.logger.Error(ctx, "unable to rotate resource server secret", zap.Error(err), zap.String("rs-id", rsID.String()))
+		return err
+	}
+
+	return nil
+}
+
+func (r *resourceServerPersistence) SetAuthorizationDetailTypes(ctx context.Context, rsID uuid.UUID, types []dto.AuthorizationDetailType) ([]dto.AuthorizationDetailType, error) {
+	detailTypes, err := r.db.SetAuthorizationDetailTypesWithTX(ctx, rsID, types)
+	if err != nil {
+		err = errors.ErrWriteError.Wrap(err, "could not register authorization detail types")
+		r.logger.Error(ctx, "unable to register authorization detail types", zap.Error(err), zap.String("rs-id", rsID.String()), zap.Any("types", types))
+		return nil, err
+	}
+
+	return detailTypes, nil
+}
+
+func (r *resourceServerPersistence) GetAuthorizationDetailTypes(ctx context.Context, types []string) ([]dto.AuthorizationDetailType, error) {
+	detailTypes, err := r.db.GetAuthorizationDetailTypes(ctx, types)
+	if err != nil {
+		err = errors.ErrReadError.Wrap(err, "could not read authorization detail types")
+		r.logger.Error(ctx, "unable to read authorization detail types", zap.Error(err), zap.Strings("types", types))
+		return nil, err
+	}
+
+	result := make([]dto.AuthorizationDetailType, 0, len(detailTypes))
+	for _, detailType := range detailTypes {
+		result = append(result, dto.AuthorizationDetailType{
+			ID:                 detailType.ID,
+			Type:               detailType.Type,
+			Description:        detailType.Description,
+			ResourceServerName: detailType.ResourceServerName,
+			CreatedAt:          detailType.CreatedAt,
+		})
+	}
+	return result, nil
+}
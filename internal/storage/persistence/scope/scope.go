@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 
+	"sso/internal/constant"
 	"sso/internal/constant/errors"
 	"sso/internal/constant/errors/sqlcerr"
 	"sso/internal/constant/model"
@@ -162,3 +163,38 @@ func (s *scopePersistence) UpdateScope(ctx context.Context, scopeUpdateParam dto
 	}
 	return nil
 }
+
+func (s *scopePersistence) GetScopeNamesByStatus(ctx context.Context, status string) ([]string, error) {
+	names, err := s.db.GetScopeNamesByStatus(ctx, status)
+	if err != nil {
+		err = errors.ErrReadError.Wrap(err, "error reading scope names")
+		s.logger.Error(ctx, "error reading scope names", zap.Error(err), zap.String("status", status))
+		return nil, err
+	}
+
+	return names, nil
+}
+
+func (s *scopePersistence) GetScopesByResourceServerName(ctx context.Context, name string) ([]dto.Scope, error) {
+	scopes, err := s.db.GetScopesByResourceServerName(ctx, sql.NullString{String: name, Valid: true})
+	if err != nil {
+		err = errors.ErrReadError.Wrap(err, "error reading resource server scopes")
+		s.logger.Error(ctx, "error reading the scopes of the resource server", zap.Error(err), zap.String("resource-server", name))
+		return nil, err
+	}
+
+	dtoScopes := make([]dto.Scope, 0, len(scopes))
+	for _, scope := range scopes {
+		if scope.Status != constant.Active {
+			continue
+		}
+		dtoScopes = append(dtoScopes, dto.Scope{
+			Name:               scope.Name,
+			Description:        scope.Description,
+			ResourceServerName: scope.ResourceServerName.String,
+			CreatedAt:          scope.CreatedAt,
+		})
+	}
+
+	return dtoScopes, nil
+}
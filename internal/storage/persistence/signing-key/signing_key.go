@@ -0,0 +1,122 @@
+package signing_key
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"sso/internal/constant/errors"
+	"sso/internal/constant/errors/sqlcerr"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/internal/constant/model/persistencedb"
+	"sso/internal/storage"
+	"sso/platform/logger"
+
+	"go.uber.org/zap"
+)
+
+type signingKeyPersistence struct {
+	logger logger.Logger
+	db     *persistencedb.PersistenceDB
+}
+
+func InitSigningKeyPersistence(logger logger.Logger, db *persistencedb.PersistenceDB) storage.SigningKeyPersistence {
+	return &signingKeyPersistence{
+		logger: logger,
+		db:     db,
+	}
+}
+
+func (s *signingKeyPersistence) CreateSigningKey(ctx context.Context, key dto.SigningKey) (dto.SigningKey, error) {
+	createdKey, err := s.db.CreateSigningKey(ctx, db.CreateSigningKeyParams{
+		Kid:         key.Kid,
+		Algorithm:   key.Algorithm,
+		PrivateKey:  key.PrivateKey,
+		PublicKey:   key.PublicKey,
+		ActivatesAt: key.ActivatesAt,
+	})
+	if err != nil {
+		if sqlcerr.IsUniqueViolation(err) {
+			err := errors.ErrDataExists.Wrap(err, "signing key already exists")
+			s.logger.Info(ctx, "signing key already exists", zap.Error(err), zap.String("kid", key.Kid))
+			return dto.SigningKey{}, err
+		}
+		err := errors.ErrWriteError.Wrap(err, "could not create signing key")
+		s.logger.Error(ctx, "unable to create signing key", zap.Error(err), zap.String("kid", key.Kid))
+		return dto.SigningKey{}, err
+	}
+
+	return toSigningKeyDTO(createdKey), nil
+}
+
+func (s *signingKeyPersistence) GetAllSigningKeys(ctx context.Context) ([]dto.SigningKey, error) {
+	keys, err := s.db.GetAllSigningKeys(ctx)
+	if err != nil {
+		err := errors.ErrReadError.Wrap(err, "could not read signing keys")
+		s.logger.Error(ctx, "unable to read signing keys", zap.Error(err))
+		return nil, err
+	}
+
+	keysDTO := make([]dto.SigningKey, 0, len(keys))
+	for _, key := range keys {
+		keysDTO = append(keysDTO, toSigningKeyDTO(key))
+	}
+
+	return keysDTO, nil
+}
+
+func (s *signingKeyPersistence) GetSigningKeyByKid(ctx context.Context, kid string) (dto.SigningKey, error) {
+	key, err := s.db.GetSigningKeyByKid(ctx, kid)
+	if err != nil {
+		if sqlcerr.Is(err, sqlcerr.ErrNoRows) {
+			err := errors.ErrNoRecordFound.Wrap(err, "signing key not found")
+			s.logger.Info(ctx, "signing key not found", zap.Error(err), zap.String("kid", kid))
+			return dto.SigningKey{}, err
+		}
+		err = errors.ErrReadError.Wrap(err, "could not read signing key")
+		s.logger.Error(ctx, "unable to read signing key", zap.Error(err), zap.String("kid", kid))
+		return dto.SigningKey{}, err
+	}
+
+	return toSigningKeyDTO(key), nil
+}
+
+func (s *signingKeyPersistence) RetireSigningKey(ctx context.Context, kid string, retiresAt time.Time) (dto.SigningKey, error) {
+	key, err := s.db.RetireSigningKey(ctx, db.RetireSigningKeyParams{
+		Kid: kid,
+		RetiresAt: sql.NullTime{
+			Time:  retiresAt,
+			Valid: true,
+		},
+	})
+	if err != nil {
+		if sqlcerr.Is(err, sqlcerr.ErrNoRows) {
+			err := errors.ErrNoRecordFound.Wrap(err, "signing key not found")
+			s.logger.Info(ctx, "signing key not found", zap.Error(err), zap.String("kid", kid))
+			return dto.SigningKey{}, err
+		}
+		err = errors.ErrUpdateError.Wrap(err, "could not retire signing key")
+		s.logger.Error(ctx, "unable to retire signing key", zap.Error(err), zap.String("kid", kid))
+		return dto.SigningKey{}, err
+	}
+
+	return toSigningKeyDTO(key), nil
+}
+
+func toSigningKeyDTO(key db.SigningKey) dto.SigningKey {
+	signingKey := dto.SigningKey{
+		ID:          key.ID,
+		Kid:         key.Kid,
+		Algorithm:   key.Algorithm,
+		PrivateKey:  key.PrivateKey,
+		PublicKey:   key.PublicKey,
+		ActivatesAt: key.ActivatesAt,
+		CreatedAt:   key.CreatedAt,
+	}
+	if key.RetiresAt.Valid {
+		signingKey.RetiresAt = &key.RetiresAt.Time
+	}
+
+	return signingKey
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"sso/internal/constant/model"
 	"sso/internal/constant/model/dto"
@@ -21,10 +22,14 @@ type OAuthPersistence interface {
 	GetUserByPhoneOrEmail(ctx context.Context, query string) (*dto.User, error)
 	GetUserByID(ctx context.Context, Id uuid.UUID) (*dto.User, error)
 	RemoveInternalRefreshToken(ctx context.Context, refreshToken string) error
-	SaveInternalRefreshToken(ctx context.Context, rf dto.InternalRefreshToken) error
+	SaveInternalRefreshToken(ctx context.Context, rf dto.InternalRefreshToken) (*dto.InternalRefreshToken, error)
 	GetInternalRefreshToken(ctx context.Context, refreshtoken string) (*dto.InternalRefreshToken, error)
-	UpdateInternalRefreshToken(ctx context.Context, oldToken, newToken string) (*dto.InternalRefreshToken, error)
+	RotateInternalRefreshToken(ctx context.Context, oldToken, newToken string) (*dto.InternalRefreshToken, error)
+	GetInternalRefreshTokenFamily(ctx context.Context, supersededToken string) (*dto.InternalRefreshToken, error)
+	RevokeInternalRefreshTokenFamily(ctx context.Context, family dto.InternalRefreshToken) error
 	GetInternalRefreshTokensByUserID(ctx context.Context, userID uuid.UUID) ([]dto.InternalRefreshToken, error)
+	GetLogoutClients(ctx context.Context, userID uuid.UUID) ([]dto.Client, error)
+	SaveBackChannelLogoutDelivery(ctx context.Context, delivery dto.BackChannelLogoutDelivery) error
 	GetUserPassword(ctx context.Context, Id uuid.UUID) (string, error)
 	GetAllIdentityProviders(ctx context.Context) ([]dto.IdentityProvider, error)
 	ChangeUserPassword(ctx context.Context, phone, newPassword string) error
@@ -49,6 +54,7 @@ type OAuth2Persistence interface {
 	PersistRefreshToken(ctx context.Context, param dto.RefreshToken) (*dto.RefreshToken, error)
 	RemoveRefreshTokenCode(ctx context.Context, code string) error
 	RemoveRefreshToken(ctx context.Context, refresh_token string) error
+	RemoveRefreshTokensOfClientByUserID(ctx context.Context, userID, clientID uuid.UUID) error
 	AddAuthHistory(ctx context.Context, param dto.AuthHistory) (*dto.AuthHistory, error)
 	CheckIfUserGrantedClient(ctx context.Context, userID uuid.UUID, clientID uuid.UUID) (bool, dto.RefreshToken, error)
 	GetRefreshToken(ctx context.Context, token string) (*dto.RefreshToken, error)
@@ -56,7 +62,9 @@ type OAuth2Persistence interface {
 	GetAuthorizedClients(ctx context.Context, userID uuid.UUID) ([]dto.AuthorizedClientsResponse, error)
 	GetOpenIDAuthorizedClients(ctx context.Context, userID uuid.UUID) ([]dto.AuthorizedClientsResponse, error)
 	UserInfo(ctx context.Context, userID uuid.UUID) (*dto.UserInfo, error)
-	UpdateRefreshToken(ctx context.Context, newRefreshToken, oldRefreshToken string) (*dto.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, newRefreshToken, oldRefreshToken string) (*dto.RefreshToken, error)
+	GetRefreshTokenFamily(ctx context.Context, supersededRefreshToken string) (*dto.RefreshToken, error)
+	RevokeRefreshTokenFamily(ctx context.Context, family dto.RefreshToken) error
 }
 
 type ConsentCache interface {
@@ -72,6 +80,11 @@ type ClientPersistence interface {
 	GetAllClients(ctx context.Context, filters db_pgnflt.FilterParams) ([]dto.Client, *model.MetaData, error)
 	UpdateClientStatus(ctx context.Context, updateClientStatusParam dto.UpdateClientStatus, clientID uuid.UUID) error
 	UpdateClient(ctx context.Context, client dto.Client) error
+	RotateClientSecret(ctx context.Context, clientID uuid.UUID, secretHash string, previousSecretExpiresAt time.Time) error
+	CreateInitialAccessToken(ctx context.Context, token dto.InitialAccessToken) (*dto.InitialAccessToken, error)
+	GetInitialAccessToken(ctx context.Context, tokenHash string) (*dto.InitialAccessToken, error)
+	SaveRegistrationAccessToken(ctx context.Context, clientID uuid.UUID, tokenHash string) error
+	GetRegistrationAccessTokenHash(ctx context.Context, clientID uuid.UUID) (string, error)
 }
 
 type AuthCodeCache interface {
@@ -80,6 +93,29 @@ type AuthCodeCache interface {
 	DeleteAuthCode(ctx context.Context, code string) error
 }
 
+type DeviceCache interface {
+	SaveDeviceAuthorization(ctx context.Context, deviceAuthorization dto.DeviceAuthorization) error
+	GetDeviceAuthorization(ctx context.Context, deviceCode string) (dto.DeviceAuthorization, error)
+	GetDeviceAuthorizationByUserCode(ctx context.Context, userCode string) (dto.DeviceAuthorization, error)
+	RecordDevicePoll(ctx context.Context, deviceCode string, polledAt time.Time, interval int) error
+	CompleteDeviceAuthorization(ctx context.Context, deviceAuthorization dto.DeviceAuthorization) error
+	ClaimDeviceAuthorization(ctx context.Context, deviceAuthorization dto.DeviceAuthorization) error
+	DeleteDeviceAuthorization(ctx context.Context, deviceAuthorization dto.DeviceAuthorization) error
+}
+
+type RevokedTokenCache interface {
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+type ClientAssertionCache interface {
+	UseClientAssertion(ctx context.Context, clientID, jti string, expiresAt time.Time) (bool, error)
+}
+
+type RequestObjectCache interface {
+	UseRequestObject(ctx context.Context, clientID, jti string, expiresAt time.Time) (bool, error)
+}
+
 type ResetCodeCache interface {
 	SaveResetCode(ctx context.Context, email, code string) error
 	GetResetCode(ctx context.Context, email string) (string, error)
@@ -94,6 +130,8 @@ type ScopePersistence interface {
 	GetAllScopes(ctx context.Context, filters db_pgnflt.FilterParams) ([]dto.Scope, *model.MetaData, error)
 	DeleteScopeByName(ctx context.Context, name string) error
 	UpdateScope(ctx context.Context, scopeUpdateParam dto.Scope) error
+	GetScopeNamesByStatus(ctx context.Context, status string) ([]string, error)
+	GetScopesByResourceServerName(ctx context.Context, name string) ([]dto.Scope, error)
 }
 
 type UserPersistence interface {
@@ -123,6 +161,9 @@ type ResourceServerPersistence interface {
 	GetResourceServerByName(ctx context.Context, name string) (dto.ResourceServer, error)
 	GetAllResourceServers(ctx context.Context, filters db_pgnflt.FilterParams) ([]dto.ResourceServer, *model.MetaData, error)
 	GetResourceServerByID(ctx context.Context, rsID uuid.UUID) (*dto.ResourceServer, error)
+	RotateResourceServerSecret(ctx context.Context, rsID uuid.UUID, secretHash string, previousSecretExpiresAt time.Time) error
+	SetAuthorizationDetailTypes(ctx context.Context, rsID uuid.UUID, types []dto.AuthorizationDetailType) ([]dto.AuthorizationDetailType, error)
+	GetAuthorizationDetailTypes(ctx context.Context, types []string) ([]dto.AuthorizationDetailType, error)
 }
 
 type MiniRidePersistence interface {
@@ -156,3 +197,10 @@ type IdentityProviderPersistence interface {
 	DeleteIdentityProvider(ctx context.Context, idPID uuid.UUID) error
 	GetAllIdentityProviders(ctx context.Context, filters db_pgnflt.FilterParams) ([]dto.IdentityProvider, *model.MetaData, error)
 }
+
+type SigningKeyPersistence interface {
+	CreateSigningKey(ctx context.Context, key dto.SigningKey) (dto.SigningKey, error)
+	GetAllSigningKeys(ctx context.Context) ([]dto.SigningKey, error)
+	GetSigningKeyByKid(ctx context.Context, kid string) (dto.SigningKey, error)
+	RetireSigningKey(ctx context.Context, kid string, retiresAt time.Time) (dto.SigningKey, error)
+}
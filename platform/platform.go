@@ -6,8 +6,6 @@ import (
 	"time"
 
 	"sso/internal/constant/model/dto"
-
-	"github.com/golang-jwt/jwt/v4"
 )
 
 type SMSConfig struct {
@@ -29,12 +27,18 @@ type SMSClient interface {
 }
 
 type Token interface {
-	GenerateAccessToken(ctx context.Context, userID string, expiresAt time.Duration) (string, error)
-	GenerateAccessTokenForClient(ctx context.Context, userID, clientID, scope string, expiresAt time.Duration) (string, error)
+	GenerateAccessToken(ctx context.Context, userID string, authentication dto.Authentication, expiresAt time.Duration) (string, error)
+	GenerateAccessTokenForClient(ctx context.Context, userID, clientID, scope string, options dto.AccessTokenOptions, expiresAt time.Duration) (string, error)
+	GenerateExchangedAccessToken(ctx context.Context, subject, clientID, audience, scope string, actor dto.Actor, expiresAt time.Duration) (string, error)
 	GenerateRefreshToken(ctx context.Context) string
-	GenerateIdToken(ctx context.Context, user *dto.User, clientId string, expiresAt time.Duration) (string, error)
-	VerifyToken(signingMethod jwt.SigningMethod, token string) (bool, *jwt.RegisteredClaims)
-	VerifyIdToken(signingMethod jwt.SigningMethod, token string) (bool, *dto.IDTokenPayload)
+	GenerateIdToken(ctx context.Context, user *dto.User, clientId string, options dto.IDTokenOptions, expiresAt time.Duration) (string, error)
+	GenerateLogoutToken(ctx context.Context, userID, clientID, sessionID, algorithm string, expiresAt time.Duration) (string, error)
+	VerifyToken(token string) (bool, *dto.InternalAccessToken)
+	VerifyIdToken(token string) (bool, *dto.IDTokenPayload)
+	VerifyAccessToken(token string) (bool, *dto.AccessToken)
+	JWKS(ctx context.Context) dto.JWKS
+	SigningAlgorithms() []string
+	SetSigningKeys(ctx context.Context, keys []dto.SigningKey) error
 }
 
 type IdentityProvider interface {
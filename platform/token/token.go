@@ -2,62 +2,285 @@ package token
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	"sort"
+	"sso/internal/constant"
 	"sso/internal/constant/errors"
 	"sso/internal/constant/model/dto"
 	"sso/platform"
 	"sso/platform/logger"
 	"sso/platform/utils"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+type signingKey struct {
+	kid string
+	// algorithm is the only algorithm the key signs and verifies tokens with.
+	algorithm   string
+	privateKey  crypto.Signer
+	publicKey   crypto.PublicKey
+	activatesAt time.Time
+	retiresAt   *time.Time
+}
+
+func (s signingKey) isRetired(at time.Time) bool {
+	return s.retiresAt != nil && !s.retiresAt.After(at)
+}
+
+// supportedAlgorithms is the list of algorithms the key ring can hold keys for.
+var supportedAlgorithms = []string{
+	constant.SigningAlgorithmPS512,
+	constant.SigningAlgorithmRS256,
+	constant.SigningAlgorithmES256,
+	constant.SigningAlgorithmEdDSA,
+}
+
 type Jwt struct {
-	logger     logger.Logger
-	privateKey *rsa.PrivateKey
-	publicKey  *rsa.PublicKey
+	logger logger.Logger
+	// issuer is set as the iss of every token and is required on verification.
+	issuer string
+	// keys is the key ring, sorted by activation time with the latest first.
+	keys  []signingKey
+	mutex sync.RWMutex
 }
 
-func JwtInit(logger logger.Logger, privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey) platform.Token {
+// JwtInit initializes the token platform with the given key pair as the only key of the key ring, used with PS512.
+// The key ring can later be replaced with SetSigningKeys.
+func JwtInit(logger logger.Logger, issuer string, privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey) platform.Token {
 	return &Jwt{
-		logger:     logger,
-		privateKey: privateKey,
-		publicKey:  publicKey,
+		logger: logger,
+		issuer: issuer,
+		keys: []signingKey{
+			{
+				kid:        utils.RSAThumbprint(publicKey),
+				algorithm:  constant.SigningAlgorithmPS512,
+				privateKey: privateKey,
+				publicKey:  publicKey,
+			},
+		},
+	}
+}
+
+func (j *Jwt) SetSigningKeys(ctx context.Context, keys []dto.SigningKey) error {
+	ring := make([]signingKey, 0, len(keys))
+	for _, key := range keys {
+		privateKey, publicKey, err := parseKeyPair(key)
+		if err != nil {
+			err := errors.ErrInternalServerError.Wrap(err, "could not parse signing key")
+			j.logger.Error(ctx, "could not parse signing key", zap.Error(err), zap.String("kid", key.Kid))
+			return err
+		}
+
+		ring = append(ring, signingKey{
+			kid:         key.Kid,
+			algorithm:   key.Algorithm,
+			privateKey:  privateKey,
+			publicKey:   publicKey,
+			activatesAt: key.ActivatesAt,
+			retiresAt:   key.RetiresAt,
+		})
+	}
+	sort.SliceStable(ring, func(i, k int) bool {
+		return ring[i].activatesAt.After(ring[k].activatesAt)
+	})
+
+	j.mutex.Lock()
+	j.keys = ring
+	j.mutex.Unlock()
+
+	return nil
+}
+
+// parseKeyPair parses the pkcs8 private key and pkix public key of a signing key,
+// making sure they are the kind of key the algorithm of the signing key is used with.
+func parseKeyPair(key dto.SigningKey) (crypto.Signer, crypto.PublicKey, error) {
+	privateBlock, _ := pem.Decode([]byte(key.PrivateKey))
+	if privateBlock == nil {
+		return nil, nil, fmt.Errorf("private key is not pem encoded")
+	}
+	parsedPrivateKey, err := x509.ParsePKCS8PrivateKey(privateBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey, ok := parsedPrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("private key can not sign")
+	}
+
+	publicBlock, _ := pem.Decode([]byte(key.PublicKey))
+	if publicBlock == nil {
+		return nil, nil, fmt.Errorf("public key is not pem encoded")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(publicBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !keyFitsAlgorithm(publicKey, key.Algorithm) {
+		return nil, nil, fmt.Errorf("key can not be used with %s", key.Algorithm)
+	}
+
+	return privateKey, publicKey, nil
+}
+
+// keyFitsAlgorithm tells if the public key is of the kind the algorithm signs with.
+func keyFitsAlgorithm(publicKey crypto.PublicKey, algorithm string) bool {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return algorithm == constant.SigningAlgorithmPS512 || algorithm == constant.SigningAlgorithmRS256
+	case *ecdsa.PublicKey:
+		return algorithm == constant.SigningAlgorithmES256 && key.Curve == elliptic.P256()
+	case ed25519.PublicKey:
+		return algorithm == constant.SigningAlgorithmEdDSA
+	default:
+		return false
+	}
+}
+
+// currentKey returns the most recently activated key of the algorithm that is not retired.
+func (j *Jwt) currentKey(algorithm string) (signingKey, bool) {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+
+	now := time.Now()
+	for _, key := range j.keys {
+		if key.algorithm == algorithm && !key.activatesAt.After(now) && !key.isRetired(now) {
+			return key, true
+		}
+	}
+
+	return signingKey{}, false
+}
+
+// verificationKeys returns the keys that are not retired.
+// If kid is given only the key with that kid is returned.
+func (j *Jwt) verificationKeys(kid string) []signingKey {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+
+	now := time.Now()
+	keys := []signingKey{}
+	for _, key := range j.keys {
+		if key.isRetired(now) || (kid != "" && key.kid != kid) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// sign signs the claims with the current key of the algorithm, setting typ as the type of the token.
+func (j *Jwt) sign(claims jwt.Claims, typ, algorithm string) (string, error) {
+	key, ok := j.currentKey(algorithm)
+	if !ok {
+		return "", fmt.Errorf("no active %s signing key", algorithm)
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(algorithm), claims)
+	token.Header["kid"] = key.kid
+	token.Header["typ"] = typ
+
+	return token.SignedString(key.privateKey)
+}
+
+// verifiableClaims is implemented by every claims type embedding jwt.RegisteredClaims.
+type verifiableClaims interface {
+	jwt.Claims
+	VerifyIssuer(cmp string, req bool) bool
+	VerifyAudience(cmp string, req bool) bool
+}
+
+// verify checks the signature of the token against the key its kid refers to,
+// or against every non-retired key if the token has no kid.
+// The alg on the header of the token picks the keys tried, a key is only ever used with its own algorithm.
+// The token must be of the given type and issued by this issuer, and if audience is given, the token must be issued to it.
+func (j *Jwt) verify(token, typ, audience string, claims verifiableClaims) bool {
+	unverified, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return false
+	}
+	if tokenType, _ := unverified.Header["typ"].(string); tokenType != typ {
+		return false
+	}
+	kid, _ := unverified.Header["kid"].(string)
+
+	for _, key := range j.verificationKeys(kid) {
+		if key.algorithm != unverified.Method.Alg() {
+			continue
+		}
+		parser := jwt.NewParser(jwt.WithValidMethods([]string{key.algorithm}))
+		if _, err := parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+			return key.publicKey, nil
+		}); err == nil {
+			return claims.VerifyIssuer(j.issuer, true) && (audience == "" || claims.VerifyAudience(audience, true))
+		}
 	}
+
+	return false
 }
 
-func (j *Jwt) GenerateAccessToken(ctx context.Context, userID string, expiresAt time.Duration) (string, error) {
-	claims := jwt.RegisteredClaims{
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresAt)),
-		Issuer:    "test",
-		NotBefore: jwt.NewNumericDate(time.Now()),
-		Subject:   userID,
+func (j *Jwt) GenerateAccessToken(ctx context.Context, userID string, authentication dto.Authentication, expiresAt time.Duration) (string, error) {
+	claims := dto.InternalAccessToken{
+		AMR: authentication.Methods,
+		SID: authentication.SessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresAt)),
+			Issuer:    j.issuer,
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Subject:   userID,
+			Audience:  jwt.ClaimStrings{j.issuer},
+			ID:        uuid.NewString(),
+		},
+	}
+	if !authentication.Time.IsZero() {
+		claims.AuthTime = jwt.NewNumericDate(authentication.Time)
 	}
 
-	token, err := jwt.NewWithClaims(jwt.SigningMethodPS512, claims).SignedString(j.privateKey)
+	token, err := j.sign(claims, constant.SessionTokenType, constant.SigningAlgorithmPS512)
 	if err != nil {
 		j.logger.Error(ctx, "could not generate access token", zap.Error(err))
 		return "", errors.ErrInternalServerError.Wrap(err, "could not generate access token")
 	}
 	return token, nil
 }
-func (j *Jwt) GenerateAccessTokenForClient(ctx context.Context, userID, clientID, scope string, expiresAt time.Duration) (string, error) {
+
+func (j *Jwt) GenerateAccessTokenForClient(ctx context.Context, userID, clientID, scope string, options dto.AccessTokenOptions, expiresAt time.Duration) (string, error) {
+	audience := jwt.ClaimStrings{clientID}
+	if len(options.Resources) > 0 {
+		audience = options.Resources
+	}
 	claims := dto.AccessToken{
-		Scope: scope,
+		ClientID:             clientID,
+		Scope:                scope,
+		AuthorizationDetails: options.AuthorizationDetails,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresAt)),
-			Issuer:    "sso",
+			Issuer:    j.issuer,
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Subject:   userID,
-			Audience:  jwt.ClaimStrings{clientID},
+			Audience:  audience,
+			ID:        uuid.NewString(),
 		},
 	}
 
-	token, err := jwt.NewWithClaims(jwt.SigningMethodPS512, claims).SignedString(j.privateKey)
+	token, err := j.sign(claims, constant.AccessTokenType, constant.SigningAlgorithmPS512)
 	if err != nil {
 		j.logger.Error(ctx, "could not generate access token", zap.Error(err))
 		return "", errors.ErrInternalServerError.Wrap(err, "could not generate access token")
@@ -65,11 +288,37 @@ func (j *Jwt) GenerateAccessTokenForClient(ctx context.Context, userID, clientID
 	return token, nil
 }
 
+func (j *Jwt) GenerateExchangedAccessToken(ctx context.Context, subject, clientID, audience, scope string, actor dto.Actor, expiresAt time.Duration) (string, error) {
+	claims := dto.AccessToken{
+		ClientID: clientID,
+		Scope:    scope,
+		Actor:    &actor,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresAt)),
+			Issuer:    j.issuer,
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Subject:   subject,
+			Audience:  jwt.ClaimStrings{audience},
+			ID:        uuid.NewString(),
+		},
+	}
+
+	token, err := j.sign(claims, constant.AccessTokenType, constant.SigningAlgorithmPS512)
+	if err != nil {
+		j.logger.Error(ctx, "could not generate exchanged access token", zap.Error(err))
+		return "", errors.ErrInternalServerError.Wrap(err, "could not generate access token")
+	}
+	return token, nil
+}
+
 func (j *Jwt) GenerateRefreshToken(_ context.Context) string {
 	return utils.GenerateRandomString(25, false)
 }
 
-func (j *Jwt) GenerateIdToken(ctx context.Context, user *dto.User, clientId string, expiresAt time.Duration) (string, error) {
+func (j *Jwt) GenerateIdToken(ctx context.Context, user *dto.User, clientId string, options dto.IDTokenOptions, expiresAt time.Duration) (string, error) {
+	if options.SigningAlgorithm == "" {
+		options.SigningAlgorithm = constant.SigningAlgorithmPS512
+	}
 	claims := dto.IDTokenPayload{
 		FirstName:   user.FirstName,
 		MiddleName:  user.MiddleName,
@@ -77,15 +326,29 @@ func (j *Jwt) GenerateIdToken(ctx context.Context, user *dto.User, clientId stri
 		Picture:     user.ProfilePicture,
 		Email:       user.Email,
 		PhoneNumber: user.Phone,
+		Nonce:       options.Nonce,
+		ACR:         options.Authentication.ACR(),
+		AMR:         options.Authentication.Methods,
+		SID:         options.Authentication.SessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    j.issuer,
 			Subject:   user.ID.String(),
 			Audience:  jwt.ClaimStrings{clientId},
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresAt)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
+	if !options.Authentication.Time.IsZero() {
+		claims.AuthTime = jwt.NewNumericDate(options.Authentication.Time)
+	}
+	if options.AccessToken != "" {
+		claims.AccessTokenHash = halfHash(options.AccessToken, options.SigningAlgorithm)
+	}
+	if options.Code != "" {
+		claims.CodeHash = halfHash(options.Code, options.SigningAlgorithm)
+	}
 
-	token, err := jwt.NewWithClaims(jwt.SigningMethodPS512, claims).SignedString(j.privateKey)
+	token, err := j.sign(claims, constant.IDTokenType, options.SigningAlgorithm)
 	if err != nil {
 		j.logger.Error(ctx, "could not generate id token", zap.Error(err))
 		return "", errors.ErrInternalServerError.Wrap(err, "could not generate id token")
@@ -93,51 +356,107 @@ func (j *Jwt) GenerateIdToken(ctx context.Context, user *dto.User, clientId stri
 	return token, nil
 }
 
-func (j *Jwt) VerifyToken(signingMethod jwt.SigningMethod, token string) (bool, *jwt.RegisteredClaims) {
-	claims := &jwt.RegisteredClaims{}
-
-	segments := strings.Split(token, ".")
-	if len(segments) < 3 {
-		return false, claims
+func (j *Jwt) GenerateLogoutToken(ctx context.Context, userID, clientID, sessionID, algorithm string, expiresAt time.Duration) (string, error) {
+	if algorithm == "" {
+		algorithm = constant.SigningAlgorithmPS512
+	}
+	claims := dto.LogoutToken{
+		SID: sessionID,
+		Events: map[string]struct{}{
+			constant.BackChannelLogoutEvent: {},
+		},
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    j.issuer,
+			Subject:   userID,
+			Audience:  jwt.ClaimStrings{clientID},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresAt)),
+			ID:        uuid.NewString(),
+		},
 	}
-	err := signingMethod.Verify(strings.Join(segments[:2], "."), segments[2], j.publicKey)
+
+	token, err := j.sign(claims, constant.LogoutTokenType, algorithm)
 	if err != nil {
-		return false, claims
+		j.logger.Error(ctx, "could not generate logout token", zap.Error(err))
+		return "", errors.ErrInternalServerError.Wrap(err, "could not generate logout token")
 	}
+	return token, nil
+}
 
-	if _, err = jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodRSAPSS); !ok {
-			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
-		}
-		return j.publicKey, nil
-	}); err != nil {
-		return false, claims
+// halfHash returns the base64url encoded left half of the hash of the value,
+// hashed with the hash function of the signing algorithm, as at_hash and c_hash are.
+// EdDSA has no hash function of its own, SHA-512 is used with it as it is with Ed25519.
+func halfHash(value, algorithm string) string {
+	var hash []byte
+	switch algorithm {
+	case constant.SigningAlgorithmRS256, constant.SigningAlgorithmES256:
+		sum := sha256.Sum256([]byte(value))
+		hash = sum[:]
+	default:
+		sum := sha512.Sum512([]byte(value))
+		hash = sum[:]
 	}
-	return true, claims
+	return base64.RawURLEncoding.EncodeToString(hash[:len(hash)/2])
+}
+
+// VerifyToken verifies a session token of the sso, the audience of which is the sso itself.
+func (j *Jwt) VerifyToken(token string) (bool, *dto.InternalAccessToken) {
+	claims := &dto.InternalAccessToken{}
+	return j.verify(token, constant.SessionTokenType, j.issuer, claims), claims
 }
 
-func (j *Jwt) VerifyIdToken(signingMethod jwt.SigningMethod, token string) (bool, *dto.IDTokenPayload) {
+func (j *Jwt) VerifyIdToken(token string) (bool, *dto.IDTokenPayload) {
 	claims := &dto.IDTokenPayload{}
+	return j.verify(token, constant.IDTokenType, "", claims), claims
+}
 
-	segments := strings.Split(token, ".")
-	if len(segments) < 3 {
-		return false, claims
-	}
+// VerifyAccessToken verifies an access token issued to a client,
+// the caller is responsible for checking the client the token is issued to.
+func (j *Jwt) VerifyAccessToken(token string) (bool, *dto.AccessToken) {
+	claims := &dto.AccessToken{}
+	return j.verify(token, constant.AccessTokenType, "", claims), claims
+}
 
-	err := signingMethod.Verify(strings.Join(segments[:2], "."), segments[2], j.publicKey)
-	if err != nil {
-		return false, claims
+func (j *Jwt) JWKS(_ context.Context) dto.JWKS {
+	keys := j.verificationKeys("")
+	jwks := dto.JWKS{
+		Keys: make([]dto.JWK, 0, len(keys)),
 	}
-
-	if _, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodRSAPSS); !ok {
-			return nil, fmt.Errorf("unexpected siging method %v", t.Header["alg"])
+	for _, key := range keys {
+		jwk := dto.JWK{
+			Use: "sig",
+			Kid: key.kid,
+			Alg: key.algorithm,
+		}
+		switch publicKey := key.publicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = utils.Base64URLUint(publicKey.N)
+			jwk.E = utils.Base64URLUint(big.NewInt(int64(publicKey.E)))
+		case *ecdsa.PublicKey:
+			jwk.Kty = "EC"
+			jwk.Crv = publicKey.Curve.Params().Name
+			jwk.X = utils.Base64URLCoordinate(publicKey.X, publicKey.Curve.Params().BitSize)
+			jwk.Y = utils.Base64URLCoordinate(publicKey.Y, publicKey.Curve.Params().BitSize)
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
 		}
-		return j.publicKey, nil
-	}); err != nil {
-		return false, claims
+		jwks.Keys = append(jwks.Keys, jwk)
 	}
 
-	return true, claims
+	return jwks
+}
+
+// SigningAlgorithms returns the algorithms there is an active key for.
+func (j *Jwt) SigningAlgorithms() []string {
+	algorithms := []string{}
+	for _, algorithm := range supportedAlgorithms {
+		if _, ok := j.currentKey(algorithm); ok {
+			algorithms = append(algorithms, algorithm)
+		}
+	}
 
+	return algorithms
 }
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+)
+
+// Thumbprint calculates the RFC 7638 thumbprint of an rsa, ecdsa or ed25519 public key.
+// It returns an empty string for any other kind of key.
+func Thumbprint(publicKey crypto.PublicKey) string {
+	var members string
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return RSAThumbprint(key)
+	case *ecdsa.PublicKey:
+		members = fmt.Sprintf(`{"crv":"%s","kty":"EC","x":"%s","y":"%s"}`,
+			key.Curve.Params().Name,
+			Base64URLCoordinate(key.X, key.Curve.Params().BitSize),
+			Base64URLCoordinate(key.Y, key.Curve.Params().BitSize))
+	case ed25519.PublicKey:
+		members = fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":"%s"}`, base64.RawURLEncoding.EncodeToString(key))
+	default:
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(members))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// RSAThumbprint calculates the RFC 7638 thumbprint of an rsa public key.
+func RSAThumbprint(publicKey *rsa.PublicKey) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`,
+		Base64URLUint(big.NewInt(int64(publicKey.E))),
+		Base64URLUint(publicKey.N))))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Base64URLUint encodes a big integer as a base64url string as described on RFC 7518.
+func Base64URLUint(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+// Base64URLCoordinate encodes a coordinate of an elliptic curve point as a base64url string,
+// padded to the size of the curve as RFC 7518 requires.
+func Base64URLCoordinate(i *big.Int, bitSize int) string {
+	return base64.RawURLEncoding.EncodeToString(i.FillBytes(make([]byte, (bitSize+7)/8)))
+}
+
+// CertificateThumbprint calculates the base64url encoded SHA-256 thumbprint of a certificate,
+// the x5t#S256 value of RFC 8705.
+func CertificateThumbprint(certificate *x509.Certificate) string {
+	sum := sha256.Sum256(certificate.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,18 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+)
+
+// S256CodeChallenge derives the PKCE code challenge of a code verifier as described on RFC 7636.
+func S256CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// VerifyS256CodeChallenge checks if the code verifier is the one the code challenge was derived from.
+func VerifyS256CodeChallenge(codeChallenge, codeVerifier string) bool {
+	return subtle.ConstantTimeCompare([]byte(S256CodeChallenge(codeVerifier)), []byte(codeChallenge)) == 1
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"crypto"
 	"crypto/rand"
+	"crypto/subtle"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"io"
+	"math/big"
 	"mime/multipart"
 	"net/http"
 	"net/url"
@@ -88,6 +90,31 @@ func CalculateSessionState(clientID, origin, opbs, salt string) string {
 	return fmt.Sprintf("%x.%s", hash.Sum(nil), salt)
 }
 
+// HashToken hashes an opaque token so it can be stored and looked up without keeping the token itself.
+func HashToken(token string) string {
+	hash := crypto.SHA256.New()
+	hash.Write([]byte(token))
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// HashSecret hashes a client or resource server secret with a random salt.
+// secrets are long random strings, so a salted SHA-256 keeps them safe without the cost of bcrypt on every request.
+func HashSecret(secret string) string {
+	salt := GenerateRandomString(16, false)
+	return fmt.Sprintf("%s.%s", HashToken(salt+secret), salt)
+}
+
+// CompareSecret reports whether the secret matches a hash produced by HashSecret, in constant time.
+func CompareSecret(hashedSecret, secret string) bool {
+	separator := strings.LastIndex(hashedSecret, ".")
+	if separator == -1 || secret == "" {
+		return false
+	}
+
+	salt := hashedSecret[separator+1:]
+	return subtle.ConstantTimeCompare([]byte(hashedSecret[:separator]), []byte(HashToken(salt+secret))) == 1
+}
+
 func GenerateRedirectString(uri *url.URL, queries map[string]string) string {
 	query := uri.Query()
 	for k, v := range queries {
@@ -97,6 +124,17 @@ func GenerateRedirectString(uri *url.URL, queries map[string]string) string {
 	return uri.String()
 }
 
+// GenerateFragmentRedirectString is like GenerateRedirectString but adds the parameters to the fragment of the uri.
+func GenerateFragmentRedirectString(uri *url.URL, params map[string]string) string {
+	fragment := url.Values{}
+	for k, v := range params {
+		fragment.Set(k, v)
+	}
+	uri.Fragment = ""
+	uri.RawFragment = ""
+	return uri.String() + "#" + fragment.Encode()
+}
+
 func SaveMultiPartFile(file *multipart.FileHeader, dst string) error {
 	src, err := file.Open()
 	if err != nil {
@@ -153,3 +191,18 @@ func SetOPBSCookie(ctx *gin.Context, value string, options CookieOptions) {
 		SameSite: http.SameSite(options.SameSite),
 	})
 }
+
+const userCodeBytes = "BCDFGHJKLMNPQRSTVWXZ"
+
+// GenerateUserCode generates a device flow user code formatted as XXXX-XXXX.
+// It uses the consonant only character set recommended on RFC 8628 to avoid ambiguous characters and words.
+func GenerateUserCode() string {
+	randBytes := make([]byte, 8)
+	max := big.NewInt(int64(len(userCodeBytes)))
+	for i := 0; i < len(randBytes); i++ {
+		n, _ := rand.Int(rand.Reader, max)
+		randBytes[i] = userCodeBytes[n.Int64()]
+	}
+
+	return fmt.Sprintf("%s-%s", randBytes[:4], randBytes[4:])
+}
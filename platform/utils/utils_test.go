@@ -13,3 +13,35 @@ func TestCalculateSessionState(t *testing.T) {
 		t.Fatalf("got %s, want %s", got, want)
 	}
 }
+
+func TestVerifyS256CodeChallenge(t *testing.T) {
+	// test vector from RFC 7636 Appendix B
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	challenge := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+	if got := S256CodeChallenge(verifier); got != challenge {
+		t.Fatalf("got %s, want %s", got, challenge)
+	}
+	if !VerifyS256CodeChallenge(challenge, verifier) {
+		t.Fatalf("expected verifier to match the challenge")
+	}
+	if VerifyS256CodeChallenge(challenge, verifier+"x") {
+		t.Fatalf("expected a different verifier not to match the challenge")
+	}
+}
+
+func TestCompareSecret(t *testing.T) {
+	secret := GenerateRandomString(25, true)
+	hash := HashSecret(secret)
+	if hash == HashSecret(secret) {
+		t.Fatalf("expected every hash of a secret to be salted differently")
+	}
+	if !CompareSecret(hash, secret) {
+		t.Fatalf("expected the secret to match its hash")
+	}
+	if CompareSecret(hash, secret+"x") {
+		t.Fatalf("expected a different secret not to match the hash")
+	}
+	if CompareSecret("", "") {
+		t.Fatalf("expected an empty secret not to match an empty hash")
+	}
+}
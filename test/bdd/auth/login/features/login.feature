@@ -18,6 +18,16 @@ Feature: Login
       |              | example@email.com | 1234abcd |        |
       | 251911121314 | example@email.com | 1234abcd | 123456 |
 
+  @success
+  Scenario: The browser gets a new opbs cookie on login
+    Given my browser holds the opbs cookie "opbs-before-login"
+    And I fill the following details
+      | phone | email             | password | otp |
+      |       | example@email.com | 1234abcd |     |
+    When I submit the registration form
+    Then I will be logged in securely to my account
+    And my browser should hold an opbs cookie other than "opbs-before-login"
+
   @invalid
   Scenario Outline: Failed Login
     Given I fill the following details
@@ -97,6 +97,28 @@ func (l *loginTest) iWillBeLoggedInSecurelyToMyAccount() error {
 	return nil
 }
 
+func (l *loginTest) myBrowserHoldsTheOpbsCookie(opbs string) error {
+	l.apiTest.AddCookie(http.Cookie{
+		Name:  "opbs",
+		Value: opbs,
+	})
+	return nil
+}
+
+// myBrowserShouldHoldAnOpbsCookieOtherThan checks the opbs cookie was replaced,
+// so the session_state relying parties hold changes.
+func (l *loginTest) myBrowserShouldHoldAnOpbsCookieOtherThan(opbs string) error {
+	for _, cookie := range l.apiTest.Response.Result().Cookies() {
+		if cookie.Name == "opbs" {
+			if cookie.Value == "" || cookie.Value == opbs {
+				return fmt.Errorf("expected a new opbs cookie, got %q", cookie.Value)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("expected the opbs cookie to be set")
+}
+
 func (l *loginTest) theLoginShouldFailWith(msg string) error {
 	if err := l.apiTest.AssertStatusCode(http.StatusBadRequest); err != nil {
 		return err
@@ -126,4 +148,6 @@ func (l *loginTest) InitializeScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^I submit the registration form$`, l.iSubmitTheRegistrationForm)
 	ctx.Step(`^I will be logged in securely to my account$`, l.iWillBeLoggedInSecurelyToMyAccount)
 	ctx.Step(`^the login should fail with "([^"]*)"$`, l.theLoginShouldFailWith)
+	ctx.Step(`^my browser holds the opbs cookie "([^"]*)"$`, l.myBrowserHoldsTheOpbsCookie)
+	ctx.Step(`^my browser should hold an opbs cookie other than "([^"]*)"$`, l.myBrowserShouldHoldAnOpbsCookieOtherThan)
 }
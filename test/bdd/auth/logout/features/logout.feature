@@ -11,3 +11,24 @@ Feature: logout
             | example@email.com | 1234abcd |
         When I logout
         Then I should Successfully logout of the system
+
+    @success
+    Scenario: The browser gets a new opbs cookie on logout
+        Given I am a loggedin  user with the following details:
+            | email             | password |
+            | example@email.com | 1234abcd |
+        And my browser holds the opbs cookie "opbs-before-logout"
+        When I logout
+        Then I should Successfully logout of the system
+        And my browser should hold an opbs cookie other than "opbs-before-logout"
+
+    @success
+    Scenario: Clients are notified of the logout
+        Given I am a loggedin  user with the following details:
+            | email             | password |
+            | example@email.com | 1234abcd |
+        And I am signed in to a client with back-channel and front-channel logout uris
+        When I logout
+        Then I should Successfully logout of the system
+        And I should get the front-channel logout uri of the client
+        And the client should receive a logout token
@@ -2,19 +2,31 @@ package logout
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sso/internal/constant"
 	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
 	"sso/test"
 	"testing"
+	"time"
 
 	"github.com/cucumber/godog"
+	"github.com/golang-jwt/jwt/v4"
 	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
 )
 
 type logoutTest struct {
 	test.TestInstance
-	apiTest src.ApiTest
-	User    db.User
+	apiTest      src.ApiTest
+	User         db.User
+	client       db.Client
+	rpServer     *httptest.Server
+	logoutTokens chan string
 }
 
 func TestLogout(t *testing.T) {
@@ -33,6 +45,44 @@ func (l *logoutTest) iAmALoggedinUserWithTheFollowingDetails(userCredentials *go
 	return nil
 }
 
+func (l *logoutTest) iAmSignedInToAClientWithLogoutURIs() error {
+	l.logoutTokens = make(chan string, 1)
+	l.rpServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		l.logoutTokens <- r.PostForm.Get("logout_token")
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	var err error
+	l.client, err = l.DB.CreateClient(context.Background(), db.CreateClientParams{
+		RedirectUris:          "https://www.google.com",
+		Name:                  "logout client",
+		Scopes:                "openid profile",
+		ClientType:            constant.ConfidentialClient,
+		Secret:                utils.HashSecret(utils.GenerateRandomString(25, true)),
+		LogoUrl:               "https://www.google.com/images/errors/robot.png",
+		BackchannelLogoutUri:  l.rpServer.URL + "/backchannel-logout",
+		FrontchannelLogoutUri: "https://www.google.com/frontchannel-logout",
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = l.DB.SaveRefreshToken(context.Background(), db.SaveRefreshTokenParams{
+		ExpiresAt:    time.Now().Add(10 * time.Minute),
+		UserID:       l.User.ID,
+		Scope:        sql.NullString{String: "openid profile", Valid: true},
+		RedirectUri:  sql.NullString{String: l.client.RedirectUris, Valid: true},
+		ClientID:     l.client.ID,
+		RefreshToken: utils.GenerateRandomString(10, false),
+		Code:         utils.GenerateRandomString(10, false),
+	})
+	return err
+}
+
 func (l *logoutTest) iLogout() error {
 	l.apiTest.Body = `{"refresh_token":"` + l.RefreshToken + `"}`
 	l.apiTest.SetHeader("Authorization", "Bearer "+l.AccessToken)
@@ -47,6 +97,84 @@ func (l *logoutTest) iShouldSuccessfullyLogoutOfTheSystem() error {
 	return nil
 }
 
+func (l *logoutTest) theClientShouldReceiveALogoutToken() error {
+	var logoutToken string
+	select {
+	case logoutToken = <-l.logoutTokens:
+	case <-time.After(10 * time.Second):
+		return fmt.Errorf("expected the client to receive a logout token")
+	}
+
+	claims := dto.LogoutToken{}
+	if _, _, err := jwt.NewParser().ParseUnverified(logoutToken, &claims); err != nil {
+		return err
+	}
+	if _, ok := claims.Events[constant.BackChannelLogoutEvent]; !ok {
+		return fmt.Errorf("expected the logout token to carry the back-channel logout event")
+	}
+	if !claims.VerifyAudience(l.client.ID.String(), true) {
+		return fmt.Errorf("expected the logout token to be issued to the client")
+	}
+	if err := l.apiTest.AssertEqual(claims.Subject, l.User.ID.String()); err != nil {
+		return err
+	}
+	if claims.SID == "" {
+		return fmt.Errorf("expected the logout token to carry the sid")
+	}
+
+	for i := 0; i < 20; i++ {
+		var status string
+		err := l.Conn.QueryRow(context.Background(),
+			"SELECT status FROM backchannel_logout_deliveries WHERE client_id = $1", l.client.ID).Scan(&status)
+		if err == nil {
+			return l.apiTest.AssertEqual(status, constant.LogoutDelivered)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("expected the delivery of the logout token to be recorded")
+}
+
+func (l *logoutTest) iShouldGetTheFrontchannelLogoutURIOfTheClient() error {
+	var resp dto.LogoutResponse
+	if err := l.apiTest.UnmarshalResponseBodyPath("data", &resp); err != nil {
+		return err
+	}
+	if len(resp.FrontChannelLogoutURIs) != 1 {
+		return fmt.Errorf("expected one frontchannel logout uri, got %v", resp.FrontChannelLogoutURIs)
+	}
+
+	frontChannelLogoutURI, err := url.Parse(resp.FrontChannelLogoutURIs[0])
+	if err != nil {
+		return err
+	}
+	if frontChannelLogoutURI.Query().Get("iss") == "" || frontChannelLogoutURI.Query().Get("sid") == "" {
+		return fmt.Errorf("expected iss and sid on the frontchannel logout uri, got %s", frontChannelLogoutURI)
+	}
+	return nil
+}
+
+func (l *logoutTest) myBrowserHoldsTheOpbsCookie(opbs string) error {
+	l.apiTest.AddCookie(http.Cookie{
+		Name:  "opbs",
+		Value: opbs,
+	})
+	return nil
+}
+
+// myBrowserShouldHoldAnOpbsCookieOtherThan checks the opbs cookie was replaced,
+// so the session_state relying parties hold changes.
+func (l *logoutTest) myBrowserShouldHoldAnOpbsCookieOtherThan(opbs string) error {
+	for _, cookie := range l.apiTest.Response.Result().Cookies() {
+		if cookie.Name == "opbs" {
+			if cookie.Value == "" || cookie.Value == opbs {
+				return fmt.Errorf("expected a new opbs cookie, got %q", cookie.Value)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("expected the opbs cookie to be set")
+}
+
 func (l *logoutTest) InitializeScenario(ctx *godog.ScenarioContext) {
 	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
 		l.apiTest.URL = "/v1/logout"
@@ -57,11 +185,22 @@ func (l *logoutTest) InitializeScenario(ctx *godog.ScenarioContext) {
 	})
 
 	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if l.rpServer != nil {
+			l.rpServer.Close()
+			l.rpServer = nil
+			_, _ = l.Conn.Exec(ctx, "DELETE FROM refresh_tokens WHERE client_id = $1", l.client.ID)
+			_, _ = l.DB.DeleteClient(ctx, l.client.ID)
+		}
 		_, _ = l.DB.DeleteUser(ctx, l.User.ID)
 		return ctx, err
 	})
 
 	ctx.Step(`^I am a loggedin  user with the following details:$`, l.iAmALoggedinUserWithTheFollowingDetails)
+	ctx.Step(`^I am signed in to a client with back-channel and front-channel logout uris$`, l.iAmSignedInToAClientWithLogoutURIs)
 	ctx.Step(`^I should Successfully logout of the system$`, l.iShouldSuccessfullyLogoutOfTheSystem)
+	ctx.Step(`^the client should receive a logout token$`, l.theClientShouldReceiveALogoutToken)
+	ctx.Step(`^I should get the front-channel logout uri of the client$`, l.iShouldGetTheFrontchannelLogoutURIOfTheClient)
 	ctx.Step(`^I logout$`, l.iLogout)
+	ctx.Step(`^my browser holds the opbs cookie "([^"]*)"$`, l.myBrowserHoldsTheOpbsCookie)
+	ctx.Step(`^my browser should hold an opbs cookie other than "([^"]*)"$`, l.myBrowserShouldHoldAnOpbsCookieOtherThan)
 }
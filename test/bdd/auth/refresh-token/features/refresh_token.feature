@@ -15,3 +15,16 @@ Feature: Refresh Token
       | myR1fr35h70k3n |
     Then I should get a new access token
 
+  Scenario: Superseded refresh token is reused
+    Given There is a registered user on the system:
+      | first_name | middle_name | last_name | phone      | email            | password |
+      | testuser1  | testuser1   | testuser1 | 0925252595 | test11@gmail.com | 1234567  |
+    And I am logged in to the system and have a refresh token:
+      | refresh_token  | expires_at                          |
+      | myR1fr35h70k3n | 2023-09-26T09:06:36.525293389+03:00 |
+    And My refresh token has been rotated
+    When  I refresh my access token using my refresh token
+      | refresh_token  |
+      | myR1fr35h70k3n |
+    Then The request should fail with error message "refresh token reuse detected"
+    And All my sessions from this refresh token should be revoked
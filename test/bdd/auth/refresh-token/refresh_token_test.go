@@ -2,7 +2,9 @@ package refreshtoken
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"sso/internal/constant"
 	"sso/internal/constant/model/db"
 	"sso/internal/constant/model/dto"
 	"sso/platform/utils"
@@ -91,9 +93,47 @@ func (r *refreshSSOTokenTest) iShouldGetANewAccessToken() error {
 	if err := r.apiTest.AssertColumnExists("data.token_type"); err != nil {
 		return err
 	}
+
+	var tokenResponse dto.TokenResponse
+	if err := r.apiTest.UnmarshalResponseBodyPath("data", &tokenResponse); err != nil {
+		return err
+	}
+	if tokenResponse.RefreshToken == r.refreshToken.RefreshToken {
+		return fmt.Errorf("refresh token was not rotated")
+	}
 	return nil
 }
 
+func (r *refreshSSOTokenTest) myRefreshTokenHasBeenRotated() error {
+	_, err := r.PersistDB.RotateInternalRefreshTokenTX(context.Background(), utils.GenerateRandomString(25, false), r.refreshToken.RefreshToken)
+	return err
+}
+
+func (r *refreshSSOTokenTest) theRequestShouldFailWithErrorMessage(message string) error {
+	if err := r.apiTest.AssertStatusCode(http.StatusUnauthorized); err != nil {
+		return err
+	}
+	return r.apiTest.AssertStringValueOnPathInResponse("error.message", message)
+}
+
+func (r *refreshSSOTokenTest) allMySessionsFromThisRefreshTokenShouldBeRevoked() error {
+	refreshTokens, err := r.DB.GetInternalRefreshTokensByUserID(context.Background(), r.user.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.apiTest.AssertEqual(len(refreshTokens), 0); err != nil {
+		return err
+	}
+
+	var status string
+	if err := r.Conn.QueryRow(context.Background(),
+		"SELECT status FROM auth_histories WHERE user_id = $1 ORDER BY created_at DESC LIMIT 1",
+		r.user.ID).Scan(&status); err != nil {
+		return err
+	}
+	return r.apiTest.AssertEqual(status, constant.Revoke)
+}
+
 func (r *refreshSSOTokenTest) thereIsARegisteredUserOnTheSystem(user *godog.Table) error {
 	body, err := r.apiTest.ReadRow(user, nil, false)
 	if err != nil {
@@ -139,4 +179,7 @@ func (r *refreshSSOTokenTest) InitializeScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^I refresh my access token using my refresh token$`, r.iRefreshMyAccessTokenUsingMyRefreshToken)
 	ctx.Step(`^I should get a new access token$`, r.iShouldGetANewAccessToken)
 	ctx.Step(`^There is a registered user on the system:$`, r.thereIsARegisteredUserOnTheSystem)
+	ctx.Step(`^My refresh token has been rotated$`, r.myRefreshTokenHasBeenRotated)
+	ctx.Step(`^The request should fail with error message "([^"]*)"$`, r.theRequestShouldFailWithErrorMessage)
+	ctx.Step(`^All my sessions from this refresh token should be revoked$`, r.allMySessionsFromThisRefreshTokenShouldBeRevoked)
 }
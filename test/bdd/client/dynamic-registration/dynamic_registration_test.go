@@ -0,0 +1,348 @@
+package dynamic_registration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sso/internal/constant/errors/sqlcerr"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/test"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cucumber/godog"
+	"github.com/google/uuid"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
+)
+
+type dynamicRegistrationTest struct {
+	test.TestInstance
+	apiTest            src.ApiTest
+	Admin              db.User
+	scopes             []db.Scope
+	initialAccessToken dto.InitialAccessToken
+	registration       dto.ClientRegistrationResponse
+}
+
+func TestDynamicRegistration(t *testing.T) {
+	d := &dynamicRegistrationTest{}
+	d.TestInstance = test.Initiate("../../../../")
+	d.apiTest.InitializeServer(d.Server)
+
+	d.apiTest.InitializeTest(t, "dynamic client registration test", "features/dynamic_registration.feature", d.InitializeScenario)
+}
+
+func (d *dynamicRegistrationTest) iAmLoggedInAsAdminUser(adminCredentials *godog.Table) error {
+	var err error
+	d.Admin, err = d.Authenticate(adminCredentials)
+	if err != nil {
+		return err
+	}
+	_, d.GrantRoleAfterFunc, err = d.GrantRoleForUserWithAfter(d.Admin.ID.String(), adminCredentials)
+
+	return err
+}
+
+func (d *dynamicRegistrationTest) theFollowingScopesExist(scopes *godog.Table) error {
+	rows, err := d.apiTest.ReadRowsToMapString(scopes)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		scope, err := d.DB.CreateScope(context.Background(), db.CreateScopeParams{
+			Name:        row["name"],
+			Description: row["description"],
+		})
+		if err != nil {
+			if sqlcerr.IsUniqueViolation(err) {
+				continue
+			}
+			return err
+		}
+		d.scopes = append(d.scopes, scope)
+	}
+
+	return nil
+}
+
+func (d *dynamicRegistrationTest) iIssueAnInitialAccessTokenAllowing(allowances *godog.Table) error {
+	rows, err := d.apiTest.ReadRowsToMapString(allowances)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("no allowances were given")
+	}
+
+	d.apiTest.URL = "/v1/clients/initialAccessTokens"
+	d.apiTest.Method = http.MethodPost
+	d.apiTest.SetHeader("Authorization", "Bearer "+d.AccessToken)
+	d.apiTest.SetBodyMap(map[string]interface{}{
+		"expires_at":  time.Now().Add(time.Hour),
+		"scopes":      rows[0]["scopes"],
+		"grant_types": strings.Split(rows[0]["grant_types"], ","),
+	})
+	d.apiTest.SendRequest()
+
+	return nil
+}
+
+func (d *dynamicRegistrationTest) iHaveIssuedAnInitialAccessTokenAllowing(allowances *godog.Table) error {
+	if err := d.iIssueAnInitialAccessTokenAllowing(allowances); err != nil {
+		return err
+	}
+
+	if err := d.apiTest.AssertStatusCode(http.StatusCreated); err != nil {
+		return err
+	}
+
+	return d.apiTest.UnmarshalResponseBodyPath("data", &d.initialAccessToken)
+}
+
+func (d *dynamicRegistrationTest) readMetadata(metadata *godog.Table) (map[string]interface{}, error) {
+	rows, err := d.apiTest.ReadRowsToMapString(metadata)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no client metadata was given")
+	}
+
+	body := map[string]interface{}{
+		"logo_uri": "https://ride.example.com/logo.png",
+	}
+	for key, value := range rows[0] {
+		switch key {
+		case "redirect_uris", "grant_types":
+			body[key] = strings.Split(value, ",")
+		default:
+			body[key] = value
+		}
+	}
+
+	return body, nil
+}
+
+func (d *dynamicRegistrationTest) register(token string, metadata *godog.Table) error {
+	body, err := d.readMetadata(metadata)
+	if err != nil {
+		return err
+	}
+
+	d.apiTest.URL = "/v1/oauth/register"
+	d.apiTest.Method = http.MethodPost
+	d.apiTest.SetHeader("Authorization", "Bearer "+token)
+	d.apiTest.SetBodyMap(body)
+	d.apiTest.SendRequest()
+
+	return nil
+}
+
+func (d *dynamicRegistrationTest) iRegisterAClientWithTheFollowingMetadata(metadata *godog.Table) error {
+	return d.register(d.initialAccessToken.Token, metadata)
+}
+
+func (d *dynamicRegistrationTest) iRegisterAClientUsingTheInitialAccessTokenWithTheFollowingMetadata(token string, metadata *godog.Table) error {
+	return d.register(token, metadata)
+}
+
+func (d *dynamicRegistrationTest) iHaveRegisteredAClientWithTheFollowingMetadata(metadata *godog.Table) error {
+	if err := d.register(d.initialAccessToken.Token, metadata); err != nil {
+		return err
+	}
+
+	return d.theClientShouldBeRegistered()
+}
+
+func (d *dynamicRegistrationTest) theClientShouldBeRegistered() error {
+	if err := d.apiTest.AssertStatusCode(http.StatusCreated); err != nil {
+		return err
+	}
+
+	if err := d.apiTest.UnmarshalResponseBody(&d.registration); err != nil {
+		return err
+	}
+
+	clientID, err := uuid.Parse(d.registration.ClientID)
+	if err != nil {
+		return err
+	}
+	client, err := d.DB.GetClientByID(context.Background(), clientID)
+	if err != nil {
+		return err
+	}
+
+	return d.apiTest.AssertEqual(client.Name, d.registration.ClientName)
+}
+
+func (d *dynamicRegistrationTest) iShouldGetARegistrationAccessToken() error {
+	if d.registration.RegistrationAccessToken == "" {
+		return fmt.Errorf("expected a registration access token")
+	}
+
+	if !strings.HasSuffix(d.registration.RegistrationClientURI, "/v1/oauth/register/"+d.registration.ClientID) {
+		return fmt.Errorf("unexpected registration client uri %s", d.registration.RegistrationClientURI)
+	}
+
+	return nil
+}
+
+func (d *dynamicRegistrationTest) manage(method, token string) {
+	d.apiTest.URL = "/v1/oauth/register/" + d.registration.ClientID
+	d.apiTest.Method = method
+	d.apiTest.SetHeader("Authorization", "Bearer "+token)
+}
+
+func (d *dynamicRegistrationTest) iReadTheRegistrationOfTheClient() error {
+	d.manage(http.MethodGet, d.registration.RegistrationAccessToken)
+	d.apiTest.SendRequest()
+	return nil
+}
+
+func (d *dynamicRegistrationTest) iReadTheRegistrationOfTheClientUsingTheRegistrationAccessToken(token string) error {
+	d.manage(http.MethodGet, token)
+	d.apiTest.SendRequest()
+	return nil
+}
+
+func (d *dynamicRegistrationTest) iUpdateTheRegistrationOfTheClientWithTheFollowingMetadata(metadata *godog.Table) error {
+	body, err := d.readMetadata(metadata)
+	if err != nil {
+		return err
+	}
+
+	d.manage(http.MethodPut, d.registration.RegistrationAccessToken)
+	d.apiTest.SetBodyMap(body)
+	d.apiTest.SendRequest()
+	return nil
+}
+
+func (d *dynamicRegistrationTest) iShouldGetTheRegistrationOfTheClientNamed(name string) error {
+	if err := d.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+
+	if err := d.apiTest.AssertStringValueOnPathInResponse("client_id", d.registration.ClientID); err != nil {
+		return err
+	}
+
+	return d.apiTest.AssertStringValueOnPathInResponse("client_name", name)
+}
+
+func (d *dynamicRegistrationTest) theRegistrationOfTheClientShouldHaveTheScopeAndTheGrantTypes(scope, grantTypes string) error {
+	if err := d.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+
+	var registration dto.ClientRegistrationResponse
+	if err := d.apiTest.UnmarshalResponseBody(&registration); err != nil {
+		return err
+	}
+	if err := d.apiTest.AssertEqual(registration.Scope, scope); err != nil {
+		return err
+	}
+
+	return d.apiTest.AssertEqual(strings.Join(registration.GrantTypes, ","), grantTypes)
+}
+
+func (d *dynamicRegistrationTest) anAdminMadeTheScopeOptionalForTheClient(scope string) error {
+	_, err := d.Conn.Exec(context.Background(), "UPDATE clients SET optional_scopes = $1 WHERE id = $2", scope, d.registration.ClientID)
+	return err
+}
+
+func (d *dynamicRegistrationTest) theScopeShouldStillBeOptionalForTheClient(scope string) error {
+	clientID, err := uuid.Parse(d.registration.ClientID)
+	if err != nil {
+		return err
+	}
+	client, err := d.DB.GetClientByID(context.Background(), clientID)
+	if err != nil {
+		return err
+	}
+
+	return d.apiTest.AssertEqual(client.OptionalScopes, scope)
+}
+
+func (d *dynamicRegistrationTest) iDeleteTheRegistrationOfTheClient() error {
+	d.manage(http.MethodDelete, d.registration.RegistrationAccessToken)
+	d.apiTest.SendRequest()
+	return nil
+}
+
+func (d *dynamicRegistrationTest) theClientShouldBeDeleted() error {
+	if err := d.apiTest.AssertStatusCode(http.StatusNoContent); err != nil {
+		return err
+	}
+
+	clientID, err := uuid.Parse(d.registration.ClientID)
+	if err != nil {
+		return err
+	}
+	_, err = d.DB.GetClientByID(context.Background(), clientID)
+	if err == nil {
+		return fmt.Errorf("client is not deleted")
+	}
+	if !sqlcerr.Is(err, sqlcerr.ErrNoRows) {
+		return err
+	}
+
+	return nil
+}
+
+func (d *dynamicRegistrationTest) theRequestShouldFailWithStatusAndMessage(status, message string) error {
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		return err
+	}
+	if err := d.apiTest.AssertStatusCode(code); err != nil {
+		return err
+	}
+
+	return d.apiTest.AssertStringValueOnPathInResponse("error.message", message)
+}
+
+func (d *dynamicRegistrationTest) InitializeScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		d.apiTest.SetHeader("Content-Type", "application/json")
+		d.registration = dto.ClientRegistrationResponse{}
+
+		return ctx, nil
+	})
+
+	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if clientID, err := uuid.Parse(d.registration.ClientID); err == nil {
+			_, _ = d.DB.DeleteClient(ctx, clientID)
+		}
+		for _, scope := range d.scopes {
+			_, _ = d.DB.DeleteScope(ctx, scope.Name)
+		}
+		d.scopes = nil
+		_, _ = d.DB.DeleteUser(ctx, d.Admin.ID)
+		_ = d.GrantRoleAfterFunc()
+		return ctx, nil
+	})
+
+	ctx.Step(`^I am logged in as admin user$`, d.iAmLoggedInAsAdminUser)
+	ctx.Step(`^the following scopes exist$`, d.theFollowingScopesExist)
+	ctx.Step(`^I issue an initial access token allowing$`, d.iIssueAnInitialAccessTokenAllowing)
+	ctx.Step(`^I have issued an initial access token allowing$`, d.iHaveIssuedAnInitialAccessTokenAllowing)
+	ctx.Step(`^I register a client with the following metadata$`, d.iRegisterAClientWithTheFollowingMetadata)
+	ctx.Step(`^I register a client using the initial access token "([^"]*)" with the following metadata$`, d.iRegisterAClientUsingTheInitialAccessTokenWithTheFollowingMetadata)
+	ctx.Step(`^I have registered a client with the following metadata$`, d.iHaveRegisteredAClientWithTheFollowingMetadata)
+	ctx.Step(`^the client should be registered$`, d.theClientShouldBeRegistered)
+	ctx.Step(`^I should get a registration access token$`, d.iShouldGetARegistrationAccessToken)
+	ctx.Step(`^I read the registration of the client$`, d.iReadTheRegistrationOfTheClient)
+	ctx.Step(`^I read the registration of the client using the registration access token "([^"]*)"$`, d.iReadTheRegistrationOfTheClientUsingTheRegistrationAccessToken)
+	ctx.Step(`^I update the registration of the client with the following metadata$`, d.iUpdateTheRegistrationOfTheClientWithTheFollowingMetadata)
+	ctx.Step(`^I should get the registration of the client named "([^"]*)"$`, d.iShouldGetTheRegistrationOfTheClientNamed)
+	ctx.Step(`^the registration of the client should have the scope "([^"]*)" and the grant types "([^"]*)"$`, d.theRegistrationOfTheClientShouldHaveTheScopeAndTheGrantTypes)
+	ctx.Step(`^an admin made the scope "([^"]*)" optional for the client$`, d.anAdminMadeTheScopeOptionalForTheClient)
+	ctx.Step(`^the scope "([^"]*)" should still be optional for the client$`, d.theScopeShouldStillBeOptionalForTheClient)
+	ctx.Step(`^I delete the registration of the client$`, d.iDeleteTheRegistrationOfTheClient)
+	ctx.Step(`^the client should be deleted$`, d.theClientShouldBeDeleted)
+	ctx.Step(`^the request should fail with status "([^"]*)" and message "([^"]*)"$`, d.theRequestShouldFailWithStatusAndMessage)
+}
@@ -0,0 +1,104 @@
+Feature: Dynamic Client Registration
+
+    As a client developer,
+    I want to register my client with an initial access token issued by an admin
+    So that I can onboard and manage my client without going through an admin
+
+    Background:
+        Given I am logged in as admin user
+            | email           | password      | role                       |
+            | admin@gmail.com | adminPassword | issue_initial_access_token |
+        And the following scopes exist
+            | name   | description              |
+            | openid | the identity of the user |
+            | email  | the email of the user    |
+        And I have issued an initial access token allowing
+            | scopes       | grant_types                      |
+            | openid email | authorization_code,refresh_token |
+
+    @success
+    Scenario: Successful registration
+        When I register a client with the following metadata
+            | client_name | redirect_uris                     | grant_types        | scope        | logo_uri                   |
+            | ride        | https://ride.example.com/callback | authorization_code | openid email | https://ride.example.com/l |
+        Then the client should be registered
+        And I should get a registration access token
+
+    @success
+    Scenario: Managing the registration with the registration access token
+        Given I have registered a client with the following metadata
+            | client_name | redirect_uris                     | grant_types        | scope        |
+            | ride        | https://ride.example.com/callback | authorization_code | openid email |
+        When I read the registration of the client
+        Then I should get the registration of the client named "ride"
+        When I update the registration of the client with the following metadata
+            | client_name | redirect_uris                     | grant_types                      | scope        |
+            | ride-v2     | https://ride.example.com/callback | authorization_code,refresh_token | openid email |
+        Then I should get the registration of the client named "ride-v2"
+        When I delete the registration of the client
+        Then the client should be deleted
+
+    @success
+    Scenario: The scopes and privileged grant types are kept on update
+        Given I have registered a client with the following metadata
+            | client_name | redirect_uris                     | grant_types        | scope  |
+            | ride        | https://ride.example.com/callback | authorization_code | openid |
+        When I update the registration of the client with the following metadata
+            | client_name | redirect_uris                     | grant_types                           | scope                |
+            | ride        | https://ride.example.com/callback | authorization_code,client_credentials | openid email profile |
+        Then the registration of the client should have the scope "openid" and the grant types "authorization_code"
+
+    @success
+    Scenario: The optional scopes an admin set are kept on update
+        Given I have registered a client with the following metadata
+            | client_name | redirect_uris                     | grant_types        | scope        |
+            | ride        | https://ride.example.com/callback | authorization_code | openid email |
+        And an admin made the scope "email" optional for the client
+        When I update the registration of the client with the following metadata
+            | client_name | redirect_uris                     | grant_types        | scope        |
+            | ride-v2     | https://ride.example.com/callback | authorization_code | openid email |
+        Then I should get the registration of the client named "ride-v2"
+        And the scope "email" should still be optional for the client
+
+    @failure
+    Scenario: Registration with a scope the initial access token does not allow
+        When I register a client with the following metadata
+            | client_name | redirect_uris                     | grant_types        | scope          |
+            | ride        | https://ride.example.com/callback | authorization_code | openid profile |
+        Then the request should fail with status "400" and message "scope profile is not allowed by the initial access token"
+
+    @failure
+    Scenario: Registration with a grant type the initial access token does not allow
+        When I register a client with the following metadata
+            | client_name | redirect_uris                     | grant_types        | scope  |
+            | ride        | https://ride.example.com/callback | client_credentials | openid |
+        Then the request should fail with status "400" and message "grant type client_credentials is not allowed by the initial access token"
+
+    @failure
+    Scenario: Issuing an initial access token allowing a scope that does not exist
+        When I issue an initial access token allowing
+            | scopes             | grant_types        |
+            | openid not-a-scope | authorization_code |
+        Then the request should fail with status "400" and message "scope not-a-scope does not exist"
+
+    @failure
+    Scenario: Registration with an invalid initial access token
+        When I register a client using the initial access token "not-a-valid-token" with the following metadata
+            | client_name | redirect_uris                     | grant_types        | scope        |
+            | ride        | https://ride.example.com/callback | authorization_code | openid email |
+        Then the request should fail with status "401" and message "invalid initial access token"
+
+    @failure
+    Scenario: Registration with unsupported metadata
+        When I register a client with the following metadata
+            | client_name | redirect_uris                     | grant_types | scope        |
+            | ride        | https://ride.example.com/callback | password    | openid email |
+        Then the request should fail with status "400" and message "invalid client metadata"
+
+    @failure
+    Scenario: Reading a registration with a wrong registration access token
+        Given I have registered a client with the following metadata
+            | client_name | redirect_uris                     | grant_types        | scope        |
+            | ride        | https://ride.example.com/callback | authorization_code | openid email |
+        When I read the registration of the client using the registration access token "not-a-valid-token"
+        Then the request should fail with status "401" and message "invalid registration access token"
@@ -30,3 +30,19 @@ Feature: Client Registration
       | newClient | my_type      | https://google.com      | profile email | https://www.google.com/images/errors/robot.png | client type must be either confidential or public |
 #      | newClient | confidential | https://google.com      | not a scope   | https://www.google.com/images/errors/robot.png | invalid scopes                                    |
       | newClient | confidential | https://google.com      | profile email | my-logo-url                                    | invalid logo_url                                  |
+
+  @failure
+  Scenario: Client Registration With An Unsupported Id Token Signing Algorithm
+    Given I fill the following client form
+      | name      | client_type  | redirect_uris      | scopes        | logo_url                                       | id_token_signed_response_alg |
+      | newClient | confidential | https://google.com | profile email | https://www.google.com/images/errors/robot.png | HS256                        |
+    When I submit the form
+    Then The registration should fail with "unsupported id_token_signed_response_alg"
+
+  @failure
+  Scenario: Client Registration With An Id Token Signing Algorithm Without An Active Key
+    Given I fill the following client form
+      | name      | client_type  | redirect_uris      | scopes        | logo_url                                       | id_token_signed_response_alg |
+      | newClient | confidential | https://google.com | profile email | https://www.google.com/images/errors/robot.png | EdDSA                        |
+    When I submit the form
+    Then The registration should fail with "no active signing key for the id_token_signed_response_alg"
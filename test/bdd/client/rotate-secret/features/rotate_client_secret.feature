@@ -0,0 +1,30 @@
+Feature: Rotate Client Secret
+
+    As an admin,
+    I want to rotate the secret of a client
+    So that a leaked or old secret can be replaced without breaking the client
+
+    Background:
+        Given I am logged in as admin user
+            | email           | password      | role                 |
+            | admin@gmail.com | adminPassword | rotate_client_secret |
+        And there is a confidential client with scopes "profile email"
+
+    @success
+    Scenario: Both secrets are accepted during the rotation window
+        When I rotate the secret of the client with the previous secret expiring in "1h"
+        Then I should get the new secret
+        And the client should be able to authenticate with the new secret
+        And the client should be able to authenticate with the previous secret
+
+    @success
+    Scenario: The previous secret is rejected after an immediate rotation
+        When I rotate the secret of the client
+        Then I should get the new secret
+        And the client should be able to authenticate with the new secret
+        But the client should not be able to authenticate with the previous secret
+
+    @failure
+    Scenario: Rotating the secret of a client that doesn't exist
+        When I rotate the secret of the client with id "c1fa44b7-a3cb-4a0c-8c43-8c5e5c1e0b06"
+        Then the rotation should fail with message "client not found"
@@ -0,0 +1,178 @@
+package rotate_secret
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sso/internal/constant"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+	"sso/test"
+	"testing"
+	"time"
+
+	"github.com/cucumber/godog"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
+)
+
+type rotateClientSecretTest struct {
+	test.TestInstance
+	apiTest        src.ApiTest
+	Admin          db.User
+	client         db.Client
+	previousSecret string
+	rotatedSecret  dto.RotatedSecret
+}
+
+func TestRotateClientSecret(t *testing.T) {
+	r := &rotateClientSecretTest{}
+	r.TestInstance = test.Initiate("../../../../")
+	r.apiTest.InitializeServer(r.Server)
+
+	r.apiTest.InitializeTest(t, "rotate client secret test", "features/rotate_client_secret.feature", r.InitializeScenario)
+}
+
+func (r *rotateClientSecretTest) iAmLoggedInAsAdminUser(adminCredentials *godog.Table) error {
+	var err error
+	r.Admin, err = r.Authenticate(adminCredentials)
+	if err != nil {
+		return err
+	}
+	_, r.GrantRoleAfterFunc, err = r.GrantRoleForUserWithAfter(r.Admin.ID.String(), adminCredentials)
+
+	return err
+}
+
+func (r *rotateClientSecretTest) thereIsAConfidentialClientWithScopes(scopes string) error {
+	var err error
+	r.previousSecret = utils.GenerateRandomString(25, true)
+	r.client, err = r.DB.CreateClient(context.Background(), db.CreateClientParams{
+		RedirectUris: utils.ArrayToString([]string{"https://www.google.com"}),
+		Name:         "backend",
+		Scopes:       scopes,
+		ClientType:   constant.ConfidentialClient,
+		Secret:       utils.HashSecret(r.previousSecret),
+		LogoUrl:      "https://www.google.com/images/errors/robot.png",
+		GrantTypes:   constant.ClientCredentials,
+	})
+
+	return err
+}
+
+func (r *rotateClientSecretTest) rotate(clientID string, previousSecretExpiresAt time.Time) {
+	body := map[string]interface{}{}
+	if !previousSecretExpiresAt.IsZero() {
+		body["previous_secret_expires_at"] = previousSecretExpiresAt
+	}
+
+	r.apiTest.URL = "/v1/clients/" + clientID + "/secret/rotate"
+	r.apiTest.Method = http.MethodPost
+	r.apiTest.SetHeader("Authorization", "Bearer "+r.AccessToken)
+	r.apiTest.SetBodyMap(body)
+	r.apiTest.SendRequest()
+}
+
+func (r *rotateClientSecretTest) iRotateTheSecretOfTheClient() error {
+	r.rotate(r.client.ID.String(), time.Time{})
+	return nil
+}
+
+func (r *rotateClientSecretTest) iRotateTheSecretOfTheClientWithThePreviousSecretExpiringIn(after string) error {
+	duration, err := time.ParseDuration(after)
+	if err != nil {
+		return err
+	}
+
+	r.rotate(r.client.ID.String(), time.Now().Add(duration))
+	return nil
+}
+
+func (r *rotateClientSecretTest) iRotateTheSecretOfTheClientWithId(clientID string) error {
+	r.rotate(clientID, time.Time{})
+	return nil
+}
+
+func (r *rotateClientSecretTest) iShouldGetTheNewSecret() error {
+	if err := r.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+
+	if err := r.apiTest.UnmarshalResponseBodyPath("data", &r.rotatedSecret); err != nil {
+		return err
+	}
+	if r.rotatedSecret.Secret == "" || r.rotatedSecret.Secret == r.previousSecret {
+		return fmt.Errorf("expected a new secret")
+	}
+
+	client, err := r.DB.GetClientByID(context.Background(), r.client.ID)
+	if err != nil {
+		return err
+	}
+	if client.Secret == r.rotatedSecret.Secret {
+		return fmt.Errorf("expected the secret to be stored hashed")
+	}
+
+	return nil
+}
+
+func (r *rotateClientSecretTest) requestToken(secret string) {
+	r.apiTest.URL = "/v1/oauth/token"
+	r.apiTest.Method = http.MethodPost
+	r.apiTest.SetHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(r.client.ID.String()+":"+secret)))
+	r.apiTest.SetBodyMap(map[string]interface{}{
+		"grant_type": constant.ClientCredentials,
+	})
+	r.apiTest.SendRequest()
+}
+
+func (r *rotateClientSecretTest) theClientShouldBeAbleToAuthenticateWithTheNewSecret() error {
+	r.requestToken(r.rotatedSecret.Secret)
+	return r.apiTest.AssertStatusCode(http.StatusOK)
+}
+
+func (r *rotateClientSecretTest) theClientShouldBeAbleToAuthenticateWithThePreviousSecret() error {
+	r.requestToken(r.previousSecret)
+	return r.apiTest.AssertStatusCode(http.StatusOK)
+}
+
+func (r *rotateClientSecretTest) theClientShouldNotBeAbleToAuthenticateWithThePreviousSecret() error {
+	r.requestToken(r.previousSecret)
+	return r.apiTest.AssertStatusCode(http.StatusForbidden)
+}
+
+func (r *rotateClientSecretTest) theRotationShouldFailWithMessage(message string) error {
+	if err := r.apiTest.AssertStatusCode(http.StatusNotFound); err != nil {
+		return err
+	}
+
+	return r.apiTest.AssertStringValueOnPathInResponse("error.message", message)
+}
+
+func (r *rotateClientSecretTest) InitializeScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		r.apiTest.SetHeader("Content-Type", "application/json")
+		r.rotatedSecret = dto.RotatedSecret{}
+
+		return ctx, nil
+	})
+
+	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		_, _ = r.DB.DeleteClient(ctx, r.client.ID)
+		_, _ = r.DB.DeleteUser(ctx, r.Admin.ID)
+		_ = r.GrantRoleAfterFunc()
+		return ctx, nil
+	})
+
+	ctx.Step(`^I am logged in as admin user$`, r.iAmLoggedInAsAdminUser)
+	ctx.Step(`^there is a confidential client with scopes "([^"]*)"$`, r.thereIsAConfidentialClientWithScopes)
+	ctx.Step(`^I rotate the secret of the client$`, r.iRotateTheSecretOfTheClient)
+	ctx.Step(`^I rotate the secret of the client with the previous secret expiring in "([^"]*)"$`, r.iRotateTheSecretOfTheClientWithThePreviousSecretExpiringIn)
+	ctx.Step(`^I rotate the secret of the client with id "([^"]*)"$`, r.iRotateTheSecretOfTheClientWithId)
+	ctx.Step(`^I should get the new secret$`, r.iShouldGetTheNewSecret)
+	ctx.Step(`^the client should be able to authenticate with the new secret$`, r.theClientShouldBeAbleToAuthenticateWithTheNewSecret)
+	ctx.Step(`^the client should be able to authenticate with the previous secret$`, r.theClientShouldBeAbleToAuthenticateWithThePreviousSecret)
+	ctx.Step(`^the client should not be able to authenticate with the previous secret$`, r.theClientShouldNotBeAbleToAuthenticateWithThePreviousSecret)
+	ctx.Step(`^the rotation should fail with message "([^"]*)"$`, r.theRotationShouldFailWithMessage)
+}
@@ -10,10 +10,12 @@ import (
 	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src/seed"
 	"net/http"
 	"net/url"
+	"sso/internal/constant"
 	"sso/internal/constant/model/db"
 	"sso/internal/constant/model/dto"
 	"sso/platform/utils"
 	"sso/test"
+	"strings"
 	"testing"
 )
 
@@ -26,6 +28,8 @@ type approveConsentTest struct {
 	consent     dto.Consent
 	User        db.User
 	scopes      []db.Scope
+	authCode    dto.AuthCode
+	idToken     *dto.IDTokenPayload
 }
 
 func TestApproveConsent(t *testing.T) {
@@ -81,6 +85,10 @@ func (a *approveConsentTest) thereIsAClientWithTheFollowingDetails(client *godog
 			Column: "redirect_uris",
 			Kind:   src.Array,
 		},
+		{
+			Column: "response_types",
+			Kind:   src.Array,
+		},
 	}, false)
 	if err != nil {
 		return err
@@ -90,12 +98,14 @@ func (a *approveConsentTest) thereIsAClientWithTheFollowingDetails(client *godog
 	}
 
 	clientData, err := a.DB.CreateClient(context.Background(), db.CreateClientParams{
-		Name:         a.client.Name,
-		RedirectUris: utils.ArrayToString(a.client.RedirectURIs),
-		Secret:       a.client.Secret,
-		Scopes:       a.client.Scopes,
-		ClientType:   a.client.ClientType,
-		LogoUrl:      a.client.LogoURL,
+		Name:           a.client.Name,
+		RedirectUris:   utils.ArrayToString(a.client.RedirectURIs),
+		Secret:         a.client.Secret,
+		Scopes:         a.client.Scopes,
+		ClientType:     a.client.ClientType,
+		LogoUrl:        a.client.LogoURL,
+		ResponseTypes:  strings.Join(a.client.ResponseTypes, ","),
+		OptionalScopes: a.client.OptionalScopes,
 	})
 	if err != nil {
 		return err
@@ -144,6 +154,33 @@ func (a *approveConsentTest) iRequestConsentApprovalWithId(consentID string) err
 	return nil
 }
 
+func (a *approveConsentTest) iRequestConsentApprovalWithIdAndScopes(consentID, scopes string) error {
+	a.apiTest.SetBodyValue("scope", scopes)
+	return a.iRequestConsentApprovalWithId(consentID)
+}
+
+func (a *approveConsentTest) theConsentShouldBeApprovedForScopesOfTheRequested(scopes, requestedScopes string) error {
+	if err := a.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+	var data dto.RedirectResponse
+	if err := a.apiTest.UnmarshalResponseBodyPath("data", &data); err != nil {
+		return err
+	}
+	redirectURL, err := url.Parse(data.Location)
+	if err != nil {
+		return err
+	}
+	code, err := a.TestInstance.CacheLayer.AuthCodeCacheLayer.GetAuthCode(context.Background(), redirectURL.Query().Get("code"))
+	if err != nil {
+		return err
+	}
+	if err := a.apiTest.AssertEqual(code.Scope, scopes); err != nil {
+		return err
+	}
+	return a.apiTest.AssertEqual(code.RequestedScope, requestedScopes)
+}
+
 func (a *approveConsentTest) theConsentShouldBeApproved() error {
 	fmt.Println(string(a.apiTest.ResponseBody))
 	if err := a.apiTest.AssertStatusCode(http.StatusOK); err != nil {
@@ -183,6 +220,71 @@ func (a *approveConsentTest) theConsentShouldBeApproved() error {
 	return nil
 }
 
+func (a *approveConsentTest) theConsentShouldBeApprovedWithAFragmentResponse() error {
+	if err := a.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+	var data dto.RedirectResponse
+	if err := a.apiTest.UnmarshalResponseBodyPath("data", &data); err != nil {
+		return err
+	}
+	redirectURL, err := url.Parse(data.Location)
+	if err != nil {
+		return err
+	}
+	if len(redirectURL.Query()) != 0 {
+		return fmt.Errorf("expected no query parameters, got %v", redirectURL.RawQuery)
+	}
+	fragment, err := url.ParseQuery(redirectURL.Fragment)
+	if err != nil {
+		return err
+	}
+	if err := a.apiTest.AssertEqual(fragment.Get("state"), a.consent.State); err != nil {
+		return err
+	}
+
+	code, err := a.TestInstance.CacheLayer.AuthCodeCacheLayer.GetAuthCode(context.Background(), fragment.Get("code"))
+	if err != nil {
+		return err
+	}
+	if err := a.apiTest.AssertEqual(code.Nonce, a.consent.Nonce); err != nil {
+		return err
+	}
+
+	valid, claims := a.PlatformLayer.Token.VerifyIdToken(fragment.Get("id_token"))
+	if !valid {
+		return fmt.Errorf("invalid id token")
+	}
+	if err := a.apiTest.AssertEqual(claims.Nonce, a.consent.Nonce); err != nil {
+		return err
+	}
+	if err := a.apiTest.AssertEqual(claims.Subject, a.User.ID.String()); err != nil {
+		return err
+	}
+	a.authCode = code
+	a.idToken = claims
+	return nil
+}
+
+func (a *approveConsentTest) theIdTokenShouldTellHowIAuthenticated() error {
+	if a.idToken.AuthTime == nil {
+		return fmt.Errorf("expected auth_time in the id token")
+	}
+	if err := a.apiTest.AssertEqual(a.idToken.AMR, []string{constant.AMRPassword}); err != nil {
+		return err
+	}
+	if err := a.apiTest.AssertEqual(a.idToken.ACR, constant.ACRSingleFactor); err != nil {
+		return err
+	}
+	if a.idToken.Issuer == "" {
+		return fmt.Errorf("expected iss in the id token")
+	}
+	if a.idToken.CodeHash == "" {
+		return fmt.Errorf("expected c_hash in the id token")
+	}
+	return a.apiTest.AssertEqual(a.authCode.Authentication.Methods, []string{constant.AMRPassword})
+}
+
 func (a *approveConsentTest) consentApprovalShouldFailWithMessage(message string) error {
 	if err := a.apiTest.AssertStatusCode(http.StatusOK); err != nil {
 		return err
@@ -217,7 +319,11 @@ func (a *approveConsentTest) InitializeScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^I am logged in with credentials$`, a.iAmLoggedInWithCredentials)
 	ctx.Step(`^I have a consent with the following details$`, a.iHaveAConsentWithTheFollowingDetails)
 	ctx.Step(`^I request consent approval with id "([^"]*)"$`, a.iRequestConsentApprovalWithId)
+	ctx.Step(`^I request consent approval with id "([^"]*)" and scopes "([^"]*)"$`, a.iRequestConsentApprovalWithIdAndScopes)
+	ctx.Step(`^The consent should be approved for scopes "([^"]*)" of the requested "([^"]*)"$`, a.theConsentShouldBeApprovedForScopesOfTheRequested)
 	ctx.Step(`^The consent should be approved$`, a.theConsentShouldBeApproved)
+	ctx.Step(`^The consent should be approved with a fragment response$`, a.theConsentShouldBeApprovedWithAFragmentResponse)
+	ctx.Step(`^The id token should tell how I authenticated$`, a.theIdTokenShouldTellHowIAuthenticated)
 	ctx.Step(`^There are registered scopes with the following details$`, a.thereAreRegisteredScopesWithTheFollowingDetails)
 	ctx.Step(`^There is a client with the following details$`, a.thereIsAClientWithTheFollowingDetails)
 }
@@ -8,8 +8,8 @@ Feature: Approve consent
       | openid | your profile info  | sso                  |
       | email  | your default email | sso                  |
     And There is a client with the following details
-      | name | redirect_uris        | secret    | scopes       | client_type  | logo_url               |
-      | ride | https://www.google.com | my_secret | openid email | confidential | http://logo.client.com |
+      | name | redirect_uris          | secret    | scopes       | optional_scopes | client_type  | logo_url               | response_types     |
+      | ride | https://www.google.com | my_secret | openid email | email           | confidential | http://logo.client.com | code,code id_token |
     And I have a consent with the following details
       | id                                   | scope  | redirect_uri         | response_type | approved | state    | prompt  |
       | 48684fe2-43fa-46b8-ba6b-78cfc7196fb8 | openid | https://www.google.com | code          | false    | my_state | consent |
@@ -17,6 +17,32 @@ Feature: Approve consent
   Scenario: Consent is approved
     When I request consent approval with id "48684fe2-43fa-46b8-ba6b-78cfc7196fb8"
     Then The consent should be approved
+  @success
+  Scenario: Hybrid consent is approved with a fragment response
+    Given I have a consent with the following details
+      | id                                   | scope  | redirect_uri           | response_type | approved | state    | prompt  | nonce    | response_mode |
+      | 0d0c7a4a-3f0e-4d35-9a63-2b1b0a2c6c11 | openid | https://www.google.com | code id_token | false    | my_state | consent | my_nonce | fragment      |
+    When I request consent approval with id "0d0c7a4a-3f0e-4d35-9a63-2b1b0a2c6c11"
+    Then The consent should be approved with a fragment response
+    And The id token should tell how I authenticated
+  @success
+  Scenario: Consent is approved without the optional scopes
+    Given I have a consent with the following details
+      | id                                   | scope        | redirect_uri           | response_type | approved | state    | prompt  |
+      | 5b0a1f4e-8c2d-4a57-9d0e-2f7c3b6e1a94 | openid email | https://www.google.com | code          | false    | my_state | consent |
+    When I request consent approval with id "5b0a1f4e-8c2d-4a57-9d0e-2f7c3b6e1a94" and scopes "openid"
+    Then The consent should be approved for scopes "openid" of the requested "openid email"
+  @failure
+  Scenario Outline: consent is not approved for the scopes
+    Given I have a consent with the following details
+      | id                                   | scope        | redirect_uri           | response_type | approved | state    | prompt  |
+      | 5b0a1f4e-8c2d-4a57-9d0e-2f7c3b6e1a94 | openid email | https://www.google.com | code          | false    | my_state | consent |
+    When I request consent approval with id "5b0a1f4e-8c2d-4a57-9d0e-2f7c3b6e1a94" and scopes "<scopes>"
+    Then Consent approval should fail with message "<message>"
+    Examples:
+      | scopes             | message                        |
+      | email              | required scope is not approved |
+      | openid email phone | invalid scope                  |
   @failure
   Scenario Outline: consent is not approved
     When I request consent approval with id "<consent_id>"
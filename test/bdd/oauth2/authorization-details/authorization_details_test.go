@@ -0,0 +1,300 @@
+package authorizationdetails
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sso/internal/constant"
+	"sso/internal/constant/errors/sqlcerr"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+	"sso/test"
+	"testing"
+
+	"github.com/cucumber/godog"
+	"github.com/golang-jwt/jwt/v4"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
+)
+
+type authorizationDetailsTest struct {
+	test.TestInstance
+	apiTest        src.ApiTest
+	resourceServer db.ResourceServer
+	client         db.Client
+	user           db.User
+	scope          *db.Scope
+	accessToken    string
+	consentID      string
+	issuedCode     string
+}
+
+func TestAuthorizationDetails(t *testing.T) {
+	a := &authorizationDetailsTest{}
+	a.TestInstance = test.Initiate("../../../../")
+	a.apiTest.InitializeServer(a.Server)
+	a.apiTest.InitializeTest(t, "authorization details test", "features/authorization_details.feature", a.InitializeScenario)
+}
+
+func (a *authorizationDetailsTest) theResourceServerRegisteredTheAuthorizationDetailType(resourceServer, detailType string) error {
+	var err error
+	if a.resourceServer, err = a.DB.CreateResourceServer(context.Background(), resourceServer); err != nil {
+		return err
+	}
+	_, err = a.DB.CreateAuthorizationDetailType(context.Background(), db.CreateAuthorizationDetailTypeParams{
+		Type:             detailType,
+		Description:      "pay an amount from your wallet",
+		ResourceServerID: a.resourceServer.ID,
+	})
+	return err
+}
+
+func (a *authorizationDetailsTest) aClientIsRegisteredOnTheSystem() error {
+	scope, err := a.DB.CreateScope(context.Background(), db.CreateScopeParams{
+		Name:        "openid",
+		Description: "the identity of the user",
+	})
+	if err == nil {
+		a.scope = &scope
+	} else if !sqlcerr.IsUniqueViolation(err) {
+		return err
+	}
+
+	secret := utils.GenerateRandomString(25, true)
+	if a.client, err = a.DB.CreateClient(context.Background(), db.CreateClientParams{
+		RedirectUris: utils.ArrayToString([]string{"https://www.google.com"}),
+		Name:         "google",
+		Scopes:       "openid",
+		ClientType:   "confidential",
+		Secret:       utils.HashSecret(secret),
+		LogoUrl:      "https://www.google.com/images/errors/robot.png",
+	}); err != nil {
+		return err
+	}
+	a.client.Secret = secret
+	return nil
+}
+
+func (a *authorizationDetailsTest) iAmLoggedInAsAUser() error {
+	var err error
+	hash, err := utils.HashAndSalt(context.Background(), []byte("password"), a.Logger)
+	if err != nil {
+		return err
+	}
+	if a.user, err = a.DB.CreateUser(context.Background(), db.CreateUserParams{
+		Email:      utils.StringOrNull("yonaskemon@gmail.com"),
+		Password:   hash,
+		FirstName:  "someone",
+		MiddleName: "someone",
+		LastName:   "someone",
+		Phone:      "0987654321",
+	}); err != nil {
+		return err
+	}
+
+	login := src.ApiTest{URL: "/v1/login", Method: http.MethodPost}
+	login.InitializeServer(a.Server)
+	login.SetHeader("Content-Type", "application/json")
+	login.SetBodyMap(map[string]interface{}{
+		"email":    a.user.Email.String,
+		"password": "password",
+	})
+	login.SendRequest()
+	if err := login.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+	return login.UnmarshalResponseBodyPath("data.access_token", &a.accessToken)
+}
+
+func (a *authorizationDetailsTest) theClientRequestsAuthorizationWithTheAuthorizationDetails(details *godog.Table) error {
+	rows, err := a.apiTest.ReadRowsToMapString(details)
+	if err != nil {
+		return err
+	}
+	authorizationDetails, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+
+	a.apiTest.URL = "/v1/oauth/authorize"
+	a.apiTest.Method = http.MethodGet
+	a.apiTest.SetQueryParam("client_id", a.client.ID.String())
+	a.apiTest.SetQueryParam("response_type", constant.ResponseTypeCode)
+	a.apiTest.SetQueryParam("redirect_uri", "https://www.google.com")
+	a.apiTest.SetQueryParam("scope", "openid")
+	a.apiTest.SetQueryParam("state", "state")
+	a.apiTest.SetQueryParam("prompt", constant.PromptConsent)
+	a.apiTest.SetQueryParam("authorization_details", string(authorizationDetails))
+	a.apiTest.SendRequest()
+	if err := a.apiTest.AssertStatusCode(http.StatusFound); err != nil {
+		return err
+	}
+
+	location, err := url.Parse(a.apiTest.Response.Header().Get("Location"))
+	if err != nil {
+		return err
+	}
+	a.consentID = location.Query().Get("consentId")
+	return nil
+}
+
+func (a *authorizationDetailsTest) theConsentShouldShowTheAuthorizationDetailsOfTypeWithAmount(detailType, amount string) error {
+	if a.consentID == "" {
+		return fmt.Errorf("expected to be redirected to the consent, got %s", a.apiTest.Response.Header().Get("Location"))
+	}
+
+	consent := src.ApiTest{URL: "/v1/oauth/consent/" + a.consentID, Method: http.MethodGet}
+	consent.InitializeServer(a.Server)
+	consent.SetHeader("Authorization", "Bearer "+a.accessToken)
+	consent.SendRequest()
+	if err := consent.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+
+	var res dto.ConsentResponse
+	if err := consent.UnmarshalResponseBodyPath("data", &res); err != nil {
+		return err
+	}
+	if err := assertAuthorizationDetails(res.AuthorizationDetails, detailType, amount); err != nil {
+		return err
+	}
+	if len(res.AuthorizationDetailTypes) != 1 {
+		return fmt.Errorf("expected the consent to describe 1 authorization detail type, got %d", len(res.AuthorizationDetailTypes))
+	}
+	if err := consent.AssertEqual(res.AuthorizationDetailTypes[0].Type, detailType); err != nil {
+		return err
+	}
+	return consent.AssertEqual(res.AuthorizationDetailTypes[0].ResourceServerName, a.resourceServer.Name)
+}
+
+func (a *authorizationDetailsTest) iApproveTheConsent() error {
+	approve := src.ApiTest{URL: "/v1/oauth/approveConsent", Method: http.MethodPost}
+	approve.InitializeServer(a.Server)
+	approve.SetHeader("Content-Type", "application/json")
+	approve.SetHeader("Authorization", "Bearer "+a.accessToken)
+	approve.SetBodyMap(map[string]interface{}{
+		"consent_id": a.consentID,
+	})
+	approve.AddCookie(http.Cookie{
+		Name:  "opbs",
+		Value: utils.GenerateNewOPBS(),
+	})
+	approve.SendRequest()
+	if err := approve.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+
+	var redirect dto.RedirectResponse
+	if err := approve.UnmarshalResponseBodyPath("data", &redirect); err != nil {
+		return err
+	}
+	redirectURL, err := url.Parse(redirect.Location)
+	if err != nil {
+		return err
+	}
+	if a.issuedCode = redirectURL.Query().Get("code"); a.issuedCode == "" {
+		return fmt.Errorf("expected a code on the redirect, got %s", redirect.Location)
+	}
+	return nil
+}
+
+func (a *authorizationDetailsTest) theClientExchangesTheIssuedCodeForToken() error {
+	a.apiTest.URL = "/v1/oauth/token"
+	a.apiTest.Method = http.MethodPost
+	a.apiTest.QueryParams = nil
+	a.apiTest.SetHeader("Content-Type", "application/json")
+	a.apiTest.SetHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(a.client.ID.String()+":"+a.client.Secret)))
+	a.apiTest.SetBodyMap(map[string]interface{}{
+		"grant_type":   constant.AuthorizationCode,
+		"code":         a.issuedCode,
+		"redirect_uri": "https://www.google.com",
+	})
+	a.apiTest.SendRequest()
+	return nil
+}
+
+func (a *authorizationDetailsTest) theAccessTokenShouldCarryTheAuthorizationDetailsOfTypeWithAmount(detailType, amount string) error {
+	if err := a.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+
+	var tokenResponse dto.TokenResponse
+	if err := a.apiTest.UnmarshalResponseBodyPath("data", &tokenResponse); err != nil {
+		return err
+	}
+	if err := assertAuthorizationDetails(tokenResponse.AuthorizationDetails, detailType, amount); err != nil {
+		return err
+	}
+
+	var claims dto.AccessToken
+	if _, _, err := new(jwt.Parser).ParseUnverified(tokenResponse.AccessToken, &claims); err != nil {
+		return err
+	}
+	return assertAuthorizationDetails(claims.AuthorizationDetails, detailType, amount)
+}
+
+func (a *authorizationDetailsTest) iShouldBeRedirectedToTheClientWithError(errorCode string) error {
+	if err := a.apiTest.AssertStatusCode(http.StatusFound); err != nil {
+		return err
+	}
+
+	location, err := url.Parse(a.apiTest.Response.Header().Get("Location"))
+	if err != nil {
+		return err
+	}
+	if err := a.apiTest.AssertEqual(location.Scheme+"://"+location.Host, "https://www.google.com"); err != nil {
+		return err
+	}
+	if err := a.apiTest.AssertEqual(location.Query().Get("error"), errorCode); err != nil {
+		return err
+	}
+	return a.apiTest.AssertEqual(location.Query().Get("state"), "state")
+}
+
+func assertAuthorizationDetails(details []dto.AuthorizationDetail, detailType, amount string) error {
+	if len(details) != 1 {
+		return fmt.Errorf("expected 1 authorization detail, got %d", len(details))
+	}
+	if details[0].Type() != detailType {
+		return fmt.Errorf("expected authorization detail of type %s, got %s", detailType, details[0].Type())
+	}
+	if details[0]["amount"] != amount {
+		return fmt.Errorf("expected authorization detail with amount %s, got %v", amount, details[0]["amount"])
+	}
+	return nil
+}
+
+func (a *authorizationDetailsTest) InitializeScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		a.consentID = ""
+		a.issuedCode = ""
+		a.apiTest.QueryParams = nil
+		return ctx, nil
+	})
+
+	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		_, _ = a.Conn.Exec(ctx, "Delete from auth_histories where true")
+		_, _ = a.Conn.Exec(ctx, "Delete from refresh_tokens where true")
+		_, _ = a.DB.DeleteUser(context.Background(), a.user.ID)
+		_, _ = a.DB.DeleteClient(context.Background(), a.client.ID)
+		_, _ = a.DB.DeleteResourceServer(context.Background(), a.resourceServer.ID)
+		if a.scope != nil {
+			_, _ = a.DB.DeleteScope(context.Background(), a.scope.Name)
+			a.scope = nil
+		}
+		return ctx, nil
+	})
+
+	ctx.Step(`^The resource server "([^"]*)" registered the authorization detail type "([^"]*)"$`, a.theResourceServerRegisteredTheAuthorizationDetailType)
+	ctx.Step(`^A client is registered on the system$`, a.aClientIsRegisteredOnTheSystem)
+	ctx.Step(`^I am logged in as a user$`, a.iAmLoggedInAsAUser)
+	ctx.Step(`^The client requests authorization with the authorization details:$`, a.theClientRequestsAuthorizationWithTheAuthorizationDetails)
+	ctx.Step(`^The consent should show the authorization details of type "([^"]*)" with amount "([^"]*)"$`, a.theConsentShouldShowTheAuthorizationDetailsOfTypeWithAmount)
+	ctx.Step(`^I approve the consent$`, a.iApproveTheConsent)
+	ctx.Step(`^The client exchanges the issued code for token$`, a.theClientExchangesTheIssuedCodeForToken)
+	ctx.Step(`^The access token should carry the authorization details of type "([^"]*)" with amount "([^"]*)"$`, a.theAccessTokenShouldCarryTheAuthorizationDetailsOfTypeWithAmount)
+	ctx.Step(`^I should be redirected to the client with error "([^"]*)"$`, a.iShouldBeRedirectedToTheClientWithError)
+}
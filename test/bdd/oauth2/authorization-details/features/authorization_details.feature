@@ -0,0 +1,29 @@
+Feature: Rich Authorization Requests
+
+  As a client
+
+  I want to ask the user to approve specific actions through authorization details
+
+  So that the access token I get carries the actions the user approved.
+
+  Background: A resource server accepts payment authorization details
+    Given The resource server "rs-payments" registered the authorization detail type "payment"
+    And A client is registered on the system
+    And I am logged in as a user
+
+  @success
+  Scenario: The approved authorization details are carried by the access token
+    When The client requests authorization with the authorization details:
+      | type    | amount | currency |
+      | payment | 10     | ETB      |
+    Then The consent should show the authorization details of type "payment" with amount "10"
+    When I approve the consent
+    And The client exchanges the issued code for token
+    Then The access token should carry the authorization details of type "payment" with amount "10"
+
+  @failure
+  Scenario: Authorization details of an unregistered type are rejected
+    When The client requests authorization with the authorization details:
+      | type     | amount | currency |
+      | transfer | 10     | ETB      |
+    Then I should be redirected to the client with error "invalid_authorization_details"
@@ -61,6 +61,7 @@ func (a *authorizationTest) iHaveTheFollowingParameters(params *godog.Table) err
 	a.apiTest.SetQueryParam("scope", a.requestParam.Scope)
 	a.apiTest.SetQueryParam("redirect_uri", a.requestParam.RedirectURI)
 	a.apiTest.SetQueryParam("prompt", a.requestParam.Prompt)
+	a.apiTest.SetQueryParam("nonce", a.requestParam.Nonce)
 
 	return nil
 }
@@ -150,6 +151,41 @@ func (a *authorizationTest) iShouldBeRedirectedToWithTheFollowingErrorParameters
 	return nil
 }
 
+func (a *authorizationTest) iShouldBeRedirectedToWithTheFollowingFragmentErrorParameters(redirect_uri string, rspParams *godog.Table) error {
+	if err := a.apiTest.AssertStatusCode(http.StatusFound); err != nil {
+		return err
+	}
+	param, err := a.apiTest.ReadRow(rspParams, nil, false)
+	if err != nil {
+		return err
+	}
+	var rspParamsFragment authRspQueryParams
+	err = a.apiTest.UnmarshalJSONAt([]byte(param), "", &rspParamsFragment)
+	if err != nil {
+		return err
+	}
+
+	location := a.apiTest.Response.Header().Get("Location")
+	parsedLocation, err := url.Parse(location)
+	if err != nil {
+		return err
+	}
+
+	rawPath := fmt.Sprintf("%s://%s%s", parsedLocation.Scheme, parsedLocation.Host, parsedLocation.Path)
+	if err := a.apiTest.AssertEqual(rawPath, redirect_uri); err != nil {
+		return err
+	}
+
+	fragment, err := url.ParseQuery(parsedLocation.Fragment)
+	if err != nil {
+		return err
+	}
+	if err := a.apiTest.AssertEqual(fragment.Get("error"), rspParamsFragment.Error); err != nil {
+		return err
+	}
+	return a.apiTest.AssertEqual(fragment.Get("state"), rspParamsFragment.State)
+}
+
 func (a *authorizationTest) thereIsRegisteredScopeWithFollowingDetails(scopeForm *godog.Table) error {
 	scopeValue := dto.Scope{}
 	body, err := a.apiTest.ReadRow(scopeForm, nil, false)
@@ -195,6 +231,7 @@ func (a *authorizationTest) InitializeScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^I have the following parameters:$`, a.iHaveTheFollowingParameters)
 	ctx.Step(`^I send a POST request$`, a.iSendAPOSTRequest)
 	ctx.Step(`^I should be redirected to "([^"]*)" with the following error parameters:$`, a.iShouldBeRedirectedToWithTheFollowingErrorParameters)
+	ctx.Step(`^I should be redirected to "([^"]*)" with the following fragment error parameters:$`, a.iShouldBeRedirectedToWithTheFollowingFragmentErrorParameters)
 	ctx.Step(`^I should be redirected to "([^"]*)" with the following success parameters:$`, a.iShouldBeRedirectedToWithTheFollowingSuccessParameters)
 	ctx.Step(`^I have the following parameters with invalid client:$`, a.iHaveTheFollowingParametersWithInvalidClient)
 	ctx.Step(`^there is registered scope with following details:$`, a.thereIsRegisteredScopeWithFollowingDetails)
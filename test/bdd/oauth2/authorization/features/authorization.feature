@@ -19,8 +19,8 @@ Feature: obtaining authorization
             | consentId   | state   |
             | <consentId> | <state> |
         Examples:
-            | response_type | client_id                            | redirect_uri            | scope  | state | consentId | state | consent_uri             | prompt |
-            | code          | ca6fed0e-6120-4c9c-be6f-b6dfdf0b3c58 | https://www.google.com/ | openid | 1234  | 1234      | 1234  | https://www.google.com/ | none   |
+            | response_type | client_id                            | redirect_uri            | scope  | state | consentId | state | consent_uri             | prompt  |
+            | code          | ca6fed0e-6120-4c9c-be6f-b6dfdf0b3c58 | https://www.google.com/ | openid | 1234  | 1234      | 1234  | https://www.google.com/ | consent |
 
     Scenario Outline: Unable to Obtain Authorization
         Given I have the following parameters:
@@ -37,6 +37,22 @@ Feature: obtaining authorization
             | authorization_code | ca6fed0e-6120-4c9c-be6f-b6dfdf0b3c58 | https://www.google.com/   | openid   | 1234  | consent      | invalid_request      | must be a valid value.    |
             | code               | ca6fed0e-6120-4c9c-be6f-b6dfdf0b3c58 | https://www.google.com/   | openid   | 1234  |              | invalid_request      | prompt is required        |
             | code               | ca6fed0e-6120-4c9c-be6f-b6dfdf0b3c58 | https://www.google.com/   | openid   | 1234  | none_consent | invalid_request      | invalid prompt value      |
+            | id_token           | ca6fed0e-6120-4c9c-be6f-b6dfdf0b3c58 | https://www.google.com/   | openid   | 1234  | consent      | invalid_request      | nonce is required         |
+            | token              | ca6fed0e-6120-4c9c-be6f-b6dfdf0b3c58 | https://www.google.com/   | openid   | 1234  | consent      | invalid_request      | unsupported response_type |
+
+    Scenario Outline: Response type not allowed for the client
+        Given I have the following parameters:
+            | response_type   | client_id   | redirect_uri   | scope   | state   | prompt   | nonce   |
+            | <response_type> | <client_id> | <redirect_uri> | <scope> | <state> | <prompt> | <nonce> |
+
+        When I send a POST request
+        Then I should be redirected to "<redirect_uri>" with the following fragment error parameters:
+            | error   | state   |
+            | <error> | <state> |
+        Examples:
+            | response_type | client_id                            | redirect_uri            | scope  | state | prompt  | nonce    | error               |
+            | id_token      | ca6fed0e-6120-4c9c-be6f-b6dfdf0b3c58 | https://www.google.com/ | openid | 1234  | consent | my_nonce | unauthorized_client |
+            | token code    | ca6fed0e-6120-4c9c-be6f-b6dfdf0b3c58 | https://www.google.com/ | openid | 1234  | consent | my_nonce | unauthorized_client |
 
     Scenario Outline: Invalid Client
         Given I have the following parameters with invalid client:
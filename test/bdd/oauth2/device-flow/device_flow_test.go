@@ -0,0 +1,204 @@
+package deviceflow
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sso/internal/constant"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+	"sso/test"
+	"testing"
+
+	"github.com/cucumber/godog"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
+)
+
+type deviceFlowTest struct {
+	test.TestInstance
+	apiTest             src.ApiTest
+	user                db.User
+	client              db.Client
+	deviceAuthorization dto.DeviceAuthorizationResponse
+	consentID           string
+}
+
+func TestDeviceFlow(t *testing.T) {
+	d := &deviceFlowTest{}
+	d.TestInstance = test.Initiate("../../../../")
+	d.apiTest.InitializeServer(d.Server)
+	d.apiTest.InitializeTest(t, "device authorization flow", "features/device_flow.feature", d.InitializeScenario)
+}
+
+func (d *deviceFlowTest) iAmLoggedInWithCredentials(credentials *godog.Table) error {
+	var err error
+	d.user, err = d.Authenticate(credentials)
+	return err
+}
+
+func (d *deviceFlowTest) aPublicClientIsRegisteredOnTheSystemWithScopes(scopes string) error {
+	var err error
+	d.client, err = d.DB.CreateClient(context.Background(), db.CreateClientParams{
+		RedirectUris: utils.ArrayToString([]string{"https://www.google.com"}),
+		Name:         "kiosk",
+		Scopes:       scopes,
+		ClientType:   constant.PublicClient,
+		Secret:       utils.GenerateRandomString(25, true),
+		LogoUrl:      "https://www.google.com/images/errors/robot.png",
+		GrantTypes:   constant.DeviceCode,
+	})
+	return err
+}
+
+func (d *deviceFlowTest) sendForm(path string, form url.Values) {
+	d.apiTest.ResetResponse()
+	d.apiTest.URL = path
+	d.apiTest.Method = http.MethodPost
+	d.apiTest.Body = form.Encode()
+	d.apiTest.SetHeader("Content-Type", "application/x-www-form-urlencoded")
+	d.apiTest.SetHeader("Authorization", "")
+	d.apiTest.SendRequest()
+}
+
+func (d *deviceFlowTest) sendConsentRequest(path string, body map[string]interface{}) error {
+	d.apiTest.ResetResponse()
+	d.apiTest.URL = path
+	d.apiTest.Method = http.MethodPost
+	d.apiTest.Body = ""
+	d.apiTest.SetHeader("Content-Type", "application/json")
+	d.apiTest.SetHeader("Authorization", "Bearer "+d.AccessToken)
+	d.apiTest.SetBodyMap(body)
+	d.apiTest.AddCookie(http.Cookie{
+		Name:  "opbs",
+		Value: utils.GenerateNewOPBS(),
+	})
+	d.apiTest.SendRequest()
+	return d.apiTest.AssertStatusCode(http.StatusOK)
+}
+
+func (d *deviceFlowTest) theDeviceRequestedAuthorization() error {
+	d.sendForm("/v1/oauth/device_authorization", url.Values{"client_id": {d.client.ID.String()}})
+	if err := d.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+	return d.apiTest.UnmarshalResponseBody(&d.deviceAuthorization)
+}
+
+func (d *deviceFlowTest) theDeviceShouldGetADeviceCodeAndAUserCode() error {
+	if err := d.apiTest.AssertColumnExists("device_code"); err != nil {
+		return err
+	}
+	if err := d.apiTest.AssertEqual(regexp.MustCompile(`^[B-Z]{4}-[B-Z]{4}$`).MatchString(d.deviceAuthorization.UserCode), true); err != nil {
+		return err
+	}
+	verificationURI, err := url.Parse(d.deviceAuthorization.VerificationURIComplete)
+	if err != nil {
+		return err
+	}
+	if err := d.apiTest.AssertEqual(verificationURI.Query().Get("user_code"), d.deviceAuthorization.UserCode); err != nil {
+		return err
+	}
+	return d.apiTest.AssertEqual(d.deviceAuthorization.Interval, 5)
+}
+
+func (d *deviceFlowTest) theDevicePollsForToken() error {
+	d.sendForm("/v1/oauth/token", url.Values{
+		"grant_type":  {constant.DeviceCode},
+		"device_code": {d.deviceAuthorization.DeviceCode},
+		"client_id":   {d.client.ID.String()},
+	})
+	return nil
+}
+
+func (d *deviceFlowTest) iVerifyTheDeviceWithItsUserCode() error {
+	if err := d.sendConsentRequest("/v1/oauth/verifyDevice", map[string]interface{}{
+		"user_code": d.deviceAuthorization.UserCode,
+	}); err != nil {
+		return err
+	}
+
+	var data dto.RedirectResponse
+	if err := d.apiTest.UnmarshalResponseBodyPath("data", &data); err != nil {
+		return err
+	}
+	consentURL, err := url.Parse(data.Location)
+	if err != nil {
+		return err
+	}
+	d.consentID = consentURL.Query().Get("consentId")
+	return d.apiTest.AssertEqual(d.consentID != "", true)
+}
+
+func (d *deviceFlowTest) iVerifyTheDeviceWithItsUserCodeWithoutLoggingIn() error {
+	d.apiTest.ResetResponse()
+	d.apiTest.URL = "/v1/oauth/verifyDevice"
+	d.apiTest.Method = http.MethodPost
+	d.apiTest.Body = ""
+	d.apiTest.SetHeader("Content-Type", "application/json")
+	d.apiTest.SetHeader("Authorization", "")
+	d.apiTest.SetBodyMap(map[string]interface{}{
+		"user_code": d.deviceAuthorization.UserCode,
+	})
+	d.apiTest.SendRequest()
+	return nil
+}
+
+func (d *deviceFlowTest) theVerificationShouldBeUnauthorized() error {
+	return d.apiTest.AssertStatusCode(http.StatusUnauthorized)
+}
+
+func (d *deviceFlowTest) iApproveTheDeviceConsent() error {
+	return d.sendConsentRequest("/v1/oauth/approveConsent", map[string]interface{}{
+		"consent_id": d.consentID,
+	})
+}
+
+func (d *deviceFlowTest) iRejectTheDeviceConsent() error {
+	return d.sendConsentRequest("/v1/oauth/rejectConsent", map[string]interface{}{
+		"consent_id":     d.consentID,
+		"failure_reason": "access_denied",
+	})
+}
+
+func (d *deviceFlowTest) pollingShouldFailWithMessage(message string) error {
+	return d.apiTest.AssertBodyColumn("error.message", message)
+}
+
+func (d *deviceFlowTest) tokenShouldSuccessfullyBeIssued() error {
+	if err := d.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+	if err := d.apiTest.AssertColumnExists("data.access_token"); err != nil {
+		return err
+	}
+	return d.apiTest.AssertColumnExists("data.id_token")
+}
+
+func (d *deviceFlowTest) InitializeScenario(ctx *godog.ScenarioContext) {
+	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		_, _ = d.Conn.Exec(ctx, "Delete from auth_histories where client_id = $1", d.client.ID)
+		_, _ = d.Conn.Exec(ctx, "Delete from refresh_tokens where client_id = $1", d.client.ID)
+		_ = d.CacheLayer.DeviceCacheLayer.DeleteDeviceAuthorization(ctx, dto.DeviceAuthorization{
+			DeviceCode: d.deviceAuthorization.DeviceCode,
+			UserCode:   d.deviceAuthorization.UserCode,
+		})
+		_, _ = d.DB.DeleteClient(ctx, d.client.ID)
+		_, _ = d.DB.DeleteUser(ctx, d.user.ID)
+		return ctx, nil
+	})
+
+	ctx.Step(`^I am logged in with credentials$`, d.iAmLoggedInWithCredentials)
+	ctx.Step(`^A public client is registered on the system with scopes "([^"]*)"$`, d.aPublicClientIsRegisteredOnTheSystemWithScopes)
+	ctx.Step(`^The device requested authorization$`, d.theDeviceRequestedAuthorization)
+	ctx.Step(`^The device should get a device code and a user code$`, d.theDeviceShouldGetADeviceCodeAndAUserCode)
+	ctx.Step(`^The device polls for token$`, d.theDevicePollsForToken)
+	ctx.Step(`^I verify the device with its user code$`, d.iVerifyTheDeviceWithItsUserCode)
+	ctx.Step(`^I verify the device with its user code without logging in$`, d.iVerifyTheDeviceWithItsUserCodeWithoutLoggingIn)
+	ctx.Step(`^The verification should be unauthorized$`, d.theVerificationShouldBeUnauthorized)
+	ctx.Step(`^I approve the device consent$`, d.iApproveTheDeviceConsent)
+	ctx.Step(`^I reject the device consent$`, d.iRejectTheDeviceConsent)
+	ctx.Step(`^Polling should fail with message "([^"]*)"$`, d.pollingShouldFailWithMessage)
+	ctx.Step(`^Token should successfully be issued$`, d.tokenShouldSuccessfullyBeIssued)
+}
@@ -0,0 +1,42 @@
+Feature: Device Authorization Flow
+
+  Background:
+    Given I am logged in with credentials
+      | email            | password |
+      | device@gmail.com | device   |
+    And A public client is registered on the system with scopes "openid"
+    And The device requested authorization
+
+  @success
+  Scenario: Device authorization is issued
+    Then The device should get a device code and a user code
+
+  @failure
+  Scenario: The device polls before the user approves
+    When The device polls for token
+    Then Polling should fail with message "authorization_pending"
+
+  @failure
+  Scenario: The device polls too fast
+    When The device polls for token
+    And The device polls for token
+    Then Polling should fail with message "slow_down"
+
+  @success
+  Scenario: The user approves the device
+    When I verify the device with its user code
+    And I approve the device consent
+    And The device polls for token
+    Then Token should successfully be issued
+
+  @failure
+  Scenario: The user denies the device
+    When I verify the device with its user code
+    And I reject the device consent
+    And The device polls for token
+    Then Polling should fail with message "access_denied"
+
+  @failure
+  Scenario: The device is verified without logging in
+    When I verify the device with its user code without logging in
+    Then The verification should be unauthorized
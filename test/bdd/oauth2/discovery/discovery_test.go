@@ -0,0 +1,175 @@
+package discovery
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+	"sso/test"
+	"strings"
+	"testing"
+
+	"github.com/cucumber/godog"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
+)
+
+type discoveryTest struct {
+	test.TestInstance
+	apiTest src.ApiTest
+	scopes  []db.Scope
+}
+
+func TestDiscovery(t *testing.T) {
+	d := &discoveryTest{}
+	d.TestInstance = test.Initiate("../../../../")
+	d.apiTest.InitializeServer(d.Server)
+
+	d.apiTest.InitializeTest(t, "OpenID Connect discovery test", "features/discovery.feature", d.InitializeScenario)
+}
+
+func (d *discoveryTest) theFollowingScopesAreRegisteredOnTheSystem(scopes *godog.Table) error {
+	scopesData, err := d.apiTest.ReadRows(scopes, nil, false)
+	if err != nil {
+		return err
+	}
+	var scopesStruct []dto.Scope
+	if err := d.apiTest.UnmarshalJSONAt([]byte(scopesData), "", &scopesStruct); err != nil {
+		return err
+	}
+	for _, scope := range scopesStruct {
+		savedScope, err := d.DB.CreateScope(context.Background(), db.CreateScopeParams{
+			Name:        scope.Name,
+			Description: scope.Description,
+			ResourceServerName: sql.NullString{
+				String: scope.ResourceServerName,
+				Valid:  true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		d.scopes = append(d.scopes, savedScope)
+	}
+	return nil
+}
+
+func (d *discoveryTest) iRequestTheOpenidConfiguration() error {
+	d.apiTest.URL = "/.well-known/openid-configuration"
+	d.apiTest.Method = http.MethodGet
+	d.apiTest.SendRequest()
+	return nil
+}
+
+func (d *discoveryTest) iShouldGetTheOpenidConfigurationWithTheFollowingScopes(scopes *godog.Table) error {
+	if err := d.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+
+	expectedScopes, err := d.apiTest.ReadCell(scopes, "scopes", &src.Type{Kind: src.Array})
+	if err != nil {
+		return err
+	}
+
+	var configuration dto.OpenIDConfiguration
+	if err := d.apiTest.UnmarshalResponseBody(&configuration); err != nil {
+		return err
+	}
+
+	for _, scope := range expectedScopes.([]string) {
+		if !utils.ContainsValue(scope, configuration.ScopesSupported) {
+			return fmt.Errorf("expected scope %s to be supported", scope)
+		}
+	}
+
+	for _, endpoint := range []string{
+		configuration.Issuer,
+		configuration.AuthorizationEndpoint,
+		configuration.TokenEndpoint,
+		configuration.UserInfoEndpoint,
+		configuration.JWKSURI,
+		configuration.IntrospectionEndpoint,
+		configuration.RevocationEndpoint,
+		configuration.CheckSessionIframe,
+	} {
+		if endpoint == "" {
+			return fmt.Errorf("expected all endpoints to be advertised")
+		}
+	}
+
+	return nil
+}
+
+func (d *discoveryTest) iRequestTheJsonWebKeySet() error {
+	d.apiTest.URL = "/v1/oauth/jwks"
+	d.apiTest.Method = http.MethodGet
+	d.apiTest.SendRequest()
+	return nil
+}
+
+func (d *discoveryTest) iShouldGetTheSigningKeys() error {
+	if err := d.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+
+	var jwks dto.JWKS
+	if err := d.apiTest.UnmarshalResponseBody(&jwks); err != nil {
+		return err
+	}
+
+	if len(jwks.Keys) == 0 {
+		return fmt.Errorf("expected at least one signing key")
+	}
+
+	for _, key := range jwks.Keys {
+		if key.Kid == "" || key.Kty == "" {
+			return fmt.Errorf("expected keys to have kid and kty")
+		}
+	}
+
+	return nil
+}
+
+func (d *discoveryTest) iRequestTheCheckSessionIframe() error {
+	d.apiTest.URL = "/v1/oauth/check_session"
+	d.apiTest.Method = http.MethodGet
+	d.apiTest.SendRequest()
+	return nil
+}
+
+func (d *discoveryTest) iShouldGetThePageCheckingTheSessionState() error {
+	if err := d.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+	if contentType := d.apiTest.Response.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/html") {
+		return fmt.Errorf("expected an html page, got %s", contentType)
+	}
+	if !strings.Contains(string(d.apiTest.ResponseBody), "postMessage") {
+		return fmt.Errorf("expected the page to answer the posted session_state")
+	}
+	return nil
+}
+
+func (d *discoveryTest) thePageShouldNotBeCached() error {
+	return d.apiTest.AssertEqual(d.apiTest.Response.Header().Get("Cache-Control"), "no-store")
+}
+
+func (d *discoveryTest) InitializeScenario(ctx *godog.ScenarioContext) {
+	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		for _, scope := range d.scopes {
+			_, _ = d.DB.DeleteScope(ctx, scope.Name)
+		}
+		d.scopes = nil
+		return ctx, nil
+	})
+	ctx.Step(`^the following scopes are registered on the system$`, d.theFollowingScopesAreRegisteredOnTheSystem)
+	ctx.Step(`^I request the openid configuration$`, d.iRequestTheOpenidConfiguration)
+	ctx.Step(`^I should get the openid configuration with the following scopes$`, d.iShouldGetTheOpenidConfigurationWithTheFollowingScopes)
+	ctx.Step(`^I request the json web key set$`, d.iRequestTheJsonWebKeySet)
+	ctx.Step(`^I should get the signing keys$`, d.iShouldGetTheSigningKeys)
+	ctx.Step(`^I request the check session iframe$`, d.iRequestTheCheckSessionIframe)
+	ctx.Step(`^I should get the page checking the session state$`, d.iShouldGetThePageCheckingTheSessionState)
+	ctx.Step(`^the page should not be cached$`, d.thePageShouldNotBeCached)
+}
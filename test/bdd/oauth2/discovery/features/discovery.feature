@@ -0,0 +1,26 @@
+Feature: OpenID Connect Discovery
+
+    As a Client
+    I want to fetch the OpenID provider metadata and its public keys
+    So that i can configure myself and verify the issued tokens
+
+    Background:
+        Given the following scopes are registered on the system
+            | name          | description                 | resource_server_name |
+            | profile       | access your profile         | sso                  |
+            | balance.read  | read your wallet balance    | wallet               |
+
+    Scenario: Successful discovery request
+        When I request the openid configuration
+        Then I should get the openid configuration with the following scopes
+            | scopes                               |
+            | openid,profile,balance.read          |
+
+    Scenario: Successful jwks request
+        When I request the json web key set
+        Then I should get the signing keys
+
+    Scenario: Successful check session iframe request
+        When I request the check session iframe
+        Then I should get the page checking the session state
+        And the page should not be cached
@@ -17,3 +17,8 @@ Feature: Get Authorized Clients
   Scenario: I get authorized clients
     When I request to get authorized clients
     Then I should get the list of authorized clients
+
+  @failure
+  Scenario: An access token issued to a client is not a session of the sso
+    When I request to get authorized clients with an access token issued to a client
+    Then The request should be unauthorized
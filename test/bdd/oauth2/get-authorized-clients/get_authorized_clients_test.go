@@ -102,6 +102,20 @@ func (g *GetAuthorizedClientsTest) iRequestToGetAuthorizedClients() error {
 	return nil
 }
 
+func (g *GetAuthorizedClientsTest) iRequestToGetAuthorizedClientsWithAnAccessTokenIssuedToAClient() error {
+	accessToken, err := g.PlatformLayer.Token.GenerateAccessTokenForClient(context.Background(), g.user.ID.String(), g.clients[0].ID.String(), g.clients[0].Scopes, dto.AccessTokenOptions{}, time.Hour)
+	if err != nil {
+		return err
+	}
+	g.apiTest.SetHeader("Authorization", "Bearer "+accessToken)
+	g.apiTest.SendRequest()
+	return nil
+}
+
+func (g *GetAuthorizedClientsTest) theRequestShouldBeUnauthorized() error {
+	return g.apiTest.AssertStatusCode(http.StatusUnauthorized)
+}
+
 func (g *GetAuthorizedClientsTest) iShouldGetTheListOfAuthorizedClients() error {
 	if err := g.apiTest.AssertStatusCode(http.StatusOK); err != nil {
 		return err
@@ -144,4 +158,6 @@ func (g *GetAuthorizedClientsTest) InitializeScenario(ctx *godog.ScenarioContext
 	ctx.Step(`^I have given authorization for the following clients$`, g.iHaveGivenAuthorizationForTheFollowingClients)
 	ctx.Step(`^I request to get authorized clients$`, g.iRequestToGetAuthorizedClients)
 	ctx.Step(`^I should get the list of authorized clients$`, g.iShouldGetTheListOfAuthorizedClients)
+	ctx.Step(`^I request to get authorized clients with an access token issued to a client$`, g.iRequestToGetAuthorizedClientsWithAnAccessTokenIssuedToAClient)
+	ctx.Step(`^The request should be unauthorized$`, g.theRequestShouldBeUnauthorized)
 }
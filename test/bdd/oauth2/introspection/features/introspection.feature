@@ -0,0 +1,56 @@
+Feature: Token Introspection
+
+  Background: A user has authorized a client
+    Given A user has authorized a client for scope "openid profile email"
+
+  @success
+  Scenario Outline: Access token is active
+    Given The client has an access token for scope "openid profile"
+    When The resource server introspects the token with hint "<hint>"
+    Then The token should be active with scope "openid profile"
+    Examples:
+      | hint          |
+      |               |
+      | access_token  |
+      | refresh_token |
+
+  @success
+  Scenario: Refresh token is active
+    Given The client has a refresh token for scope "profile" that expires in "24h"
+    When The resource server introspects the token with hint "refresh_token"
+    Then The token should be active with scope "profile"
+
+  @success
+  Scenario Outline: Token is inactive
+    Given The client has the token "<token>"
+    When The resource server introspects the token with hint ""
+    Then The token should be inactive
+    Examples:
+      | token                                 |
+      | eyJhbGciOiJQUzUxMiIsInR5cCI6IkpXVCJ9. |
+      | not-a-token                           |
+
+  @success
+  Scenario: Expired refresh token is inactive
+    Given The client has a refresh token for scope "profile" that expires in "-1h"
+    When The resource server introspects the token with hint "refresh_token"
+    Then The token should be inactive
+
+  @success
+  Scenario: Refresh token unused for longer than the idle lifetime of the client is inactive
+    Given The client has a refresh token for scope "profile" that expires in "24h"
+    And The client has a refresh token idle lifetime of 60 seconds
+    And The refresh token was last used "2h" ago
+    When The resource server introspects the token with hint "refresh_token"
+    Then The token should be inactive
+
+  @success
+  Scenario Outline: Token of a user who is no longer active is inactive
+    Given The client has an access token for scope "profile"
+    And The user is "<status>"
+    When The resource server introspects the token with hint ""
+    Then The token should be inactive
+    Examples:
+      | status   |
+      | INACTIVE |
+      | PENDING  |
@@ -0,0 +1,201 @@
+package introspection
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"net/http"
+	"sso/internal/constant"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+	"sso/test"
+	"testing"
+	"time"
+
+	"github.com/cucumber/godog"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
+)
+
+type introspectionTest struct {
+	test.TestInstance
+	apiTest        src.ApiTest
+	user           db.User
+	client         db.Client
+	resourceServer db.ResourceServer
+	token          string
+}
+
+func TestIntrospection(t *testing.T) {
+	i := &introspectionTest{}
+	i.TestInstance = test.Initiate("../../../../")
+	i.apiTest.InitializeServer(i.Server)
+	i.apiTest.InitializeTest(t, "token introspection", "features/introspection.feature", i.InitializeScenario)
+}
+
+func (i *introspectionTest) aUserHasAuthorizedAClientForScope(scope string) error {
+	var err error
+	if i.user, err = i.DB.CreateUser(context.Background(), db.CreateUserParams{
+		FirstName:  "john",
+		MiddleName: "doe",
+		LastName:   "smith",
+		Email:      sql.NullString{String: "introspection@gmail.com", Valid: true},
+		Phone:      "0912345678",
+		Gender:     "male",
+	}); err != nil {
+		return err
+	}
+
+	if i.client, err = i.DB.CreateClient(context.Background(), db.CreateClientParams{
+		RedirectUris: utils.ArrayToString([]string{"https://www.google.com"}),
+		Name:         "google",
+		Scopes:       scope,
+		ClientType:   constant.ConfidentialClient,
+		Secret:       utils.GenerateRandomString(25, true),
+		LogoUrl:      "https://www.google.com/images/errors/robot.png",
+	}); err != nil {
+		return err
+	}
+
+	if i.resourceServer, err = i.DB.CreateResourceServer(context.Background(), "introspection-rs"); err != nil {
+		return err
+	}
+
+	secret := utils.GenerateRandomString(25, true)
+	if _, err = i.DB.RotateResourceServerSecret(context.Background(), db.RotateResourceServerSecretParams{
+		ID:     i.resourceServer.ID,
+		Secret: utils.HashSecret(secret),
+	}); err != nil {
+		return err
+	}
+	i.resourceServer.Secret = secret
+
+	return nil
+}
+
+func (i *introspectionTest) theClientHasAnAccessTokenForScope(scope string) error {
+	var err error
+	i.token, err = i.PlatformLayer.Token.GenerateAccessTokenForClient(context.Background(),
+		i.user.ID.String(), i.client.ID.String(), scope, dto.AccessTokenOptions{}, time.Hour)
+	return err
+}
+
+func (i *introspectionTest) theClientHasARefreshTokenForScopeThatExpiresIn(scope, expiresIn string) error {
+	duration, err := time.ParseDuration(expiresIn)
+	if err != nil {
+		return err
+	}
+
+	i.token = utils.GenerateRandomString(25, false)
+	_, err = i.DB.SaveRefreshToken(context.Background(), db.SaveRefreshTokenParams{
+		ExpiresAt:    time.Now().Add(duration),
+		UserID:       i.user.ID,
+		Scope:        sql.NullString{String: scope, Valid: true},
+		RedirectUri:  sql.NullString{String: "https://www.google.com", Valid: true},
+		ClientID:     i.client.ID,
+		RefreshToken: i.token,
+		Code:         utils.GenerateRandomString(25, false),
+	})
+	return err
+}
+
+func (i *introspectionTest) theClientHasARefreshTokenIdleLifetimeOfSeconds(lifetime int) error {
+	_, err := i.Conn.Exec(context.Background(), "UPDATE clients SET refresh_token_idle_lifetime = $1 WHERE id = $2", lifetime, i.client.ID)
+	return err
+}
+
+func (i *introspectionTest) theRefreshTokenWasLastUsedAgo(ago string) error {
+	duration, err := time.ParseDuration(ago)
+	if err != nil {
+		return err
+	}
+
+	_, err = i.Conn.Exec(context.Background(), "UPDATE refresh_tokens SET updated_at = $1 WHERE refresh_token = $2", time.Now().Add(-duration), i.token)
+	return err
+}
+
+func (i *introspectionTest) theClientHasTheToken(token string) error {
+	i.token = token
+	return nil
+}
+
+func (i *introspectionTest) theUserIs(status string) error {
+	_, err := i.Conn.Exec(context.Background(), "UPDATE users SET status = $1 WHERE id = $2", status, i.user.ID)
+	return err
+}
+
+func (i *introspectionTest) theResourceServerIntrospectsTheTokenWithHint(hint string) error {
+	i.apiTest.SetHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString(
+		[]byte(i.resourceServer.ID.String()+":"+i.resourceServer.Secret)))
+	i.apiTest.SetHeader("Content-Type", "application/json")
+	i.apiTest.SetBodyValue("token", i.token)
+	if hint != "" {
+		i.apiTest.SetBodyValue("token_type_hint", hint)
+	}
+	i.apiTest.SendRequest()
+	return nil
+}
+
+func (i *introspectionTest) theTokenShouldBeActiveWithScope(scope string) error {
+	if err := i.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+
+	var resp dto.IntrospectionResponse
+	if err := i.apiTest.UnmarshalResponseBody(&resp); err != nil {
+		return err
+	}
+	if err := i.apiTest.AssertEqual(resp.Active, true); err != nil {
+		return err
+	}
+	if err := i.apiTest.AssertEqual(resp.Scope, scope); err != nil {
+		return err
+	}
+	if err := i.apiTest.AssertEqual(resp.ClientID, i.client.ID.String()); err != nil {
+		return err
+	}
+	return i.apiTest.AssertEqual(resp.Sub, i.user.ID.String())
+}
+
+func (i *introspectionTest) theTokenShouldBeInactive() error {
+	if err := i.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+
+	var resp dto.IntrospectionResponse
+	if err := i.apiTest.UnmarshalResponseBody(&resp); err != nil {
+		return err
+	}
+	if err := i.apiTest.AssertEqual(resp.Active, false); err != nil {
+		return err
+	}
+	return i.apiTest.AssertEqual(resp.Sub, "")
+}
+
+func (i *introspectionTest) InitializeScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		i.apiTest.URL = "/v1/oauth/introspect"
+		i.apiTest.Method = http.MethodPost
+		i.apiTest.Body = ""
+		return ctx, nil
+	})
+
+	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		_, _ = i.Conn.Exec(ctx, "DELETE FROM refresh_tokens WHERE client_id = $1", i.client.ID)
+		_, _ = i.DB.DeleteClient(ctx, i.client.ID)
+		_, _ = i.DB.DeleteResourceServer(ctx, i.resourceServer.ID)
+		_, _ = i.DB.DeleteUser(ctx, i.user.ID)
+		return ctx, nil
+	})
+
+	ctx.Step(`^A user has authorized a client for scope "([^"]*)"$`, i.aUserHasAuthorizedAClientForScope)
+	ctx.Step(`^The client has an access token for scope "([^"]*)"$`, i.theClientHasAnAccessTokenForScope)
+	ctx.Step(`^The client has a refresh token for scope "([^"]*)" that expires in "([^"]*)"$`, i.theClientHasARefreshTokenForScopeThatExpiresIn)
+	ctx.Step(`^The client has a refresh token idle lifetime of (\d+) seconds$`, i.theClientHasARefreshTokenIdleLifetimeOfSeconds)
+	ctx.Step(`^The refresh token was last used "([^"]*)" ago$`, i.theRefreshTokenWasLastUsedAgo)
+	ctx.Step(`^The client has the token "([^"]*)"$`, i.theClientHasTheToken)
+	ctx.Step(`^The user is "([^"]*)"$`, i.theUserIs)
+	ctx.Step(`^The resource server introspects the token with hint "([^"]*)"$`, i.theResourceServerIntrospectsTheTokenWithHint)
+	ctx.Step(`^The token should be active with scope "([^"]*)"$`, i.theTokenShouldBeActiveWithScope)
+	ctx.Step(`^The token should be inactive$`, i.theTokenShouldBeInactive)
+}
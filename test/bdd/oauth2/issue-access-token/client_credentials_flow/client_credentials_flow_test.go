@@ -0,0 +1,170 @@
+package clientcredentialsflow
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sso/internal/constant"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+	"sso/test"
+	"testing"
+	"time"
+
+	"github.com/cucumber/godog"
+	"github.com/golang-jwt/jwt/v4"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
+)
+
+type clientCredentialsFlowTest struct {
+	test.TestInstance
+	apiTest src.ApiTest
+	client  db.Client
+}
+
+func TestClientCredentialsFlow(t *testing.T) {
+	c := &clientCredentialsFlowTest{}
+
+	c.TestInstance = test.Initiate("../../../../../")
+	c.apiTest.InitializeServer(c.Server)
+	c.apiTest.InitializeTest(t, "issue access token with client credentials", "features/client_credentials_flow.feature", c.InitializeScenario)
+}
+
+func (c *clientCredentialsFlowTest) aConfidentialClientIsRegisteredOnTheSystemWithScopes(scopes string) error {
+	var err error
+	secret := utils.GenerateRandomString(25, true)
+	if c.client, err = c.DB.CreateClient(context.Background(), db.CreateClientParams{
+		RedirectUris: utils.ArrayToString([]string{"https://www.google.com"}),
+		Name:         "backend",
+		Scopes:       scopes,
+		ClientType:   constant.ConfidentialClient,
+		Secret:       utils.HashSecret(secret),
+		LogoUrl:      "https://www.google.com/images/errors/robot.png",
+		GrantTypes:   constant.ClientCredentials,
+	}); err != nil {
+		return err
+	}
+	c.client.Secret = secret
+	return nil
+}
+
+func (c *clientCredentialsFlowTest) iHaveTheFollowingParameters(tokenParam *godog.Table) error {
+	body, err := c.apiTest.ReadRow(tokenParam, nil, false)
+	if err != nil {
+		return err
+	}
+	c.apiTest.Body = body
+	return nil
+}
+
+func (c *clientCredentialsFlowTest) theClientRequestForToken() error {
+	c.apiTest.SetHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(c.client.ID.String()+":"+c.client.Secret)))
+	c.apiTest.SetHeader("Content-Type", "application/json")
+	c.apiTest.SendRequest()
+	return nil
+}
+
+func (c *clientCredentialsFlowTest) tokenShouldSuccessfullyBeIssuedForScope(scope string) error {
+	if err := c.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+
+	var tokenResponse dto.TokenResponse
+	if err := c.apiTest.UnmarshalResponseBodyPath("data", &tokenResponse); err != nil {
+		return err
+	}
+	if err := c.apiTest.AssertEqual(tokenResponse.RefreshToken, ""); err != nil {
+		return err
+	}
+
+	claims := dto.AccessToken{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(tokenResponse.AccessToken, &claims); err != nil {
+		return err
+	}
+	if err := c.apiTest.AssertEqual(claims.Subject, c.client.ID.String()); err != nil {
+		return err
+	}
+	return c.apiTest.AssertEqual(claims.Scope, scope)
+}
+
+func (c *clientCredentialsFlowTest) theClientHasAnAccessTokenLifetimeOfSeconds(lifetime int) error {
+	_, err := c.Conn.Exec(context.Background(), "UPDATE clients SET access_token_lifetime = $1 WHERE id = $2", lifetime, c.client.ID)
+	return err
+}
+
+func (c *clientCredentialsFlowTest) theClientHasNoGrantTypesRegistered() error {
+	_, err := c.Conn.Exec(context.Background(), "UPDATE clients SET grant_types = '' WHERE id = $1", c.client.ID)
+	return err
+}
+
+func (c *clientCredentialsFlowTest) tokenShouldBeValidForSeconds(lifetime int) error {
+	if err := c.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+
+	var tokenResponse dto.TokenResponse
+	if err := c.apiTest.UnmarshalResponseBodyPath("data", &tokenResponse); err != nil {
+		return err
+	}
+	if err := c.apiTest.AssertEqual(tokenResponse.ExpiresIn, fmt.Sprintf("%vs", lifetime)); err != nil {
+		return err
+	}
+
+	claims := dto.AccessToken{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(tokenResponse.AccessToken, &claims); err != nil {
+		return err
+	}
+	return c.apiTest.AssertEqual(claims.ExpiresAt.Sub(claims.NotBefore.Time), time.Duration(lifetime)*time.Second)
+}
+
+func (c *clientCredentialsFlowTest) theGrantShouldBeRecorded() error {
+	var status string
+	if err := c.Conn.QueryRow(context.Background(),
+		"SELECT status FROM auth_histories WHERE client_id = $1 AND user_id IS NULL ORDER BY created_at DESC LIMIT 1",
+		c.client.ID).Scan(&status); err != nil {
+		return err
+	}
+	return c.apiTest.AssertEqual(status, constant.Grant)
+}
+
+func (c *clientCredentialsFlowTest) theRequestShouldFailWithFieldErrorAndMessage(fieldMessage, errorMessage string) error {
+	if errorMessage != "" {
+		if err := c.apiTest.AssertBodyColumn("error.message", errorMessage); err != nil {
+			return err
+		}
+	}
+	if fieldMessage != "" {
+		if err := c.apiTest.AssertBodyColumn("error.field_error.0.description", fieldMessage); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *clientCredentialsFlowTest) InitializeScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		c.apiTest.URL = "/v1/oauth/token"
+		c.apiTest.Method = http.MethodPost
+
+		return ctx, nil
+	})
+
+	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		_, _ = c.Conn.Exec(ctx, "Delete from auth_histories where client_id = $1", c.client.ID)
+		_, _ = c.DB.DeleteClient(context.Background(), c.client.ID)
+		return ctx, nil
+	})
+
+	ctx.Step(`^A confidential client is registered on the system with scopes "([^"]*)"$`, c.aConfidentialClientIsRegisteredOnTheSystemWithScopes)
+	ctx.Step(`^I have the following parameters:$`, c.iHaveTheFollowingParameters)
+	ctx.Step(`^The client request for token$`, c.theClientRequestForToken)
+	ctx.Step(`^Token should successfully be issued for scope "([^"]*)"$`, c.tokenShouldSuccessfullyBeIssuedForScope)
+	ctx.Step(`^The grant should be recorded$`, c.theGrantShouldBeRecorded)
+	ctx.Step(`^The client has an access token lifetime of (\d+) seconds$`, c.theClientHasAnAccessTokenLifetimeOfSeconds)
+	ctx.Step(`^The client has no grant types registered$`, c.theClientHasNoGrantTypesRegistered)
+	ctx.Step(`^Token should be valid for (\d+) seconds$`, c.tokenShouldBeValidForSeconds)
+	ctx.Step(`^The request should fail with field error "([^"]*)" and message "([^"]*)"$`, c.theRequestShouldFailWithFieldErrorAndMessage)
+}
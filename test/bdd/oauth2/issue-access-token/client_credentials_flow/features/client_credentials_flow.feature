@@ -0,0 +1,48 @@
+Feature: Client Credentials Flow
+
+  Background: A backend service is registered as a client
+    Given A confidential client is registered on the system with scopes "profile email"
+
+  @success
+  Scenario Outline: Access Token successfully issued to the client
+    Given I have the following parameters:
+      | grant_type         | scope   |
+      | client_credentials | <scope> |
+    When The client request for token
+    Then Token should successfully be issued for scope "<issued_scope>"
+    And The grant should be recorded
+    Examples:
+      | scope         | issued_scope  |
+      |               | profile email |
+      | email         | email         |
+      | profile email | profile email |
+
+  @success
+  Scenario: Access Token issued for the lifetime of the client
+    Given The client has an access token lifetime of 60 seconds
+    And I have the following parameters:
+      | grant_type         | scope |
+      | client_credentials | email |
+    When The client request for token
+    Then Token should be valid for 60 seconds
+
+  @failure
+  Scenario Outline: Issuing Access Token to the client failed
+    Given I have the following parameters:
+      | grant_type   | scope   |
+      | <grant_type> | <scope> |
+    When The client request for token
+    Then The request should fail with field error "<field_error>" and message "<error_message>"
+    Examples:
+      | grant_type         | scope  | field_error            | error_message |
+      | client_credentials | openid |                        | invalid scope |
+      | password           |        | unsupported grant_type |               |
+
+  @failure
+  Scenario: Issuing Access Token to a client without the grant type enabled
+    Given The client has no grant types registered
+    And I have the following parameters:
+      | grant_type         | scope |
+      | client_credentials | email |
+    When The client request for token
+    Then The request should fail with field error "" and message "unauthorized_client"
@@ -6,6 +6,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sso/internal/constant"
+	"sso/internal/constant/errors/sqlcerr"
 	"sso/internal/constant/model/db"
 	"sso/internal/constant/model/dto"
 	"sso/internal/constant/state"
@@ -27,6 +30,8 @@ type issueAccessTokenCodeGrantTest struct {
 	user        db.User
 	redisSeeder seed.RedisDB
 	authCode    seed.RedisModel
+	scope       *db.Scope
+	issuedCode  string
 }
 
 func TestIssueAccessTokenCodeGrant(t *testing.T) {
@@ -127,19 +132,116 @@ func (i *issueAccessTokenCodeGrantTest) theRequestShouldFailWithFieldErrorAndMes
 
 func (i *issueAccessTokenCodeGrantTest) aClientIsRegisteredOnTheSystem() error {
 	var err error
+	secret := utils.GenerateRandomString(25, true)
 	if i.client, err = i.DB.CreateClient(context.Background(), db.CreateClientParams{
 		RedirectUris: utils.ArrayToString([]string{"https://www.google.com"}),
 		Name:         "google",
 		Scopes:       "openid",
 		ClientType:   "confidential",
-		Secret:       utils.GenerateRandomString(25, true),
+		Secret:       utils.HashSecret(secret),
 		LogoUrl:      "https://www.google.com/images/errors/robot.png",
 	}); err != nil {
 		return err
 	}
+	i.client.Secret = secret
 	return nil
 }
 
+func (i *issueAccessTokenCodeGrantTest) theClientHasAnAuthCodeLifetimeOfSeconds(seconds int) error {
+	_, err := i.Conn.Exec(context.Background(), "UPDATE clients SET auth_code_lifetime = $1 WHERE id = $2", seconds, i.client.ID)
+	return err
+}
+
+// theUserApprovedTheAuthorizationRequestOfTheClient gets a code issued the way the client gets it,
+// the user logs in, the client sends the user to the authorization endpoint and the user approves the consent.
+func (i *issueAccessTokenCodeGrantTest) theUserApprovedTheAuthorizationRequestOfTheClient() error {
+	scope, err := i.DB.CreateScope(context.Background(), db.CreateScopeParams{
+		Name:        "openid",
+		Description: "the identity of the user",
+	})
+	if err == nil {
+		i.scope = &scope
+	} else if !sqlcerr.IsUniqueViolation(err) {
+		return err
+	}
+
+	login := src.ApiTest{URL: "/v1/login", Method: http.MethodPost}
+	login.InitializeServer(i.Server)
+	login.SetHeader("Content-Type", "application/json")
+	login.SetBodyMap(map[string]interface{}{
+		"email":    i.user.Email.String,
+		"password": "password",
+	})
+	login.SendRequest()
+	if err := login.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+	var accessToken string
+	if err := login.UnmarshalResponseBodyPath("data.access_token", &accessToken); err != nil {
+		return err
+	}
+
+	authorize := src.ApiTest{URL: "/v1/oauth/authorize", Method: http.MethodGet}
+	authorize.InitializeServer(i.Server)
+	authorize.SetQueryParam("client_id", i.client.ID.String())
+	authorize.SetQueryParam("response_type", constant.ResponseTypeCode)
+	authorize.SetQueryParam("redirect_uri", "https://www.google.com")
+	authorize.SetQueryParam("scope", "openid")
+	authorize.SetQueryParam("state", "state")
+	authorize.SetQueryParam("prompt", constant.PromptConsent)
+	authorize.SendRequest()
+	if err := authorize.AssertStatusCode(http.StatusFound); err != nil {
+		return err
+	}
+	consentURL, err := url.Parse(authorize.Response.Header().Get("Location"))
+	if err != nil {
+		return err
+	}
+
+	approve := src.ApiTest{URL: "/v1/oauth/approveConsent", Method: http.MethodPost}
+	approve.InitializeServer(i.Server)
+	approve.SetHeader("Content-Type", "application/json")
+	approve.SetHeader("Authorization", "Bearer "+accessToken)
+	approve.SetBodyMap(map[string]interface{}{
+		"consent_id": consentURL.Query().Get("consentId"),
+	})
+	approve.AddCookie(http.Cookie{
+		Name:  "opbs",
+		Value: utils.GenerateNewOPBS(),
+	})
+	approve.SendRequest()
+	if err := approve.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+	var redirect dto.RedirectResponse
+	if err := approve.UnmarshalResponseBodyPath("data", &redirect); err != nil {
+		return err
+	}
+	redirectURL, err := url.Parse(redirect.Location)
+	if err != nil {
+		return err
+	}
+	if i.issuedCode = redirectURL.Query().Get("code"); i.issuedCode == "" {
+		return fmt.Errorf("expected a code on the redirect, got %s", redirect.Location)
+	}
+
+	return nil
+}
+
+func (i *issueAccessTokenCodeGrantTest) secondsHavePassed(seconds int) error {
+	time.Sleep(time.Duration(seconds) * time.Second)
+	return nil
+}
+
+func (i *issueAccessTokenCodeGrantTest) theClientExchangesTheIssuedCodeForToken() error {
+	i.apiTest.SetBodyMap(map[string]interface{}{
+		"grant_type":   constant.AuthorizationCode,
+		"code":         i.issuedCode,
+		"redirect_uri": "https://www.google.com",
+	})
+	return i.theClientRequestForToken()
+}
+
 func (i *issueAccessTokenCodeGrantTest) tokenShouldSuccessfullyBeIssued() error {
 	if err := i.apiTest.AssertStatusCode(http.StatusOK); err != nil {
 		return err
@@ -171,6 +273,10 @@ func (i *issueAccessTokenCodeGrantTest) InitializeScenario(ctx *godog.ScenarioCo
 		_, _ = i.Conn.Exec(ctx, "Delete from auth_histories where true")
 		_, _ = i.Conn.Exec(ctx, "Delete from refresh_tokens where true")
 		_, _ = i.DB.DeleteClient(context.Background(), i.client.ID)
+		if i.scope != nil {
+			_, _ = i.DB.DeleteScope(context.Background(), i.scope.Name)
+			i.scope = nil
+		}
 		return ctx, nil
 	})
 
@@ -181,4 +287,8 @@ func (i *issueAccessTokenCodeGrantTest) InitializeScenario(ctx *godog.ScenarioCo
 	ctx.Step(`^A client is registered on the system$`, i.aClientIsRegisteredOnTheSystem)
 	ctx.Step(`^A user is registered on the system$`, i.aUserIsRegisteredOnTheSystem)
 	ctx.Step(`^Token should successfully be issued$`, i.tokenShouldSuccessfullyBeIssued)
+	ctx.Step(`^The client has an auth code lifetime of (\d+) seconds$`, i.theClientHasAnAuthCodeLifetimeOfSeconds)
+	ctx.Step(`^The user approved the authorization request of the client$`, i.theUserApprovedTheAuthorizationRequestOfTheClient)
+	ctx.Step(`^(\d+) seconds have passed$`, i.secondsHavePassed)
+	ctx.Step(`^The client exchanges the issued code for token$`, i.theClientExchangesTheIssuedCodeForToken)
 }
@@ -29,3 +29,18 @@ Feature: Code Grant Flow
       |                    | 002a05af-15ae-4c21-8e4a-d7bde55f6aff | https://www.google.com/ | grant_type is required   |                         |
       | authorization_code | 002a05af-15ae-4c21-8e4a-d7bde55f6afz | https://www.google.com/ |                          | no record of code found |
       | authorization_code | 002a05af-15ae-4c21-8e4a-d7bde55f6aff | https://www.yahoo.com/  |                          | redirect uri mismatch   |
+
+  @success
+  Scenario: The auth code is exchanged within the auth code lifetime of the client
+    Given The client has an auth code lifetime of 60 seconds
+    And The user approved the authorization request of the client
+    When The client exchanges the issued code for token
+    Then Token should successfully be issued
+
+  @failure
+  Scenario: The auth code expires after the auth code lifetime of the client
+    Given The client has an auth code lifetime of 1 seconds
+    And The user approved the authorization request of the client
+    And 2 seconds have passed
+    When The client exchanges the issued code for token
+    Then The request should fail with field error "" and message "no record of code found"
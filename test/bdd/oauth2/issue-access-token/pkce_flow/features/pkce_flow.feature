@@ -0,0 +1,29 @@
+Feature: PKCE Code Grant Flow
+
+  Background: A public client has an authorization code issued with a code challenge
+    Given A public client is registered on the system
+    And A user is registered on the system
+    And The user granted access to the client with code challenge:
+      | code                                 | code_challenge                              |
+      | 5c1e2d4a-7a1b-4f0e-9c55-0c3f1b1e2a77 | E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM |
+
+  @success
+  Scenario: Access Token successfully issued to a public client
+    Given I have the following parameters:
+      | grant_type         | code                                 | redirect_uri           | code_verifier                               |
+      | authorization_code | 5c1e2d4a-7a1b-4f0e-9c55-0c3f1b1e2a77 | https://www.google.com | dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk |
+    When The public client request for token
+    Then Token should successfully be issued
+
+  @failure
+  Scenario Outline: Issuing Access Token to a public client failed
+    Given I have the following parameters:
+      | grant_type         | code                                 | redirect_uri           | code_verifier   |
+      | authorization_code | 5c1e2d4a-7a1b-4f0e-9c55-0c3f1b1e2a77 | https://www.google.com | <code_verifier> |
+    When The public client request for token
+    Then The request should fail with field error "<field_error>" and message "<error_message>"
+    Examples:
+      | code_verifier                                | field_error                                         | error_message             |
+      |                                              |                                                     | code_verifier is required |
+      | dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXkx |                                                     | invalid code_verifier     |
+      | short                                        | code_verifier must be between 43 and 128 characters |                           |
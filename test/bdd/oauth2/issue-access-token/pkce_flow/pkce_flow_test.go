@@ -0,0 +1,180 @@
+package pkceflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sso/internal/constant"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/internal/constant/state"
+	"sso/platform/utils"
+	"sso/test"
+	"testing"
+	"time"
+
+	"github.com/cucumber/godog"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src/seed"
+)
+
+type pkceFlowTest struct {
+	test.TestInstance
+	apiTest     src.ApiTest
+	client      db.Client
+	user        db.User
+	redisSeeder seed.RedisDB
+	authCode    seed.RedisModel
+	tokenParam  map[string]string
+}
+
+func TestPKCEFlow(t *testing.T) {
+	p := &pkceFlowTest{}
+
+	p.TestInstance = test.Initiate("../../../../../")
+	p.redisSeeder = seed.RedisDB{
+		DB: p.Redis,
+	}
+	p.apiTest.InitializeServer(p.Server)
+	p.apiTest.InitializeTest(t, "issue access token with pkce", "features/pkce_flow.feature", p.InitializeScenario)
+}
+
+func (p *pkceFlowTest) aPublicClientIsRegisteredOnTheSystem() error {
+	var err error
+	if p.client, err = p.DB.CreateClient(context.Background(), db.CreateClientParams{
+		RedirectUris: utils.ArrayToString([]string{"https://www.google.com"}),
+		Name:         "mobile",
+		Scopes:       "openid",
+		ClientType:   constant.PublicClient,
+		Secret:       utils.GenerateRandomString(25, true),
+		LogoUrl:      "https://www.google.com/images/errors/robot.png",
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *pkceFlowTest) aUserIsRegisteredOnTheSystem() error {
+	var err error
+	hash, err := utils.HashAndSalt(context.Background(), []byte("password"), p.Logger)
+	if err != nil {
+		return err
+	}
+	if p.user, err = p.DB.CreateUser(context.Background(), db.CreateUserParams{
+		Email:      utils.StringOrNull("pkce@gmail.com"),
+		Password:   hash,
+		FirstName:  "someone",
+		MiddleName: "someone",
+		LastName:   "someone",
+		Phone:      "0987654322",
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *pkceFlowTest) theUserGrantedAccessToTheClientWithCodeChallenge(authCodeTable *godog.Table) error {
+	code, err := p.apiTest.ReadCellString(authCodeTable, "code")
+	if err != nil {
+		return err
+	}
+	codeChallenge, err := p.apiTest.ReadCellString(authCodeTable, "code_challenge")
+	if err != nil {
+		return err
+	}
+
+	authCode := dto.AuthCode{
+		Code:                code,
+		Scope:               "openid",
+		RedirectURI:         "https://www.google.com",
+		ClientID:            p.client.ID,
+		UserID:              p.user.ID,
+		CodeChallenge:       codeChallenge,
+		CodeChallengeMethod: constant.CodeChallengeS256,
+	}
+
+	authCodeValue, err := json.Marshal(authCode)
+	if err != nil {
+		return err
+	}
+	p.authCode = seed.RedisModel{
+		Key:      fmt.Sprintf(state.AuthCodeKey, authCode.Code),
+		Value:    string(authCodeValue),
+		ExpireAt: time.Duration(time.Minute * 2),
+	}
+
+	return p.redisSeeder.Feed(p.authCode)
+}
+
+func (p *pkceFlowTest) iHaveTheFollowingParameters(tokenParam *godog.Table) error {
+	body, err := p.apiTest.ReadRow(tokenParam, nil, false)
+	if err != nil {
+		return err
+	}
+	return p.apiTest.UnmarshalJSON([]byte(body), &p.tokenParam)
+}
+
+func (p *pkceFlowTest) thePublicClientRequestForToken() error {
+	form := url.Values{}
+	for k, v := range p.tokenParam {
+		form.Set(k, v)
+	}
+	form.Set("client_id", p.client.ID.String())
+
+	p.apiTest.Body = form.Encode()
+	p.apiTest.SetHeader("Content-Type", "application/x-www-form-urlencoded")
+	p.apiTest.SendRequest()
+	return nil
+}
+
+func (p *pkceFlowTest) theRequestShouldFailWithFieldErrorAndMessage(fieldMessage, errorMessage string) error {
+	if errorMessage != "" {
+		if err := p.apiTest.AssertBodyColumn("error.message", errorMessage); err != nil {
+			return err
+		}
+	}
+	if fieldMessage != "" {
+		if err := p.apiTest.AssertBodyColumn("error.field_error.0.description", fieldMessage); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *pkceFlowTest) tokenShouldSuccessfullyBeIssued() error {
+	if err := p.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+
+	return p.apiTest.AssertColumnExists("data.access_token")
+}
+
+func (p *pkceFlowTest) InitializeScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		p.apiTest.URL = "/v1/oauth/token"
+		p.apiTest.Method = http.MethodPost
+
+		return ctx, nil
+	})
+
+	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		_, _ = p.DB.DeleteUser(context.Background(), p.user.ID)
+		_ = p.redisSeeder.Starve(p.authCode)
+		_, _ = p.Conn.Exec(ctx, "Delete from auth_histories where true")
+		_, _ = p.Conn.Exec(ctx, "Delete from refresh_tokens where true")
+		_, _ = p.DB.DeleteClient(context.Background(), p.client.ID)
+		return ctx, nil
+	})
+
+	ctx.Step(`^A public client is registered on the system$`, p.aPublicClientIsRegisteredOnTheSystem)
+	ctx.Step(`^A user is registered on the system$`, p.aUserIsRegisteredOnTheSystem)
+	ctx.Step(`^The user granted access to the client with code challenge:$`, p.theUserGrantedAccessToTheClientWithCodeChallenge)
+	ctx.Step(`^I have the following parameters:$`, p.iHaveTheFollowingParameters)
+	ctx.Step(`^The public client request for token$`, p.thePublicClientRequestForToken)
+	ctx.Step(`^The request should fail with field error "([^"]*)" and message "([^"]*)"$`, p.theRequestShouldFailWithFieldErrorAndMessage)
+	ctx.Step(`^Token should successfully be issued$`, p.tokenShouldSuccessfullyBeIssued)
+}
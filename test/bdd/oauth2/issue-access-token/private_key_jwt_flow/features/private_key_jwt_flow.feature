@@ -0,0 +1,28 @@
+Feature: Private Key JWT Client Authentication
+
+  Background: A backend service authenticating with private_key_jwt is registered as a client
+    Given A client authenticating with private_key_jwt is registered on the system with scopes "profile email"
+
+  @success
+  Scenario: Access Token successfully issued to the client authenticated with a client assertion
+    Given The client signed a client assertion
+    When The client request for token with the client assertion
+    Then Token should successfully be issued for scope "profile email"
+
+  @failure
+  Scenario: The same client assertion can not be used twice
+    Given The client signed a client assertion
+    And The client requested for token with the client assertion
+    When The client request for token with the client assertion
+    Then The request should fail with status 401 and message "client assertion was already used"
+
+  @failure
+  Scenario: Client assertion signed with another key is rejected
+    Given The client signed a client assertion with another key
+    When The client request for token with the client assertion
+    Then The request should fail with status 401 and message "invalid client assertion"
+
+  @failure
+  Scenario: Client authenticating with private_key_jwt can not use its secret
+    When The client request for token with its secret
+    Then The request should fail with status 403 and message "unauthorized_client"
@@ -0,0 +1,189 @@
+package privatekeyjwtflow
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sso/internal/constant"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+	"sso/test"
+	"testing"
+	"time"
+
+	"github.com/cucumber/godog"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
+)
+
+type privateKeyJWTFlowTest struct {
+	test.TestInstance
+	apiTest   src.ApiTest
+	client    db.Client
+	key       *ecdsa.PrivateKey
+	assertion string
+}
+
+func TestPrivateKeyJWTFlow(t *testing.T) {
+	p := &privateKeyJWTFlowTest{}
+
+	p.TestInstance = test.Initiate("../../../../../")
+	p.apiTest.InitializeServer(p.Server)
+	p.apiTest.InitializeTest(t, "authenticate the client with private_key_jwt", "features/private_key_jwt_flow.feature", p.InitializeScenario)
+}
+
+func (p *privateKeyJWTFlowTest) aClientAuthenticatingWithPrivateKeyJWTIsRegisteredOnTheSystemWithScopes(scopes string) error {
+	var err error
+	if p.key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+		return err
+	}
+
+	x, y := make([]byte, 32), make([]byte, 32)
+	jwks, err := json.Marshal(dto.JWKS{Keys: []dto.JWK{{
+		Kty: "EC",
+		Use: "sig",
+		Kid: "client-key",
+		Alg: constant.SigningAlgorithmES256,
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(p.key.X.FillBytes(x)),
+		Y:   base64.RawURLEncoding.EncodeToString(p.key.Y.FillBytes(y)),
+	}}})
+	if err != nil {
+		return err
+	}
+
+	secret := utils.GenerateRandomString(25, true)
+	if p.client, err = p.DB.CreateClient(context.Background(), db.CreateClientParams{
+		RedirectUris:            utils.ArrayToString([]string{"https://www.google.com"}),
+		Name:                    "backend",
+		Scopes:                  scopes,
+		ClientType:              constant.ConfidentialClient,
+		Secret:                  utils.HashSecret(secret),
+		LogoUrl:                 "https://www.google.com/images/errors/robot.png",
+		GrantTypes:              constant.ClientCredentials,
+		TokenEndpointAuthMethod: constant.PrivateKeyJWT,
+		Jwks:                    string(jwks),
+	}); err != nil {
+		return err
+	}
+	p.client.Secret = secret
+	return nil
+}
+
+func (p *privateKeyJWTFlowTest) signClientAssertion(key *ecdsa.PrivateKey) error {
+	configuration, err := p.Module.OAuth2Module.OpenIDConfiguration(context.Background())
+	if err != nil {
+		return err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+		Issuer:    p.client.ID.String(),
+		Subject:   p.client.ID.String(),
+		Audience:  jwt.ClaimStrings{configuration.TokenEndpoint},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ID:        uuid.NewString(),
+	})
+	token.Header["kid"] = "client-key"
+	p.assertion, err = token.SignedString(key)
+	return err
+}
+
+func (p *privateKeyJWTFlowTest) theClientSignedAClientAssertion() error {
+	return p.signClientAssertion(p.key)
+}
+
+func (p *privateKeyJWTFlowTest) theClientSignedAClientAssertionWithAnotherKey() error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	return p.signClientAssertion(key)
+}
+
+func (p *privateKeyJWTFlowTest) theClientRequestForTokenWithTheClientAssertion() error {
+	form := url.Values{}
+	form.Set("grant_type", constant.ClientCredentials)
+	form.Set("client_assertion_type", constant.ClientAssertionTypeJWTBearer)
+	form.Set("client_assertion", p.assertion)
+	p.apiTest.Body = form.Encode()
+	p.apiTest.SetHeader("Authorization", "")
+	p.apiTest.SetHeader("Content-Type", "application/x-www-form-urlencoded")
+	p.apiTest.SendRequest()
+	return nil
+}
+
+func (p *privateKeyJWTFlowTest) theClientRequestedForTokenWithTheClientAssertion() error {
+	if err := p.theClientRequestForTokenWithTheClientAssertion(); err != nil {
+		return err
+	}
+	return p.apiTest.AssertStatusCode(http.StatusOK)
+}
+
+func (p *privateKeyJWTFlowTest) theClientRequestForTokenWithItsSecret() error {
+	form := url.Values{}
+	form.Set("grant_type", constant.ClientCredentials)
+	p.apiTest.Body = form.Encode()
+	p.apiTest.SetHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(p.client.ID.String()+":"+p.client.Secret)))
+	p.apiTest.SetHeader("Content-Type", "application/x-www-form-urlencoded")
+	p.apiTest.SendRequest()
+	return nil
+}
+
+func (p *privateKeyJWTFlowTest) tokenShouldSuccessfullyBeIssuedForScope(scope string) error {
+	if err := p.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+
+	var tokenResponse dto.TokenResponse
+	if err := p.apiTest.UnmarshalResponseBodyPath("data", &tokenResponse); err != nil {
+		return err
+	}
+
+	claims := dto.AccessToken{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(tokenResponse.AccessToken, &claims); err != nil {
+		return err
+	}
+	if err := p.apiTest.AssertEqual(claims.Subject, p.client.ID.String()); err != nil {
+		return err
+	}
+	return p.apiTest.AssertEqual(claims.Scope, scope)
+}
+
+func (p *privateKeyJWTFlowTest) theRequestShouldFailWithStatusAndMessage(status int, message string) error {
+	if err := p.apiTest.AssertStatusCode(status); err != nil {
+		return err
+	}
+	return p.apiTest.AssertBodyColumn("error.message", message)
+}
+
+func (p *privateKeyJWTFlowTest) InitializeScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		p.apiTest.URL = "/v1/oauth/token"
+		p.apiTest.Method = http.MethodPost
+
+		return ctx, nil
+	})
+
+	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		_, _ = p.Conn.Exec(ctx, "Delete from auth_histories where client_id = $1", p.client.ID)
+		_, _ = p.DB.DeleteClient(context.Background(), p.client.ID)
+		return ctx, nil
+	})
+
+	ctx.Step(`^A client authenticating with private_key_jwt is registered on the system with scopes "([^"]*)"$`, p.aClientAuthenticatingWithPrivateKeyJWTIsRegisteredOnTheSystemWithScopes)
+	ctx.Step(`^The client signed a client assertion$`, p.theClientSignedAClientAssertion)
+	ctx.Step(`^The client signed a client assertion with another key$`, p.theClientSignedAClientAssertionWithAnotherKey)
+	ctx.Step(`^The client request for token with the client assertion$`, p.theClientRequestForTokenWithTheClientAssertion)
+	ctx.Step(`^The client requested for token with the client assertion$`, p.theClientRequestedForTokenWithTheClientAssertion)
+	ctx.Step(`^The client request for token with its secret$`, p.theClientRequestForTokenWithItsSecret)
+	ctx.Step(`^Token should successfully be issued for scope "([^"]*)"$`, p.tokenShouldSuccessfullyBeIssuedForScope)
+	ctx.Step(`^The request should fail with status (\d+) and message "([^"]*)"$`, p.theRequestShouldFailWithStatusAndMessage)
+}
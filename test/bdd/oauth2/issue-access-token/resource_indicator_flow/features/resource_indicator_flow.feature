@@ -0,0 +1,23 @@
+Feature: Resource Indicator Flow
+
+  Background: A backend service calls the rs-api resource server
+    Given The resource server "rs-api" has scopes "rides.read rides.write"
+    And A confidential client is registered on the system with scopes "profile rides.read rides.write"
+
+  @success
+  Scenario Outline: Access Token restricted to the resource server is issued
+    When The client requests a token for resource "rs-api" and scope "<scope>"
+    Then A token for "rs-api" should be issued for scope "<issued_scope>"
+    Examples:
+      | scope              | issued_scope           |
+      |                    | rides.read rides.write |
+      | profile rides.read | rides.read             |
+
+  @failure
+  Scenario Outline: Issuing Access Token for the resource failed
+    When The client requests a token for resource "<resource>" and scope "<scope>"
+    Then The request should fail with message "<error_message>"
+    Examples:
+      | resource | scope   | error_message  |
+      | rs-maps  |         | invalid target |
+      | rs-api   | profile | invalid target |
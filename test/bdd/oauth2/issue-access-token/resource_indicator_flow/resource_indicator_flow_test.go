@@ -0,0 +1,143 @@
+package resourceindicatorflow
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"sso/internal/constant"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+	"sso/test"
+	"strings"
+	"testing"
+
+	"github.com/cucumber/godog"
+	"github.com/golang-jwt/jwt/v4"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
+)
+
+type resourceIndicatorFlowTest struct {
+	test.TestInstance
+	apiTest        src.ApiTest
+	client         db.Client
+	resourceServer db.ResourceServer
+	scopes         []string
+}
+
+func TestResourceIndicatorFlow(t *testing.T) {
+	r := &resourceIndicatorFlowTest{}
+
+	r.TestInstance = test.Initiate("../../../../../")
+	r.apiTest.InitializeServer(r.Server)
+	r.apiTest.InitializeTest(t, "issue access token restricted to a resource server", "features/resource_indicator_flow.feature", r.InitializeScenario)
+}
+
+func (r *resourceIndicatorFlowTest) theResourceServerHasScopes(resourceServer, scopes string) error {
+	var err error
+	if r.resourceServer, err = r.DB.CreateResourceServer(context.Background(), resourceServer); err != nil {
+		return err
+	}
+	for _, scope := range strings.Fields(scopes) {
+		if _, err := r.DB.CreateScope(context.Background(), db.CreateScopeParams{
+			Name:               scope,
+			Description:        "scope for " + scope,
+			ResourceServerName: sql.NullString{String: resourceServer, Valid: true},
+		}); err != nil {
+			return err
+		}
+		r.scopes = append(r.scopes, scope)
+	}
+	return nil
+}
+
+func (r *resourceIndicatorFlowTest) aConfidentialClientIsRegisteredOnTheSystemWithScopes(scopes string) error {
+	var err error
+	secret := utils.GenerateRandomString(25, true)
+	if r.client, err = r.DB.CreateClient(context.Background(), db.CreateClientParams{
+		RedirectUris: utils.ArrayToString([]string{"https://www.google.com"}),
+		Name:         "backend",
+		Scopes:       scopes,
+		ClientType:   constant.ConfidentialClient,
+		Secret:       utils.HashSecret(secret),
+		LogoUrl:      "https://www.google.com/images/errors/robot.png",
+		GrantTypes:   constant.ClientCredentials,
+	}); err != nil {
+		return err
+	}
+	r.client.Secret = secret
+	return nil
+}
+
+func (r *resourceIndicatorFlowTest) theClientRequestsATokenForResourceAndScope(resource, scope string) error {
+	form := url.Values{
+		"grant_type": {constant.ClientCredentials},
+		"resource":   {resource},
+	}
+	if scope != "" {
+		form.Set("scope", scope)
+	}
+	r.apiTest.Body = form.Encode()
+	r.apiTest.SetHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(r.client.ID.String()+":"+r.client.Secret)))
+	r.apiTest.SetHeader("Content-Type", "application/x-www-form-urlencoded")
+	r.apiTest.SendRequest()
+	return nil
+}
+
+func (r *resourceIndicatorFlowTest) aTokenShouldBeIssuedForScope(resource, scope string) error {
+	if err := r.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+
+	var tokenResponse dto.TokenResponse
+	if err := r.apiTest.UnmarshalResponseBodyPath("data", &tokenResponse); err != nil {
+		return err
+	}
+	if err := r.apiTest.AssertEqual(tokenResponse.Scope, scope); err != nil {
+		return err
+	}
+
+	claims := dto.AccessToken{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(tokenResponse.AccessToken, &claims); err != nil {
+		return err
+	}
+	if err := r.apiTest.AssertEqual([]string(claims.Audience), []string{resource}); err != nil {
+		return err
+	}
+	if err := r.apiTest.AssertEqual(claims.ClientID, r.client.ID.String()); err != nil {
+		return err
+	}
+	return r.apiTest.AssertEqual(claims.Scope, scope)
+}
+
+func (r *resourceIndicatorFlowTest) theRequestShouldFailWithMessage(errorMessage string) error {
+	return r.apiTest.AssertBodyColumn("error.message", errorMessage)
+}
+
+func (r *resourceIndicatorFlowTest) InitializeScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		r.apiTest.URL = "/v1/oauth/token"
+		r.apiTest.Method = http.MethodPost
+		r.scopes = nil
+
+		return ctx, nil
+	})
+
+	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		_, _ = r.Conn.Exec(ctx, "Delete from auth_histories where client_id = $1", r.client.ID)
+		_, _ = r.DB.DeleteClient(context.Background(), r.client.ID)
+		_, _ = r.DB.DeleteResourceServer(context.Background(), r.resourceServer.ID)
+		for _, scope := range r.scopes {
+			_, _ = r.DB.DeleteScope(context.Background(), scope)
+		}
+		return ctx, nil
+	})
+
+	ctx.Step(`^The resource server "([^"]*)" has scopes "([^"]*)"$`, r.theResourceServerHasScopes)
+	ctx.Step(`^A confidential client is registered on the system with scopes "([^"]*)"$`, r.aConfidentialClientIsRegisteredOnTheSystemWithScopes)
+	ctx.Step(`^The client requests a token for resource "([^"]*)" and scope "([^"]*)"$`, r.theClientRequestsATokenForResourceAndScope)
+	ctx.Step(`^A token for "([^"]*)" should be issued for scope "([^"]*)"$`, r.aTokenShouldBeIssuedForScope)
+	ctx.Step(`^The request should fail with message "([^"]*)"$`, r.theRequestShouldFailWithMessage)
+}
@@ -0,0 +1,40 @@
+Feature: Token Exchange Flow
+
+  Background: A service calls the payments resource server on behalf of a user
+    Given The resource server "payments" has scopes "payments.read payments.write"
+
+  @success
+  Scenario Outline: The user token is exchanged for a down-scoped token
+    Given A service is registered with scopes "openid payments.read payments.write" and grant types "urn:ietf:params:oauth:grant-type:token-exchange"
+    And A user has an access token for scope "openid payments.read payments.write":
+      | email             | password |
+      | example@email.com | 1234abcd |
+    When The service exchanges the token for audience "payments" and scope "<scope>"
+    Then A token for "payments" should be issued for scope "<issued_scope>" acted on by the service
+    Examples:
+      | scope         | issued_scope                 |
+      |               | payments.read payments.write |
+      | payments.read | payments.read                |
+
+  @failure
+  Scenario Outline: Exchanging the token failed
+    Given A service is registered with scopes "<client_scopes>" and grant types "<grant_types>"
+    And A user has an access token for scope "openid payments.read":
+      | email             | password |
+      | example@email.com | 1234abcd |
+    When The service exchanges the token for audience "<audience>" and scope "<scope>"
+    Then The request should fail with field error "" and message "<error_message>"
+    Examples:
+      | client_scopes                | grant_types                                     | audience | scope          | error_message       |
+      | payments.read payments.write | urn:ietf:params:oauth:grant-type:token-exchange | payments | payments.write | invalid scope       |
+      | payments.read                | urn:ietf:params:oauth:grant-type:token-exchange | rides    |                | invalid target      |
+      | payments.read                | client_credentials                              | payments |                | unauthorized_client |
+
+  @failure
+  Scenario: Exchanging the token of another client
+    Given A service is registered with scopes "openid payments.read" and grant types "urn:ietf:params:oauth:grant-type:token-exchange"
+    And A user has an access token of another client for scope "openid payments.read":
+      | email             | password |
+      | example@email.com | 1234abcd |
+    When The service exchanges the token for audience "payments" and scope ""
+    Then The request should fail with field error "" and message "invalid token"
@@ -0,0 +1,186 @@
+package tokenexchangeflow
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"sso/internal/constant"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+	"sso/test"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cucumber/godog"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
+)
+
+type tokenExchangeFlowTest struct {
+	test.TestInstance
+	apiTest      src.ApiTest
+	client       db.Client
+	scopes       []string
+	user         db.User
+	subjectToken string
+}
+
+func TestTokenExchangeFlow(t *testing.T) {
+	e := &tokenExchangeFlowTest{}
+
+	e.TestInstance = test.Initiate("../../../../../")
+	e.apiTest.InitializeServer(e.Server)
+	e.apiTest.InitializeTest(t, "issue access token with token exchange", "features/token_exchange_flow.feature", e.InitializeScenario)
+}
+
+func (e *tokenExchangeFlowTest) theResourceServerHasScopes(resourceServer, scopes string) error {
+	for _, scope := range strings.Fields(scopes) {
+		if _, err := e.DB.CreateScope(context.Background(), db.CreateScopeParams{
+			Name:               scope,
+			Description:        "scope for " + scope,
+			ResourceServerName: sql.NullString{String: resourceServer, Valid: true},
+		}); err != nil {
+			return err
+		}
+		e.scopes = append(e.scopes, scope)
+	}
+	return nil
+}
+
+func (e *tokenExchangeFlowTest) aServiceIsRegisteredWithScopesAndGrantTypes(scopes, grantTypes string) error {
+	var err error
+	secret := utils.GenerateRandomString(25, true)
+	if e.client, err = e.DB.CreateClient(context.Background(), db.CreateClientParams{
+		RedirectUris: utils.ArrayToString([]string{"https://www.google.com"}),
+		Name:         "ride-matching",
+		Scopes:       scopes,
+		ClientType:   constant.ConfidentialClient,
+		Secret:       utils.HashSecret(secret),
+		LogoUrl:      "https://www.google.com/images/errors/robot.png",
+		GrantTypes:   grantTypes,
+	}); err != nil {
+		return err
+	}
+	e.client.Secret = secret
+	return nil
+}
+
+func (e *tokenExchangeFlowTest) aUserHasAnAccessTokenForScope(scope string, credentials *godog.Table) error {
+	var err error
+	if e.user, err = e.Authenticate(credentials); err != nil {
+		return err
+	}
+	e.subjectToken, err = e.PlatformLayer.Token.GenerateAccessTokenForClient(context.Background(),
+		e.user.ID.String(), e.client.ID.String(), scope, dto.AccessTokenOptions{}, time.Minute)
+	return err
+}
+
+func (e *tokenExchangeFlowTest) aUserHasAnAccessTokenOfAnotherClientForScope(scope string, credentials *godog.Table) error {
+	var err error
+	if e.user, err = e.Authenticate(credentials); err != nil {
+		return err
+	}
+	e.subjectToken, err = e.PlatformLayer.Token.GenerateAccessTokenForClient(context.Background(),
+		e.user.ID.String(), uuid.NewString(), scope, dto.AccessTokenOptions{}, time.Minute)
+	return err
+}
+
+func (e *tokenExchangeFlowTest) theServiceExchangesTheTokenForAudienceAndScope(audience, scope string) error {
+	form := url.Values{
+		"grant_type":         {constant.TokenExchange},
+		"subject_token":      {e.subjectToken},
+		"subject_token_type": {constant.AccessTokenTypeURI},
+		"audience":           {audience},
+	}
+	if scope != "" {
+		form.Set("scope", scope)
+	}
+	e.apiTest.Body = form.Encode()
+	e.apiTest.SetHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(e.client.ID.String()+":"+e.client.Secret)))
+	e.apiTest.SetHeader("Content-Type", "application/x-www-form-urlencoded")
+	e.apiTest.SendRequest()
+	return nil
+}
+
+func (e *tokenExchangeFlowTest) aTokenShouldBeIssuedForScopeActedOnByTheService(audience, scope string) error {
+	if err := e.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+
+	var tokenResponse dto.TokenResponse
+	if err := e.apiTest.UnmarshalResponseBodyPath("data", &tokenResponse); err != nil {
+		return err
+	}
+	if err := e.apiTest.AssertEqual(tokenResponse.IssuedTokenType, constant.AccessTokenTypeURI); err != nil {
+		return err
+	}
+
+	claims := dto.AccessToken{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(tokenResponse.AccessToken, &claims); err != nil {
+		return err
+	}
+	if err := e.apiTest.AssertEqual(claims.Subject, e.user.ID.String()); err != nil {
+		return err
+	}
+	if err := e.apiTest.AssertEqual(claims.Scope, scope); err != nil {
+		return err
+	}
+	if err := e.apiTest.AssertEqual([]string(claims.Audience), []string{audience}); err != nil {
+		return err
+	}
+	if err := e.apiTest.AssertEqual(claims.ClientID, e.client.ID.String()); err != nil {
+		return err
+	}
+	if err := e.apiTest.AssertEqual(claims.Actor != nil, true); err != nil {
+		return err
+	}
+	return e.apiTest.AssertEqual(claims.Actor.Subject, e.client.ID.String())
+}
+
+func (e *tokenExchangeFlowTest) theRequestShouldFailWithFieldErrorAndMessage(fieldMessage, errorMessage string) error {
+	if errorMessage != "" {
+		if err := e.apiTest.AssertBodyColumn("error.message", errorMessage); err != nil {
+			return err
+		}
+	}
+	if fieldMessage != "" {
+		if err := e.apiTest.AssertBodyColumn("error.field_error.0.description", fieldMessage); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *tokenExchangeFlowTest) InitializeScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		e.apiTest.URL = "/v1/oauth/token"
+		e.apiTest.Method = http.MethodPost
+		e.scopes = nil
+
+		return ctx, nil
+	})
+
+	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		_, _ = e.Conn.Exec(ctx, "Delete from auth_histories where client_id = $1", e.client.ID)
+		_, _ = e.DB.DeleteClient(context.Background(), e.client.ID)
+		_, _ = e.DB.DeleteUser(context.Background(), e.user.ID)
+		for _, scope := range e.scopes {
+			_, _ = e.DB.DeleteScope(context.Background(), scope)
+		}
+		return ctx, nil
+	})
+
+	ctx.Step(`^The resource server "([^"]*)" has scopes "([^"]*)"$`, e.theResourceServerHasScopes)
+	ctx.Step(`^A service is registered with scopes "([^"]*)" and grant types "([^"]*)"$`, e.aServiceIsRegisteredWithScopesAndGrantTypes)
+	ctx.Step(`^A user has an access token for scope "([^"]*)":$`, e.aUserHasAnAccessTokenForScope)
+	ctx.Step(`^A user has an access token of another client for scope "([^"]*)":$`, e.aUserHasAnAccessTokenOfAnotherClientForScope)
+	ctx.Step(`^The service exchanges the token for audience "([^"]*)" and scope "([^"]*)"$`, e.theServiceExchangesTheTokenForAudienceAndScope)
+	ctx.Step(`^A token for "([^"]*)" should be issued for scope "([^"]*)" acted on by the service$`, e.aTokenShouldBeIssuedForScopeActedOnByTheService)
+	ctx.Step(`^The request should fail with field error "([^"]*)" and message "([^"]*)"$`, e.theRequestShouldFailWithFieldErrorAndMessage)
+}
@@ -0,0 +1,46 @@
+Feature: Pushed Authorization Requests
+
+    As a client
+
+    I want to push my authorization request to the sso
+
+    So that its parameters don't pass through the browser.
+    Background: there is a registered client
+        Given there is registered scope "openid"
+        And A confidential client is registered with redirect uri "https://www.google.com/"
+
+    @success
+    Scenario: Authorization request is pushed and referenced on the authorization endpoint
+        Given The client pushed the following authorization request:
+            | response_type | redirect_uri            | scope  | state | prompt  |
+            | code          | https://www.google.com/ | openid | 1234  | consent |
+        When The user is sent to the authorization endpoint with the request_uri
+        Then The user should be asked consent for the pushed request
+
+    @failure
+    Scenario: A request_uri can only be used once
+        Given The client pushed the following authorization request:
+            | response_type | redirect_uri            | scope  | state | prompt  |
+            | code          | https://www.google.com/ | openid | 1234  | consent |
+        And The user was sent to the authorization endpoint with the request_uri
+        When The user is sent to the authorization endpoint with the request_uri
+        Then The user should be redirected with error "invalid_request_uri"
+
+    @failure
+    Scenario Outline: Invalid authorization request is not pushed
+        When The client pushes the following authorization request:
+            | response_type   | redirect_uri   | scope   | state   | prompt   |
+            | <response_type> | <redirect_uri> | <scope> | <state> | <prompt> |
+        Then The push should fail with message "<message>"
+        Examples:
+            | response_type | redirect_uri             | scope  | state | prompt  | message              |
+            | code          | https://www.example.com/ | openid | 1234  | consent | invalid redirect uri |
+            | code          | https://www.google.com/  | openid | 1234  |         | invalid input        |
+
+    @failure
+    Scenario: Client requiring pushed authorization requests can not send the parameters on the authorization endpoint
+        Given The client requires pushed authorization requests
+        When The user is sent to the authorization endpoint with the following parameters:
+            | response_type | redirect_uri            | scope  | state | prompt  |
+            | code          | https://www.google.com/ | openid | 1234  | consent |
+        Then The user should be redirected with error "invalid_request"
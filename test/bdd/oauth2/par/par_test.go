@@ -0,0 +1,226 @@
+package par
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sso/internal/constant"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+	"sso/test"
+	"testing"
+
+	"github.com/cucumber/godog"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
+)
+
+type parTest struct {
+	test.TestInstance
+	apiTest       src.ApiTest
+	client        db.Client
+	scope         string
+	requestParam  dto.AuthorizationRequestParam
+	pushedRequest dto.PushedAuthorizationResponse
+}
+
+func TestPushedAuthorizationRequest(t *testing.T) {
+	p := &parTest{}
+	p.TestInstance = test.Initiate("../../../../")
+	p.apiTest.InitializeServer(p.Server)
+	p.apiTest.InitializeTest(t, "pushed authorization request test", "features/par.feature", p.InitializeScenario)
+}
+
+func (p *parTest) thereIsRegisteredScope(name string) error {
+	scope, err := p.DB.CreateScope(context.Background(), db.CreateScopeParams{
+		Name:        name,
+		Description: "scope for " + name,
+	})
+	if err != nil {
+		return err
+	}
+	p.scope = scope.Name
+	return nil
+}
+
+func (p *parTest) aConfidentialClientIsRegisteredWithRedirectURI(redirectURI string) error {
+	var err error
+	secret := utils.GenerateRandomString(25, true)
+	if p.client, err = p.DB.CreateClient(context.Background(), db.CreateClientParams{
+		RedirectUris: redirectURI,
+		Name:         "par client",
+		Scopes:       "openid profile email",
+		ClientType:   constant.ConfidentialClient,
+		Secret:       utils.HashSecret(secret),
+		LogoUrl:      "https://www.google.com/images/errors/robot.png",
+	}); err != nil {
+		return err
+	}
+	p.client.Secret = secret
+	return nil
+}
+
+func (p *parTest) theClientRequiresPushedAuthorizationRequests() error {
+	_, err := p.Conn.Exec(context.Background(), "UPDATE clients SET require_pushed_authorization_requests = true WHERE id = $1", p.client.ID)
+	return err
+}
+
+func (p *parTest) theClientPushesTheFollowingAuthorizationRequest(request *godog.Table) error {
+	body, err := p.apiTest.ReadRow(request, nil, false)
+	if err != nil {
+		return err
+	}
+	if err := p.apiTest.UnmarshalJSONAt([]byte(body), "", &p.requestParam); err != nil {
+		return err
+	}
+
+	form := url.Values{}
+	form.Set("response_type", p.requestParam.ResponseType)
+	form.Set("redirect_uri", p.requestParam.RedirectURI)
+	form.Set("scope", p.requestParam.Scope)
+	form.Set("state", p.requestParam.State)
+	form.Set("prompt", p.requestParam.Prompt)
+
+	p.apiTest.URL = "/v1/oauth/par"
+	p.apiTest.Method = http.MethodPost
+	p.apiTest.QueryParams = nil
+	p.apiTest.Body = form.Encode()
+	p.apiTest.SetHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(p.client.ID.String()+":"+p.client.Secret)))
+	p.apiTest.SetHeader("Content-Type", "application/x-www-form-urlencoded")
+	p.apiTest.SendRequest()
+	return nil
+}
+
+func (p *parTest) theClientPushedTheFollowingAuthorizationRequest(request *godog.Table) error {
+	if err := p.theClientPushesTheFollowingAuthorizationRequest(request); err != nil {
+		return err
+	}
+	if err := p.apiTest.AssertStatusCode(http.StatusCreated); err != nil {
+		return err
+	}
+	if err := p.apiTest.UnmarshalResponseBody(&p.pushedRequest); err != nil {
+		return err
+	}
+	if p.pushedRequest.RequestURI == "" || p.pushedRequest.ExpiresIn <= 0 {
+		return fmt.Errorf("expected a request_uri and its expiry, got %+v", p.pushedRequest)
+	}
+	return nil
+}
+
+func (p *parTest) theUserIsSentToTheAuthorizationEndpointWithTheRequestURI() error {
+	p.apiTest.URL = "/v1/oauth/authorize"
+	p.apiTest.Method = http.MethodGet
+	p.apiTest.Body = ""
+	p.apiTest.QueryParams = nil
+	p.apiTest.SetQueryParam("client_id", p.client.ID.String())
+	p.apiTest.SetQueryParam("request_uri", p.pushedRequest.RequestURI)
+	p.apiTest.SendRequest()
+	return nil
+}
+
+func (p *parTest) theUserWasSentToTheAuthorizationEndpointWithTheRequestURI() error {
+	if err := p.theUserIsSentToTheAuthorizationEndpointWithTheRequestURI(); err != nil {
+		return err
+	}
+	return p.apiTest.AssertStatusCode(http.StatusFound)
+}
+
+func (p *parTest) theUserIsSentToTheAuthorizationEndpointWithTheFollowingParameters(request *godog.Table) error {
+	body, err := p.apiTest.ReadRow(request, nil, false)
+	if err != nil {
+		return err
+	}
+	if err := p.apiTest.UnmarshalJSONAt([]byte(body), "", &p.requestParam); err != nil {
+		return err
+	}
+
+	p.apiTest.URL = "/v1/oauth/authorize"
+	p.apiTest.Method = http.MethodGet
+	p.apiTest.Body = ""
+	p.apiTest.QueryParams = nil
+	p.apiTest.SetQueryParam("client_id", p.client.ID.String())
+	p.apiTest.SetQueryParam("response_type", p.requestParam.ResponseType)
+	p.apiTest.SetQueryParam("redirect_uri", p.requestParam.RedirectURI)
+	p.apiTest.SetQueryParam("scope", p.requestParam.Scope)
+	p.apiTest.SetQueryParam("state", p.requestParam.State)
+	p.apiTest.SetQueryParam("prompt", p.requestParam.Prompt)
+	p.apiTest.SendRequest()
+	return nil
+}
+
+func (p *parTest) redirectQuery() (url.Values, error) {
+	if err := p.apiTest.AssertStatusCode(http.StatusFound); err != nil {
+		return nil, err
+	}
+	location, err := url.Parse(p.apiTest.Response.Header().Get("Location"))
+	if err != nil {
+		return nil, err
+	}
+	return location.Query(), nil
+}
+
+func (p *parTest) theUserShouldBeAskedConsentForThePushedRequest() error {
+	query, err := p.redirectQuery()
+	if err != nil {
+		return err
+	}
+	if !query.Has("consentId") {
+		return fmt.Errorf("expected consentId on the redirect, got %v", query)
+	}
+
+	consent, err := p.CacheLayer.ConsentCacheLayer.GetConsent(context.Background(), query.Get("consentId"))
+	if err != nil {
+		return err
+	}
+	if err := p.apiTest.AssertEqual(consent.ClientID, p.client.ID); err != nil {
+		return err
+	}
+	if err := p.apiTest.AssertEqual(consent.RedirectURI, p.requestParam.RedirectURI); err != nil {
+		return err
+	}
+	return p.apiTest.AssertEqual(consent.State, p.requestParam.State)
+}
+
+func (p *parTest) theUserShouldBeRedirectedWithError(errorCode string) error {
+	query, err := p.redirectQuery()
+	if err != nil {
+		return err
+	}
+	return p.apiTest.AssertEqual(query.Get("error"), errorCode)
+}
+
+func (p *parTest) thePushShouldFailWithMessage(message string) error {
+	if err := p.apiTest.AssertStatusCode(http.StatusBadRequest); err != nil {
+		return err
+	}
+	return p.apiTest.AssertBodyColumn("error.message", message)
+}
+
+func (p *parTest) InitializeScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		p.pushedRequest = dto.PushedAuthorizationResponse{}
+		p.requestParam = dto.AuthorizationRequestParam{}
+		return ctx, nil
+	})
+
+	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		_, _ = p.DB.DeleteClient(context.Background(), p.client.ID)
+		_, _ = p.DB.DeleteScope(context.Background(), p.scope)
+		p.apiTest.QueryParams = nil
+		return ctx, nil
+	})
+
+	ctx.Step(`^there is registered scope "([^"]*)"$`, p.thereIsRegisteredScope)
+	ctx.Step(`^A confidential client is registered with redirect uri "([^"]*)"$`, p.aConfidentialClientIsRegisteredWithRedirectURI)
+	ctx.Step(`^The client requires pushed authorization requests$`, p.theClientRequiresPushedAuthorizationRequests)
+	ctx.Step(`^The client pushes the following authorization request:$`, p.theClientPushesTheFollowingAuthorizationRequest)
+	ctx.Step(`^The client pushed the following authorization request:$`, p.theClientPushedTheFollowingAuthorizationRequest)
+	ctx.Step(`^The user is sent to the authorization endpoint with the request_uri$`, p.theUserIsSentToTheAuthorizationEndpointWithTheRequestURI)
+	ctx.Step(`^The user was sent to the authorization endpoint with the request_uri$`, p.theUserWasSentToTheAuthorizationEndpointWithTheRequestURI)
+	ctx.Step(`^The user is sent to the authorization endpoint with the following parameters:$`, p.theUserIsSentToTheAuthorizationEndpointWithTheFollowingParameters)
+	ctx.Step(`^The user should be asked consent for the pushed request$`, p.theUserShouldBeAskedConsentForThePushedRequest)
+	ctx.Step(`^The user should be redirected with error "([^"]*)"$`, p.theUserShouldBeRedirectedWithError)
+	ctx.Step(`^The push should fail with message "([^"]*)"$`, p.thePushShouldFailWithMessage)
+}
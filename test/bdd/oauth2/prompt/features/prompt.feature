@@ -0,0 +1,65 @@
+Feature: Silent authorization and max_age
+
+    As a client
+
+    I want to authorize users who already logged in and granted me access without showing them any page
+
+    So that I can renew their tokens in the background and ask them to log in again when their login is too old.
+    Background: there is a client the user may have granted access to
+        Given there is registered scope "openid"
+        And A client is registered with redirect uri "https://www.google.com/"
+        And I am logged in with the following credentials
+            | email              | password   |
+            | prompt@example.com | myPassword |
+
+    @success
+    Scenario: The code is issued right away to a client the user granted access to
+        Given I have granted the client access to "openid"
+        When I am sent to the authorization endpoint with prompt "none" and state "silent-state"
+        Then I should be redirected to the client with a code and state "silent-state"
+
+    @failure
+    Scenario: Login is required when the user agent has no session
+        Given I have granted the client access to "openid"
+        And My browser has no session
+        When I am sent to the authorization endpoint with prompt "none" and state "silent-state"
+        Then I should be redirected to the client with error "login_required" and state "silent-state"
+
+    @failure
+    Scenario: Consent is required when the user has not granted the client access
+        When I am sent to the authorization endpoint with prompt "none" and state "silent-state"
+        Then I should be redirected to the client with error "consent_required" and state "silent-state"
+
+    @failure
+    Scenario: Login is required when the user logged in before max_age
+        Given I have granted the client access to "openid"
+        And I logged in 10 minutes ago
+        When I am sent to the authorization endpoint with prompt "none", state "silent-state" and max_age 60
+        Then I should be redirected to the client with error "login_required" and state "silent-state"
+
+    @success
+    Scenario: The user is asked to log in again when the login is older than max_age
+        Given I logged in 10 minutes ago
+        When I am sent to the authorization endpoint with prompt "consent", state "state" and max_age 60
+        Then I should be asked consent with prompt "login"
+
+    @success
+    Scenario: The user is not asked to log in again when the login is within max_age
+        When I am sent to the authorization endpoint with prompt "consent", state "state" and max_age 600
+        Then I should be asked consent with prompt "consent"
+
+    @success
+    Scenario: The user logs in again to approve a consent with a max_age of 0
+        Given a second has passed since I logged in
+        When I am sent to the authorization endpoint with prompt "consent", state "state" and max_age 0
+        Then I should be asked consent with prompt "login"
+        When I log in again
+        And I approve the consent
+        Then the consent should redirect me to the client with a code and state "state"
+
+    @failure
+    Scenario: A consent with a max_age of 0 can't be approved with a login made before the request
+        Given a second has passed since I logged in
+        When I am sent to the authorization endpoint with prompt "consent", state "state" and max_age 0
+        And I approve the consent
+        Then the consent should redirect me to the client with error "login_required" and state "state"
@@ -0,0 +1,295 @@
+package prompt
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sso/internal/constant"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+	"sso/test"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cucumber/godog"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
+)
+
+type promptTest struct {
+	test.TestInstance
+	apiTest      src.ApiTest
+	client       db.Client
+	scope        string
+	user         db.User
+	credentials  map[string]string
+	refreshToken string
+	consentID    string
+}
+
+func TestPrompt(t *testing.T) {
+	p := &promptTest{}
+	p.TestInstance = test.Initiate("../../../../")
+	p.apiTest.InitializeServer(p.Server)
+	p.apiTest.InitializeTest(t, "prompt and max_age test", "features/prompt.feature", p.InitializeScenario)
+}
+
+func (p *promptTest) thereIsRegisteredScope(name string) error {
+	scope, err := p.DB.CreateScope(context.Background(), db.CreateScopeParams{
+		Name:        name,
+		Description: "scope for " + name,
+	})
+	if err != nil {
+		return err
+	}
+	p.scope = scope.Name
+	return nil
+}
+
+func (p *promptTest) aClientIsRegisteredWithRedirectURI(redirectURI string) error {
+	var err error
+	p.client, err = p.DB.CreateClient(context.Background(), db.CreateClientParams{
+		RedirectUris: redirectURI,
+		Name:         "prompt client",
+		Scopes:       "openid profile email",
+		ClientType:   constant.ConfidentialClient,
+		Secret:       utils.HashSecret(utils.GenerateRandomString(25, true)),
+		LogoUrl:      "https://www.google.com/images/errors/robot.png",
+	})
+	return err
+}
+
+func (p *promptTest) iAmLoggedInWithTheFollowingCredentials(credentials *godog.Table) error {
+	rows, err := p.apiTest.ReadRowsToMapString(credentials)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("no credentials were given")
+	}
+	p.credentials = rows[0]
+
+	if p.user, err = p.Authenticate(credentials); err != nil {
+		return err
+	}
+	p.refreshToken = p.RefreshToken
+	return nil
+}
+
+func (p *promptTest) aSecondHasPassedSinceILoggedIn() error {
+	// auth_time is in seconds, the login must fall on an earlier second than the request
+	time.Sleep(time.Second)
+	return nil
+}
+
+func (p *promptTest) iLogInAgain() error {
+	login := src.ApiTest{
+		URL:    "/v1/login",
+		Method: http.MethodPost,
+	}
+	login.InitializeServer(p.Server)
+	login.SetHeader("Content-Type", "application/json")
+	login.SetBodyMap(map[string]interface{}{
+		"email":    p.credentials["email"],
+		"password": p.credentials["password"],
+	})
+	login.SendRequest()
+	if err := login.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+
+	return login.UnmarshalResponseBodyPath("data.access_token", &p.AccessToken)
+}
+
+func (p *promptTest) iApproveTheConsent() error {
+	p.apiTest.ResetResponse()
+	p.apiTest.URL = "/v1/oauth/approveConsent"
+	p.apiTest.Method = http.MethodPost
+	p.apiTest.QueryParams = nil
+	p.apiTest.SetHeader("Content-Type", "application/json")
+	p.apiTest.SetHeader("Authorization", "Bearer "+p.AccessToken)
+	p.apiTest.SetBodyMap(map[string]interface{}{
+		"consent_id": p.consentID,
+	})
+	p.apiTest.AddCookie(http.Cookie{
+		Name:  "opbs",
+		Value: utils.GenerateNewOPBS(),
+	})
+	p.apiTest.SendRequest()
+	return nil
+}
+
+func (p *promptTest) iHaveGrantedTheClientAccessTo(scope string) error {
+	_, err := p.DB.SaveRefreshToken(context.Background(), db.SaveRefreshTokenParams{
+		ExpiresAt:    time.Now().Add(10 * time.Minute),
+		UserID:       p.user.ID,
+		Scope:        sql.NullString{String: scope, Valid: true},
+		RedirectUri:  sql.NullString{String: p.client.RedirectUris, Valid: true},
+		ClientID:     p.client.ID,
+		RefreshToken: utils.GenerateRandomString(10, false),
+		Code:         utils.GenerateRandomString(10, false),
+	})
+	return err
+}
+
+func (p *promptTest) myBrowserHasNoSession() error {
+	p.refreshToken = ""
+	return nil
+}
+
+func (p *promptTest) iLoggedInMinutesAgo(minutes int) error {
+	_, err := p.Conn.Exec(context.Background(), "UPDATE internalrefreshtokens SET auth_time = $1 WHERE refresh_token = $2",
+		time.Now().Add(-time.Duration(minutes)*time.Minute), p.refreshToken)
+	return err
+}
+
+func (p *promptTest) iAmSentToTheAuthorizationEndpointWithPromptAndState(prompt, state string) error {
+	p.apiTest.SetQueryParam("client_id", p.client.ID.String())
+	p.apiTest.SetQueryParam("response_type", constant.ResponseTypeCode)
+	p.apiTest.SetQueryParam("redirect_uri", p.client.RedirectUris)
+	p.apiTest.SetQueryParam("scope", p.scope)
+	p.apiTest.SetQueryParam("state", state)
+	p.apiTest.SetQueryParam("prompt", prompt)
+	p.apiTest.SetHeader("Cookie", "")
+	if p.refreshToken != "" {
+		p.apiTest.SetHeader("Cookie", fmt.Sprintf("ab_fen=%s; opbs=%s", p.refreshToken, utils.GenerateNewOPBS()))
+	}
+	p.apiTest.SendRequest()
+	return nil
+}
+
+func (p *promptTest) iAmSentToTheAuthorizationEndpointWithPromptStateAndMaxAge(prompt, state string, maxAge int) error {
+	p.apiTest.SetQueryParam("max_age", strconv.Itoa(maxAge))
+	return p.iAmSentToTheAuthorizationEndpointWithPromptAndState(prompt, state)
+}
+
+func (p *promptTest) redirectQuery() (url.Values, error) {
+	if err := p.apiTest.AssertStatusCode(http.StatusFound); err != nil {
+		return nil, err
+	}
+	location, err := url.Parse(p.apiTest.Response.Header().Get("Location"))
+	if err != nil {
+		return nil, err
+	}
+	return location.Query(), nil
+}
+
+// consentRedirectQuery returns the query of the url the approval of the consent redirects to.
+func (p *promptTest) consentRedirectQuery() (url.Values, error) {
+	if err := p.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return nil, err
+	}
+	var redirect dto.RedirectResponse
+	if err := p.apiTest.UnmarshalResponseBodyPath("data", &redirect); err != nil {
+		return nil, err
+	}
+	location, err := url.Parse(redirect.Location)
+	if err != nil {
+		return nil, err
+	}
+	return location.Query(), nil
+}
+
+func (p *promptTest) iShouldBeRedirectedToTheClientWithACodeAndState(state string) error {
+	query, err := p.redirectQuery()
+	if err != nil {
+		return err
+	}
+	return p.assertCodeAndState(query, state)
+}
+
+func (p *promptTest) theConsentShouldRedirectMeToTheClientWithACodeAndState(state string) error {
+	query, err := p.consentRedirectQuery()
+	if err != nil {
+		return err
+	}
+	return p.assertCodeAndState(query, state)
+}
+
+func (p *promptTest) assertCodeAndState(query url.Values, state string) error {
+	if query.Get("code") == "" || query.Get("session_state") == "" {
+		return fmt.Errorf("expected a code and session_state on the redirect, got %v", query)
+	}
+
+	authCode, err := p.CacheLayer.AuthCodeCacheLayer.GetAuthCode(context.Background(), query.Get("code"))
+	if err != nil {
+		return err
+	}
+	if err := p.apiTest.AssertEqual(authCode.UserID, p.user.ID); err != nil {
+		return err
+	}
+	return p.apiTest.AssertEqual(query.Get("state"), state)
+}
+
+func (p *promptTest) iShouldBeRedirectedToTheClientWithErrorAndState(errorCode, state string) error {
+	query, err := p.redirectQuery()
+	if err != nil {
+		return err
+	}
+	return p.assertErrorAndState(query, errorCode, state)
+}
+
+func (p *promptTest) theConsentShouldRedirectMeToTheClientWithErrorAndState(errorCode, state string) error {
+	query, err := p.consentRedirectQuery()
+	if err != nil {
+		return err
+	}
+	return p.assertErrorAndState(query, errorCode, state)
+}
+
+func (p *promptTest) assertErrorAndState(query url.Values, errorCode, state string) error {
+	if err := p.apiTest.AssertEqual(query.Get("error"), errorCode); err != nil {
+		return err
+	}
+	return p.apiTest.AssertEqual(query.Get("state"), state)
+}
+
+func (p *promptTest) iShouldBeAskedConsentWithPrompt(prompt string) error {
+	query, err := p.redirectQuery()
+	if err != nil {
+		return err
+	}
+	if !query.Has("consentId") {
+		return fmt.Errorf("expected consentId on the redirect, got %v", query)
+	}
+	p.consentID = query.Get("consentId")
+	return p.apiTest.AssertEqual(query.Get("prompt"), prompt)
+}
+
+func (p *promptTest) InitializeScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		p.apiTest.URL = "/v1/oauth/authorize"
+		p.apiTest.Method = http.MethodGet
+		p.apiTest.QueryParams = nil
+		p.apiTest.SetHeader("Authorization", "")
+		return ctx, nil
+	})
+
+	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		_, _ = p.Conn.Exec(context.Background(), "DELETE FROM refresh_tokens WHERE client_id = $1", p.client.ID)
+		_, _ = p.DB.DeleteClient(context.Background(), p.client.ID)
+		_, _ = p.DB.DeleteScope(context.Background(), p.scope)
+		_, _ = p.DB.DeleteUser(context.Background(), p.user.ID)
+		return ctx, nil
+	})
+
+	ctx.Step(`^there is registered scope "([^"]*)"$`, p.thereIsRegisteredScope)
+	ctx.Step(`^A client is registered with redirect uri "([^"]*)"$`, p.aClientIsRegisteredWithRedirectURI)
+	ctx.Step(`^I am logged in with the following credentials$`, p.iAmLoggedInWithTheFollowingCredentials)
+	ctx.Step(`^I have granted the client access to "([^"]*)"$`, p.iHaveGrantedTheClientAccessTo)
+	ctx.Step(`^My browser has no session$`, p.myBrowserHasNoSession)
+	ctx.Step(`^I logged in (\d+) minutes ago$`, p.iLoggedInMinutesAgo)
+	ctx.Step(`^a second has passed since I logged in$`, p.aSecondHasPassedSinceILoggedIn)
+	ctx.Step(`^I log in again$`, p.iLogInAgain)
+	ctx.Step(`^I approve the consent$`, p.iApproveTheConsent)
+	ctx.Step(`^I am sent to the authorization endpoint with prompt "([^"]*)" and state "([^"]*)"$`, p.iAmSentToTheAuthorizationEndpointWithPromptAndState)
+	ctx.Step(`^I am sent to the authorization endpoint with prompt "([^"]*)", state "([^"]*)" and max_age (\d+)$`, p.iAmSentToTheAuthorizationEndpointWithPromptStateAndMaxAge)
+	ctx.Step(`^I should be redirected to the client with a code and state "([^"]*)"$`, p.iShouldBeRedirectedToTheClientWithACodeAndState)
+	ctx.Step(`^I should be redirected to the client with error "([^"]*)" and state "([^"]*)"$`, p.iShouldBeRedirectedToTheClientWithErrorAndState)
+	ctx.Step(`^the consent should redirect me to the client with a code and state "([^"]*)"$`, p.theConsentShouldRedirectMeToTheClientWithACodeAndState)
+	ctx.Step(`^the consent should redirect me to the client with error "([^"]*)" and state "([^"]*)"$`, p.theConsentShouldRedirectMeToTheClientWithErrorAndState)
+	ctx.Step(`^I should be asked consent with prompt "([^"]*)"$`, p.iShouldBeAskedConsentWithPrompt)
+}
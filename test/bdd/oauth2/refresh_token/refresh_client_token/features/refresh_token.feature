@@ -17,7 +17,17 @@ Feature: Refresh Token
         When I refresh the access token:
             | grant_type    | refresh_token            |
             | refresh_token | +toNc!tKC8q;,SXt7h%iu#aX |
-        Then I should get a new access token with the old refresh token
+        Then I should get a new access token with a new refresh token
+        And The old refresh token should be deleted
+        And The id token should carry the authentication of the grant
+
+    Scenario: Superseded refresh token is reused
+        Given The refresh token has been rotated
+        When I refresh the access token:
+            | grant_type    | refresh_token            |
+            | refresh_token | +toNc!tKC8q;,SXt7h%iu#aX |
+        Then The request should fail with error message "refresh token reuse detected":
+        And The refresh token family should be revoked
 
     Scenario Outline:missing required inputs
         When I refresh the access token:
@@ -39,4 +49,23 @@ Feature: Refresh Token
         Then The request should fail with error message "<error_message>":
         Examples:
             | error_message         |
-            | refresh token expired |
\ No newline at end of file
+            | refresh token expired |
+
+    Scenario: Refresh token is rejected after the idle lifetime of the client
+        Given The client has a refresh token idle lifetime of 60 seconds
+        And The refresh token was issued 300 seconds ago and last used 120 seconds ago
+        When I refresh the access token:
+            | grant_type    | refresh_token            |
+            | refresh_token | +toNc!tKC8q;,SXt7h%iu#aX |
+        Then The request should fail with error message "refresh token expired":
+
+    Scenario: Refresh token is rejected after the absolute lifetime of the client even when it keeps being rotated
+        Given The client has a refresh token absolute lifetime of 3600 seconds
+        And The refresh token was issued 3500 seconds ago and last used 10 seconds ago
+        When I refresh the access token:
+            | grant_type    | refresh_token            |
+            | refresh_token | +toNc!tKC8q;,SXt7h%iu#aX |
+        Then I should get a new access token with a new refresh token
+        Given The refresh token was issued 3700 seconds ago and last used 0 seconds ago
+        When I refresh the access token with the new refresh token
+        Then The request should fail with error message "refresh token expired":
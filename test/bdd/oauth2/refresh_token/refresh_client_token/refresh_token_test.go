@@ -2,15 +2,19 @@ package refreshtoken
 
 import (
 	"context"
+	"database/sql"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"net/http"
+	"sso/internal/constant"
 	"sso/internal/constant/errors/sqlcerr"
 	"sso/internal/constant/model/db"
 	"sso/internal/constant/model/dto"
 	"sso/platform/utils"
 	"sso/test"
 	"testing"
+	"time"
 
 	"github.com/cucumber/godog"
 	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
@@ -25,6 +29,7 @@ type refreshClientTokenTest struct {
 	user                dto.User
 	refreshToken        dto.RefreshToken
 	expiredRefreshToken dto.RefreshToken
+	authTime            time.Time
 	redisSeeder         seed.RedisDB
 }
 
@@ -106,7 +111,7 @@ func (r *refreshClientTokenTest) thereIsAClientOnTheSystem(client *godog.Table)
 	clientData, err := r.DB.CreateClient(context.Background(), db.CreateClientParams{
 		Name:         r.client.Name,
 		RedirectUris: utils.ArrayToString(r.client.RedirectURIs),
-		Secret:       r.client.Secret,
+		Secret:       utils.HashSecret(r.client.Secret),
 		Scopes:       r.client.Scopes,
 		ClientType:   r.client.ClientType,
 		LogoUrl:      r.client.LogoURL,
@@ -171,6 +176,7 @@ func (r *refreshClientTokenTest) theUserGrantsAccessToTheClient(rfParam *godog.T
 		return err
 	}
 
+	r.authTime = time.Now().Add(-time.Hour).Truncate(time.Second)
 	rfData, err := r.DB.SaveRefreshToken(context.Background(), db.SaveRefreshTokenParams{
 		UserID:       r.user.ID,
 		ClientID:     r.client.ID,
@@ -178,6 +184,8 @@ func (r *refreshClientTokenTest) theUserGrantsAccessToTheClient(rfParam *godog.T
 		RefreshToken: r.refreshToken.RefreshToken,
 		RedirectUri:  utils.StringOrNull(utils.ArrayToString(r.client.RedirectURIs)),
 		ExpiresAt:    r.refreshToken.ExpiresAt,
+		AuthTime:     sql.NullTime{Time: r.authTime, Valid: true},
+		Amr:          utils.StringOrNull(constant.AMRPassword),
 	})
 	if err != nil {
 		return err
@@ -210,6 +218,13 @@ func (r *refreshClientTokenTest) iShouldGetANewAccessTokenWithANewRefreshToken()
 	if err := r.apiTest.AssertColumnExists("data.token_type"); err != nil {
 		return err
 	}
+
+	if err := r.apiTest.UnmarshalResponseBodyPath("data", &r.AccessToken); err != nil {
+		return err
+	}
+	if r.AccessToken.RefreshToken == r.refreshToken.RefreshToken {
+		return errors.New("refresh token was not rotated")
+	}
 	return nil
 }
 
@@ -221,6 +236,75 @@ func (r *refreshClientTokenTest) theOldRefreshTokenShouldBeDeleted() error {
 	}
 	return nil
 }
+func (r *refreshClientTokenTest) theIDTokenShouldCarryTheAuthenticationOfTheGrant() error {
+	valid, claims := r.PlatformLayer.Token.VerifyIdToken(r.AccessToken.IDToken)
+	if err := r.apiTest.AssertEqual(valid, true); err != nil {
+		return err
+	}
+	if claims.AuthTime == nil {
+		return errors.New("auth_time is not set on the id token")
+	}
+	if err := r.apiTest.AssertEqual(claims.AuthTime.Unix(), r.authTime.Unix()); err != nil {
+		return err
+	}
+	if err := r.apiTest.AssertEqual(claims.ACR, dto.Authentication{Methods: []string{constant.AMRPassword}}.ACR()); err != nil {
+		return err
+	}
+	return r.apiTest.AssertEqual(claims.AMR, []string{constant.AMRPassword})
+}
+
+func (r *refreshClientTokenTest) theClientHasARefreshTokenLifetimeOfSeconds(kind string, seconds int) error {
+	_, err := r.Conn.Exec(context.Background(),
+		fmt.Sprintf("UPDATE clients SET refresh_token_%s_lifetime = $1 WHERE id = $2", kind), seconds, r.client.ID)
+	return err
+}
+
+// theRefreshTokenWasIssuedSecondsAgoAndLastUsedSecondsAgo moves the grant back in time,
+// the refresh token itself is kept from expiring so only the lifetimes of the client apply.
+func (r *refreshClientTokenTest) theRefreshTokenWasIssuedSecondsAgoAndLastUsedSecondsAgo(issued, used int) error {
+	_, err := r.Conn.Exec(context.Background(),
+		`UPDATE refresh_tokens
+		SET expires_at = now() + interval '1 day',
+		    created_at = now() - make_interval(secs => $1),
+		    updated_at = now() - make_interval(secs => $2)
+		WHERE id = $3`, issued, used, r.refreshToken.ID)
+	return err
+}
+
+func (r *refreshClientTokenTest) iRefreshTheAccessTokenWithTheNewRefreshToken() error {
+	r.apiTest.SetBodyMap(map[string]interface{}{
+		"grant_type":    constant.RefreshToken,
+		"refresh_token": r.AccessToken.RefreshToken,
+	})
+	r.apiTest.SetHeader("Authorization", "Basic "+basicAuth(r.client.ID.String(), r.client.Secret))
+	r.apiTest.SendRequest()
+	return nil
+}
+
+func (r *refreshClientTokenTest) theRefreshTokenHasBeenRotated() error {
+	_, err := r.PersistDB.RotateRefreshTokenTX(context.Background(), utils.GenerateRandomString(25, false), r.refreshToken.RefreshToken)
+	return err
+}
+
+func (r *refreshClientTokenTest) theRefreshTokenFamilyShouldBeRevoked() error {
+	var count int
+	if err := r.Conn.QueryRow(context.Background(),
+		"SELECT count(*) FROM refresh_tokens WHERE id = $1", r.refreshToken.ID).Scan(&count); err != nil {
+		return err
+	}
+	if err := r.apiTest.AssertEqual(count, 0); err != nil {
+		return err
+	}
+
+	var status string
+	if err := r.Conn.QueryRow(context.Background(),
+		"SELECT status FROM auth_histories WHERE client_id = $1 AND user_id = $2 ORDER BY created_at DESC LIMIT 1",
+		r.client.ID, r.user.ID).Scan(&status); err != nil {
+		return err
+	}
+	return r.apiTest.AssertEqual(status, constant.Revoke)
+}
+
 func (r *refreshClientTokenTest) InitializeScenario(ctx *godog.ScenarioContext) {
 	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
 		r.apiTest.URL = "/v1/oauth/token"
@@ -231,6 +315,7 @@ func (r *refreshClientTokenTest) InitializeScenario(ctx *godog.ScenarioContext)
 	})
 
 	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		_, _ = r.Conn.Exec(ctx, "DELETE FROM auth_histories WHERE client_id = $1", r.client.ID)
 		_, _ = r.DB.DeleteClient(context.Background(), r.client.ID)
 		_, _ = r.DB.DeleteUser(context.Background(), r.user.ID)
 		_ = r.DB.RemoveRefreshToken(context.Background(), r.AccessToken.RefreshToken)
@@ -239,8 +324,14 @@ func (r *refreshClientTokenTest) InitializeScenario(ctx *godog.ScenarioContext)
 
 	ctx.Step(`^I have an expired refresh token:$`, r.iHaveAnExpiredRefreshToken)
 	ctx.Step(`^I refresh the access token:$`, r.iRefreshTheAccessToken)
-	ctx.Step(`^I should get a new access token with the old refresh token$`, r.iShouldGetANewAccessTokenWithANewRefreshToken)
-	//ctx.Step(`^The old refresh token should be deleted$`, r.theOldRefreshTokenShouldBeDeleted)
+	ctx.Step(`^I refresh the access token with the new refresh token$`, r.iRefreshTheAccessTokenWithTheNewRefreshToken)
+	ctx.Step(`^The client has a refresh token (idle|absolute) lifetime of (\d+) seconds$`, r.theClientHasARefreshTokenLifetimeOfSeconds)
+	ctx.Step(`^The refresh token was issued (\d+) seconds ago and last used (\d+) seconds ago$`, r.theRefreshTokenWasIssuedSecondsAgoAndLastUsedSecondsAgo)
+	ctx.Step(`^I should get a new access token with a new refresh token$`, r.iShouldGetANewAccessTokenWithANewRefreshToken)
+	ctx.Step(`^The old refresh token should be deleted$`, r.theOldRefreshTokenShouldBeDeleted)
+	ctx.Step(`^The id token should carry the authentication of the grant$`, r.theIDTokenShouldCarryTheAuthenticationOfTheGrant)
+	ctx.Step(`^The refresh token has been rotated$`, r.theRefreshTokenHasBeenRotated)
+	ctx.Step(`^The refresh token family should be revoked$`, r.theRefreshTokenFamilyShouldBeRevoked)
 	ctx.Step(`^The request should fail with error message "([^"]*)":$`, r.theRequestShouldFailWithErrorMessage)
 	ctx.Step(`^The request should fail with field error "([^"]*)":$`, r.theRequestShouldFailWithFieldError)
 	ctx.Step(`^The user grants access to the client:$`, r.theUserGrantsAccessToTheClient)
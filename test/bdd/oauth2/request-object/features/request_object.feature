@@ -0,0 +1,48 @@
+Feature: JWT Secured Authorization Requests
+
+    As a client
+
+    I want to sign the parameters of my authorization requests
+
+    So that they can't be tampered with in the browser.
+    Background: there is a client with registered keys
+        Given there is registered scope "openid"
+        And A client with registered keys is registered with redirect uri "https://www.google.com/"
+
+    @success
+    Scenario: Parameters of the request object take precedence over the query parameters
+        Given The client signed a request object with the following parameters:
+            | response_type | redirect_uri            | scope  | state        | prompt  |
+            | code          | https://www.google.com/ | openid | object-state | consent |
+        When The user is sent to the authorization endpoint with the request object and the following parameters:
+            | response_type | redirect_uri            | scope  | state       | prompt |
+            | code          | https://www.google.com/ | openid | query-state | none   |
+        Then The user should be asked consent with state "object-state" and prompt "consent"
+
+    @failure
+    Scenario Outline: Invalid request object is rejected
+        Given The client signed a request object <problem> with the following parameters:
+            | response_type | redirect_uri            | scope  | state        | prompt  |
+            | code          | https://www.google.com/ | openid | object-state | consent |
+        When The user is sent to the authorization endpoint with the request object and the following parameters:
+            | response_type | redirect_uri            | scope  | state       | prompt |
+            | code          | https://www.google.com/ | openid | query-state | none   |
+        Then The user should be redirected to the error page with "invalid_request_object" and "<error_description>"
+        Examples:
+            | problem                  | error_description                |
+            | with another key         | invalid request object signature |
+            | that is expired          | request object is expired        |
+            | for another audience     | invalid request object audience  |
+            | issued by another client | invalid request object issuer    |
+            | without a jti            | request object must have a jti   |
+
+    @failure
+    Scenario: Replayed request object is rejected
+        Given The client signed a request object with the following parameters:
+            | response_type | redirect_uri            | scope  | state        | prompt  |
+            | code          | https://www.google.com/ | openid | object-state | consent |
+        And The request object was already used
+        When The user is sent to the authorization endpoint with the request object and the following parameters:
+            | response_type | redirect_uri            | scope  | state       | prompt |
+            | code          | https://www.google.com/ | openid | query-state | none   |
+        Then The user should be redirected to the error page with "invalid_request_object" and "request object was already used"
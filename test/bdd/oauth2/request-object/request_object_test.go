@@ -0,0 +1,227 @@
+package requestobject
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sso/internal/constant"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/test"
+	"testing"
+	"time"
+
+	"github.com/cucumber/godog"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
+)
+
+type requestObjectTest struct {
+	test.TestInstance
+	apiTest       src.ApiTest
+	client        db.Client
+	scope         string
+	key           *ecdsa.PrivateKey
+	requestObject string
+}
+
+func TestRequestObject(t *testing.T) {
+	r := &requestObjectTest{}
+	r.TestInstance = test.Initiate("../../../../")
+	r.apiTest.InitializeServer(r.Server)
+	r.apiTest.InitializeTest(t, "request object test", "features/request_object.feature", r.InitializeScenario)
+}
+
+func (r *requestObjectTest) thereIsRegisteredScope(name string) error {
+	scope, err := r.DB.CreateScope(context.Background(), db.CreateScopeParams{
+		Name:        name,
+		Description: "scope for " + name,
+	})
+	if err != nil {
+		return err
+	}
+	r.scope = scope.Name
+	return nil
+}
+
+func (r *requestObjectTest) aClientWithRegisteredKeysIsRegisteredWithRedirectURI(redirectURI string) error {
+	var err error
+	if r.key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+		return err
+	}
+
+	x, y := make([]byte, 32), make([]byte, 32)
+	jwks, err := json.Marshal(dto.JWKS{Keys: []dto.JWK{{
+		Kty: "EC",
+		Use: "sig",
+		Kid: "client-key",
+		Alg: constant.SigningAlgorithmES256,
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(r.key.X.FillBytes(x)),
+		Y:   base64.RawURLEncoding.EncodeToString(r.key.Y.FillBytes(y)),
+	}}})
+	if err != nil {
+		return err
+	}
+
+	r.client, err = r.DB.CreateClient(context.Background(), db.CreateClientParams{
+		RedirectUris:            redirectURI,
+		Name:                    "signing client",
+		Scopes:                  "openid profile email",
+		ClientType:              constant.ConfidentialClient,
+		LogoUrl:                 "https://www.google.com/images/errors/robot.png",
+		TokenEndpointAuthMethod: constant.PrivateKeyJWT,
+		Jwks:                    string(jwks),
+	})
+	return err
+}
+
+func (r *requestObjectTest) theClientSignedARequestObjectWithTheFollowingParameters(params *godog.Table) error {
+	return r.theClientSignedARequestObjectProblemWithTheFollowingParameters("", params)
+}
+
+func (r *requestObjectTest) theClientSignedARequestObjectProblemWithTheFollowingParameters(problem string, params *godog.Table) error {
+	body, err := r.apiTest.ReadRow(params, nil, false)
+	if err != nil {
+		return err
+	}
+	var claims dto.RequestObject
+	if err := r.apiTest.UnmarshalJSONAt([]byte(body), "", &claims); err != nil {
+		return err
+	}
+
+	configuration, err := r.Module.OAuth2Module.OpenIDConfiguration(context.Background())
+	if err != nil {
+		return err
+	}
+	claims.ClientID = r.client.ID.String()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Issuer:    r.client.ID.String(),
+		Audience:  jwt.ClaimStrings{configuration.Issuer},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ID:        uuid.NewString(),
+	}
+
+	key := r.key
+	switch problem {
+	case "with another key":
+		if key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+			return err
+		}
+	case "that is expired":
+		claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	case "for another audience":
+		claims.Audience = jwt.ClaimStrings{"https://another.example.com"}
+	case "issued by another client":
+		claims.Issuer = uuid.NewString()
+	case "without a jti":
+		claims.ID = ""
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = "client-key"
+	r.requestObject, err = token.SignedString(key)
+	return err
+}
+
+func (r *requestObjectTest) theUserIsSentToTheAuthorizationEndpointWithTheRequestObjectAndTheFollowingParameters(params *godog.Table) error {
+	body, err := r.apiTest.ReadRow(params, nil, false)
+	if err != nil {
+		return err
+	}
+	var requestParam dto.AuthorizationRequestParam
+	if err := r.apiTest.UnmarshalJSONAt([]byte(body), "", &requestParam); err != nil {
+		return err
+	}
+
+	r.apiTest.SetQueryParam("client_id", r.client.ID.String())
+	r.apiTest.SetQueryParam("response_type", requestParam.ResponseType)
+	r.apiTest.SetQueryParam("redirect_uri", requestParam.RedirectURI)
+	r.apiTest.SetQueryParam("scope", requestParam.Scope)
+	r.apiTest.SetQueryParam("state", requestParam.State)
+	r.apiTest.SetQueryParam("prompt", requestParam.Prompt)
+	r.apiTest.SetQueryParam("request", r.requestObject)
+	r.apiTest.SendRequest()
+	return nil
+}
+
+func (r *requestObjectTest) theRequestObjectWasAlreadyUsed() error {
+	r.apiTest.SetQueryParam("client_id", r.client.ID.String())
+	r.apiTest.SetQueryParam("request", r.requestObject)
+	r.apiTest.SendRequest()
+	r.apiTest.ResetResponse()
+	return nil
+}
+
+func (r *requestObjectTest) redirectQuery() (url.Values, error) {
+	if err := r.apiTest.AssertStatusCode(http.StatusFound); err != nil {
+		return nil, err
+	}
+	location, err := url.Parse(r.apiTest.Response.Header().Get("Location"))
+	if err != nil {
+		return nil, err
+	}
+	return location.Query(), nil
+}
+
+func (r *requestObjectTest) theUserShouldBeAskedConsentWithStateAndPrompt(state, prompt string) error {
+	query, err := r.redirectQuery()
+	if err != nil {
+		return err
+	}
+	if !query.Has("consentId") {
+		return fmt.Errorf("expected consentId on the redirect, got %v", query)
+	}
+	if err := r.apiTest.AssertEqual(query.Get("prompt"), prompt); err != nil {
+		return err
+	}
+
+	consent, err := r.CacheLayer.ConsentCacheLayer.GetConsent(context.Background(), query.Get("consentId"))
+	if err != nil {
+		return err
+	}
+	return r.apiTest.AssertEqual(consent.State, state)
+}
+
+func (r *requestObjectTest) theUserShouldBeRedirectedToTheErrorPageWithAnd(errorCode, errorDescription string) error {
+	query, err := r.redirectQuery()
+	if err != nil {
+		return err
+	}
+	if err := r.apiTest.AssertEqual(query.Get("error"), errorCode); err != nil {
+		return err
+	}
+	return r.apiTest.AssertEqual(query.Get("error_description"), errorDescription)
+}
+
+func (r *requestObjectTest) InitializeScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		r.apiTest.URL = "/v1/oauth/authorize"
+		r.apiTest.Method = http.MethodGet
+		r.apiTest.QueryParams = nil
+		return ctx, nil
+	})
+
+	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		_, _ = r.DB.DeleteClient(context.Background(), r.client.ID)
+		_, _ = r.DB.DeleteScope(context.Background(), r.scope)
+		return ctx, nil
+	})
+
+	ctx.Step(`^there is registered scope "([^"]*)"$`, r.thereIsRegisteredScope)
+	ctx.Step(`^A client with registered keys is registered with redirect uri "([^"]*)"$`, r.aClientWithRegisteredKeysIsRegisteredWithRedirectURI)
+	ctx.Step(`^The client signed a request object with the following parameters:$`, r.theClientSignedARequestObjectWithTheFollowingParameters)
+	ctx.Step(`^The client signed a request object (with another key|that is expired|for another audience|issued by another client|without a jti) with the following parameters:$`, r.theClientSignedARequestObjectProblemWithTheFollowingParameters)
+	ctx.Step(`^The request object was already used$`, r.theRequestObjectWasAlreadyUsed)
+	ctx.Step(`^The user is sent to the authorization endpoint with the request object and the following parameters:$`, r.theUserIsSentToTheAuthorizationEndpointWithTheRequestObjectAndTheFollowingParameters)
+	ctx.Step(`^The user should be asked consent with state "([^"]*)" and prompt "([^"]*)"$`, r.theUserShouldBeAskedConsentWithStateAndPrompt)
+	ctx.Step(`^The user should be redirected to the error page with "([^"]*)" and "([^"]*)"$`, r.theUserShouldBeRedirectedToTheErrorPageWithAnd)
+}
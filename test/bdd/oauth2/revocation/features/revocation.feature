@@ -0,0 +1,39 @@
+Feature: Token Revocation
+
+  Background: A user has authorized a client
+    Given A user has authorized a client
+
+  @success
+  Scenario Outline: Client revokes a refresh token
+    Given The client has a refresh token
+    When The client revokes the token with hint "<hint>"
+    Then The revocation should succeed
+    And The refresh token should be deleted
+    Examples:
+      | hint          |
+      |               |
+      | refresh_token |
+
+  @success
+  Scenario Outline: Client revokes an access token
+    Given The client has an access token
+    When The client revokes the token with hint "<hint>"
+    Then The revocation should succeed
+    And The access token should be rejected
+    Examples:
+      | hint          |
+      |               |
+      | access_token  |
+      | refresh_token |
+
+  @success
+  Scenario: Revoking an unknown token is ignored
+    Given The client has the token "not-a-token"
+    When The client revokes the token with hint ""
+    Then The revocation should succeed
+
+  @failure
+  Scenario: Client can not revoke a token issued to another client
+    Given The client has a refresh token
+    When Another client revokes the token
+    Then The revocation should fail with message "unauthorized_client"
@@ -0,0 +1,174 @@
+package revocation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"net/http"
+	"sso/internal/constant"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+	"sso/test"
+	"testing"
+	"time"
+
+	"github.com/cucumber/godog"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
+)
+
+type revocationTest struct {
+	test.TestInstance
+	apiTest     src.ApiTest
+	user        db.User
+	client      db.Client
+	otherClient db.Client
+	token       string
+}
+
+func TestRevocation(t *testing.T) {
+	r := &revocationTest{}
+	r.TestInstance = test.Initiate("../../../../")
+	r.apiTest.InitializeServer(r.Server)
+	r.apiTest.InitializeTest(t, "token revocation", "features/revocation.feature", r.InitializeScenario)
+}
+
+func (r *revocationTest) createClient(name string) (db.Client, error) {
+	secret := utils.GenerateRandomString(25, true)
+	client, err := r.DB.CreateClient(context.Background(), db.CreateClientParams{
+		RedirectUris: utils.ArrayToString([]string{"https://www.google.com"}),
+		Name:         name,
+		Scopes:       "openid profile",
+		ClientType:   constant.ConfidentialClient,
+		Secret:       utils.HashSecret(secret),
+		LogoUrl:      "https://www.google.com/images/errors/robot.png",
+	})
+	client.Secret = secret
+
+	return client, err
+}
+
+func (r *revocationTest) aUserHasAuthorizedAClient() error {
+	var err error
+	if r.user, err = r.DB.CreateUser(context.Background(), db.CreateUserParams{
+		FirstName:  "john",
+		MiddleName: "doe",
+		LastName:   "smith",
+		Email:      sql.NullString{String: "revocation@gmail.com", Valid: true},
+		Phone:      "0912345679",
+		Gender:     "male",
+	}); err != nil {
+		return err
+	}
+
+	if r.client, err = r.createClient("google"); err != nil {
+		return err
+	}
+	r.otherClient, err = r.createClient("facebook")
+	return err
+}
+
+func (r *revocationTest) theClientHasAnAccessToken() error {
+	var err error
+	r.token, err = r.PlatformLayer.Token.GenerateAccessTokenForClient(context.Background(),
+		r.user.ID.String(), r.client.ID.String(), "openid profile", dto.AccessTokenOptions{}, time.Hour)
+	return err
+}
+
+func (r *revocationTest) theClientHasARefreshToken() error {
+	r.token = utils.GenerateRandomString(25, false)
+	_, err := r.DB.SaveRefreshToken(context.Background(), db.SaveRefreshTokenParams{
+		ExpiresAt:    time.Now().Add(time.Hour),
+		UserID:       r.user.ID,
+		Scope:        sql.NullString{String: "openid profile", Valid: true},
+		RedirectUri:  sql.NullString{String: "https://www.google.com", Valid: true},
+		ClientID:     r.client.ID,
+		RefreshToken: r.token,
+		Code:         utils.GenerateRandomString(25, false),
+	})
+	return err
+}
+
+func (r *revocationTest) theClientHasTheToken(token string) error {
+	r.token = token
+	return nil
+}
+
+func (r *revocationTest) revokes(client db.Client, hint string) {
+	r.apiTest.SetHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(client.ID.String()+":"+client.Secret)))
+	r.apiTest.SetHeader("Content-Type", "application/json")
+	r.apiTest.SetBodyValue("token", r.token)
+	if hint != "" {
+		r.apiTest.SetBodyValue("token_type_hint", hint)
+	}
+	r.apiTest.SendRequest()
+}
+
+func (r *revocationTest) theClientRevokesTheTokenWithHint(hint string) error {
+	r.revokes(r.client, hint)
+	return nil
+}
+
+func (r *revocationTest) anotherClientRevokesTheToken() error {
+	r.revokes(r.otherClient, "")
+	return nil
+}
+
+func (r *revocationTest) theRevocationShouldSucceed() error {
+	return r.apiTest.AssertStatusCode(http.StatusOK)
+}
+
+func (r *revocationTest) theRefreshTokenShouldBeDeleted() error {
+	var count int
+	if err := r.Conn.QueryRow(context.Background(),
+		"SELECT count(*) FROM refresh_tokens WHERE refresh_token = $1", r.token).Scan(&count); err != nil {
+		return err
+	}
+	return r.apiTest.AssertEqual(count, 0)
+}
+
+func (r *revocationTest) theAccessTokenShouldBeRejected() error {
+	userInfo := src.ApiTest{}
+	userInfo.InitializeServer(r.Server)
+	userInfo.URL = "/v1/oauth/userinfo"
+	userInfo.Method = http.MethodGet
+	userInfo.SetHeader("Authorization", "Bearer "+r.token)
+	userInfo.SendRequest()
+	return userInfo.AssertStatusCode(http.StatusUnauthorized)
+}
+
+func (r *revocationTest) theRevocationShouldFailWithMessage(message string) error {
+	if err := r.apiTest.AssertStatusCode(http.StatusForbidden); err != nil {
+		return err
+	}
+	return r.apiTest.AssertStringValueOnPathInResponse("error.message", message)
+}
+
+func (r *revocationTest) InitializeScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		r.apiTest.URL = "/v1/oauth/revoke"
+		r.apiTest.Method = http.MethodPost
+		r.apiTest.Body = ""
+		return ctx, nil
+	})
+
+	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		_, _ = r.Conn.Exec(ctx, "DELETE FROM refresh_tokens WHERE client_id = $1", r.client.ID)
+		_, _ = r.Conn.Exec(ctx, "DELETE FROM auth_histories WHERE client_id = $1", r.client.ID)
+		_, _ = r.DB.DeleteClient(ctx, r.client.ID)
+		_, _ = r.DB.DeleteClient(ctx, r.otherClient.ID)
+		_, _ = r.DB.DeleteUser(ctx, r.user.ID)
+		return ctx, nil
+	})
+
+	ctx.Step(`^A user has authorized a client$`, r.aUserHasAuthorizedAClient)
+	ctx.Step(`^The client has an access token$`, r.theClientHasAnAccessToken)
+	ctx.Step(`^The client has a refresh token$`, r.theClientHasARefreshToken)
+	ctx.Step(`^The client has the token "([^"]*)"$`, r.theClientHasTheToken)
+	ctx.Step(`^The client revokes the token with hint "([^"]*)"$`, r.theClientRevokesTheTokenWithHint)
+	ctx.Step(`^Another client revokes the token$`, r.anotherClientRevokesTheToken)
+	ctx.Step(`^The revocation should succeed$`, r.theRevocationShouldSucceed)
+	ctx.Step(`^The refresh token should be deleted$`, r.theRefreshTokenShouldBeDeleted)
+	ctx.Step(`^The access token should be rejected$`, r.theAccessTokenShouldBeRejected)
+	ctx.Step(`^The revocation should fail with message "([^"]*)"$`, r.theRevocationShouldFailWithMessage)
+}
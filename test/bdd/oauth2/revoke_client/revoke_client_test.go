@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/cucumber/godog"
+	"github.com/google/uuid"
 	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
 	"net/http"
 	"sso/internal/constant"
@@ -115,7 +116,7 @@ func (r *revokeClientTest) theClientShouldNoLongerHaveAccessToMyData() error {
 }
 func (r *revokeClientTest) myActionShouldBeRecorded() error {
 	record, err := r.DB.GetLastAuthHistory(context.Background(), db.GetLastAuthHistoryParams{
-		UserID:   r.user.ID,
+		UserID:   uuid.NullUUID{UUID: r.user.ID, Valid: true},
 		ClientID: r.client.ID,
 	})
 	if err != nil {
@@ -41,16 +41,18 @@ func TestLogout(t *testing.T) {
 func (r *rpLogoutTest) iAmRegisteredOnTheSystem() error {
 
 	var err error
+	secret := utils.GenerateRandomString(25, true)
 	if r.client, err = r.DB.CreateClient(context.Background(), db.CreateClientParams{
 		RedirectUris: utils.ArrayToString([]string{"https://www.google.com"}),
 		Name:         "google",
 		Scopes:       "openid",
 		ClientType:   "confidential",
-		Secret:       utils.GenerateRandomString(25, true),
+		Secret:       utils.HashSecret(secret),
 		LogoUrl:      "https://www.google.com/images/errors/robot.png",
 	}); err != nil {
 		return err
 	}
+	r.client.Secret = secret
 	return nil
 }
 
@@ -61,7 +63,7 @@ func (r *rpLogoutTest) iHaveId_token() error {
 		FirstName:  r.user.FirstName,
 		Email:      r.user.Email.String,
 		MiddleName: r.user.MiddleName,
-	}, r.client.ID.String(), time.Hour*24)
+	}, r.client.ID.String(), dto.IDTokenOptions{}, time.Hour*24)
 	if err != nil {
 		return err
 	}
@@ -10,6 +10,13 @@ Feature: UserInfo Endpoint
             | jon        | doe         | john      | 251923456789 | normal@gmail.com | male   |
         When I send userInfo request
         Then I should get correct userInfo response 
+    Scenario: Session token of the sso is not accepted
+        Given there is authenticated user using openid connect with following details
+            | first_name | middle_name | last_name | phone        | email            | gender |
+            | jon        | doe         | john      | 251923456789 | normal@gmail.com | male   |
+        And the user presents a session token of the sso
+        When I send userInfo request
+        Then the request should fail with message "Unauthorized"
     Scenario Outline: Unsuccessful userInfo request
         Given there is invalid access token "<access_token>"
         When I send userInfo request
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/cucumber/godog"
+	"github.com/google/uuid"
 	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
 )
 
@@ -54,7 +55,7 @@ func (u *userInfoTest) thereIsAuthenticatedUserUsingOpenidConnectWithFollowingDe
 		return err
 	}
 
-	accessToken, err := u.PlatformLayer.Token.GenerateAccessToken(context.Background(), u.user.ID.String(), time.Hour)
+	accessToken, err := u.PlatformLayer.Token.GenerateAccessTokenForClient(context.Background(), u.user.ID.String(), uuid.NewString(), "openid", dto.AccessTokenOptions{}, time.Hour)
 	if err != nil {
 		return err
 	}
@@ -63,6 +64,16 @@ func (u *userInfoTest) thereIsAuthenticatedUserUsingOpenidConnectWithFollowingDe
 	return nil
 }
 
+func (u *userInfoTest) theUserPresentsASessionTokenOfTheSso() error {
+	sessionToken, err := u.PlatformLayer.Token.GenerateAccessToken(context.Background(), u.user.ID.String(), dto.Authentication{}, time.Hour)
+	if err != nil {
+		return err
+	}
+	u.apiTest.SetHeader("Authorization", "Bearer "+sessionToken)
+
+	return nil
+}
+
 func (u *userInfoTest) thereIsInvalidAccessToken(accessToken string) error {
 	u.apiTest.SetHeader("Authorization", "Bearer "+accessToken)
 	return nil
@@ -127,6 +138,7 @@ func (u *userInfoTest) InitializeScenario(ctx *godog.ScenarioContext) {
 		return ctx, nil
 	})
 	ctx.Step(`^I send userInfo request$`, u.iSendUserInfoRequest)
+	ctx.Step(`^the user presents a session token of the sso$`, u.theUserPresentsASessionTokenOfTheSso)
 	ctx.Step(`^I should get correct userInfo response$`, u.iShouldGetCorrectUserInfoResponse)
 	ctx.Step(`^the request should fail with message "([^"]*)"$`, u.theRequestShouldFailWithMessage)
 	ctx.Step(`^there is authenticated user using openid connect with following details$`, u.thereIsAuthenticatedUserUsingOpenidConnectWithFollowingDetails)
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"sso/internal/constant/model/db"
 	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
 	"sso/test"
 	"testing"
 )
@@ -45,7 +46,7 @@ func (g *getUserByIDOrPhone) iHaveAuthenticatedMySelfAsAResourceServer() error {
 	g.resourceServer.ID = uuid.New()
 	g.resourceServer.Name = "resource_server_test"
 	g.resourceServer.Secret = "rs_secret"
-	_, err := g.Conn.Exec(context.Background(), fmt.Sprintf("INSERT INTO resource_servers (id, name, secret) values ('%s', '%s', '%s')", g.resourceServer.ID.String(), g.resourceServer.Name, g.resourceServer.Secret))
+	_, err := g.Conn.Exec(context.Background(), fmt.Sprintf("INSERT INTO resource_servers (id, name, secret) values ('%s', '%s', '%s')", g.resourceServer.ID.String(), g.resourceServer.Name, utils.HashSecret(g.resourceServer.Secret)))
 	if err != nil {
 		return err
 	}
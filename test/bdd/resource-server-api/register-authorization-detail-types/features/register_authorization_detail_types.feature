@@ -0,0 +1,36 @@
+Feature: Register Authorization Detail Types
+
+  Background:
+    Given I have authenticated my self as a resource server
+
+  Scenario: I register the authorization detail types I accept
+    When I register the authorization detail types
+      | type                | description                          |
+      | payment_initiation  | initiate a payment from your account |
+      | account_information | read your account balances           |
+    Then the authorization detail types should be registered
+
+  Scenario: I replace the authorization detail types I registered before
+    Given I have registered the authorization detail type "payment_initiation"
+    When I register the authorization detail types
+      | type                | description                 |
+      | account_information | read your account balances |
+    Then the authorization detail types should be registered
+    And the authorization detail type "payment_initiation" should not be registered
+
+  Scenario: The authorization detail type is registered by another resource server
+    Given another resource server registered the authorization detail type "payment_initiation"
+    When I register the authorization detail types
+      | type               | description                          |
+      | payment_initiation | initiate a payment from your account |
+    Then my request should fail with message "authorization detail type is taken"
+
+  Scenario Outline: Invalid authorization detail types
+    When I register the authorization detail types
+      | type   | description   |
+      | <type> | <description> |
+    Then my request should fail with field error "<message>"
+    Examples:
+      | type               | description                          | message                      |
+      |                    | initiate a payment from your account | type is required             |
+      | payment_initiation |                                      | type description is required |
@@ -0,0 +1,187 @@
+package register_authorization_detail_types
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/platform/utils"
+	"sso/test"
+	"testing"
+
+	"github.com/cucumber/godog"
+	"github.com/google/uuid"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
+)
+
+type registerAuthorizationDetailTypes struct {
+	test.TestInstance
+	apiTest             src.ApiTest
+	resourceServer      db.ResourceServer
+	otherResourceServer db.ResourceServer
+	types               []dto.AuthorizationDetailType
+}
+
+func TestRegisterAuthorizationDetailTypes(t *testing.T) {
+	r := registerAuthorizationDetailTypes{}
+	r.TestInstance = test.Initiate("../../../../")
+	r.apiTest.Server = r.Server
+	r.apiTest.URL = "/v1/internal/authorization_detail_types"
+	r.apiTest.Method = http.MethodPut
+	r.apiTest.SetHeader("Content-Type", "application/json")
+	r.apiTest.RunTest(t,
+		"register authorization detail types test",
+		&src.TestOptions{
+			Paths: []string{"features/register_authorization_detail_types.feature"},
+		},
+		r.InitializeScenario,
+		nil,
+	)
+}
+
+func (r *registerAuthorizationDetailTypes) createResourceServer(name, secret string) (db.ResourceServer, error) {
+	rs := db.ResourceServer{
+		ID:     uuid.New(),
+		Name:   name,
+		Secret: secret,
+	}
+	_, err := r.Conn.Exec(context.Background(), fmt.Sprintf("INSERT INTO resource_servers (id, name, secret) values ('%s', '%s', '%s')", rs.ID.String(), rs.Name, utils.HashSecret(rs.Secret)))
+	return rs, err
+}
+
+func (r *registerAuthorizationDetailTypes) iHaveAuthenticatedMySelfAsAResourceServer() error {
+	var err error
+	r.resourceServer, err = r.createResourceServer("resource_server_test", "rs_secret")
+	if err != nil {
+		return err
+	}
+	r.apiTest.SetHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(r.resourceServer.ID.String()+":"+r.resourceServer.Secret)))
+
+	return nil
+}
+
+func (r *registerAuthorizationDetailTypes) iHaveRegisteredTheAuthorizationDetailType(detailType string) error {
+	_, err := r.DB.CreateAuthorizationDetailType(context.Background(), db.CreateAuthorizationDetailTypeParams{
+		Type:             detailType,
+		Description:      detailType,
+		ResourceServerID: r.resourceServer.ID,
+	})
+	return err
+}
+
+func (r *registerAuthorizationDetailTypes) anotherResourceServerRegisteredTheAuthorizationDetailType(detailType string) error {
+	var err error
+	r.otherResourceServer, err = r.createResourceServer("other_resource_server_test", "other_rs_secret")
+	if err != nil {
+		return err
+	}
+	_, err = r.DB.CreateAuthorizationDetailType(context.Background(), db.CreateAuthorizationDetailTypeParams{
+		Type:             detailType,
+		Description:      detailType,
+		ResourceServerID: r.otherResourceServer.ID,
+	})
+	return err
+}
+
+func (r *registerAuthorizationDetailTypes) iRegisterTheAuthorizationDetailTypes(types *godog.Table) error {
+	rows, err := r.apiTest.ReadRowsToMapString(types)
+	if err != nil {
+		return err
+	}
+	r.types = nil
+	for _, row := range rows {
+		r.types = append(r.types, dto.AuthorizationDetailType{
+			Type:        row["type"],
+			Description: row["description"],
+		})
+	}
+	r.apiTest.SetBodyMap(map[string]interface{}{
+		"types": r.types,
+	})
+
+	r.apiTest.SendRequest()
+	return nil
+}
+
+func (r *registerAuthorizationDetailTypes) theAuthorizationDetailTypesShouldBeRegistered() error {
+	if err := r.apiTest.AssertStatusCode(http.StatusOK); err != nil {
+		return err
+	}
+
+	var res []dto.AuthorizationDetailType
+	if err := r.apiTest.UnmarshalResponseBodyPath("data", &res); err != nil {
+		return err
+	}
+	if err := r.apiTest.AssertEqual(len(res), len(r.types)); err != nil {
+		return err
+	}
+
+	var types []string
+	for _, t := range r.types {
+		types = append(types, t.Type)
+	}
+	registered, err := r.DB.GetAuthorizationDetailTypes(context.Background(), types)
+	if err != nil {
+		return err
+	}
+	if err := r.apiTest.AssertEqual(len(registered), len(r.types)); err != nil {
+		return err
+	}
+	for _, t := range registered {
+		if err := r.apiTest.AssertEqual(t.ResourceServerID, r.resourceServer.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *registerAuthorizationDetailTypes) theAuthorizationDetailTypeShouldNotBeRegistered(detailType string) error {
+	registered, err := r.DB.GetAuthorizationDetailTypes(context.Background(), []string{detailType})
+	if err != nil {
+		return err
+	}
+	return r.apiTest.AssertEqual(len(registered), 0)
+}
+
+func (r *registerAuthorizationDetailTypes) myRequestShouldFailWithMessage(message string) error {
+	if err := r.apiTest.AssertStatusCode(http.StatusBadRequest); err != nil {
+		return err
+	}
+	return r.apiTest.AssertStringValueOnPathInResponse("error.message", message)
+}
+
+func (r *registerAuthorizationDetailTypes) myRequestShouldFailWithFieldError(message string) error {
+	if err := r.apiTest.AssertStatusCode(http.StatusBadRequest); err != nil {
+		return err
+	}
+	return r.apiTest.AssertStringValueOnPathInResponse("error.field_error.0.description", message)
+}
+
+func (r *registerAuthorizationDetailTypes) InitializeScenario(ctx *godog.ScenarioContext) {
+	ctx.Step(`^I have authenticated my self as a resource server$`, r.iHaveAuthenticatedMySelfAsAResourceServer)
+	ctx.Step(`^I have registered the authorization detail type "([^"]*)"$`, r.iHaveRegisteredTheAuthorizationDetailType)
+	ctx.Step(`^another resource server registered the authorization detail type "([^"]*)"$`, r.anotherResourceServerRegisteredTheAuthorizationDetailType)
+	ctx.Step(`^I register the authorization detail types$`, r.iRegisterTheAuthorizationDetailTypes)
+	ctx.Step(`^the authorization detail types should be registered$`, r.theAuthorizationDetailTypesShouldBeRegistered)
+	ctx.Step(`^the authorization detail type "([^"]*)" should not be registered$`, r.theAuthorizationDetailTypeShouldNotBeRegistered)
+	ctx.Step(`^my request should fail with message "([^"]*)"$`, r.myRequestShouldFailWithMessage)
+	ctx.Step(`^my request should fail with field error "([^"]*)"$`, r.myRequestShouldFailWithFieldError)
+	ctx.After(func(ctx context.Context, _ *godog.Scenario, _ error) (context.Context, error) {
+		for _, rs := range []db.ResourceServer{r.resourceServer, r.otherResourceServer} {
+			if rs.ID == uuid.Nil {
+				continue
+			}
+			if _, err := r.Conn.Exec(ctx, fmt.Sprintf("DELETE FROM resource_servers WHERE id='%s'", rs.ID.String())); err != nil {
+				return ctx, err
+			}
+		}
+		r.resourceServer = db.ResourceServer{}
+		r.otherResourceServer = db.ResourceServer{}
+		r.types = nil
+
+		return ctx, nil
+	})
+}
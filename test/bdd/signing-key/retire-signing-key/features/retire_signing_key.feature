@@ -0,0 +1,27 @@
+Feature: Retire Signing Key
+
+    As an admin,
+    I want to retire signing keys
+    So that tokens signed with an old key stop being accepted
+
+    Background:
+        Given I am logged in as admin user
+            | email           | password      | role                                  |
+            | admin@gmail.com | adminPassword | retire_signing_key,rotate_signing_key |
+
+    @success
+    Scenario: Successful retirement of a rotated key
+        Given I have a token signed with the current key
+        And the signing key is rotated
+        When I retire the previous signing key
+        Then the token signed with the previous key should be rejected
+
+    @failure
+    Scenario: Retiring the only active key
+        When I retire the current signing key
+        Then the retirement should fail with message "no other signing key will be active, rotate the signing key first"
+
+    @failure
+    Scenario: Retiring an unknown key
+        When I retire the signing key with kid "unknown"
+        Then the retirement should fail with message "signing key not found"
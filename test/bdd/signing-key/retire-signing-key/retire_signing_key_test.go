@@ -0,0 +1,136 @@
+package retire_signing_key
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/test"
+	"testing"
+	"time"
+
+	"github.com/cucumber/godog"
+	"github.com/golang-jwt/jwt/v4"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
+)
+
+type retireSigningKeyTest struct {
+	test.TestInstance
+	apiTest  src.ApiTest
+	Admin    db.User
+	oldToken string
+	oldKid   string
+}
+
+func TestRetireSigningKey(t *testing.T) {
+	r := &retireSigningKeyTest{}
+	r.TestInstance = test.Initiate("../../../../")
+	r.apiTest.InitializeServer(r.Server)
+
+	r.apiTest.InitializeTest(t, "retire signing key test", "features/retire_signing_key.feature", r.InitializeScenario)
+}
+
+func (r *retireSigningKeyTest) iAmLoggedInAsAdminUser(adminCredentials *godog.Table) error {
+	var err error
+	r.Admin, err = r.Authenticate(adminCredentials)
+	if err != nil {
+		return err
+	}
+	_, r.GrantRoleAfterFunc, err = r.GrantRoleForUserWithAfter(r.Admin.ID.String(), adminCredentials)
+	r.apiTest.SetHeader("Authorization", "Bearer "+r.AccessToken)
+
+	return err
+}
+
+func (r *retireSigningKeyTest) currentKid() (string, error) {
+	token, err := r.PlatformLayer.Token.GenerateAccessToken(context.Background(), r.Admin.ID.String(), dto.Authentication{}, time.Hour)
+	if err != nil {
+		return "", err
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return "", err
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid, nil
+}
+
+func (r *retireSigningKeyTest) iHaveATokenSignedWithTheCurrentKey() error {
+	var err error
+	r.oldToken, err = r.PlatformLayer.Token.GenerateAccessToken(context.Background(), r.Admin.ID.String(), dto.Authentication{}, time.Hour)
+	if err != nil {
+		return err
+	}
+	r.oldKid, err = r.currentKid()
+	return err
+}
+
+func (r *retireSigningKeyTest) theSigningKeyIsRotated() error {
+	_, err := r.Module.SigningKeyModule.RotateSigningKey(context.Background(), dto.RotateSigningKeyRequest{})
+	return err
+}
+
+func (r *retireSigningKeyTest) retire(kid string) error {
+	r.apiTest.URL = "/v1/signingKeys/" + kid + "/retire"
+	r.apiTest.SetBodyMap(map[string]interface{}{})
+	r.apiTest.SendRequest()
+	return nil
+}
+
+func (r *retireSigningKeyTest) iRetireThePreviousSigningKey() error {
+	if err := r.retire(r.oldKid); err != nil {
+		return err
+	}
+
+	return r.apiTest.AssertStatusCode(http.StatusOK)
+}
+
+func (r *retireSigningKeyTest) iRetireTheCurrentSigningKey() error {
+	kid, err := r.currentKid()
+	if err != nil {
+		return err
+	}
+
+	return r.retire(kid)
+}
+
+func (r *retireSigningKeyTest) iRetireTheSigningKeyWithKid(kid string) error {
+	return r.retire(kid)
+}
+
+func (r *retireSigningKeyTest) theTokenSignedWithThePreviousKeyShouldBeRejected() error {
+	if valid, _ := r.PlatformLayer.Token.VerifyToken(r.oldToken); valid {
+		return fmt.Errorf("expected the token signed with the retired key to be rejected")
+	}
+
+	return nil
+}
+
+func (r *retireSigningKeyTest) theRetirementShouldFailWithMessage(message string) error {
+	return r.apiTest.AssertStringValueOnPathInResponse("error.message", message)
+}
+
+func (r *retireSigningKeyTest) InitializeScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		r.apiTest.Method = http.MethodPatch
+		r.apiTest.SetHeader("Content-Type", "application/json")
+
+		return ctx, nil
+	})
+
+	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		_, _ = r.DB.DeleteUser(ctx, r.Admin.ID)
+		_ = r.GrantRoleAfterFunc()
+		return ctx, nil
+	})
+
+	ctx.Step(`^I am logged in as admin user$`, r.iAmLoggedInAsAdminUser)
+	ctx.Step(`^I have a token signed with the current key$`, r.iHaveATokenSignedWithTheCurrentKey)
+	ctx.Step(`^the signing key is rotated$`, r.theSigningKeyIsRotated)
+	ctx.Step(`^I retire the previous signing key$`, r.iRetireThePreviousSigningKey)
+	ctx.Step(`^I retire the current signing key$`, r.iRetireTheCurrentSigningKey)
+	ctx.Step(`^I retire the signing key with kid "([^"]*)"$`, r.iRetireTheSigningKeyWithKid)
+	ctx.Step(`^the token signed with the previous key should be rejected$`, r.theTokenSignedWithThePreviousKeyShouldBeRejected)
+	ctx.Step(`^the retirement should fail with message "([^"]*)"$`, r.theRetirementShouldFailWithMessage)
+}
@@ -0,0 +1,30 @@
+Feature: Rotate Signing Key
+
+    As an admin,
+    I want to rotate the key tokens are signed with
+    So that a compromised or old key can be phased out without invalidating every issued token
+
+    Background:
+        Given I am logged in as admin user
+            | email           | password      | role               |
+            | admin@gmail.com | adminPassword | rotate_signing_key |
+
+    @success
+    Scenario: Successful immediate rotation
+        Given I have a token signed with the current key
+        When I rotate the signing key
+        Then the new key should sign the new tokens
+        And the token signed with the previous key should still be valid
+
+    @success
+    Scenario: Successful scheduled rotation
+        When I rotate the signing key to activate in "1h"
+        Then the new key should be published but not sign tokens yet
+
+    @success
+    Scenario: Successful rotation to an elliptic curve key
+        Given I have a token signed with the current key
+        When I rotate the signing key to an "ES256" key
+        Then the new key should be published as an "EC" key
+        And the id tokens of clients using "ES256" should be signed with the new key
+        And the token signed with the previous key should still be valid
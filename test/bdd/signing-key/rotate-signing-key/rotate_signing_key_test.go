@@ -0,0 +1,208 @@
+package rotate_signing_key
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sso/internal/constant/model/db"
+	"sso/internal/constant/model/dto"
+	"sso/test"
+	"testing"
+	"time"
+
+	"github.com/cucumber/godog"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"gitlab.com/2ftimeplc/2fbackend/bdd-testing-framework/src"
+)
+
+type rotateSigningKeyTest struct {
+	test.TestInstance
+	apiTest  src.ApiTest
+	Admin    db.User
+	oldToken string
+	newKey   dto.SigningKey
+}
+
+func TestRotateSigningKey(t *testing.T) {
+	r := &rotateSigningKeyTest{}
+	r.TestInstance = test.Initiate("../../../../")
+	r.apiTest.InitializeServer(r.Server)
+
+	r.apiTest.InitializeTest(t, "rotate signing key test", "features/rotate_signing_key.feature", r.InitializeScenario)
+}
+
+func (r *rotateSigningKeyTest) iAmLoggedInAsAdminUser(adminCredentials *godog.Table) error {
+	var err error
+	r.Admin, err = r.Authenticate(adminCredentials)
+	if err != nil {
+		return err
+	}
+	_, r.GrantRoleAfterFunc, err = r.GrantRoleForUserWithAfter(r.Admin.ID.String(), adminCredentials)
+	r.apiTest.SetHeader("Authorization", "Bearer "+r.AccessToken)
+
+	return err
+}
+
+func (r *rotateSigningKeyTest) iHaveATokenSignedWithTheCurrentKey() error {
+	var err error
+	r.oldToken, err = r.PlatformLayer.Token.GenerateAccessToken(context.Background(), r.Admin.ID.String(), dto.Authentication{}, time.Hour)
+	return err
+}
+
+func (r *rotateSigningKeyTest) rotate(activatesAt time.Time, algorithm string) error {
+	body := map[string]interface{}{}
+	if !activatesAt.IsZero() {
+		body["activates_at"] = activatesAt
+	}
+	if algorithm != "" {
+		body["algorithm"] = algorithm
+	}
+	r.apiTest.SetBodyMap(body)
+	r.apiTest.SendRequest()
+
+	if err := r.apiTest.AssertStatusCode(http.StatusCreated); err != nil {
+		return err
+	}
+
+	return r.apiTest.UnmarshalResponseBodyPath("data", &r.newKey)
+}
+
+func (r *rotateSigningKeyTest) iRotateTheSigningKey() error {
+	return r.rotate(time.Time{}, "")
+}
+
+func (r *rotateSigningKeyTest) iRotateTheSigningKeyToAnKey(algorithm string) error {
+	return r.rotate(time.Time{}, algorithm)
+}
+
+func (r *rotateSigningKeyTest) iRotateTheSigningKeyToActivateIn(after string) error {
+	duration, err := time.ParseDuration(after)
+	if err != nil {
+		return err
+	}
+
+	return r.rotate(time.Now().Add(duration), "")
+}
+
+func (r *rotateSigningKeyTest) kidOf(token string) (string, error) {
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return "", err
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid, nil
+}
+
+func (r *rotateSigningKeyTest) theNewKeyShouldSignTheNewTokens() error {
+	token, err := r.PlatformLayer.Token.GenerateAccessToken(context.Background(), r.Admin.ID.String(), dto.Authentication{}, time.Hour)
+	if err != nil {
+		return err
+	}
+
+	kid, err := r.kidOf(token)
+	if err != nil {
+		return err
+	}
+
+	return r.apiTest.AssertEqual(kid, r.newKey.Kid)
+}
+
+func (r *rotateSigningKeyTest) theTokenSignedWithThePreviousKeyShouldStillBeValid() error {
+	if valid, _ := r.PlatformLayer.Token.VerifyToken(r.oldToken); !valid {
+		return fmt.Errorf("expected the token signed with the previous key to be valid")
+	}
+
+	return nil
+}
+
+func (r *rotateSigningKeyTest) theNewKeyShouldBePublishedAsAnKey(kty string) error {
+	for _, key := range r.PlatformLayer.Token.JWKS(context.Background()).Keys {
+		if key.Kid == r.newKey.Kid {
+			if err := r.apiTest.AssertEqual(key.Kty, kty); err != nil {
+				return err
+			}
+			return r.apiTest.AssertEqual(key.Alg, r.newKey.Algorithm)
+		}
+	}
+
+	return fmt.Errorf("expected the new key to be published")
+}
+
+func (r *rotateSigningKeyTest) theIdTokensOfClientsUsingShouldBeSignedWithTheNewKey(algorithm string) error {
+	idToken, err := r.PlatformLayer.Token.GenerateIdToken(context.Background(), &dto.User{
+		ID: r.Admin.ID,
+	}, uuid.NewString(), dto.IDTokenOptions{
+		SigningAlgorithm: algorithm,
+	}, time.Hour)
+	if err != nil {
+		return err
+	}
+
+	kid, err := r.kidOf(idToken)
+	if err != nil {
+		return err
+	}
+	if err := r.apiTest.AssertEqual(kid, r.newKey.Kid); err != nil {
+		return err
+	}
+
+	if valid, _ := r.PlatformLayer.Token.VerifyIdToken(idToken); !valid {
+		return fmt.Errorf("expected the id token signed with the new key to be valid")
+	}
+
+	return nil
+}
+
+func (r *rotateSigningKeyTest) theNewKeyShouldBePublishedButNotSignTokensYet() error {
+	published := false
+	for _, key := range r.PlatformLayer.Token.JWKS(context.Background()).Keys {
+		if key.Kid == r.newKey.Kid {
+			published = true
+		}
+	}
+	if !published {
+		return fmt.Errorf("expected the scheduled key to be published")
+	}
+
+	token, err := r.PlatformLayer.Token.GenerateAccessToken(context.Background(), r.Admin.ID.String(), dto.Authentication{}, time.Hour)
+	if err != nil {
+		return err
+	}
+	kid, err := r.kidOf(token)
+	if err != nil {
+		return err
+	}
+	if kid == r.newKey.Kid {
+		return fmt.Errorf("expected the scheduled key not to sign tokens yet")
+	}
+
+	return nil
+}
+
+func (r *rotateSigningKeyTest) InitializeScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		r.apiTest.URL = "/v1/signingKeys/rotate"
+		r.apiTest.Method = http.MethodPost
+		r.apiTest.SetHeader("Content-Type", "application/json")
+
+		return ctx, nil
+	})
+
+	ctx.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		_, _ = r.DB.DeleteUser(ctx, r.Admin.ID)
+		_ = r.GrantRoleAfterFunc()
+		return ctx, nil
+	})
+
+	ctx.Step(`^I am logged in as admin user$`, r.iAmLoggedInAsAdminUser)
+	ctx.Step(`^I have a token signed with the current key$`, r.iHaveATokenSignedWithTheCurrentKey)
+	ctx.Step(`^I rotate the signing key$`, r.iRotateTheSigningKey)
+	ctx.Step(`^I rotate the signing key to activate in "([^"]*)"$`, r.iRotateTheSigningKeyToActivateIn)
+	ctx.Step(`^I rotate the signing key to an "([^"]*)" key$`, r.iRotateTheSigningKeyToAnKey)
+	ctx.Step(`^the new key should be published as an "([^"]*)" key$`, r.theNewKeyShouldBePublishedAsAnKey)
+	ctx.Step(`^the id tokens of clients using "([^"]*)" should be signed with the new key$`, r.theIdTokensOfClientsUsingShouldBeSignedWithTheNewKey)
+	ctx.Step(`^the new key should sign the new tokens$`, r.theNewKeyShouldSignTheNewTokens)
+	ctx.Step(`^the token signed with the previous key should still be valid$`, r.theTokenSignedWithThePreviousKeyShouldStillBeValid)
+	ctx.Step(`^the new key should be published but not sign tokens yet$`, r.theNewKeyShouldBePublishedButNotSignTokensYet)
+}
@@ -118,6 +118,7 @@ func Initiate(path string) TestInstance {
 		SessionExpireTime:  viper.GetDuration("redis.session_expire_time"),
 		ConsentExpireTime:  viper.GetDuration("redis.consent_expire_time"),
 		AuthCodeExpireTime: viper.GetDuration("redis.authcode_expire_time"),
+		DeviceExpireTime:   viper.GetDuration("redis.device_code_expire_time"),
 	})
 	log.Info(context.Background(), "cache layer initialized")
 
@@ -133,6 +134,12 @@ func Initiate(path string) TestInstance {
 	module := initiator.InitMockModule(persistence, cacheLayer, path+viper.GetString("private_key"), platformLayer, log, enforcer, state, path)
 	log.Info(context.Background(), "module initialized")
 
+	log.Info(context.Background(), "initializing signing keys")
+	if err := module.SigningKeyModule.LoadSigningKeys(context.Background()); err != nil {
+		log.Fatal(context.Background(), "failed to load signing keys")
+	}
+	log.Info(context.Background(), "signing keys initialized")
+
 	log.Info(context.Background(), "initializing handler")
 	handler := initiator.InitHandler(module, log)
 	log.Info(context.Background(), "handler initialized")